@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+	"github.com/zemanlx/kat/internal/loader"
+	"github.com/zemanlx/kat/internal/reporter"
+)
+
+var errInvalidBenchtime = errors.New("invalid benchtime")
+
+// maxExpressionWidth limits how much of an expression is shown in benchmark names.
+const maxExpressionWidth = 60
+
+// benchtime controls how long each test is benchmarked: either a fixed
+// number of iterations ("100x") or a minimum duration ("1s").
+type benchtime struct {
+	iterations int
+	duration   time.Duration
+}
+
+func parseBenchtime(value string) (benchtime, error) {
+	if count, ok := strings.CutSuffix(value, "x"); ok {
+		n, err := strconv.Atoi(count)
+		if err != nil || n <= 0 {
+			return benchtime{}, fmt.Errorf("%w: %q", errInvalidBenchtime, value)
+		}
+
+		return benchtime{iterations: n}, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return benchtime{}, fmt.Errorf("%w: %q", errInvalidBenchtime, value)
+	}
+
+	return benchtime{duration: d}, nil
+}
+
+// benchmarks aggregates timings per policy and per policy expression.
+type benchmarks struct {
+	policies    map[string]*reporter.Benchmark
+	expressions map[string]*reporter.Benchmark
+}
+
+func newBenchmarks() *benchmarks {
+	return &benchmarks{
+		policies:    make(map[string]*reporter.Benchmark),
+		expressions: make(map[string]*reporter.Benchmark),
+	}
+}
+
+// run evaluates a test repeatedly according to bt and attributes the
+// measured durations to the test's policy.
+func (b *benchmarks) run(
+	eval *evaluator.Evaluator,
+	bt benchtime,
+	test *loader.TestCase,
+	evaluate func() *evaluator.TestResult,
+) {
+	eval.ResetTimings()
+
+	var (
+		iterations int
+		total      time.Duration
+	)
+
+	for {
+		start := time.Now()
+		evaluate()
+		total += time.Since(start)
+		iterations++
+
+		if bt.iterations > 0 && iterations >= bt.iterations {
+			break
+		}
+
+		if bt.duration > 0 && total >= bt.duration {
+			break
+		}
+	}
+
+	policy := b.get(b.policies, test.PolicyName)
+	policy.Iterations += iterations
+	policy.Total += total
+
+	for _, timing := range eval.Timings() {
+		expression := b.get(b.expressions, test.PolicyName+"/"+shortenExpression(timing.Expression))
+		expression.Iterations += timing.Count
+		expression.Total += timing.Total
+	}
+}
+
+func (b *benchmarks) get(m map[string]*reporter.Benchmark, name string) *reporter.Benchmark {
+	bench, ok := m[name]
+	if !ok {
+		bench = &reporter.Benchmark{Name: name}
+		m[name] = bench
+	}
+
+	return bench
+}
+
+// report hands the sorted results to the reporter.
+func (b *benchmarks) report(rep *reporter.Reporter, slowThreshold time.Duration) {
+	rep.ReportBenchmarks(sortedBenchmarks(b.policies), sortedBenchmarks(b.expressions), slowThreshold)
+}
+
+func sortedBenchmarks(m map[string]*reporter.Benchmark) []reporter.Benchmark {
+	result := make([]reporter.Benchmark, 0, len(m))
+	for _, bench := range m {
+		result = append(result, *bench)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// shortenExpression collapses whitespace and truncates long expressions so
+// they fit on a single line of the benchmark table.
+func shortenExpression(expression string) string {
+	expression = strings.Join(strings.Fields(expression), " ")
+	if len(expression) > maxExpressionWidth {
+		return expression[:maxExpressionWidth-3] + "..."
+	}
+
+	return expression
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/zemanlx/kat/internal/loader"
+	"github.com/zemanlx/kat/pkg/evaluator"
+)
+
+var (
+	errCheckFlags    = errors.New("-policies and -f are required")
+	errCheckDenied   = errors.New("manifests denied by admission policies")
+	errNoPolicies    = errors.New("no validating admission policies found")
+	errFetchPolicies = errors.New("fetch policies")
+)
+
+// maxPolicyFetchBytes bounds the size of policies downloaded by kat check.
+const maxPolicyFetchBytes = 10 << 20
+
+// checkCounts tallies the outcome of kat check over all manifests.
+type checkCounts struct {
+	denied   int
+	warnings int
+	errors   int
+}
+
+// runCheck implements "kat check -policies <dir-or-url> -f <path>": it submits
+// every manifest under path as a CREATE request to all validating policies and
+// reports denials and warnings per manifest file. There are no expectations;
+// any denial fails the check.
+func runCheck(ctx context.Context, args []string, stdout, stderr *os.File) error {
+	fs := flag.NewFlagSet(args[0]+" check", flag.ContinueOnError)
+
+	policiesFlag := fs.String("policies", "", "policy `dir` (searched recursively) or http(s) URL of a policy YAML file")
+	manifestsFlag := fs.String("f", "", "manifest `path`: a file or a directory searched recursively for .yaml, .yml and .json files")
+
+	if err := parseFlagSet(fs, args[2:], stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: parse flags: %w", errUsage, err)
+	}
+
+	if *policiesFlag == "" || *manifestsFlag == "" {
+		return fmt.Errorf("%w: %w", errUsage, errCheckFlags)
+	}
+
+	policySet, err := loadCheckPolicies(ctx, *policiesFlag)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errLoad, err)
+	}
+
+	manifests, err := loader.LoadManifests(*manifestsFlag)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errLoad, err)
+	}
+
+	eval, err := evaluator.New()
+	if err != nil {
+		return fmt.Errorf("create evaluator: %w", err)
+	}
+
+	policies := checkablePolicies(policySet, stderr)
+
+	counts := checkManifests(ctx, eval, policies, manifests, stdout)
+
+	status := "ok"
+	if counts.denied > 0 || counts.errors > 0 {
+		status = "FAIL"
+	}
+
+	fmt.Fprintf(stdout, "%s\t%d object(s) in %d file(s): %d denied, %d warning(s), %d error(s)\n",
+		status, len(manifests), countFiles(manifests), counts.denied, counts.warnings, counts.errors)
+
+	if status == "FAIL" {
+		return errCheckDenied
+	}
+
+	return nil
+}
+
+// loadCheckPolicies loads the policies from a directory, or from a single YAML
+// file downloaded from an http(s) URL.
+func loadCheckPolicies(ctx context.Context, location string) (*loader.PolicySet, error) {
+	dir := location
+
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		tmpDir, err := os.MkdirTemp("", "kat-policies-")
+		if err != nil {
+			return nil, fmt.Errorf("create policy directory: %w", err)
+		}
+
+		defer os.RemoveAll(tmpDir)
+
+		if err := fetchPolicies(ctx, location, filepath.Join(tmpDir, "policies.yaml")); err != nil {
+			return nil, err
+		}
+
+		dir = tmpDir
+	}
+
+	policySet, err := loader.LoadPolicySet(dir)
+	if err != nil {
+		return nil, fmt.Errorf("load policies: %w", err)
+	}
+
+	if len(policySet.ValidatingPolicies) == 0 {
+		return nil, fmt.Errorf("%w in %s", errNoPolicies, location)
+	}
+
+	return policySet, nil
+}
+
+func fetchPolicies(ctx context.Context, url, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errFetchPolicies, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errFetchPolicies, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s: %s", errFetchPolicies, url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPolicyFetchBytes))
+	if err != nil {
+		return fmt.Errorf("%w: %w", errFetchPolicies, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write policies: %w", err)
+	}
+
+	return nil
+}
+
+// checkPolicy is a validating policy with one of its bindings; the binding is
+// nil for unbound policies, which are evaluated as in tests.
+type checkPolicy struct {
+	policy  *admissionregv1.ValidatingAdmissionPolicy
+	binding *admissionregv1.ValidatingAdmissionPolicyBinding
+}
+
+// checkablePolicies pairs every validating policy with each of its bindings.
+// Policies with a paramKind are skipped with a warning, as their params only
+// exist in a cluster.
+func checkablePolicies(policySet *loader.PolicySet, stderr io.Writer) []checkPolicy {
+	var policies []checkPolicy
+
+	for _, policy := range policySet.ValidatingPolicies {
+		if policy.Spec.ParamKind != nil {
+			fmt.Fprintf(stderr, "warning: policy %s needs params and is not checked\n", policy.Name)
+
+			continue
+		}
+
+		bound := false
+
+		for _, binding := range policySet.ValidatingBindings {
+			if binding.Spec.PolicyName == policy.Name {
+				policies = append(policies, checkPolicy{policy: policy, binding: binding})
+				bound = true
+			}
+		}
+
+		if !bound {
+			policies = append(policies, checkPolicy{policy: policy})
+		}
+	}
+
+	return policies
+}
+
+// checkManifests evaluates the manifests file by file and prints each file's
+// status followed by its denials, warnings and evaluation errors.
+func checkManifests(ctx context.Context, eval *evaluator.Evaluator, policies []checkPolicy, manifests []*loader.Manifest, stdout io.Writer) checkCounts {
+	var total checkCounts
+
+	for start := 0; start < len(manifests); {
+		end := start
+		for end < len(manifests) && manifests[end].File == manifests[start].File {
+			end++
+		}
+
+		var (
+			counts checkCounts
+			lines  []string
+		)
+
+		for _, manifest := range manifests[start:end] {
+			lines = append(lines, checkManifest(ctx, eval, policies, manifest, &counts)...)
+		}
+
+		status := "ok"
+		if counts.denied > 0 || counts.errors > 0 {
+			status = "FAIL"
+		}
+
+		fmt.Fprintf(stdout, "%s\t%s\n", status, manifests[start].File)
+
+		for _, line := range lines {
+			fmt.Fprintf(stdout, "    %s\n", line)
+		}
+
+		total.denied += counts.denied
+		total.warnings += counts.warnings
+		total.errors += counts.errors
+		start = end
+	}
+
+	return total
+}
+
+func checkManifest(ctx context.Context, eval *evaluator.Evaluator, policies []checkPolicy, manifest *loader.Manifest, counts *checkCounts) []string {
+	var lines []string
+
+	obj := manifest.Object
+	id := objectID(obj)
+
+	for _, p := range policies {
+		result, err := eval.EvaluateValidating(ctx, evaluator.ValidatingInput{Policy: p.policy, Binding: p.binding, Object: obj})
+		if err != nil {
+			counts.errors++
+			lines = append(lines, fmt.Sprintf("%s: error: %v", id, err))
+
+			continue
+		}
+
+		if !result.Allowed {
+			counts.denied++
+			lines = append(lines, fmt.Sprintf("%s: denied by %s: %s", id, p.policy.Name, result.Message))
+		}
+
+		for _, warning := range result.Warnings {
+			counts.warnings++
+			lines = append(lines, fmt.Sprintf("%s: warning from %s: %s", id, p.policy.Name, warning))
+		}
+	}
+
+	return lines
+}
+
+// objectID identifies an object as "Kind namespace/name" or "Kind name".
+func objectID(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() != "" {
+		return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+}
+
+func countFiles(manifests []*loader.Manifest) int {
+	files := map[string]bool{}
+	for _, manifest := range manifests {
+		files[manifest.File] = true
+	}
+
+	return len(files)
+}
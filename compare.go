@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+	"github.com/zemanlx/kat/internal/loader"
+	"github.com/zemanlx/kat/internal/reporter"
+	"github.com/zemanlx/kat/internal/runner"
+)
+
+var errNoComparePolicies = errors.New("no admission policies found")
+
+// comparePolicies evaluates every test of suites against the suite's policy
+// and against the policy of the same name in the -compare file, with the
+// suite's bindings, and fails the tests for which the two decide differently:
+// a different allowed, message or mutated object, or an evaluation error of
+// only one of them. The tests' expectations are not checked. Tests of
+// policies the file does not define are skipped.
+func comparePolicies(suites []*loader.TestSuite, cfg *config, stdout, stderr *os.File) error {
+	oldPath := cfg.compareFile
+
+	old, err := loader.LoadPolicyFile(oldPath)
+	if err != nil {
+		return fmt.Errorf("%w: load policies to compare: %w", errLoad, err)
+	}
+
+	if len(old.ValidatingPolicies) == 0 && len(old.MutatingPolicies) == 0 {
+		return fmt.Errorf("%w: %w in %s", errLoad, errNoComparePolicies, oldPath)
+	}
+
+	eval, err := evaluator.New(evaluatorOptions(cfg, stderr)...)
+	if err != nil {
+		return fmt.Errorf("%w: create evaluator: %w", errUsage, err)
+	}
+
+	rep := reporter.New(stdout)
+	configureReporter(rep, cfg, stderr)
+
+	for _, suite := range suites {
+		suiteRep := rep.StartSuite(suite.Name)
+
+		for _, test := range suite.Tests {
+			suiteRep.StartTest(test.Name, test.FilePath)
+			suiteRep.SetTestPolicy(test.PolicyName)
+			compareTest(eval, suiteRep, suite, test, old, oldPath)
+		}
+
+		suiteRep.End()
+	}
+
+	if err := rep.Summary(); err != nil {
+		return fmt.Errorf("compare summary: %w", err)
+	}
+
+	return rep.Results().Err()
+}
+
+// compareTest evaluates test against the policies of suite and of old and
+// reports whether they decide the same.
+func compareTest(eval *evaluator.Evaluator, suiteRep *reporter.SuiteReporter, suite *loader.TestSuite, test *loader.TestCase, old *loader.PolicySet, oldPath string) {
+	if test.Error != nil {
+		suiteRep.ReportFail(test.Name, fmt.Sprintf("test loading error: %v", test.Error))
+
+		return
+	}
+
+	policies, err := runner.ForTest(suite, test)
+	if err != nil {
+		suiteRep.ReportFail(test.Name, err.Error())
+
+		return
+	}
+
+	oldPolicies, ok := replacePolicies(policies, old)
+	if !ok {
+		suiteRep.ReportSkip(test.Name, fmt.Sprintf("no policy %q in %s", test.PolicyName, oldPath))
+
+		return
+	}
+
+	newResult := policies.Evaluate(eval, test)
+	oldResult := oldPolicies.Evaluate(eval, test)
+
+	if diff := decisionDifference(oldResult, newResult); diff != "" {
+		suiteRep.ReportFail(test.Name, diff)
+
+		return
+	}
+
+	suiteRep.ReportPass(test.Name)
+}
+
+// replacePolicies returns policies with each policy replaced by the policy of
+// the same name and kind in old, keeping the bindings. It reports false when
+// old defines none of them.
+func replacePolicies(policies runner.Policies, old *loader.PolicySet) (runner.Policies, bool) {
+	replaced := false
+
+	if policies.MutatingPolicy != nil {
+		for _, policy := range old.MutatingPolicies {
+			if policy.Name == policies.MutatingPolicy.Name {
+				policies.MutatingPolicy, replaced = policy, true
+			}
+		}
+	}
+
+	if policies.ValidatingPolicy != nil {
+		for _, policy := range old.ValidatingPolicies {
+			if policy.Name == policies.ValidatingPolicy.Name {
+				policies.ValidatingPolicy, replaced = policy, true
+			}
+		}
+	}
+
+	return policies, replaced
+}
+
+// decisionDifference describes how the decisions of the old and the new
+// policy differ, or returns "" when they are the same.
+func decisionDifference(oldResult, newResult *evaluator.TestResult) string {
+	oldOutcome, newOutcome := oldResult.Actual, newResult.Actual
+
+	if oldOutcome.EvaluationErr != nil || newOutcome.EvaluationErr != nil {
+		if errorText(oldOutcome.EvaluationErr) == errorText(newOutcome.EvaluationErr) {
+			return ""
+		}
+
+		return fmt.Sprintf("evaluation error differs: old %s, new %s",
+			errorText(oldOutcome.EvaluationErr), errorText(newOutcome.EvaluationErr))
+	}
+
+	if oldOutcome.Allowed != newOutcome.Allowed {
+		return fmt.Sprintf("decision differs: old policy allowed=%v, new policy allowed=%v; old message %q, new message %q",
+			oldOutcome.Allowed, newOutcome.Allowed, oldOutcome.Message, newOutcome.Message)
+	}
+
+	if oldOutcome.Message != newOutcome.Message {
+		return fmt.Sprintf("message differs: old %q, new %q", oldOutcome.Message, newOutcome.Message)
+	}
+
+	if !reflect.DeepEqual(oldResult.PatchedObject, newResult.PatchedObject) {
+		return "mutated object differs:\n" + objectDiff(oldResult, newResult)
+	}
+
+	return ""
+}
+
+// errorText returns the text of err, or "none".
+func errorText(err error) string {
+	if err == nil {
+		return "none"
+	}
+
+	return fmt.Sprintf("%q", err.Error())
+}
+
+// objectDiff returns the unified diff of the objects the old and the new
+// policy produced.
+func objectDiff(oldResult, newResult *evaluator.TestResult) string {
+	var oldYAML, newYAML []byte
+
+	if oldResult.PatchedObject != nil {
+		oldYAML, _ = yaml.Marshal(oldResult.PatchedObject.Object)
+	}
+
+	if newResult.PatchedObject != nil {
+		newYAML, _ = yaml.Marshal(newResult.PatchedObject.Object)
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(oldYAML)),
+		B:        difflib.SplitLines(string(newYAML)),
+		FromFile: "Old policy",
+		ToFile:   "New policy",
+		Context:  3,
+	})
+
+	return diff
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	errConfigUnknownFlag = errors.New("unknown flag")
+	errConfigValue       = errors.New("unsupported value")
+)
+
+// defaultConfigFiles are the config files looked up in the working directory
+// when -config is not given.
+//
+//nolint:gochecknoglobals // Static lookup table
+var defaultConfigFiles = []string{".kat.yaml", ".kat.yml"}
+
+// applyConfigFile sets the flags of fs that were not given on the command
+// line from a config file: path, or the first of defaultConfigFiles in the
+// working directory when path is empty. The file maps flag names without
+// dashes to values, e.g. "strict: true" or "default-namespace: team-a";
+// repeatable flags take a list. A missing default config file is not an
+// error.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	data, path, err := readConfigFile(path)
+	if err != nil || data == nil {
+		return err
+	}
+
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if fs.Lookup(name) == nil || name == "config" {
+			return fmt.Errorf("config file %s: %w %q", path, errConfigUnknownFlag, name)
+		}
+
+		// Flags given on the command line take precedence.
+		if set[name] {
+			continue
+		}
+
+		items, ok := values[name].([]any)
+		if !ok {
+			items = []any{values[name]}
+		}
+
+		for _, item := range items {
+			value, err := configValue(item)
+			if err != nil {
+				return fmt.Errorf("config file %s: %s: %w", path, name, err)
+			}
+
+			if err := fs.Set(name, value); err != nil {
+				return fmt.Errorf("config file %s: %s: %w", path, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// configValue returns the flag value of a scalar of a config file.
+func configValue(item any) (string, error) {
+	switch v := item.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		// Avoid exponents, e.g. for -cost-limit 1000000.
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("%w %v", errConfigValue, item)
+	}
+}
+
+// readConfigFile returns the content and path of the config file, or nil
+// when path is empty and there is no default config file.
+func readConfigFile(path string) ([]byte, string, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("read config file: %w", err)
+		}
+
+		return data, path, nil
+	}
+
+	for _, name := range defaultConfigFiles {
+		data, err := os.ReadFile(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, "", fmt.Errorf("read config file: %w", err)
+		}
+
+		return data, name, nil
+	}
+
+	return nil, "", nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+var errDirOutsideRoot = errors.New("-dir must be a relative path below the path arguments")
+
+// dirFlags collects repeated -dir flags.
+type dirFlags []string
+
+func (f *dirFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *dirFlags) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+// parseDirs cleans the -dir prefixes, which are relative to the path
+// arguments and must not leave them.
+func parseDirs(values []string) ([]string, error) {
+	dirs := make([]string, 0, len(values))
+
+	for _, value := range values {
+		dir := filepath.Clean(value)
+		if filepath.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%w, got %q", errDirOutsideRoot, value)
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	return dirs, nil
+}
+
+// filters describes the flags limiting which tests run, e.g. "-dir=payments",
+// for the run metadata of verbose and JSON output.
+func (c *config) filters() []string {
+	var filters []string
+
+	for _, dir := range c.dirs {
+		filters = append(filters, "-dir="+dir)
+	}
+
+	if c.runPattern != "" {
+		filters = append(filters, "-run="+c.runPattern)
+
+		if c.runExact {
+			filters = append(filters, "-run-exact")
+		}
+	}
+
+	return filters
+}
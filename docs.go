@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zemanlx/kat/internal/docs"
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+var errDuplicateSuiteName = errors.New("suites share a name")
+
+// runDocs implements "kat docs [-o dir] [paths...]": it writes one Markdown
+// file per test suite to the output directory.
+func runDocs(args []string, stdout *os.File) error {
+	fs := flag.NewFlagSet(args[0]+" docs", flag.ContinueOnError)
+
+	outputDir := fs.String("o", "docs", "write Markdown files to `dir`")
+
+	if err := parseFlagSet(fs, args[2:], stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: parse flags: %w", errUsage, err)
+	}
+
+	paths := []string{"."}
+	if fs.NArg() > 0 {
+		paths = fs.Args()
+	}
+
+	suites, err := loadSuites(paths, loader.Options{})
+	if err != nil {
+		return fmt.Errorf("%w: %w", errLoad, err)
+	}
+
+	//nolint:gosec // Generated docs are committed to the repository.
+	if err := os.MkdirAll(*outputDir, 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	written := make(map[string]string, len(suites))
+
+	for _, suite := range suites {
+		name := docs.FileName(suite)
+		if other, ok := written[name]; ok {
+			return fmt.Errorf("%w: %s and %s", errDuplicateSuiteName, other, suite.Path)
+		}
+
+		written[name] = suite.Path
+
+		if err := writeSuiteDocs(filepath.Join(*outputDir, name), suite); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(stdout, filepath.Join(*outputDir, name))
+	}
+
+	return nil
+}
+
+func writeSuiteDocs(path string, suite *loader.TestSuite) (err error) {
+	file, err := os.Create(path) //nolint:gosec // Path is built from the output flag and suite name.
+	if err != nil {
+		return fmt.Errorf("create docs: %w", err)
+	}
+
+	defer func() {
+		err = errors.Join(err, file.Close())
+	}()
+
+	if err := docs.Render(file, suite); err != nil {
+		return fmt.Errorf("write docs %s: %w", path, err)
+	}
+
+	return nil
+}
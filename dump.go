@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+)
+
+// dumpMarker marks a directory created by -dump-failures, so that only such
+// directories are cleared by later runs.
+const dumpMarker = ".kat-dump"
+
+var errDumpDirNotEmpty = errors.New("is not empty and was not created by -dump-failures")
+
+//nolint:gochecknoglobals // Compiled once
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// failureDumper writes the artifacts of failing tests below a directory. A nil
+// *failureDumper dumps nothing.
+type failureDumper struct {
+	dir string
+}
+
+// newFailureDumper prepares dir for a new dump: a directory left by a previous
+// dump is removed, while any other non-empty directory is an error so that an
+// unrelated directory is never deleted. It returns nil when dir is empty.
+func newFailureDumper(dir string) (*failureDumper, error) {
+	if dir == "" {
+		return nil, nil //nolint:nilnil // nil disables dumping
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read dump directory: %w", err)
+	}
+
+	if len(entries) > 0 {
+		if _, err := os.Stat(filepath.Join(dir, dumpMarker)); err != nil {
+			return nil, fmt.Errorf("dump directory %s %w", dir, errDumpDirNotEmpty)
+		}
+
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, fmt.Errorf("remove previous dump: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create dump directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, dumpMarker), nil, 0o600); err != nil {
+		return nil, fmt.Errorf("mark dump directory: %w", err)
+	}
+
+	return &failureDumper{dir: dir}, nil
+}
+
+// dump writes message.txt and, when known, expected.yaml (the expected object),
+// actual.yaml (the object after admission) and patch.yaml (the JSON Patch
+// operations applied) to <dir>/<suite>/<test>/, and returns that directory.
+func (d *failureDumper) dump(suiteName, testName string, result *evaluator.TestResult) (string, error) {
+	if d == nil {
+		return "", nil
+	}
+
+	testDir := filepath.Join(d.dir, dumpPath(suiteName), sanitizePathElement(strings.TrimSuffix(testName, ".yaml")))
+	if err := os.MkdirAll(testDir, 0o750); err != nil {
+		return "", fmt.Errorf("create dump directory: %w", err)
+	}
+
+	files := map[string]any{}
+
+	if result.Expected.Object != nil {
+		files["expected.yaml"] = result.Expected.Object.Object
+	}
+
+	if result.Actual.Object != nil {
+		files["actual.yaml"] = result.Actual.Object.Object
+	}
+
+	if result.Actual.Patch != nil {
+		files["patch.yaml"] = result.Actual.Patch
+	}
+
+	for name, content := range files {
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return "", fmt.Errorf("marshal %s: %w", name, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(testDir, name), data, 0o600); err != nil {
+			return "", fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+
+	message := strings.TrimRight(result.Message, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(testDir, "message.txt"), []byte(message), 0o600); err != nil {
+		return "", fmt.Errorf("write message.txt: %w", err)
+	}
+
+	return testDir, nil
+}
+
+// dumpPath turns a suite name, which may contain '/', into a relative path of
+// sanitized elements.
+func dumpPath(suiteName string) string {
+	parts := strings.Split(filepath.ToSlash(suiteName), "/")
+	for i, part := range parts {
+		parts[i] = sanitizePathElement(part)
+	}
+
+	return filepath.Join(parts...)
+}
+
+// sanitizePathElement replaces characters that are unsafe in file names with
+// '_' and never returns "", "." or "..".
+func sanitizePathElement(name string) string {
+	name = unsafePathChars.ReplaceAllString(name, "_")
+	if strings.Trim(name, ".") == "" {
+		return "_" + name
+	}
+
+	return name
+}
+
+// dumpFailure dumps a failing test and appends where its artifacts were
+// written, or why they could not be, to the failure message.
+func dumpFailure(dumper *failureDumper, suiteName, testName string, result *evaluator.TestResult) {
+	dir, err := dumper.dump(suiteName, testName, result)
+
+	switch {
+	case err != nil:
+		result.Message += fmt.Sprintf("\ndump failure artifacts: %v", err)
+	case dir != "":
+		result.Message += "\nfailure artifacts: " + dir
+	}
+}
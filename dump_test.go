@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+)
+
+func TestNewFailureDumper(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, dir string)
+		wantErr error
+	}{
+		{
+			name:  "missing directory",
+			setup: func(*testing.T, string) {},
+		},
+		{
+			name: "empty directory",
+			setup: func(t *testing.T, dir string) {
+				t.Helper()
+				mustWriteFile(t, filepath.Join(dir, "placeholder"), "")
+				mustRemove(t, filepath.Join(dir, "placeholder"))
+			},
+		},
+		{
+			name: "previous dump",
+			setup: func(t *testing.T, dir string) {
+				t.Helper()
+				mustWriteFile(t, filepath.Join(dir, dumpMarker), "")
+				mustWriteFile(t, filepath.Join(dir, "suite", "old", "message.txt"), "old failure")
+			},
+		},
+		{
+			name: "unrelated directory",
+			setup: func(t *testing.T, dir string) {
+				t.Helper()
+				mustWriteFile(t, filepath.Join(dir, "keep.txt"), "keep")
+			},
+			wantErr: errDumpDirNotEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := filepath.Join(t.TempDir(), "dump")
+			tt.setup(t, dir)
+
+			dumper, err := newFailureDumper(dir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("newFailureDumper() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				if _, statErr := os.Stat(filepath.Join(dir, "keep.txt")); statErr != nil {
+					t.Errorf("unrelated file was removed: %v", statErr)
+				}
+
+				return
+			}
+
+			entries, err := os.ReadDir(dumper.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 1 || entries[0].Name() != dumpMarker {
+				t.Errorf("dump directory entries = %v, want only %s", entries, dumpMarker)
+			}
+		})
+	}
+}
+
+func TestNewFailureDumper_Disabled(t *testing.T) {
+	t.Parallel()
+
+	dumper, err := newFailureDumper("")
+	if dumper != nil || err != nil {
+		t.Fatalf("newFailureDumper(\"\") = %v, %v, want nil, nil", dumper, err)
+	}
+
+	if dir, err := dumper.dump("suite", "test.yaml", &evaluator.TestResult{}); dir != "" || err != nil {
+		t.Errorf("nil dumper dump() = %q, %v, want nothing", dir, err)
+	}
+}
+
+func TestFailureDumper_Dump(t *testing.T) {
+	t.Parallel()
+
+	dumper, err := newFailureDumper(filepath.Join(t.TempDir(), "dump"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := func(value string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "web", "labels": map[string]any{"env": value}},
+		}}
+	}
+
+	result := &evaluator.TestResult{
+		Expected: evaluator.TestExpectation{Object: labels("prod")},
+		Actual: evaluator.TestOutcome{
+			Object: labels("dev"),
+			Patch:  []evaluator.PatchOperation{{Op: "add", Path: "/metadata/labels/env", Value: "dev"}},
+		},
+		Message: "mutated object mismatch",
+	}
+
+	dir, err := dumper.dump("mutating/add-labels", "add-labels.limits[max-5].yaml", result)
+	if err != nil {
+		t.Fatalf("dump() error = %v", err)
+	}
+
+	wantDir := filepath.Join(dumper.dir, "mutating", "add-labels", "add-labels.limits_max-5_")
+	if dir != wantDir {
+		t.Errorf("dump() dir = %q, want %q", dir, wantDir)
+	}
+
+	want := map[string]string{
+		"expected.yaml": "env: prod",
+		"actual.yaml":   "env: dev",
+		"patch.yaml":    "path: /metadata/labels/env",
+		"message.txt":   "mutated object mismatch\n",
+	}
+
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("read %s: %v", name, err)
+
+			continue
+		}
+
+		if !strings.Contains(string(data), content) {
+			t.Errorf("%s = %q, want it to contain %q", name, data, content)
+		}
+	}
+}
+
+func TestSanitizePathElement(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"policy.case.allow":  "policy.case.allow",
+		"policy.limits[max]": "policy.limits_max_",
+		"a b/c":              "a_b_c",
+		"":                   "_",
+		".":                  "_.",
+		"..":                 "_..",
+	}
+
+	for name, want := range tests {
+		if got := sanitizePathElement(name); got != want {
+			t.Errorf("sanitizePathElement(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRun_DumpFailures(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "dump")
+	args := []string{"kat", "-dump-failures", dir, "test-policies-fail/add-default-labels"}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	if err := run(t.Context(), args, nil, nil, devNull, devNull); exitCode(err) != exitTestsFailed {
+		t.Fatalf("run() error = %v, want failing tests", err)
+	}
+
+	messages, err := filepath.Glob(filepath.Join(dir, "add-default-labels", "*", "message.txt"))
+	if err != nil || len(messages) == 0 {
+		t.Fatalf("no message.txt dumped below %s (err %v)", dir, err)
+	}
+
+	for _, message := range messages {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(message), "actual.yaml")); err != nil {
+			t.Errorf("actual.yaml missing next to %s: %v", message, err)
+		}
+	}
+}
+
+func mustWriteFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustRemove(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+)
+
+var errExtraVarSyntax = errors.New("-extra-var must be name=<cel-expression-or-file>")
+
+// extraVarFlags collects repeated -extra-var flags.
+type extraVarFlags []string
+
+func (f *extraVarFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *extraVarFlags) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+// parseExtraVars turns name=value flags into extra CEL variables. A value
+// naming an existing file is bound to the file's YAML or JSON content; any
+// other value is a CEL expression evaluated for every request.
+func parseExtraVars(values []string) ([]evaluator.ExtraVariable, error) {
+	vars := make([]evaluator.ExtraVariable, 0, len(values))
+
+	for _, value := range values {
+		name, source, ok := strings.Cut(value, "=")
+		if !ok || name == "" || source == "" {
+			return nil, fmt.Errorf("%w, got %q", errExtraVarSyntax, value)
+		}
+
+		info, err := os.Stat(source)
+		if err != nil || info.IsDir() {
+			vars = append(vars, evaluator.ExtraVariable{Name: name, Expression: source})
+
+			continue
+		}
+
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("extra variable %s: %w", name, err)
+		}
+
+		var content any
+		if err := yaml.Unmarshal(data, &content); err != nil {
+			return nil, fmt.Errorf("extra variable %s: parse %s: %w", name, source, err)
+		}
+
+		vars = append(vars, evaluator.ExtraVariable{Name: name, Value: content})
+	}
+
+	return vars, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/zemanlx/kat/internal/gen"
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+// runGen implements "kat gen [suite-dirs...]": it scaffolds skeleton tests for
+// every validation of the validating policies in each suite directory.
+func runGen(args []string, stdout *os.File) error {
+	fs := flag.NewFlagSet(args[0]+" gen", flag.ContinueOnError)
+
+	if err := parseFlagSet(fs, args[2:], stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: parse flags: %w", errUsage, err)
+	}
+
+	dirs := []string{"."}
+	if fs.NArg() > 0 {
+		dirs = fs.Args()
+	}
+
+	for _, dir := range dirs {
+		policySet, err := loader.LoadPolicySet(dir)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errLoad, err)
+		}
+
+		files, err := gen.Generate(policySet.ValidatingPolicies)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", errUsage, dir, err)
+		}
+
+		written, err := gen.Write(dir, files)
+		for _, path := range written {
+			fmt.Fprintln(stdout, path)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+	}
+
+	return nil
+}
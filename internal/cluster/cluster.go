@@ -0,0 +1,180 @@
+// Package cluster submits test objects to a real cluster as server-side
+// dry-run requests and captures the admission response.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+)
+
+const defaultNamespace = "default"
+
+var (
+	errNoObject             = errors.New("request has no object to submit")
+	errUnsupportedOperation = errors.New("operation cannot be recorded")
+)
+
+// Client submits admission requests to a cluster.
+type Client struct {
+	dynamic  dynamic.Interface
+	mapper   meta.RESTMapper
+	warnings *warningRecorder
+}
+
+// NewClientFromKubeconfig creates a Client from a kubeconfig file and context.
+// An empty path uses the standard loading rules ($KUBECONFIG, then
+// ~/.kube/config) and an empty context uses the current context.
+func NewClientFromKubeconfig(kubeconfig, kubeContext string) (*Client, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+
+	return NewClient(config)
+}
+
+// NewClient creates a Client for the given REST config.
+func NewClient(config *rest.Config) (*Client, error) {
+	config = rest.CopyConfig(config)
+	warnings := &warningRecorder{}
+	config.WarningHandler = warnings
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("create discovery client: %w", err)
+	}
+
+	return &Client{
+		dynamic:  dynamicClient,
+		mapper:   restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		warnings: warnings,
+	}, nil
+}
+
+// Admit submits the object of a test as a dry-run request for the given
+// operation and returns the cluster's admission response. Denials by
+// admission are returned as a response; other API errors are returned as errors.
+func (c *Client) Admit(ctx context.Context, operation admissionv1.Operation, object, oldObject *unstructured.Unstructured) (*evaluator.RecordedResponse, error) {
+	target := object
+	if operation == admissionv1.Delete {
+		target = oldObject
+	}
+
+	if target == nil {
+		return nil, fmt.Errorf("%s: %w", operation, errNoObject)
+	}
+
+	resource, err := c.resourceFor(target.GroupVersionKind(), target.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	c.warnings.reset()
+
+	dryRun := []string{metav1.DryRunAll}
+
+	switch operation {
+	case admissionv1.Create:
+		_, err = resource.Create(ctx, target, metav1.CreateOptions{DryRun: dryRun})
+	case admissionv1.Update:
+		_, err = resource.Update(ctx, target, metav1.UpdateOptions{DryRun: dryRun})
+	case admissionv1.Delete:
+		err = resource.Delete(ctx, target.GetName(), metav1.DeleteOptions{DryRun: dryRun})
+	case admissionv1.Connect:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedOperation, operation)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedOperation, operation)
+	}
+
+	response := &evaluator.RecordedResponse{Allowed: true, Warnings: c.warnings.get()}
+
+	if err != nil {
+		// Admission denials surface as Forbidden (validating policies) or
+		// Invalid (e.g. a mutation producing an invalid object).
+		if !apierrors.IsForbidden(err) && !apierrors.IsInvalid(err) {
+			return nil, fmt.Errorf("dry-run %s %s: %w", operation, target.GetName(), err)
+		}
+
+		response.Allowed = false
+		response.Message = err.Error()
+	}
+
+	return response, nil
+}
+
+func (c *Client) resourceFor(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("find resource for %s: %w", gvk, err)
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return c.dynamic.Resource(mapping.Resource), nil
+	}
+
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	return c.dynamic.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+// warningRecorder collects the warning headers of the current request.
+type warningRecorder struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+// HandleWarningHeader implements rest.WarningHandler.
+func (w *warningRecorder) HandleWarningHeader(code int, _ string, text string) {
+	// 299 is the only warning code used by the API server.
+	if code != 299 || text == "" { //nolint:mnd // HTTP warning code
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.warnings = append(w.warnings, text)
+}
+
+func (w *warningRecorder) reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.warnings = nil
+}
+
+func (w *warningRecorder) get() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.warnings...)
+}
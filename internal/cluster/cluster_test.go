@@ -0,0 +1,188 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+)
+
+const deniedMessage = `pods "denied" is forbidden: ValidatingAdmissionPolicy 'no-denied' with binding 'no-denied-binding' denied request: name is denied`
+
+// newFakeAPIServer serves discovery for core/v1 pods and configmaps and answers
+// dry-run pod requests based on the pod name: "denied" is forbidden, "warned"
+// is allowed with a warning, "broken" fails, and anything else is allowed.
+// ConfigMaps are served by newConfigMapHandlers.
+func newFakeAPIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	writeJSON := func(w http.ResponseWriter, code int, body string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_, _ = io.WriteString(w, body)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"kind":"APIVersions","versions":["v1"]}`)
+	})
+	mux.HandleFunc("GET /apis", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"kind":"APIGroupList","apiVersion":"v1","groups":[]}`)
+	})
+	mux.HandleFunc("GET /api/v1", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"kind":"APIResourceList","groupVersion":"v1","resources":[`+
+			`{"name":"pods","singularName":"pod","namespaced":true,"kind":"Pod","verbs":["create","update","delete"]},`+
+			`{"name":"configmaps","singularName":"configmap","namespaced":true,"kind":"ConfigMap","verbs":["get","list"]}]}`)
+	})
+	newConfigMapHandlers(mux, writeJSON)
+
+	respond := func(w http.ResponseWriter, name string) {
+		switch name {
+		case "denied":
+			writeJSON(w, http.StatusForbidden, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403,"message":"`+
+				strings.ReplaceAll(deniedMessage, `"`, `\"`)+`"}`)
+		case "broken":
+			writeJSON(w, http.StatusInternalServerError, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"InternalError","code":500,"message":"boom"}`)
+		case "warned":
+			w.Header().Add("Warning", `299 - "Validation failed for ValidatingAdmissionPolicy 'warn' with binding 'warn-binding': missing label"`)
+
+			fallthrough
+		default:
+			writeJSON(w, http.StatusOK, `{"kind":"Pod","apiVersion":"v1","metadata":{"name":"`+name+`"}}`)
+		}
+	}
+
+	mux.HandleFunc("POST /api/v1/namespaces/default/pods", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dryRun") != "All" {
+			t.Errorf("create %s is not a dry run", r.URL)
+		}
+
+		var pod unstructured.Unstructured
+		if err := json.NewDecoder(r.Body).Decode(&pod.Object); err != nil {
+			t.Errorf("decode pod: %v", err)
+		}
+
+		respond(w, pod.GetName())
+	})
+	mux.HandleFunc("DELETE /api/v1/namespaces/default/pods/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var options metav1.DeleteOptions
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil || !slices.Equal(options.DryRun, []string{"All"}) {
+			t.Errorf("delete %s is not a dry run (err %v)", r.URL, err)
+		}
+
+		respond(w, r.PathValue("name"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newPod(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": name},
+	}}
+}
+
+func TestClient_Admit(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeAPIServer(t)
+
+	client, err := NewClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		object    *unstructured.Unstructured
+		oldObject *unstructured.Unstructured
+		want      *evaluator.RecordedResponse
+		wantErr   error
+	}{
+		{
+			name:      "allowed",
+			operation: admissionv1.Create,
+			object:    newPod("web"),
+			want:      &evaluator.RecordedResponse{Allowed: true},
+		},
+		{
+			name:      "denied",
+			operation: admissionv1.Create,
+			object:    newPod("denied"),
+			want:      &evaluator.RecordedResponse{Allowed: false, Message: deniedMessage},
+		},
+		{
+			name:      "warned",
+			operation: admissionv1.Create,
+			object:    newPod("warned"),
+			want: &evaluator.RecordedResponse{
+				Allowed:  true,
+				Warnings: []string{"Validation failed for ValidatingAdmissionPolicy 'warn' with binding 'warn-binding': missing label"},
+			},
+		},
+		{
+			name:      "delete submits old object",
+			operation: admissionv1.Delete,
+			oldObject: newPod("denied"),
+			want:      &evaluator.RecordedResponse{Allowed: false, Message: deniedMessage},
+		},
+		{
+			name:      "missing object",
+			operation: admissionv1.Create,
+			wantErr:   errNoObject,
+		},
+		{
+			name:      "connect is not supported",
+			operation: admissionv1.Connect,
+			object:    newPod("web"),
+			wantErr:   errUnsupportedOperation,
+		},
+	}
+
+	for _, tt := range tests {
+		// Subtests share the client's warning recorder and run sequentially.
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.Admit(t.Context(), tt.operation, tt.object, tt.oldObject)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Admit() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Admit() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_Admit_ServerError(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeAPIServer(t)
+
+	client, err := NewClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, err := client.Admit(t.Context(), admissionv1.Create, newPod("broken"), nil); err == nil {
+		t.Error("Admit() error = nil, want error for a server failure")
+	}
+}
@@ -0,0 +1,113 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ParamRef identifies policy params in a cluster, like a binding's paramRef.
+type ParamRef struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	// Name selects a single object; otherwise Selector lists matching objects.
+	Name     string
+	Selector *metav1.LabelSelector
+}
+
+// ParamResolver fetches policy params from a cluster and caches them, and
+// any error, for the rest of the run.
+type ParamResolver struct {
+	client *Client
+
+	mu    sync.Mutex
+	cache map[string]paramResult
+}
+
+type paramResult struct {
+	params []*unstructured.Unstructured
+	err    error
+}
+
+// NewParamResolver creates a ParamResolver using client.
+func NewParamResolver(client *Client) *ParamResolver {
+	return &ParamResolver{
+		client: client,
+		cache:  make(map[string]paramResult),
+	}
+}
+
+// Resolve returns the params referenced by ref, sorted by namespace and name.
+// A reference by name that does not exist resolves to no params.
+func (r *ParamResolver) Resolve(ctx context.Context, ref ParamRef) ([]*unstructured.Unstructured, error) {
+	key := fmt.Sprintf("%s|%s|%s|%s|%s", ref.APIVersion, ref.Kind, ref.Namespace, ref.Name, metav1.FormatLabelSelector(ref.Selector))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cached, ok := r.cache[key]; ok {
+		return cached.params, cached.err
+	}
+
+	params, err := r.fetch(ctx, ref)
+	r.cache[key] = paramResult{params: params, err: err}
+
+	return params, err
+}
+
+func (r *ParamResolver) fetch(ctx context.Context, ref ParamRef) ([]*unstructured.Unstructured, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("parse paramKind apiVersion: %w", err)
+	}
+
+	resource, err := r.client.resourceFor(gv.WithKind(ref.Kind), ref.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.Name != "" {
+		obj, err := resource.Get(ctx, ref.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("get %s %s: %w", ref.Kind, ref.Name, err)
+		}
+
+		return []*unstructured.Unstructured{obj}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("parse paramRef selector: %w", err)
+	}
+
+	list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", ref.Kind, err)
+	}
+
+	params := make([]*unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		params = append(params, &list.Items[i])
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].GetNamespace() != params[j].GetNamespace() {
+			return params[i].GetNamespace() < params[j].GetNamespace()
+		}
+
+		return params[i].GetName() < params[j].GetName()
+	})
+
+	return params, nil
+}
@@ -0,0 +1,139 @@
+package cluster
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// newConfigMapHandlers serves the "limits" ConfigMap in namespace "default" and
+// two "tier=gold" ConfigMaps in namespace "team"; namespace "broken" fails.
+func newConfigMapHandlers(mux *http.ServeMux, writeJSON func(http.ResponseWriter, int, string)) {
+	configMap := func(namespace, name string) string {
+		return `{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"` + name + `","namespace":"` + namespace + `"},"data":{"maxReplicas":"3"}}`
+	}
+
+	mux.HandleFunc("GET /api/v1/namespaces/{namespace}/configmaps/{name}", func(w http.ResponseWriter, r *http.Request) {
+		namespace, name := r.PathValue("namespace"), r.PathValue("name")
+
+		switch {
+		case namespace == "broken":
+			writeJSON(w, http.StatusInternalServerError, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"InternalError","code":500,"message":"boom"}`)
+		case namespace == "default" && name == "limits":
+			writeJSON(w, http.StatusOK, configMap(namespace, name))
+		default:
+			writeJSON(w, http.StatusNotFound, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404,"message":"not found"}`)
+		}
+	})
+	mux.HandleFunc("GET /api/v1/namespaces/{namespace}/configmaps", func(w http.ResponseWriter, r *http.Request) {
+		items := ""
+		if r.PathValue("namespace") == "team" && r.URL.Query().Get("labelSelector") == "tier=gold" {
+			// Served out of order to check sorting.
+			items = configMap("team", "gold-b") + "," + configMap("team", "gold-a")
+		}
+
+		writeJSON(w, http.StatusOK, `{"kind":"ConfigMapList","apiVersion":"v1","metadata":{},"items":[`+items+`]}`)
+	})
+}
+
+func TestParamResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeAPIServer(t)
+
+	client, err := NewClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	resolver := NewParamResolver(client)
+
+	tests := []struct {
+		name      string
+		ref       ParamRef
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "by name",
+			ref:       ParamRef{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "limits"},
+			wantNames: []string{"limits"},
+		},
+		{
+			name: "missing name",
+			ref:  ParamRef{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "missing"},
+		},
+		{
+			name: "by selector",
+			ref: ParamRef{
+				APIVersion: "v1", Kind: "ConfigMap", Namespace: "team",
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}},
+			},
+			wantNames: []string{"gold-a", "gold-b"},
+		},
+		{
+			name:    "server error",
+			ref:     ParamRef{APIVersion: "v1", Kind: "ConfigMap", Namespace: "broken", Name: "limits"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kind",
+			ref:     ParamRef{APIVersion: "example.com/v1", Kind: "Limits", Namespace: "default", Name: "limits"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := resolver.Resolve(t.Context(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var names []string
+			for _, obj := range got {
+				names = append(names, obj.GetName())
+			}
+
+			if diff := cmp.Diff(tt.wantNames, names); diff != "" {
+				t.Errorf("Resolve() names mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParamResolver_Resolve_Cached(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeAPIServer(t)
+
+	client, err := NewClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	resolver := NewParamResolver(client)
+	ref := ParamRef{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "limits"}
+
+	first, err := resolver.Resolve(t.Context(), ref)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// The cached result is returned even once the cluster is gone.
+	server.Close()
+
+	second, err := resolver.Resolve(t.Context(), ref)
+	if err != nil {
+		t.Fatalf("Resolve() after server close error = %v", err)
+	}
+
+	if len(second) != 1 || second[0] != first[0] {
+		t.Errorf("Resolve() = %v, want cached %v", second, first)
+	}
+}
@@ -0,0 +1,284 @@
+// Package docs renders Markdown documentation of policy test suites: the
+// policies with their match constraints and expressions, and the test cases
+// with their expected outcomes.
+package docs
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+// descriptionAnnotations are the policy annotations rendered as its
+// description, in order of preference.
+//
+//nolint:gochecknoglobals // Static list
+var descriptionAnnotations = []string{"kubernetes.io/description", "description"}
+
+//go:embed suite.md.tmpl
+var suiteTemplateText string
+
+//nolint:gochecknoglobals // Parsed once
+var suiteTemplate = template.Must(template.New("suite").Funcs(template.FuncMap{
+	"cell": cell,
+	"code": code,
+	"inc":  func(i int) int { return i + 1 },
+	"join": strings.Join,
+}).Parse(suiteTemplateText))
+
+// suiteDoc is the data rendered for a suite.
+type suiteDoc struct {
+	Name     string
+	Policies []policyDoc
+	Tests    []testDoc
+}
+
+type policyDoc struct {
+	Name         string
+	Kind         string
+	Description  string
+	ParamKind    string
+	Rules        []ruleDoc
+	ExcludeRules []ruleDoc
+	Conditions   []expressionDoc
+	Validations  []expressionDoc
+	Mutations    []expressionDoc
+}
+
+type ruleDoc struct {
+	Operations  []string
+	APIGroups   []string
+	APIVersions []string
+	Resources   []string
+	Scope       string
+}
+
+type expressionDoc struct {
+	Name              string
+	Expression        string
+	Message           string
+	MessageExpression string
+}
+
+type testDoc struct {
+	Name      string
+	Policy    string
+	Operation string
+	Expected  string
+	Message   string
+}
+
+// FileName returns the name of the Markdown file documenting suite.
+func FileName(suite *loader.TestSuite) string {
+	return suite.Name + ".md"
+}
+
+// Render writes the Markdown documentation of suite to w. Policies are ordered
+// by name and tests keep the loader's order, so the output is deterministic.
+func Render(w io.Writer, suite *loader.TestSuite) error {
+	doc := suiteDoc{Name: suite.Name}
+
+	for _, policy := range suite.ValidatingPolicies {
+		doc.Policies = append(doc.Policies, validatingPolicyDoc(policy))
+	}
+
+	for _, policy := range suite.MutatingPolicies {
+		doc.Policies = append(doc.Policies, mutatingPolicyDoc(policy))
+	}
+
+	sort.Slice(doc.Policies, func(i, j int) bool {
+		return doc.Policies[i].Name < doc.Policies[j].Name
+	})
+
+	for _, test := range suite.Tests {
+		doc.Tests = append(doc.Tests, newTestDoc(test))
+	}
+
+	if err := suiteTemplate.Execute(w, doc); err != nil {
+		return fmt.Errorf("render suite %s: %w", suite.Name, err)
+	}
+
+	return nil
+}
+
+func validatingPolicyDoc(policy *admissionregv1.ValidatingAdmissionPolicy) policyDoc {
+	doc := policyDoc{
+		Name:        policy.Name,
+		Kind:        "ValidatingAdmissionPolicy",
+		Description: description(policy.ObjectMeta),
+	}
+
+	if kind := policy.Spec.ParamKind; kind != nil {
+		doc.ParamKind = kind.APIVersion + " " + kind.Kind
+	}
+
+	if constraints := policy.Spec.MatchConstraints; constraints != nil {
+		doc.Rules = ruleDocs(constraints.ResourceRules)
+		doc.ExcludeRules = ruleDocs(constraints.ExcludeResourceRules)
+	}
+
+	for _, condition := range policy.Spec.MatchConditions {
+		doc.Conditions = append(doc.Conditions, expressionDoc{Name: condition.Name, Expression: condition.Expression})
+	}
+
+	for _, validation := range policy.Spec.Validations {
+		doc.Validations = append(doc.Validations, expressionDoc{
+			Expression:        validation.Expression,
+			Message:           validation.Message,
+			MessageExpression: validation.MessageExpression,
+		})
+	}
+
+	return doc
+}
+
+func mutatingPolicyDoc(policy *admissionv1beta1.MutatingAdmissionPolicy) policyDoc {
+	doc := policyDoc{
+		Name:        policy.Name,
+		Kind:        "MutatingAdmissionPolicy",
+		Description: description(policy.ObjectMeta),
+	}
+
+	if kind := policy.Spec.ParamKind; kind != nil {
+		doc.ParamKind = kind.APIVersion + " " + kind.Kind
+	}
+
+	if constraints := policy.Spec.MatchConstraints; constraints != nil {
+		doc.Rules = ruleDocsV1Beta1(constraints.ResourceRules)
+		doc.ExcludeRules = ruleDocsV1Beta1(constraints.ExcludeResourceRules)
+	}
+
+	for _, condition := range policy.Spec.MatchConditions {
+		doc.Conditions = append(doc.Conditions, expressionDoc{Name: condition.Name, Expression: condition.Expression})
+	}
+
+	for _, mutation := range policy.Spec.Mutations {
+		var expression string
+
+		switch {
+		case mutation.ApplyConfiguration != nil:
+			expression = mutation.ApplyConfiguration.Expression
+		case mutation.JSONPatch != nil:
+			expression = mutation.JSONPatch.Expression
+		}
+
+		doc.Mutations = append(doc.Mutations, expressionDoc{Name: string(mutation.PatchType), Expression: expression})
+	}
+
+	return doc
+}
+
+func description(meta metav1.ObjectMeta) string {
+	for _, key := range descriptionAnnotations {
+		if value := meta.Annotations[key]; value != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
+
+func ruleDocs(rules []admissionregv1.NamedRuleWithOperations) []ruleDoc {
+	docs := make([]ruleDoc, 0, len(rules))
+	for _, rule := range rules {
+		docs = append(docs, newRuleDoc(rule.RuleWithOperations))
+	}
+
+	return docs
+}
+
+func ruleDocsV1Beta1(rules []admissionv1beta1.NamedRuleWithOperations) []ruleDoc {
+	docs := make([]ruleDoc, 0, len(rules))
+	for _, rule := range rules {
+		docs = append(docs, newRuleDoc(rule.RuleWithOperations))
+	}
+
+	return docs
+}
+
+func newRuleDoc(rule admissionregv1.RuleWithOperations) ruleDoc {
+	doc := ruleDoc{
+		APIVersions: rule.APIVersions,
+		Resources:   rule.Resources,
+	}
+
+	for _, group := range rule.APIGroups {
+		if group == "" {
+			group = `""` // the core group
+		}
+
+		doc.APIGroups = append(doc.APIGroups, group)
+	}
+
+	for _, operation := range rule.Operations {
+		doc.Operations = append(doc.Operations, string(operation))
+	}
+
+	if rule.Scope != nil {
+		doc.Scope = string(*rule.Scope)
+	}
+
+	return doc
+}
+
+func newTestDoc(test *loader.TestCase) testDoc {
+	doc := testDoc{
+		Name:    test.Name,
+		Policy:  test.PolicyName,
+		Message: test.ExpectMessage,
+	}
+
+	if test.ChainPolicyName != "" {
+		doc.Policy += " + " + test.ChainPolicyName
+	}
+
+	if test.Request != nil {
+		doc.Operation = string(test.Request.Operation)
+	}
+
+	expected := []string{"deny"}
+	if test.ExpectAllowed {
+		expected = []string{"allow"}
+	}
+
+	if test.ExpectedObject != nil {
+		expected = append(expected, "mutate")
+	}
+
+	if len(test.ExpectWarnings) > 0 {
+		expected = append(expected, "warn")
+	}
+
+	if len(test.ExpectAuditAnnotations) > 0 {
+		expected = append(expected, "audit")
+	}
+
+	doc.Expected = strings.Join(expected, ", ")
+
+	return doc
+}
+
+// cell makes text safe for a Markdown table cell.
+func cell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// code formats an expression as inline code for a Markdown table cell.
+func code(expression string) string {
+	if expression == "" {
+		return ""
+	}
+
+	return "`" + cell(expression) + "`"
+}
@@ -0,0 +1,85 @@
+package docs
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+//nolint:gochecknoglobals // Test flag
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "validating with params", path: "../../test-policies-pass/validating/replica-limit-with-params"},
+		{name: "mutating", path: "../../test-policies-pass/mutating/sidecar-injection"},
+		{name: "binding rules", path: "../../test-policies-pass/validating/binding-resource-rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			suites, err := loader.Load(tt.path, loader.Options{})
+			if err != nil || len(suites) != 1 {
+				t.Fatalf("Load(%s) = %d suites, error %v", tt.path, len(suites), err)
+			}
+
+			var buf bytes.Buffer
+			if err := Render(&buf, suites[0]); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			goldenPath := filepath.Join("testdata", FileName(suites[0]))
+
+			if *update {
+				if err := os.WriteFile(goldenPath, buf.Bytes(), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("read golden file %s: %v", goldenPath, err)
+			}
+
+			if diff := cmp.Diff(string(want), buf.String()); diff != "" {
+				t.Errorf("Render() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCell(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "plain", want: "plain"},
+		{text: "a || b", want: `a \|\| b`},
+		{text: "multi\n  line\ttext", want: "multi line text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cell(tt.text); got != tt.want {
+				t.Errorf("cell(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
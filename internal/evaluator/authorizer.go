@@ -2,7 +2,9 @@ package evaluator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/google/cel-go/common/types/ref"
 	"k8s.io/apiserver/pkg/authentication/user"
@@ -20,9 +22,55 @@ func NewAuthorizerValue(auth authorizer.Authorizer, userInfo user.Info) ref.Val
 	return library.NewAuthorizerVal(userInfo, auth)
 }
 
+// mockReason is the reason of mocked decisions without a configured one.
+const mockReason = "mock decision"
+
 // MockAuthorizer is a simple mock authorizer for testing.
 type MockAuthorizer struct {
-	decisions map[string]authorizer.Decision
+	decisions map[string][]mockDecision
+}
+
+// mockDecision is a decision limited to the users matching username and
+// groups, where empty matchers match anyone, with the reason and error the
+// authorizer returns with it.
+type mockDecision struct {
+	username string
+	groups   []string
+	decision authorizer.Decision
+	reason   string
+	err      error
+}
+
+// matches reports whether u is the user or in one of the groups of d.
+func (d mockDecision) matches(u user.Info) bool {
+	if d.username != "" && (u == nil || u.GetName() != d.username) {
+		return false
+	}
+
+	if len(d.groups) == 0 {
+		return true
+	}
+
+	if u == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(d.groups, func(g string) bool {
+		return slices.Contains(u.GetGroups(), g)
+	})
+}
+
+// specificity ranks decisions for a user over those for groups over those for
+// anyone.
+func (d mockDecision) specificity() int {
+	switch {
+	case d.username != "":
+		return 2
+	case len(d.groups) > 0:
+		return 1
+	default:
+		return 0
+	}
 }
 
 // AuthorizationMockConfig represents a mocked authorization decision configuration.
@@ -33,12 +81,23 @@ type AuthorizationMockConfig struct {
 	Namespace   string `json:"namespace,omitempty"`
 	Verb        string `json:"verb"`
 	Decision    string `json:"decision"` // "allow" or "deny"
+	// Reason is the reason of the decision, as returned by reason(); it
+	// defaults to "mock decision".
+	Reason string `json:"reason,omitempty"`
+	// Error makes the authorizer fail with this message, as a timed-out
+	// webhook does, so errored() is true and error() returns it. Without a
+	// Decision the authorizer has no opinion.
+	Error string `json:"error,omitempty"`
+	// Username and Groups limit the decision to the requesting user or to
+	// users in any of the groups.
+	Username string   `json:"username,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
 }
 
 // NewMockAuthorizer creates a new mock authorizer.
 func NewMockAuthorizer() *MockAuthorizer {
 	return &MockAuthorizer{
-		decisions: make(map[string]authorizer.Decision),
+		decisions: make(map[string][]mockDecision),
 	}
 }
 
@@ -56,38 +115,90 @@ func NewMockAuthorizerFromConfig(configs []AuthorizationMockConfig) *MockAuthori
 func (m *MockAuthorizer) Add(c AuthorizationMockConfig) {
 	key := fmt.Sprintf("%s/%s/%s/%s/%s", c.Group, c.Resource, c.Subresource, c.Namespace, c.Verb)
 
-	if c.Decision == "allow" {
-		m.decisions[key] = authorizer.DecisionAllow
-	} else {
-		m.decisions[key] = authorizer.DecisionDeny
+	decision := authorizer.DecisionDeny
+
+	switch {
+	case c.Decision == "allow":
+		decision = authorizer.DecisionAllow
+	case c.Decision == "" && c.Error != "":
+		decision = authorizer.DecisionNoOpinion
 	}
+
+	reason := c.Reason
+	if reason == "" {
+		reason = mockReason
+	}
+
+	var err error
+	if c.Error != "" {
+		err = errors.New(c.Error) //nolint:err113 // The mocked authorizer's error
+	}
+
+	m.decisions[key] = append(m.decisions[key], mockDecision{
+		username: c.Username,
+		groups:   c.Groups,
+		decision: decision,
+		reason:   reason,
+		err:      err,
+	})
 }
 
 // Allow configures the mock to allow a specific request.
 func (m *MockAuthorizer) Allow(group, resource, subresource, namespace, verb string) {
 	key := fmt.Sprintf("%s/%s/%s/%s/%s", group, resource, subresource, namespace, verb)
-	m.decisions[key] = authorizer.DecisionAllow
+	m.decisions[key] = append(m.decisions[key], mockDecision{decision: authorizer.DecisionAllow, reason: mockReason})
 }
 
 // Deny configures the mock to deny a specific request.
 func (m *MockAuthorizer) Deny(group, resource, subresource, namespace, verb string) {
 	key := fmt.Sprintf("%s/%s/%s/%s/%s", group, resource, subresource, namespace, verb)
-	m.decisions[key] = authorizer.DecisionDeny
+	m.decisions[key] = append(m.decisions[key], mockDecision{decision: authorizer.DecisionDeny, reason: mockReason})
 }
 
-// Authorize implements the authorizer.Authorizer interface.
+// Authorize implements the authorizer.Authorizer interface. A decision with an
+// empty namespace applies to any namespace and one with an empty group to any
+// group; an exact match takes precedence, then a match on the group, then on
+// the namespace. Decisions limited to other users are skipped; among the
+// rest, one for the user wins over one for their groups over one for anyone,
+// and a later decision over an earlier one. The decision is returned with its
+// configured reason and error.
 func (m *MockAuthorizer) Authorize(_ context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
-	// Try specific match
-	key := fmt.Sprintf("%s/%s/%s/%s/%s", attrs.GetAPIGroup(), attrs.GetResource(), attrs.GetSubresource(), attrs.GetNamespace(), attrs.GetVerb())
-	if decision, ok := m.decisions[key]; ok {
-		return decision, "mock decision", nil
+	group, namespace := attrs.GetAPIGroup(), attrs.GetNamespace()
+
+	candidates := [][2]string{
+		{group, namespace},
+		{group, ""},
+		{"", namespace},
+		{"", ""},
 	}
 
-	// Try match allowing empty namespace in config to mean all namespaces (optional enhancement, but keeping simple for now)
+	for _, c := range candidates {
+		key := fmt.Sprintf("%s/%s/%s/%s/%s", c[0], attrs.GetResource(), attrs.GetSubresource(), c[1], attrs.GetVerb())
+		if d, ok := bestDecision(m.decisions[key], attrs.GetUser()); ok {
+			return d.decision, d.reason, d.err
+		}
+	}
 
 	return authorizer.DecisionNoOpinion, "no opinion", nil
 }
 
+// bestDecision returns the most specific of decisions matching u.
+func bestDecision(decisions []mockDecision, u user.Info) (mockDecision, bool) {
+	best := -1
+
+	for i, d := range decisions {
+		if d.matches(u) && (best < 0 || d.specificity() >= decisions[best].specificity()) {
+			best = i
+		}
+	}
+
+	if best < 0 {
+		return mockDecision{}, false
+	}
+
+	return decisions[best], true
+}
+
 // MockUserInfo creates a simple user.Info for testing.
 func MockUserInfo(username string, groups []string) user.Info {
 	return &user.DefaultInfo{
@@ -0,0 +1,120 @@
+package evaluator
+
+import (
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+func TestMockAuthorizer_Wildcards(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		configs []AuthorizationMockConfig
+		attrs   authorizer.AttributesRecord
+		want    authorizer.Decision
+	}{
+		{
+			name:    "cluster-wide allow matches a namespaced check",
+			configs: []AuthorizationMockConfig{{Resource: "pods", Verb: "create", Decision: "allow"}},
+			attrs:   authorizer.AttributesRecord{Resource: "pods", Namespace: "team-a", Verb: "create"},
+			want:    authorizer.DecisionAllow,
+		},
+		{
+			name:    "exact namespace overrides the cluster-wide decision",
+			configs: []AuthorizationMockConfig{{Resource: "pods", Verb: "create", Decision: "allow"}, {Resource: "pods", Namespace: "kube-system", Verb: "create", Decision: "deny"}},
+			attrs:   authorizer.AttributesRecord{Resource: "pods", Namespace: "kube-system", Verb: "create"},
+			want:    authorizer.DecisionDeny,
+		},
+		{
+			name:    "empty group matches any group",
+			configs: []AuthorizationMockConfig{{Resource: "deployments", Namespace: "team-a", Verb: "update", Decision: "allow"}},
+			attrs:   authorizer.AttributesRecord{APIGroup: "apps", Resource: "deployments", Namespace: "team-a", Verb: "update"},
+			want:    authorizer.DecisionAllow,
+		},
+		{
+			name: "group match takes precedence over namespace match",
+			configs: []AuthorizationMockConfig{
+				{Resource: "deployments", Namespace: "team-a", Verb: "update", Decision: "allow"},
+				{Group: "apps", Resource: "deployments", Verb: "update", Decision: "deny"},
+			},
+			attrs: authorizer.AttributesRecord{APIGroup: "apps", Resource: "deployments", Namespace: "team-a", Verb: "update"},
+			want:  authorizer.DecisionDeny,
+		},
+		{
+			name:    "empty group and namespace match anything",
+			configs: []AuthorizationMockConfig{{Resource: "secrets", Verb: "get", Decision: "deny"}},
+			attrs:   authorizer.AttributesRecord{APIGroup: "example.com", Resource: "secrets", Namespace: "team-a", Verb: "get"},
+			want:    authorizer.DecisionDeny,
+		},
+		{
+			name:    "namespaced decision does not match other namespaces",
+			configs: []AuthorizationMockConfig{{Resource: "pods", Namespace: "team-a", Verb: "create", Decision: "allow"}},
+			attrs:   authorizer.AttributesRecord{Resource: "pods", Namespace: "team-b", Verb: "create"},
+			want:    authorizer.DecisionNoOpinion,
+		},
+		{
+			name:    "other verb",
+			configs: []AuthorizationMockConfig{{Resource: "pods", Verb: "create", Decision: "allow"}},
+			attrs:   authorizer.AttributesRecord{Resource: "pods", Namespace: "team-a", Verb: "delete"},
+			want:    authorizer.DecisionNoOpinion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _, err := NewMockAuthorizerFromConfig(tt.configs).Authorize(t.Context(), tt.attrs)
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMockAuthorizer_Users(t *testing.T) {
+	t.Parallel()
+
+	configs := []AuthorizationMockConfig{
+		{Resource: "secrets", Verb: "get", Decision: "deny"},
+		{Resource: "secrets", Verb: "get", Decision: "allow", Username: "admin"},
+		{Resource: "secrets", Verb: "get", Decision: "allow", Groups: []string{"system:masters", "ops"}},
+		{Resource: "secrets", Verb: "get", Decision: "deny", Username: "dev", Groups: []string{"ops"}},
+	}
+
+	tests := []struct {
+		name string
+		user user.Info
+		want authorizer.Decision
+	}{
+		{name: "user", user: MockUserInfo("admin", nil), want: authorizer.DecisionAllow},
+		{name: "other user", user: MockUserInfo("dev", nil), want: authorizer.DecisionDeny},
+		{name: "group", user: MockUserInfo("alice", []string{"ops"}), want: authorizer.DecisionAllow},
+		{name: "user over group", user: MockUserInfo("dev", []string{"ops"}), want: authorizer.DecisionDeny},
+		{name: "no user", want: authorizer.DecisionDeny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			attrs := authorizer.AttributesRecord{User: tt.user, Resource: "secrets", Namespace: "team-a", Verb: "get"}
+
+			got, _, err := NewMockAuthorizerFromConfig(configs).Authorize(t.Context(), attrs)
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package evaluator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/apiserver/pkg/cel/library"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+)
+
+// celLibrary is a CEL library of the evaluator environment together with the
+// Kubernetes versions whose API server provides it. The environment itself is
+// built from the API server's base environment (see baseEnv); this table only
+// names its libraries for -list-libraries, and TestCELLibraries checks that
+// it stays in sync with the base environment.
+type celLibrary struct {
+	name   string
+	option func() cel.EnvOption
+	// introduced is the first minor version providing the library. Nil means
+	// no API server provides it and it is only part of the latest environment.
+	introduced *version.Version
+	// removed is the first minor version no longer providing the library.
+	// Libraries with a removed version are not part of the latest environment.
+	removed *version.Version
+	// enabled, if set, makes the library available at any version while it
+	// returns true, like the feature gates of the base environment.
+	enabled func() bool
+	// extension marks libraries kat adds to the base environment.
+	extension bool
+}
+
+// celLibraries lists the libraries of the base environment of
+// k8s.io/apiserver/pkg/cel/environment, followed by those kat adds.
+//
+//nolint:gochecknoglobals // Static library table
+var celLibraries = []celLibrary{
+	{name: "URLs", option: library.URLs, introduced: version.MajorMinor(1, 0)},
+	{name: "Regex", option: library.Regex, introduced: version.MajorMinor(1, 0)},
+	{name: "Lists", option: library.Lists, introduced: version.MajorMinor(1, 0)},
+	{name: "Authz", option: library.Authz, introduced: version.MajorMinor(1, 27)},
+	{name: "Quantity", option: library.Quantity, introduced: version.MajorMinor(1, 28)}, // Kubernetes quantity parsing (e.g., "100Mi", "2Gi")
+	// Ordering across int, uint and double, e.g. 1 < 2.0 or object.spec.replicas <= params.max.
+	{name: "CrossTypeNumericComparisons", option: func() cel.EnvOption { return cel.CrossTypeNumericComparisons(true) }, introduced: version.MajorMinor(1, 28)},
+	{name: "OptionalTypes", option: func() cel.EnvOption { return cel.OptionalTypes() }, introduced: version.MajorMinor(1, 28)}, // object.?spec, optional.of()
+	{
+		name:       "ext.Strings",
+		option:     func() cel.EnvOption { return ext.Strings(ext.StringsVersion(0)) },
+		introduced: version.MajorMinor(1, 0),
+		removed:    version.MajorMinor(1, 29),
+	},
+	{name: "ext.Strings", option: func() cel.EnvOption { return ext.Strings(ext.StringsVersion(2)) }, introduced: version.MajorMinor(1, 29)}, // split(), replace(), substring(), trim(), etc.
+	{name: "ext.Sets", option: func() cel.EnvOption { return ext.Sets() }, introduced: version.MajorMinor(1, 29)},                            // Set operations (sets.contains, etc.)
+	{name: "IP", option: library.IP, introduced: version.MajorMinor(1, 30)},                                                                  // IP address operations
+	{name: "CIDR", option: library.CIDR, introduced: version.MajorMinor(1, 30)},                                                              // CIDR parsing and operations
+	{name: "Format", option: library.Format, introduced: version.MajorMinor(1, 31)},                                                          // String formatting
+	{
+		name:       "AuthzSelectors",
+		option:     library.AuthzSelectors,
+		introduced: version.MajorMinor(1, 31),
+		enabled:    func() bool { return utilfeature.DefaultFeatureGate.Enabled(genericfeatures.AuthorizeWithSelectors) },
+	},
+	{name: "TwoVarComprehensions", option: func() cel.EnvOption { return ext.TwoVarComprehensions() }, introduced: version.MajorMinor(1, 32)},    // all(k, v, ...), transformMap
+	{name: "Semver", option: func() cel.EnvOption { return library.SemverLib(library.SemverVersion(1)) }, introduced: version.MajorMinor(1, 33)}, // Semantic version comparison
+	{name: "ext.Lists", option: func() cel.EnvOption { return ext.Lists(ext.ListsVersion(3)) }, introduced: version.MajorMinor(1, 34)},           // Additional list operations
+	// The API server adds JSONPatch to the environment of mutating policies
+	// only; kat compiles all expressions in one environment, and evaluates
+	// mutating policies whatever the version.
+	{name: "JSONPatch", option: library.JSONPatch, introduced: version.MajorMinor(1, 32), extension: true},
+	// CEL standard extensions not provided by the API server.
+	{name: "ext.Math", option: func() cel.EnvOption { return ext.Math() }, extension: true},         // Math operations (min, max, etc.)
+	{name: "ext.Encoders", option: func() cel.EnvOption { return ext.Encoders() }, extension: true}, // Base64 encoding/decoding
+}
+
+// baseEnv returns the base CEL environment of the API server at kubeVersion,
+// for new expressions as the API server compiles them on create, or the
+// environment of stored expressions, holding every library of the current
+// version, when kubeVersion is nil.
+func baseEnv(kubeVersion *version.Version) *cel.Env {
+	if kubeVersion == nil {
+		return environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion()).StoredExpressionsEnv()
+	}
+
+	return environment.MustBaseEnvSet(kubeVersion).NewExpressionsEnv()
+}
+
+// celExtensionOptions returns the environment options of the libraries kat
+// adds to the base environment at kubeVersion, or of all of them when
+// kubeVersion is nil.
+func celExtensionOptions(kubeVersion *version.Version) []cel.EnvOption {
+	var opts []cel.EnvOption
+
+	for _, lib := range celLibraries {
+		if lib.extension && lib.inEnvAt(kubeVersion) {
+			opts = append(opts, lib.option())
+		}
+	}
+
+	return opts
+}
+
+// inEnvAt reports whether the evaluator environment at kubeVersion holds the
+// library.
+func (l celLibrary) inEnvAt(kubeVersion *version.Version) bool {
+	return l.availableAt(kubeVersion) || (l.extension && l.name == "JSONPatch")
+}
+
+func (l celLibrary) availableAt(kubeVersion *version.Version) bool {
+	if kubeVersion == nil {
+		return l.removed == nil
+	}
+
+	if l.enabled != nil && l.enabled() {
+		return true
+	}
+
+	if l.introduced == nil || kubeVersion.LessThan(l.introduced) {
+		return false
+	}
+
+	return l.removed == nil || kubeVersion.LessThan(l.removed)
+}
+
+// Library describes a CEL library of the evaluator environment.
+type Library struct {
+	Name string
+	// Introduced is the first Kubernetes minor version providing the library,
+	// e.g. "1.30"; empty for libraries no API server provides.
+	Introduced string
+	// Functions are the functions the library adds to the CEL standard
+	// library, sorted. Libraries that only change the semantics of existing
+	// functions, such as CrossTypeNumericComparisons, have none.
+	Functions []string
+}
+
+// Libraries returns the CEL libraries available at kubeVersion, or the current
+// libraries when kubeVersion is nil: those of the API server's base
+// environment in the order it registers them, followed by those kat adds.
+func Libraries(kubeVersion *version.Version) ([]Library, error) {
+	base, err := cel.NewEnv()
+	if err != nil {
+		return nil, fmt.Errorf("create CEL environment: %w", err)
+	}
+
+	baseFunctions := base.Functions()
+
+	var libraries []Library
+
+	for _, lib := range celLibraries {
+		if !lib.inEnvAt(kubeVersion) {
+			continue
+		}
+
+		env, err := cel.NewEnv(lib.option())
+		if err != nil {
+			return nil, fmt.Errorf("create CEL environment with %s: %w", lib.name, err)
+		}
+
+		info := Library{Name: lib.name}
+		if lib.introduced != nil {
+			info.Introduced = fmt.Sprintf("%d.%d", lib.introduced.Major(), lib.introduced.Minor())
+		}
+
+		for name, fn := range env.Functions() {
+			baseFn, ok := baseFunctions[name]
+			// Names containing "@" are internal helpers of macros.
+			if strings.Contains(name, "@") {
+				continue
+			}
+
+			if !ok || len(fn.OverloadDecls()) > len(baseFn.OverloadDecls()) {
+				info.Functions = append(info.Functions, name)
+			}
+		}
+
+		sort.Strings(info.Functions)
+		libraries = append(libraries, info)
+	}
+
+	return libraries, nil
+}
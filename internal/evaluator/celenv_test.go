@@ -0,0 +1,307 @@
+package evaluator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestNew_KubeVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		kubeVersion *version.Version
+		expression  string
+		wantCompile bool
+	}{
+		{name: "ip at 1.30", kubeVersion: version.MajorMinor(1, 30), expression: "ip('10.0.0.1').family() == 4", wantCompile: true},
+		{name: "ip at 1.29", kubeVersion: version.MajorMinor(1, 29), expression: "ip('10.0.0.1').family() == 4"},
+		{name: "quantity at 1.28", kubeVersion: version.MajorMinor(1, 28), expression: "isQuantity('100Mi')", wantCompile: true},
+		{name: "quantity at 1.27", kubeVersion: version.MajorMinor(1, 27), expression: "isQuantity('100Mi')"},
+		{name: "sets at 1.29", kubeVersion: version.MajorMinor(1, 29), expression: "sets.contains([1, 2], [1])", wantCompile: true},
+		{name: "sets at 1.28", kubeVersion: version.MajorMinor(1, 28), expression: "sets.contains([1, 2], [1])"},
+		{name: "semver at 1.33", kubeVersion: version.MajorMinor(1, 33), expression: "isSemver('1.2.3')", wantCompile: true},
+		{name: "semver at 1.32", kubeVersion: version.MajorMinor(1, 32), expression: "isSemver('1.2.3')"},
+		{name: "strings v0 at 1.28", kubeVersion: version.MajorMinor(1, 28), expression: "'a,b'.split(',').size() == 2", wantCompile: true},
+		{name: "strings quote at 1.28", kubeVersion: version.MajorMinor(1, 28), expression: "strings.quote('a') == '\"a\"'"},
+		{name: "strings quote at 1.29", kubeVersion: version.MajorMinor(1, 29), expression: "strings.quote('a') == '\"a\"'", wantCompile: true},
+		{name: "math is not provided by the API server", kubeVersion: version.MajorMinor(1, 35), expression: "math.greatest(1, 2) == 2"},
+		{name: "two-variable comprehensions at 1.32", kubeVersion: version.MajorMinor(1, 32), expression: "{'a': 1}.all(k, v, v > 0)", wantCompile: true},
+		{name: "two-variable comprehensions at 1.31", kubeVersion: version.MajorMinor(1, 31), expression: "{'a': 1}.all(k, v, v > 0)"},
+		{name: "lists v3 at 1.34", kubeVersion: version.MajorMinor(1, 34), expression: "[3, 1].sortBy(x, x) == [1, 3]", wantCompile: true},
+		{name: "jsonpatch at 1.28", kubeVersion: version.MajorMinor(1, 28), expression: "jsonpatch.escapeKey('a/b') == 'a~1b'", wantCompile: true},
+		{name: "heterogeneous list literal", expression: "[1, 'a'].size() == 2"},
+		{name: "heterogeneous map literal", expression: "{'a': 1, 'b': 'c'}.size() == 2"},
+		{name: "map literal of dyn values", expression: "{'a': dyn(1), 'b': dyn('c')}.size() == 2", wantCompile: true},
+		{name: "latest has math", expression: "math.greatest(1, 2) == 2", wantCompile: true},
+		{name: "latest has semver", expression: "isSemver('1.2.3')", wantCompile: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := New(WithKubeVersion(tt.kubeVersion))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			_, issues := e.env.Compile(tt.expression)
+			if gotCompile := issues.Err() == nil; gotCompile != tt.wantCompile {
+				t.Errorf("Compile(%q) error = %v, want compile %v", tt.expression, issues.Err(), tt.wantCompile)
+			}
+		})
+	}
+}
+
+func TestNew_Limits(t *testing.T) {
+	t.Parallel()
+
+	containers := make([]any, 0, 100)
+	for range 100 {
+		containers = append(containers, map[string]any{"ports": []any{int64(80), int64(443)}})
+	}
+
+	vars := map[string]any{"object": map[string]any{
+		"metadata": map[string]any{"name": strings.Repeat("a", 200*1024)},
+		"spec":     map[string]any{"containers": containers},
+	}}
+	nested := "object.spec.containers.all(c, c.ports.all(p, p > 0))"
+	// String functions cost in proportion to the length of their arguments, so
+	// 100 searches of 200 KiB cost over the API server's limit of 1000000.
+	expensive := "object.spec.containers.all(c, !object.metadata.name.contains('b'))"
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		expression string
+		wantErr    string
+	}{
+		{name: "no limits"},
+		{name: "nesting within limit", opts: []Option{WithComprehensionNestingLimit(2)}},
+		{name: "nesting over limit", opts: []Option{WithComprehensionNestingLimit(1)}, wantErr: "comprehension exceeds nesting limit"},
+		{name: "cost within limit", opts: []Option{WithCostLimit(1000000)}},
+		{name: "cost over limit", opts: []Option{WithCostLimit(100)}, wantErr: "cost limit exceeded"},
+		{name: "cost over the API server's limit", expression: expensive, wantErr: "cost limit exceeded"},
+		{name: "cost limit raised", opts: []Option{WithCostLimit(100 * DefaultCostLimit)}, expression: expensive},
+		{name: "cost limit disabled", opts: []Option{WithCostLimit(0)}, expression: expensive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := New(tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			expression := nested
+			if tt.expression != "" {
+				expression = tt.expression
+			}
+
+			_, err = e.evaluateExpression(expression, vars)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("evaluateExpression() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("evaluateExpression() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_CrossTypeNumericComparisons(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]any{
+		"object": map[string]any{"spec": map[string]any{
+			"replicas": int64(3),
+			"ratio":    0.5,
+			"limit":    uint64(10),
+		}},
+		"params": map[string]any{
+			"maxReplicas": 5.0,
+			"maxRatio":    int64(1),
+			"minLimit":    int64(2),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		kubeVersion *version.Version
+		expression  string
+		want        bool
+		wantErr     string
+	}{
+		{name: "int literal < double literal", expression: "1 < 2.0", want: true},
+		{name: "uint literal < int literal", expression: "1u < 2", want: true},
+		{name: "double literal > int literal", expression: "2.5 > 1", want: true},
+		{name: "double literal >= uint literal", expression: "1.0 >= 1u", want: true},
+		{name: "int object <= double params", expression: "object.spec.replicas <= params.maxReplicas", want: true},
+		{name: "double object < int params", expression: "object.spec.ratio < params.maxRatio", want: true},
+		{name: "uint object > int params", expression: "object.spec.limit > params.minLimit", want: true},
+		{name: "int object > double literal", expression: "object.spec.replicas > 2.5", want: true},
+		{name: "double params < uint literal", expression: "params.maxReplicas < 4u"},
+		{
+			name:        "literals at 1.28",
+			kubeVersion: version.MajorMinor(1, 28),
+			expression:  "1 < 2.0",
+			want:        true,
+		},
+		{
+			name:        "literals at 1.27",
+			kubeVersion: version.MajorMinor(1, 27),
+			expression:  "1 < 2.0",
+			wantErr:     "no matching overload",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := New(WithKubeVersion(tt.kubeVersion))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := e.evaluateExpression(tt.expression, vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("evaluateExpression(%q) error = %v, want %q", tt.expression, err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("evaluateExpression(%q) error = %v", tt.expression, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("evaluateExpression(%q) = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLibraries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		kubeVersion *version.Version
+		want        map[string]string // library name to a function it adds, or "" for none
+		absent      []string
+	}{
+		{
+			name: "latest",
+			want: map[string]string{"Quantity": "quantity", "CIDR": "cidr", "ext.Sets": "sets.contains", "ext.Math": "math.abs", "CrossTypeNumericComparisons": ""},
+		},
+		{
+			name:        "1.28",
+			kubeVersion: version.MajorMinor(1, 28),
+			want:        map[string]string{"Authz": "check", "ext.Strings": "split"},
+			absent:      []string{"IP", "Semver", "ext.Math"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			libraries, err := Libraries(tt.kubeVersion)
+			if err != nil {
+				t.Fatalf("Libraries() error = %v", err)
+			}
+
+			byName := make(map[string]Library, len(libraries))
+			for _, library := range libraries {
+				byName[library.Name] = library
+			}
+
+			for name, function := range tt.want {
+				library, ok := byName[name]
+				if !ok {
+					t.Errorf("Libraries() has no %s", name)
+
+					continue
+				}
+
+				if function == "" && len(library.Functions) > 0 {
+					t.Errorf("%s functions = %v, want none", name, library.Functions)
+				}
+
+				if function != "" && !slices.Contains(library.Functions, function) {
+					t.Errorf("%s functions = %v, want %s", name, library.Functions, function)
+				}
+			}
+
+			for _, name := range tt.absent {
+				if _, ok := byName[name]; ok {
+					t.Errorf("Libraries() has %s, want it absent", name)
+				}
+			}
+		})
+	}
+}
+
+// TestCELLibraries checks that celLibraries, which only names the libraries
+// for -list-libraries, adds exactly the functions of the evaluator
+// environment, which is built from the API server's base environment.
+func TestCELLibraries(t *testing.T) {
+	t.Parallel()
+
+	base, err := cel.NewEnv()
+	if err != nil {
+		t.Fatalf("cel.NewEnv() error = %v", err)
+	}
+
+	for _, kubeVersion := range []*version.Version{nil, version.MajorMinor(1, 27), version.MajorMinor(1, 28), version.MajorMinor(1, 29), version.MajorMinor(1, 30), version.MajorMinor(1, 31), version.MajorMinor(1, 32), version.MajorMinor(1, 33), version.MajorMinor(1, 34), version.MajorMinor(1, 35)} {
+		t.Run(fmt.Sprint(kubeVersion), func(t *testing.T) {
+			t.Parallel()
+
+			e, err := New(WithKubeVersion(kubeVersion))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var want []string
+
+			for name, fn := range e.env.Functions() {
+				baseFn, ok := base.Functions()[name]
+				if strings.Contains(name, "@") || ok && len(fn.OverloadDecls()) <= len(baseFn.OverloadDecls()) {
+					continue
+				}
+
+				want = append(want, name)
+			}
+
+			libraries, err := Libraries(kubeVersion)
+			if err != nil {
+				t.Fatalf("Libraries() error = %v", err)
+			}
+
+			var got []string
+			for _, library := range libraries {
+				got = append(got, library.Functions...)
+			}
+
+			slices.Sort(want)
+			slices.Sort(got)
+
+			if diff := cmp.Diff(want, slices.Compact(got)); diff != "" {
+				t.Errorf("functions of celLibraries mismatch the environment (-env +table):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package evaluator
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+)
+
+// WithDeterminismCheck evaluates every test twice and fails tests whose two
+// evaluations differ in their decision, message, warnings, audit annotations,
+// patch or resulting object. It surfaces outcomes that depend on the
+// iteration order of Go maps, in policies or in the evaluator itself.
+func WithDeterminismCheck() Option {
+	return func(e *Evaluator) {
+		e.checkDeterminism = true
+	}
+}
+
+// determinismFailure evaluates the test again and returns a failure message
+// when the outcome differs from first, or "" when it is the same.
+func (e *Evaluator) determinismFailure(
+	mutatingPolicy *admissionv1beta1.MutatingAdmissionPolicy,
+	mutatingBinding *admissionv1beta1.MutatingAdmissionPolicyBinding,
+	validatingPolicy *admissionregv1.ValidatingAdmissionPolicy,
+	validatingBinding *admissionregv1.ValidatingAdmissionPolicyBinding,
+	testCase TestCase,
+	first *EvaluationResult,
+) string {
+	second, err := e.evaluatePolicy(mutatingPolicy, mutatingBinding, validatingPolicy, validatingBinding, testCase)
+	if err != nil {
+		return fmt.Sprintf("evaluation is not deterministic: second evaluation error: %v", err)
+	}
+
+	if diff := outcomeDifference(first, second); diff != "" {
+		return "evaluation is not deterministic: " + diff
+	}
+
+	return ""
+}
+
+// outcomeDifference describes the first difference between the outcomes of
+// two evaluations of the same test, or returns "" when there is none.
+func outcomeDifference(first, second *EvaluationResult) string {
+	switch {
+	case first.Allowed != second.Allowed:
+		return fmt.Sprintf("allowed=%v, then allowed=%v", first.Allowed, second.Allowed)
+	case first.Message != second.Message:
+		return fmt.Sprintf("message %q, then %q", first.Message, second.Message)
+	case !reflect.DeepEqual(first.Warnings, second.Warnings):
+		return fmt.Sprintf("warnings %q, then %q", first.Warnings, second.Warnings)
+	case !reflect.DeepEqual(first.AuditAnnotations, second.AuditAnnotations):
+		return fmt.Sprintf("audit annotations %v, then %v", first.AuditAnnotations, second.AuditAnnotations)
+	case !reflect.DeepEqual(first.Patch, second.Patch):
+		return "patch differs:\n" + yamlDiff(first.Patch, second.Patch, "First", "Second")
+	}
+
+	var firstObject, secondObject map[string]any
+	if first.PatchedObject != nil {
+		firstObject = first.PatchedObject.Object
+	}
+
+	if second.PatchedObject != nil {
+		secondObject = second.PatchedObject.Object
+	}
+
+	if !reflect.DeepEqual(firstObject, secondObject) {
+		return "mutated object differs:\n" + yamlDiff(firstObject, secondObject, "First", "Second")
+	}
+
+	return ""
+}
+
+// yamlDiff returns the unified diff of the YAML of a and b.
+func yamlDiff(a, b any, fromFile, toFile string) string {
+	aYAML, err := yaml.Marshal(a)
+	if err != nil {
+		aYAML = []byte(fmt.Sprintf("%+v", a))
+	}
+
+	bYAML, err := yaml.Marshal(b)
+	if err != nil {
+		bYAML = []byte(fmt.Sprintf("%+v", b))
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(aYAML)),
+		B:        difflib.SplitLines(string(bYAML)),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  diffContextLines,
+	})
+
+	return diff
+}
@@ -0,0 +1,126 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestOutcomeDifference(t *testing.T) {
+	t.Parallel()
+
+	pod := func(label string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"kind":     "Pod",
+			"metadata": map[string]any{"labels": map[string]any{"first": label}},
+		}}
+	}
+
+	tests := []struct {
+		name   string
+		first  EvaluationResult
+		second EvaluationResult
+		want   string
+	}{
+		{
+			name:   "same outcome",
+			first:  EvaluationResult{Allowed: true, Warnings: []string{"w"}, PatchedObject: pod("a")},
+			second: EvaluationResult{Allowed: true, Warnings: []string{"w"}, PatchedObject: pod("a")},
+		},
+		{
+			name:   "decision",
+			first:  EvaluationResult{Allowed: true},
+			second: EvaluationResult{Allowed: false},
+			want:   "allowed=true, then allowed=false",
+		},
+		{
+			name:   "message",
+			first:  EvaluationResult{Message: "missing a, b"},
+			second: EvaluationResult{Message: "missing b, a"},
+			want:   `message "missing a, b", then "missing b, a"`,
+		},
+		{
+			name:   "warnings",
+			first:  EvaluationResult{Warnings: []string{"a", "b"}},
+			second: EvaluationResult{Warnings: []string{"b", "a"}},
+			want:   `warnings ["a" "b"], then ["b" "a"]`,
+		},
+		{
+			name:   "audit annotations",
+			first:  EvaluationResult{AuditAnnotations: map[string]string{"keys": "a,b"}},
+			second: EvaluationResult{AuditAnnotations: map[string]string{"keys": "b,a"}},
+			want:   "audit annotations map[keys:a,b], then map[keys:b,a]",
+		},
+		{
+			name:   "patch",
+			first:  EvaluationResult{Patch: []PatchOperation{{Op: "add", Path: "/a"}, {Op: "add", Path: "/b"}}},
+			second: EvaluationResult{Patch: []PatchOperation{{Op: "add", Path: "/b"}, {Op: "add", Path: "/a"}}},
+			want:   "patch differs:\n--- First\n+++ Second\n",
+		},
+		{
+			name:   "mutated object",
+			first:  EvaluationResult{PatchedObject: pod("a")},
+			second: EvaluationResult{PatchedObject: pod("b")},
+			want:   "mutated object differs:\n--- First\n+++ Second\n",
+		},
+		{
+			name:   "mutated only once",
+			first:  EvaluationResult{PatchedObject: pod("a")},
+			second: EvaluationResult{},
+			want:   "mutated object differs:\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := outcomeDifference(&tt.first, &tt.second)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("outcomeDifference() = %q, want none", got)
+				}
+
+				return
+			}
+
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("outcomeDifference() = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateTest_DeterminismCheck(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{{
+				Expression:        "object.metadata.labels.all(k, k.startsWith('app'))",
+				MessageExpression: "'unexpected labels: ' + object.metadata.labels.filter(k, !k.startsWith('app')).join(', ')",
+			}},
+		},
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "test-pod", "labels": map[string]any{"team": "a"}},
+	}}
+
+	e, err := New(WithDeterminismCheck())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	result := e.EvaluateTest(nil, nil, policy, nil, MockTestCase{
+		Object:        object,
+		ExpectMessage: "unexpected labels: team",
+	})
+
+	if !result.Passed {
+		t.Errorf("EvaluateTest() Passed = false, want true (message: %s)", result.Message)
+	}
+}
@@ -0,0 +1,17 @@
+package evaluator
+
+import (
+	"fmt"
+	"time"
+)
+
+// CheckDuration fails a passing result whose policy evaluation took longer
+// than limit, reporting the actual duration. A zero limit disables the check.
+func CheckDuration(result *TestResult, limit time.Duration) {
+	if limit <= 0 || !result.Passed || result.Duration <= limit {
+		return
+	}
+
+	result.Passed = false
+	result.Message = fmt.Sprintf("evaluation took %s, exceeding the maximum test duration of %s", result.Duration, limit)
+}
@@ -0,0 +1,59 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		result      TestResult
+		limit       time.Duration
+		wantPassed  bool
+		wantMessage string
+	}{
+		{
+			name:       "disabled",
+			result:     TestResult{Passed: true, Duration: time.Second},
+			wantPassed: true,
+		},
+		{
+			name:       "within limit",
+			result:     TestResult{Passed: true, Duration: 5 * time.Millisecond},
+			limit:      5 * time.Millisecond,
+			wantPassed: true,
+		},
+		{
+			name:        "exceeds limit",
+			result:      TestResult{Passed: true, Duration: 7 * time.Millisecond},
+			limit:       5 * time.Millisecond,
+			wantMessage: "evaluation took 7ms, exceeding the maximum test duration of 5ms",
+		},
+		{
+			name:        "already failed keeps its message",
+			result:      TestResult{Message: "expected deny, got allow", Duration: 7 * time.Millisecond},
+			limit:       5 * time.Millisecond,
+			wantMessage: "expected deny, got allow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := tt.result
+			CheckDuration(&result, tt.limit)
+
+			if result.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v", result.Passed, tt.wantPassed)
+			}
+
+			if result.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -4,16 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
 	"github.com/google/cel-go/common/types/traits"
-	"github.com/google/cel-go/ext"
 	"github.com/pmezard/go-difflib/difflib"
 	"google.golang.org/protobuf/types/known/structpb"
 	"gopkg.in/yaml.v3"
@@ -23,11 +27,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/version"
 	plugin "k8s.io/apiserver/pkg/admission/plugin/cel"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	celcommon "k8s.io/apiserver/pkg/cel/common"
-	"k8s.io/apiserver/pkg/cel/library"
 	"k8s.io/apiserver/pkg/cel/mutation"
 	"k8s.io/apiserver/pkg/cel/mutation/dynamic"
 	"k8s.io/utils/ptr"
@@ -43,18 +48,174 @@ var (
 	errUnexpectedPatchType      = errors.New("unexpected patch type")
 	errConversionNotSupported   = errors.New("conversion not supported")
 	errNoPolicy                 = errors.New("no policy provided")
+	errImmutableFieldChanged    = errors.New("mutation changed immutable field")
 )
 
 const diffContextLines = 3
 
+// DefaultCostLimit is the runtime cost limit of an expression evaluation, the
+// API server's per-call limit.
+const DefaultCostLimit = celconfig.PerCallLimit
+
 // Evaluator evaluates admission policies using CEL expressions.
 type Evaluator struct {
 	env *cel.Env
+
+	// timings collects per-expression evaluation durations when enabled.
+	timings   map[string]*ExpressionTiming
+	timingsMu sync.Mutex
+
+	// createMissingParents pre-creates missing intermediate maps for JSONPatch add operations.
+	createMissingParents bool
+
+	// assertNoUnexpectedWarnings fails tests that produce warnings without expecting any.
+	assertNoUnexpectedWarnings bool
+
+	// prefixAuditAnnotations records audit annotation keys as "<policy-name>/<key>".
+	prefixAuditAnnotations bool
+
+	// requireGold fails mutating tests without an expected object.
+	requireGold bool
+
+	// kubeVersion limits the CEL libraries to those of a Kubernetes minor
+	// version; nil enables all of them.
+	kubeVersion *version.Version
+
+	// comprehensionNestingLimit rejects expressions nesting more comprehensions
+	// (all, exists, map, filter, ...) than this at compile time; 0 disables it.
+	comprehensionNestingLimit int
+
+	// costLimit aborts evaluations whose runtime cost exceeds it; it defaults
+	// to the API server's per-call limit, and 0 disables it.
+	costLimit uint64
+
+	// extraVariables are declared and bound in addition to the standard variables.
+	extraVariables []ExtraVariable
+
+	// checkReinvocation fails mutations that change the object again when
+	// their policy is reinvoked.
+	checkReinvocation bool
+
+	// checkDeterminism evaluates tests twice and fails those whose outcomes
+	// differ.
+	checkDeterminism bool
+
+	// compareQuantities compares resource quantities of expected objects by value.
+	compareQuantities bool
+
+	// keepLongWarnings disables the API server's truncation of warnings.
+	keepLongWarnings bool
+
+	// deleteObjectFallback binds object to the deleted object on DELETE
+	// instead of null.
+	deleteObjectFallback bool
+
+	// trace prints the AST of compiled expressions, if set.
+	trace *celTrace
+}
+
+// Option configures an Evaluator.
+type Option func(*Evaluator)
+
+// WithTimings enables recording of per-expression evaluation durations.
+func WithTimings() Option {
+	return func(e *Evaluator) {
+		e.timings = make(map[string]*ExpressionTiming)
+	}
+}
+
+// WithCreateMissingParents makes JSONPatch "add" operations create missing
+// intermediate maps (e.g. metadata.labels) instead of failing. The API server
+// does not do this, so it is opt-in.
+func WithCreateMissingParents() Option {
+	return func(e *Evaluator) {
+		e.createMissingParents = true
+	}
+}
+
+// WithAssertNoUnexpectedWarnings makes tests without expected warnings fail
+// when the policy produces any warnings.
+func WithAssertNoUnexpectedWarnings() Option {
+	return func(e *Evaluator) {
+		e.assertNoUnexpectedWarnings = true
+	}
+}
+
+// WithoutWarningTruncation reports warnings as the policy produces them,
+// instead of truncating them as the API server does when a response's
+// warnings exceed MaxWarningsRunes.
+func WithoutWarningTruncation() Option {
+	return func(e *Evaluator) {
+		e.keepLongWarnings = true
+	}
+}
+
+// WithDeleteObjectFallback binds object to the deleted object, oldObject, in
+// validating policies on DELETE, as kat did before. The API server binds it
+// to null, so this only exists for suites written against the old behavior.
+func WithDeleteObjectFallback() Option {
+	return func(e *Evaluator) {
+		e.deleteObjectFallback = true
+	}
+}
+
+// WithPolicyNameAuditAnnotationKeys records audit annotation keys as
+// "<policy-name>/<key>", the key the API server writes to the audit log.
+func WithPolicyNameAuditAnnotationKeys() Option {
+	return func(e *Evaluator) {
+		e.prefixAuditAnnotations = true
+	}
+}
+
+// WithRequireGold makes mutating tests without an expected object (.gold.yaml)
+// fail instead of passing without checking the mutation.
+func WithRequireGold() Option {
+	return func(e *Evaluator) {
+		e.requireGold = true
+	}
+}
+
+// WithKubeVersion limits the CEL environment to the libraries available in the
+// API server of the given Kubernetes minor version, so expressions using newer
+// functions fail to compile.
+func WithKubeVersion(kubeVersion *version.Version) Option {
+	return func(e *Evaluator) {
+		e.kubeVersion = kubeVersion
+	}
+}
+
+// WithComprehensionNestingLimit rejects expressions that nest more than limit
+// comprehensions, such as object.spec.containers.all(c, c.ports.all(p, ...)).
+func WithComprehensionNestingLimit(limit int) Option {
+	return func(e *Evaluator) {
+		e.comprehensionNestingLimit = limit
+	}
+}
+
+// WithCostLimit aborts any expression evaluation whose runtime cost exceeds limit
+// instead of the API server's per-call limit, [DefaultCostLimit]. Iterations of
+// comprehensions are the main contributor, so a lower limit keeps a safety
+// margin for large objects. 0 disables the limit.
+func WithCostLimit(limit uint64) Option {
+	return func(e *Evaluator) {
+		e.costLimit = limit
+	}
+}
+
+// ExpressionTiming holds the accumulated evaluation time of a single CEL expression.
+type ExpressionTiming struct {
+	Expression string
+	Count      int
+	Total      time.Duration
 }
 
 // New creates a new Evaluator with a CEL environment configured for Kubernetes admission policies.
-func New() (*Evaluator, error) {
-	// Build environment options with all Kubernetes CEL libraries
+func New(opts ...Option) (*Evaluator, error) {
+	e := &Evaluator{costLimit: DefaultCostLimit}
+	for _, opt := range opts {
+		opt(e)
+	}
+
 	envOpts := []cel.EnvOption{
 		cel.Variable(plugin.ObjectVarName, cel.DynType),
 		cel.Variable(plugin.OldObjectVarName, cel.DynType),
@@ -62,34 +223,85 @@ func New() (*Evaluator, error) {
 		cel.Variable(plugin.ParamsVarName, cel.DynType),
 		cel.Variable(plugin.NamespaceVarName, cel.DynType),
 		cel.Variable(plugin.AuthorizerVarName, cel.DynType),
-		// Add all Kubernetes CEL function libraries
-		library.Authz(),
-		library.AuthzSelectors(),
-		library.CIDR(),   // CIDR parsing and operations
-		library.Format(), // String formatting
-		library.IP(),     // IP address operations
-		library.JSONPatch(),
-		library.Lists(),
-		library.Quantity(), // Kubernetes quantity parsing (e.g., "100Mi", "2Gi")
-		library.Regex(),
-		library.SemverLib(), // Semantic version comparison
-		library.URLs(),
-		// Add CEL standard extensions for maximum compatibility
-		ext.Strings(),  // split(), replace(), substring(), trim(), etc.
-		ext.Lists(),    // Additional list operations
-		ext.Math(),     // Math operations (min, max, etc.)
-		ext.Encoders(), // Base64 encoding/decoding
-		ext.Sets(),     // Set operations (sets.contains, etc.)
-		// Add type resolver for JSONPatch and Object types (for mutations)
-		celcommon.ResolverEnvOption(&mutation.DynamicTypeResolver{}),
-	}
-
-	env, err := cel.NewEnv(envOpts...)
+		cel.Variable(plugin.VariableVarName, cel.DynType),
+	}
+
+	extraOpts, err := extraVariableOptions(e.extraVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	envOpts = append(envOpts, extraOpts...)
+	// Add the libraries kat provides beyond the API server's base environment
+	envOpts = append(envOpts, celExtensionOptions(e.kubeVersion)...)
+	// Add type resolver for JSONPatch and Object types (for mutations)
+	envOpts = append(envOpts, celcommon.ResolverEnvOption(&mutation.DynamicTypeResolver{}))
+
+	if e.comprehensionNestingLimit > 0 {
+		envOpts = append(envOpts, cel.ASTValidators(cel.ValidateComprehensionNestingLimit(e.comprehensionNestingLimit)))
+	}
+
+	// Start from the base environment of the API server at the configured
+	// Kubernetes version, with its libraries, validators and cost options
+	env, err := baseEnv(e.kubeVersion).Extend(envOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("create CEL environment: %w", err)
 	}
 
-	return &Evaluator{env: env}, nil
+	e.env = env
+
+	if err := e.compileExtraVariables(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Timings returns the recorded expression timings sorted by expression.
+// It returns nil when timings are not enabled.
+func (e *Evaluator) Timings() []ExpressionTiming {
+	e.timingsMu.Lock()
+	defer e.timingsMu.Unlock()
+
+	if e.timings == nil {
+		return nil
+	}
+
+	result := make([]ExpressionTiming, 0, len(e.timings))
+	for _, timing := range e.timings {
+		result = append(result, *timing)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Expression < result[j].Expression
+	})
+
+	return result
+}
+
+// ResetTimings clears the recorded expression timings.
+func (e *Evaluator) ResetTimings() {
+	e.timingsMu.Lock()
+	defer e.timingsMu.Unlock()
+
+	if e.timings != nil {
+		e.timings = make(map[string]*ExpressionTiming)
+	}
+}
+
+// recordTiming adds a single evaluation duration for the given expression.
+func (e *Evaluator) recordTiming(expression string, elapsed time.Duration) {
+	e.timingsMu.Lock()
+	defer e.timingsMu.Unlock()
+
+	timing, ok := e.timings[expression]
+	if !ok {
+		timing = &ExpressionTiming{Expression: expression}
+		e.timings[expression] = timing
+	}
+
+	timing.Count++
+	timing.Total += elapsed
 }
 
 // TestCase represents a test case with inputs and expected outcomes.
@@ -104,28 +316,65 @@ type TestCase interface {
 	GetNamespaceObj() *unstructured.Unstructured
 	GetUserInfo() user.Info
 	GetExpectAllowed() bool
+	GetExpectAnyAllowed() bool
 	GetExpectMessage() string
 	GetExpectWarnings() []string
 	GetExpectAuditAnnotations() map[string]string
 	GetExpectedObject() *unstructured.Unstructured
 	GetError() error
 	GetAuthorizer() []AuthorizationMockConfig
+	GetRecordedResponse() *RecordedResponse
+	GetExpectedPatch() []PatchOperation
+	GetExpectStatus() *metav1.Status
 }
 
-// EvaluateTest evaluates a policy against a test case and returns whether it passed.
+// EvaluateTest evaluates a policy against a test case and returns whether it
+// passed. The result identifies the policy and binding the test exercised.
 func (e *Evaluator) EvaluateTest(
 	mutatingPolicy *admissionv1beta1.MutatingAdmissionPolicy,
 	mutatingBinding *admissionv1beta1.MutatingAdmissionPolicyBinding,
 	validatingPolicy *admissionregv1.ValidatingAdmissionPolicy,
 	validatingBinding *admissionregv1.ValidatingAdmissionPolicyBinding,
 	testCase TestCase,
+) *TestResult {
+	result := e.evaluateTest(mutatingPolicy, mutatingBinding, validatingPolicy, validatingBinding, testCase)
+
+	// A chained test is named after, and reported for, its mutating policy.
+	switch {
+	case mutatingPolicy != nil:
+		result.PolicyName, result.PolicyKind = mutatingPolicy.Name, PolicyKindMutating
+		if mutatingBinding != nil {
+			result.BindingName = mutatingBinding.Name
+		}
+	case validatingPolicy != nil:
+		result.PolicyName, result.PolicyKind = validatingPolicy.Name, PolicyKindValidating
+		if validatingBinding != nil {
+			result.BindingName = validatingBinding.Name
+		}
+	}
+
+	return result
+}
+
+func (e *Evaluator) evaluateTest(
+	mutatingPolicy *admissionv1beta1.MutatingAdmissionPolicy,
+	mutatingBinding *admissionv1beta1.MutatingAdmissionPolicyBinding,
+	validatingPolicy *admissionregv1.ValidatingAdmissionPolicy,
+	validatingBinding *admissionregv1.ValidatingAdmissionPolicyBinding,
+	testCase TestCase,
 ) *TestResult {
 	expected := TestExpectation{
-		Allowed:          testCase.GetExpectAllowed(),
-		Message:          testCase.GetExpectMessage(),
-		Object:           testCase.GetExpectedObject(),
-		Warnings:         testCase.GetExpectWarnings(),
-		AuditAnnotations: testCase.GetExpectAuditAnnotations(),
+		Allowed:              testCase.GetExpectAllowed(),
+		AnyAllowed:           testCase.GetExpectAnyAllowed(),
+		Message:              testCase.GetExpectMessage(),
+		Object:               testCase.GetExpectedObject(),
+		Warnings:             testCase.GetExpectWarnings(),
+		AuditAnnotations:     testCase.GetExpectAuditAnnotations(),
+		NoUnexpectedWarnings: e.assertNoUnexpectedWarnings,
+		CompareQuantities:    e.compareQuantities,
+		Recorded:             testCase.GetRecordedResponse(),
+		Patch:                testCase.GetExpectedPatch(),
+		Status:               testCase.GetExpectStatus(),
 	}
 
 	// Check for loading errors first
@@ -137,13 +386,27 @@ func (e *Evaluator) EvaluateTest(
 		}
 	}
 
+	// Chained tests are checked by their validating policy and need no gold file.
+	if e.requireGold && mutatingPolicy != nil && validatingPolicy == nil && expected.Object == nil && expected.Patch == nil {
+		return &TestResult{
+			Passed:   false,
+			Expected: expected,
+			Message:  "mutating test has no expected object (.gold.yaml) or patch (.patch.yaml)",
+		}
+	}
+
 	// Evaluate policy
+	start := time.Now()
 	evalResult, err := e.evaluatePolicy(mutatingPolicy, mutatingBinding, validatingPolicy, validatingBinding, testCase)
+	duration := time.Since(start)
+
 	if err != nil {
 		return &TestResult{
 			Passed:   false,
 			Expected: expected,
+			Actual:   TestOutcome{EvaluationErr: err},
 			Message:  fmt.Sprintf("evaluation error: %v", err),
+			Duration: duration,
 		}
 	}
 
@@ -157,16 +420,21 @@ func (e *Evaluator) EvaluateTest(
 
 	// Populate actual outcome
 	actual := TestOutcome{
-		Allowed:          evalResult.Allowed,
-		Message:          evalResult.Message,
-		Warnings:         evalResult.Warnings,
-		AuditAnnotations: evalResult.AuditAnnotations,
+		Allowed:              evalResult.Allowed,
+		Message:              evalResult.Message,
+		Warnings:             evalResult.Warnings,
+		AuditAnnotations:     evalResult.AuditAnnotations,
+		Patch:                evalResult.Patch,
+		AppliedConfiguration: evalResult.AppliedConfiguration,
+		FailedValidation:     evalResult.FailedValidation,
+		Status:               evalResult.Status,
 	}
 
 	if evalResult.PatchedObject != nil {
 		actual.Object = evalResult.PatchedObject
 	} else {
-		actual.Object = testCase.GetObject()
+		// A copy, so that nothing downstream can change the test input.
+		actual.Object = testCase.GetObject().DeepCopy()
 	}
 
 	// Compare expected vs actual
@@ -176,20 +444,56 @@ func (e *Evaluator) EvaluateTest(
 		PatchedObject: evalResult.PatchedObject,
 	}
 
-	return validateTestResult(result, &expected, &actual)
+	result = validateTestResult(result, &expected, &actual)
+	result.Notes = evalResult.Notes
+	result.Duration = duration
+
+	// Chains are checked by their validating policy and are not reinvoked here.
+	if e.checkReinvocation && result.Passed && mutatingPolicy != nil && validatingPolicy == nil && evalResult.PatchedObject != nil {
+		if msg := e.reinvocationFailure(mutatingPolicy, mutatingBinding, testCase, evalResult.PatchedObject); msg != "" {
+			result.Passed = false
+			result.Message = msg
+		}
+	}
+
+	if e.checkDeterminism && result.Passed {
+		if msg := e.determinismFailure(mutatingPolicy, mutatingBinding, validatingPolicy, validatingBinding, testCase, evalResult); msg != "" {
+			result.Passed = false
+			result.Message = msg
+		}
+	}
+
+	if evalResult.SkipReason != "" {
+		result.Notes = append(result.Notes, "policy not applied: "+evalResult.SkipReason)
+	}
+
+	return result
 }
 
 func validateTestResult(result *TestResult, expected *TestExpectation, actual *TestOutcome) *TestResult {
 	// Check if test passed with early returns
-	if actual.Allowed != expected.Allowed {
+	if !expected.AnyAllowed && actual.Allowed != expected.Allowed {
 		result.Passed = false
 		result.Message = fmt.Sprintf("expected allowed=%v, got allowed=%v", expected.Allowed, actual.Allowed)
+		if actual.FailedValidation != nil {
+			result.Message += fmt.Sprintf(": %s failed", actual.FailedValidation)
+		}
 
 		return result
 	}
 
 	if chk := checkAuditAnnotations(expected, actual); chk != nil {
-		return chk
+		result.Passed = false
+		result.Message = chk.Message
+
+		return result
+	}
+
+	if expected.NoUnexpectedWarnings && len(expected.Warnings) == 0 && len(actual.Warnings) > 0 {
+		result.Passed = false
+		result.Message = fmt.Sprintf("expected no warnings, got %q", actual.Warnings)
+
+		return result
 	}
 
 	if chk := checkWarnings(expected.Warnings, actual.Warnings); chk != nil {
@@ -211,11 +515,39 @@ func validateTestResult(result *TestResult, expected *TestExpectation, actual *T
 			result.Message = fmt.Sprintf("expected message %q, got %q", expected.Message, actual.Message)
 		}
 
+		if actual.FailedValidation != nil {
+			result.Message = fmt.Sprintf("%s failed; %s", actual.FailedValidation, result.Message)
+		}
+
 		return result
 	}
 
 	if chk := checkMutatedObject(expected, actual); chk != nil {
-		return chk
+		result.Passed = false
+		result.Message = chk.Message
+
+		return result
+	}
+
+	if msg := checkStatus(expected.Status, actual.Status); msg != "" {
+		result.Passed = false
+		result.Message = msg
+
+		return result
+	}
+
+	if msg := checkPatch(expected.Patch, actual); msg != "" {
+		result.Passed = false
+		result.Message = msg
+
+		return result
+	}
+
+	if msg := checkRecordedResponse(expected.Recorded, actual); msg != "" {
+		result.Passed = false
+		result.Message = msg
+
+		return result
 	}
 
 	result.Passed = true
@@ -251,35 +583,93 @@ func (e *Evaluator) evaluatePolicy(
 	}
 
 	switch {
+	case mutatingPolicy != nil && validatingPolicy != nil:
+		return e.evaluateChain(mutatingPolicy, mutatingBinding, validatingPolicy, validatingBinding, testCase, auth)
 	case mutatingPolicy != nil:
-		return e.EvaluateMutating(
-			mutatingPolicy,
-			mutatingBinding,
-			testCase.GetRequest(),
-			testCase.GetObject(),
-			testCase.GetOldObject(),
-			testCase.GetParams(),
-			testCase.GetNamespaceObj(),
-			auth,
-			testCase.GetUserInfo(),
-		)
+		return e.EvaluateMutating(mutatingInput(mutatingPolicy, mutatingBinding, testCase, auth))
 	case validatingPolicy != nil:
-		return e.EvaluateValidating(
-			validatingPolicy,
-			validatingBinding,
-			testCase.GetRequest(),
-			testCase.GetObject(),
-			testCase.GetOldObject(),
-			testCase.GetParams(),
-			testCase.GetNamespaceObj(),
-			auth,
-			testCase.GetUserInfo(),
-		)
+		return e.EvaluateValidating(validatingInput(validatingPolicy, validatingBinding, testCase, auth))
 	default:
 		return nil, errNoPolicy
 	}
 }
 
+// mutatingInput adapts a test case to the input of EvaluateMutating.
+func mutatingInput(
+	policy *admissionv1beta1.MutatingAdmissionPolicy,
+	binding *admissionv1beta1.MutatingAdmissionPolicyBinding,
+	testCase TestCase,
+	auth authorizer.Authorizer,
+) MutatingInput {
+	return MutatingInput{
+		Policy:     policy,
+		Binding:    binding,
+		Request:    testCase.GetRequest(),
+		Object:     testCase.GetObject(),
+		OldObject:  testCase.GetOldObject(),
+		Params:     testCase.GetParams(),
+		Namespace:  testCase.GetNamespaceObj(),
+		Authorizer: auth,
+		UserInfo:   testCase.GetUserInfo(),
+	}
+}
+
+// validatingInput adapts a test case to the input of EvaluateValidating.
+func validatingInput(
+	policy *admissionregv1.ValidatingAdmissionPolicy,
+	binding *admissionregv1.ValidatingAdmissionPolicyBinding,
+	testCase TestCase,
+	auth authorizer.Authorizer,
+) ValidatingInput {
+	return ValidatingInput{
+		Policy:     policy,
+		Binding:    binding,
+		Request:    testCase.GetRequest(),
+		Object:     testCase.GetObject(),
+		OldObject:  testCase.GetOldObject(),
+		Params:     testCase.GetParams(),
+		Namespace:  testCase.GetNamespaceObj(),
+		Authorizer: auth,
+		UserInfo:   testCase.GetUserInfo(),
+	}
+}
+
+// evaluateChain mutates the test object with the mutating policy and then validates
+// the result with the validating policy, mirroring the admission chain order.
+// The returned result carries the validation decision and the mutated object.
+func (e *Evaluator) evaluateChain(
+	mutatingPolicy *admissionv1beta1.MutatingAdmissionPolicy,
+	mutatingBinding *admissionv1beta1.MutatingAdmissionPolicyBinding,
+	validatingPolicy *admissionregv1.ValidatingAdmissionPolicy,
+	validatingBinding *admissionregv1.ValidatingAdmissionPolicyBinding,
+	testCase TestCase,
+	auth authorizer.Authorizer,
+) (*EvaluationResult, error) {
+	mutResult, err := e.EvaluateMutating(mutatingInput(mutatingPolicy, mutatingBinding, testCase, auth))
+	if err != nil {
+		return nil, fmt.Errorf("mutating policy %s: %w", mutatingPolicy.Name, err)
+	}
+
+	valInput := validatingInput(validatingPolicy, validatingBinding, testCase, auth)
+	if mutResult.PatchedObject != nil {
+		valInput.Object = mutResult.PatchedObject
+	}
+
+	valResult, err := e.EvaluateValidating(valInput)
+	if err != nil {
+		return nil, fmt.Errorf("validating policy %s: %w", validatingPolicy.Name, err)
+	}
+
+	valResult.PatchedObject = mutResult.PatchedObject
+	valResult.Notes = append(mutResult.Notes, valResult.Notes...)
+
+	if mutResult.SkipReason != "" {
+		valResult.Notes = append(valResult.Notes, "mutating policy not applied: "+mutResult.SkipReason)
+	}
+
+	return valResult, nil
+}
+
 // checkWarnings verifies that actual warnings match expected warnings.
 // Returns a TestResult on mismatch, or nil if all checks pass.
 func checkWarnings(expected, actual []string) *TestResult {
@@ -383,14 +773,21 @@ func checkMutatedObject(expected *TestExpectation, actual *TestOutcome) *TestRes
 		return result
 	}
 
+	expectedObject := expected.Object.Object
+
+	var equated []string
+	if expected.CompareQuantities {
+		expectedObject, equated = equateQuantities(expectedObject, actual.Object.Object)
+	}
+
 	// Compare objects - they should match exactly
-	if !reflect.DeepEqual(expected.Object.Object, actual.Object.Object) {
+	if !reflect.DeepEqual(expectedObject, actual.Object.Object) {
 		result.Passed = false
 
 		// Convert to YAML for consistent diffing
-		expectedYAML, err := yaml.Marshal(expected.Object.Object)
+		expectedYAML, err := yaml.Marshal(expectedObject)
 		if err != nil {
-			expectedYAML = []byte(fmt.Sprintf("%+v", expected.Object.Object))
+			expectedYAML = []byte(fmt.Sprintf("%+v", expectedObject))
 		}
 
 		actualYAML, err := yaml.Marshal(actual.Object.Object)
@@ -408,6 +805,10 @@ func checkMutatedObject(expected *TestExpectation, actual *TestOutcome) *TestRes
 		}
 
 		result.Message = "mutated object does not match expected:\n" + diff
+		if len(equated) > 0 {
+			result.Message = strings.TrimRight(result.Message, "\n") +
+				"\nquantity equivalence applied to:\n  " + strings.Join(equated, "\n  ")
+		}
 
 		return result
 	}
@@ -416,7 +817,7 @@ func checkMutatedObject(expected *TestExpectation, actual *TestOutcome) *TestRes
 }
 
 // handleValidationFailure handles the case when validation fails, determining the appropriate action.
-func (e *Evaluator) handleValidationFailure(validation *admissionregv1.Validation, binding *admissionregv1.ValidatingAdmissionPolicyBinding, auditAnnotations map[string]string, vars map[string]any) (*EvaluationResult, error) {
+func (e *Evaluator) handleValidationFailure(validation *admissionregv1.Validation, ref ValidationRef, binding *admissionregv1.ValidatingAdmissionPolicyBinding, auditAnnotations map[string]string, vars map[string]any) (*EvaluationResult, error) {
 	message := validation.Message
 
 	// If messageExpression is provided, evaluate it
@@ -435,38 +836,33 @@ func (e *Evaluator) handleValidationFailure(validation *admissionregv1.Validatio
 		message = "validation failed: " + validation.Expression
 	}
 
-	// Check binding actions
-	action := e.getValidationAction(binding)
-	switch action {
-	case admissionregv1.Warn:
-		return &EvaluationResult{
-			Allowed:          true,
-			Warnings:         []string{message},
-			AuditAnnotations: auditAnnotations,
-		}, nil
-	case admissionregv1.Audit:
-		return &EvaluationResult{
-			Allowed:          true,
-			AuditAnnotations: auditAnnotations,
-		}, nil
-	case admissionregv1.Deny:
-		fallthrough
-	default:
-		return &EvaluationResult{
-			Allowed:          false,
-			Message:          message,
-			AuditAnnotations: auditAnnotations,
-		}, nil
+	// Apply every action of the binding, as the API server does.
+	actions := e.getValidationActions(binding)
+	result := &EvaluationResult{
+		Allowed:          !slices.Contains(actions, admissionregv1.Deny),
+		AuditAnnotations: auditAnnotations,
+		FailedValidation: &ref,
+	}
+
+	if !result.Allowed {
+		result.Message = message
+	}
+
+	if slices.Contains(actions, admissionregv1.Warn) {
+		result.Warnings = []string{message}
 	}
+
+	return result, nil
 }
 
-// getValidationAction returns the validation action to take (Warn, Audit, or Deny).
-func (e *Evaluator) getValidationAction(binding *admissionregv1.ValidatingAdmissionPolicyBinding) admissionregv1.ValidationAction {
+// getValidationActions returns the validation actions of binding; without a
+// binding or actions, a failing validation denies.
+func (e *Evaluator) getValidationActions(binding *admissionregv1.ValidatingAdmissionPolicyBinding) []admissionregv1.ValidationAction {
 	if binding == nil || len(binding.Spec.ValidationActions) == 0 {
-		return ""
+		return []admissionregv1.ValidationAction{admissionregv1.Deny}
 	}
 
-	return binding.Spec.ValidationActions[0]
+	return binding.Spec.ValidationActions
 }
 
 // setupValidatingVars sets up CEL variables for validating evaluation.
@@ -480,19 +876,20 @@ func (e *Evaluator) setupValidatingVars(
 		plugin.RequestVarName: requestMap,
 	}
 
-	// For connect/delete/other operations, bind 'object' variable appropriately
+	// As on the API server, object is null on DELETE. With
+	// WithDeleteObjectFallback it is the deleted object instead.
 	switch {
 	case object != nil:
-		vars[plugin.ObjectVarName] = object.Object
-	case oldObject != nil:
-		vars[plugin.ObjectVarName] = oldObject.Object
+		vars[plugin.ObjectVarName] = celNumbers(object.Object)
+	case oldObject != nil && e.deleteObjectFallback:
+		vars[plugin.ObjectVarName] = celNumbers(oldObject.Object)
 	default:
 		vars[plugin.ObjectVarName] = nil
 	}
 
 	// Always add params (as null if not provided) so CEL can check for null
 	if params != nil {
-		vars[plugin.ParamsVarName] = params.Object
+		vars[plugin.ParamsVarName] = celNumbers(params.Object)
 	} else {
 		vars[plugin.ParamsVarName] = nil
 	}
@@ -501,21 +898,29 @@ func (e *Evaluator) setupValidatingVars(
 		vars[plugin.AuthorizerVarName] = NewAuthorizerValue(authorizer, userInfo)
 	}
 
+	// Always add oldObject (as null on CREATE), matching the API server
 	if oldObject != nil {
-		vars[plugin.OldObjectVarName] = oldObject.Object
+		vars[plugin.OldObjectVarName] = celNumbers(oldObject.Object)
+	} else {
+		vars[plugin.OldObjectVarName] = nil
 	}
 
 	if namespaceObj != nil {
-		vars[plugin.NamespaceVarName] = namespaceObj.Object
+		vars[plugin.NamespaceVarName] = celNumbers(namespaceObj.Object)
 	}
 
 	return vars
 }
 
 // evaluateAuditAnnotations evaluates all audit annotations and returns them as a map.
-func (e *Evaluator) evaluateAuditAnnotations(annotations []admissionregv1.AuditAnnotation, vars map[string]any) (map[string]string, error) {
+func (e *Evaluator) evaluateAuditAnnotations(policyName string, annotations []admissionregv1.AuditAnnotation, vars map[string]any) (map[string]string, error) {
 	auditAnnotations := make(map[string]string)
 
+	keyPrefix := ""
+	if e.prefixAuditAnnotations {
+		keyPrefix = policyName + "/"
+	}
+
 	for _, annotation := range annotations {
 		value, err := e.evaluateExpression(annotation.ValueExpression, vars)
 		if err != nil {
@@ -523,7 +928,7 @@ func (e *Evaluator) evaluateAuditAnnotations(annotations []admissionregv1.AuditA
 		}
 		// Convert value to string
 		if strValue, ok := value.(string); ok && strValue != "" {
-			auditAnnotations[annotation.Key] = strValue
+			auditAnnotations[keyPrefix+annotation.Key] = strValue
 		}
 	}
 
@@ -532,14 +937,33 @@ func (e *Evaluator) evaluateAuditAnnotations(annotations []admissionregv1.AuditA
 
 // EvaluationResult contains the result of evaluating a policy.
 type EvaluationResult struct {
-	Allowed          bool
-	Message          string
-	Warnings         []string
-	PatchType        *admissionv1.PatchType
-	PatchedObject    *unstructured.Unstructured // The object after applying mutations
-	AuditAnnotations map[string]string
+	Allowed       bool
+	Message       string
+	Warnings      []string
+	PatchType     *admissionv1.PatchType
+	PatchedObject *unstructured.Unstructured // The object after applying mutations; nil when unchanged
+	Patch         []PatchOperation           // JSON Patch operations emitted by JSONPatch mutations, in order; empty when they return empty lists
+	// AppliedConfiguration is set when an ApplyConfiguration mutation was
+	// applied; such mutations are not described by Patch.
+	AppliedConfiguration bool
+	AuditAnnotations     map[string]string
+	Matched              bool     // The mutating policy matched the request
+	Mutated              bool     // The mutating policy changed the object
+	Notes                []string // Deviations from API server behavior applied during evaluation
+	SkipReason           string   // Why the policy was not applied; empty when it was
+	// FailedValidation is the validation that failed; nil when all passed.
+	FailedValidation *ValidationRef
+	// Status is the status the API server responds with when a validating
+	// policy denies the request; nil when allowed.
+	Status *metav1.Status
 }
 
+// Policy kinds of TestResult.PolicyKind.
+const (
+	PolicyKindValidating = "ValidatingAdmissionPolicy"
+	PolicyKindMutating   = "MutatingAdmissionPolicy"
+)
+
 // TestResult contains the result of evaluating a test case.
 type TestResult struct {
 	Passed        bool
@@ -547,6 +971,16 @@ type TestResult struct {
 	Actual        TestOutcome
 	Message       string // Failure explanation or diff
 	PatchedObject *unstructured.Unstructured
+	Notes         []string // Informational notes shown in verbose output
+	// Duration is the time spent evaluating the policies, excluding the
+	// loading of fixtures and the comparison with expectations.
+	Duration time.Duration
+	// PolicyName and PolicyKind identify the policy the test exercised, the
+	// mutating policy of a chained test; BindingName is its binding, empty
+	// when the policy has none.
+	PolicyName  string
+	PolicyKind  string
+	BindingName string
 }
 
 // TestExpectation contains what the test expects to happen.
@@ -556,36 +990,82 @@ type TestExpectation struct {
 	Object           *unstructured.Unstructured
 	Warnings         []string
 	AuditAnnotations map[string]string
+	// AnyAllowed skips the check of Allowed, asserting only the other outcomes.
+	AnyAllowed bool
+	// NoUnexpectedWarnings fails the test when warnings are produced but none are expected.
+	NoUnexpectedWarnings bool
+	// CompareQuantities compares resource quantities of Object by value, not text.
+	CompareQuantities bool
+	// Recorded is the admission response recorded from a cluster, if any.
+	Recorded *RecordedResponse
+	// Patch is the ordered list of expected JSON Patch operations; nil skips the check.
+	Patch []PatchOperation
+	// Status is the expected status of a denial; nil skips the check.
+	Status *metav1.Status
 }
 
 // TestOutcome contains what actually happened during evaluation.
 type TestOutcome struct {
-	Allowed          bool
-	Message          string
-	Object           *unstructured.Unstructured
-	Warnings         []string
-	AuditAnnotations map[string]string
-	EvaluationErr    error
+	Allowed              bool
+	Message              string
+	Object               *unstructured.Unstructured
+	Warnings             []string
+	AuditAnnotations     map[string]string
+	Patch                []PatchOperation
+	AppliedConfiguration bool
+	EvaluationErr        error
+	FailedValidation     *ValidationRef
+	Status               *metav1.Status
+}
+
+// MutatingInput is an admission request to evaluate against a mutating policy.
+// Policy, Request and Object are required; the other fields may be nil.
+type MutatingInput struct {
+	Policy     *admissionv1beta1.MutatingAdmissionPolicy
+	Binding    *admissionv1beta1.MutatingAdmissionPolicyBinding // nil evaluates the policy unbound
+	Request    *admissionv1.AdmissionRequest
+	Object     *unstructured.Unstructured
+	OldObject  *unstructured.Unstructured
+	Params     *unstructured.Unstructured
+	Namespace  *unstructured.Unstructured // The namespace object, for namespaceSelector and namespaceObject
+	Authorizer authorizer.Authorizer
+	UserInfo   user.Info
+}
+
+// ValidatingInput is an admission request to evaluate against a validating
+// policy. Policy, Request and Object are required; the other fields may be nil.
+type ValidatingInput struct {
+	Policy     *admissionregv1.ValidatingAdmissionPolicy
+	Binding    *admissionregv1.ValidatingAdmissionPolicyBinding // nil evaluates the policy unbound
+	Request    *admissionv1.AdmissionRequest
+	Object     *unstructured.Unstructured
+	OldObject  *unstructured.Unstructured
+	Params     *unstructured.Unstructured
+	Namespace  *unstructured.Unstructured // The namespace object, for namespaceSelector and namespaceObject
+	Authorizer authorizer.Authorizer
+	UserInfo   user.Info
 }
 
 // EvaluateMutating evaluates a MutatingAdmissionPolicy against an admission request.
-func (e *Evaluator) EvaluateMutating(
-	policy *admissionv1beta1.MutatingAdmissionPolicy,
-	binding *admissionv1beta1.MutatingAdmissionPolicyBinding,
-	request *admissionv1.AdmissionRequest,
-	object *unstructured.Unstructured,
-	oldObject *unstructured.Unstructured,
-	params *unstructured.Unstructured,
-	namespaceObj *unstructured.Unstructured,
-	authorizer authorizer.Authorizer,
-	userInfo user.Info,
-) (*EvaluationResult, error) {
+func (e *Evaluator) EvaluateMutating(in MutatingInput) (*EvaluationResult, error) {
+	policy, binding, request := in.Policy, in.Binding, in.Request
+	object, oldObject, params, namespaceObj := in.Object, in.OldObject, in.Params, in.Namespace
+	authorizer, userInfo := in.Authorizer, in.UserInfo
+
 	// Evaluate binding's namespaceSelector if present
 	if matched, err := e.matchesNamespaceSelectorV1Beta1(binding, namespaceObj); err != nil {
 		return nil, fmt.Errorf("evaluate namespace selector: %w", err)
 	} else if !matched {
 		// Namespace selector doesn't match, policy doesn't apply
-		return &EvaluationResult{Allowed: true}, nil
+		return &EvaluationResult{Allowed: true, SkipReason: skipBindingNamespaceSelector}, nil
+	}
+
+	if reason := matchesPolicyResourceRulesV1Beta1(policy, request); reason != "" {
+		return &EvaluationResult{Allowed: true, SkipReason: reason}, nil
+	}
+
+	if reason := matchesBindingResourceRulesV1Beta1(binding, request); reason != "" {
+		return &EvaluationResult{Allowed: true, SkipReason: reason}, nil
 	}
 
 	requestMap, err := convertAdmissionRequest(request)
@@ -599,6 +1079,11 @@ func (e *Evaluator) EvaluateMutating(
 	}
 
 	vars := prepareMutatingVars(requestMap, primaryObject, oldObject, params, namespaceObj, authorizer, userInfo)
+	if err := e.bindExtraVariables(vars); err != nil {
+		return nil, err
+	}
+
+	e.bindPolicyVariables(vars, v1Variables(policy.Spec.Variables))
 
 	matched, err := e.evaluateMatchConditionsV1Beta1(policy.Spec.MatchConditions, vars)
 	if err != nil {
@@ -606,18 +1091,78 @@ func (e *Evaluator) EvaluateMutating(
 	}
 
 	if !matched {
-		return &EvaluationResult{Allowed: true}, nil
+		return &EvaluationResult{Allowed: true, SkipReason: skipPolicyMatchConditions}, nil
 	}
 
-	patchedObject, err := e.applyMutations(policy.Spec.Mutations, object, vars)
+	mutated, err := e.applyMutations(policy.Spec.Mutations, object, vars)
 	if err != nil {
 		return nil, err
 	}
 
-	return &EvaluationResult{
-		Allowed:       true,
-		PatchedObject: patchedObject,
-	}, nil
+	if err := checkImmutableFields(object, mutated.object); err != nil {
+		return nil, err
+	}
+
+	result := &EvaluationResult{
+		Allowed:              true,
+		Matched:              true,
+		Patch:                mutated.patch,
+		AppliedConfiguration: mutated.appliedConfiguration,
+		Notes:                mutated.notes,
+	}
+
+	// Report a patched object only when the mutations actually changed something.
+	if !objectsEqual(object, mutated.object) {
+		result.Mutated = true
+		result.PatchedObject = mutated.object
+	}
+
+	return result, nil
+}
+
+// checkImmutableFields mirrors the API server, which rejects mutations that
+// change an object's identity.
+func checkImmutableFields(original, patched *unstructured.Unstructured) error {
+	if original == nil || patched == nil {
+		return nil
+	}
+
+	fields := []struct {
+		name   string
+		before string
+		after  string
+	}{
+		{"apiVersion", original.GetAPIVersion(), patched.GetAPIVersion()},
+		{"kind", original.GetKind(), patched.GetKind()},
+		{"metadata.name", original.GetName(), patched.GetName()},
+		{"metadata.namespace", original.GetNamespace(), patched.GetNamespace()},
+		{"metadata.uid", string(original.GetUID()), string(patched.GetUID())},
+	}
+
+	for _, field := range fields {
+		if field.before != field.after {
+			return fmt.Errorf("%w %s: %q -> %q", errImmutableFieldChanged, field.name, field.before, field.after)
+		}
+	}
+
+	return nil
+}
+
+// objectsEqual reports whether two objects are equal after JSON normalization,
+// so that numbers decoded as int64 and float64 compare equal.
+func objectsEqual(a, b *unstructured.Unstructured) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	aJSON, errA := json.Marshal(a.Object)
+	bJSON, errB := json.Marshal(b.Object)
+
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a.Object, b.Object)
+	}
+
+	return string(aJSON) == string(bJSON)
 }
 
 func getPrimaryObject(object, oldObject *unstructured.Unstructured) *unstructured.Unstructured {
@@ -640,13 +1185,13 @@ func prepareMutatingVars(
 	userInfo user.Info,
 ) map[string]any {
 	vars := map[string]any{
-		plugin.ObjectVarName:  primaryObject.Object,
+		plugin.ObjectVarName:  celNumbers(primaryObject.Object),
 		plugin.RequestVarName: requestMap,
 	}
 
 	// Always add params (as null if not provided) so CEL can check for null
 	if params != nil {
-		vars[plugin.ParamsVarName] = params.Object
+		vars[plugin.ParamsVarName] = celNumbers(params.Object)
 	} else {
 		vars[plugin.ParamsVarName] = nil
 	}
@@ -655,23 +1200,34 @@ func prepareMutatingVars(
 		vars[plugin.AuthorizerVarName] = NewAuthorizerValue(authorizer, userInfo)
 	}
 
+	// Always add oldObject (as null on CREATE), matching the API server
 	if oldObject != nil {
-		vars[plugin.OldObjectVarName] = oldObject.Object
+		vars[plugin.OldObjectVarName] = celNumbers(oldObject.Object)
+	} else {
+		vars[plugin.OldObjectVarName] = nil
 	}
 
 	if namespaceObj != nil {
-		vars[plugin.NamespaceVarName] = namespaceObj.Object
+		vars[plugin.NamespaceVarName] = celNumbers(namespaceObj.Object)
 	}
 
 	return vars
 }
 
+// mutationResult is the outcome of applying a policy's mutations.
+type mutationResult struct {
+	object               *unstructured.Unstructured
+	patch                []PatchOperation
+	appliedConfiguration bool
+	notes                []string
+}
+
 func (e *Evaluator) applyMutations(
 	mutations []admissionv1beta1.Mutation,
 	object *unstructured.Unstructured,
 	vars map[string]any,
-) (*unstructured.Unstructured, error) {
-	patchedObject := object.DeepCopy()
+) (*mutationResult, error) {
+	result := &mutationResult{object: object.DeepCopy()}
 
 	for _, mutation := range mutations {
 		switch mutation.PatchType {
@@ -682,12 +1238,17 @@ func (e *Evaluator) applyMutations(
 			}
 
 			if patch != nil {
-				var err error
-
-				patchedObject, err = e.applyJSONPatches([]any{patch}, patchedObject)
+				patched, err := e.applyJSONPatches([]any{patch}, result.object)
 				if err != nil {
 					return nil, err
 				}
+
+				result.object = patched.object
+				result.patch = append(result.patch, patched.ops...)
+
+				for _, path := range patched.created {
+					result.notes = append(result.notes, "created missing parent map "+path+" for JSONPatch add")
+				}
 			}
 		case admissionv1beta1.PatchTypeApplyConfiguration:
 			config, err := e.evaluateApplyConfigurationMutation(mutation, vars)
@@ -696,34 +1257,40 @@ func (e *Evaluator) applyMutations(
 			}
 
 			if config != nil {
-				patchedObject = e.applyApplyConfigurations([]*unstructured.Unstructured{config}, patchedObject)
+				result.object = e.applyApplyConfigurations([]*unstructured.Unstructured{config}, result.object)
+				result.appliedConfiguration = true
 			}
 		default:
 			return nil, fmt.Errorf("%w: %s", errUnsupportedPatchType, mutation.PatchType)
 		}
 	}
 
-	return patchedObject, nil
+	return result, nil
 }
 
 // EvaluateValidating evaluates a ValidatingAdmissionPolicy against an admission request.
-func (e *Evaluator) EvaluateValidating( //nolint:cyclop // Complexity is inherent in evaluating all aspects of a validating policy
-	policy *admissionregv1.ValidatingAdmissionPolicy,
-	binding *admissionregv1.ValidatingAdmissionPolicyBinding,
-	request *admissionv1.AdmissionRequest,
-	object *unstructured.Unstructured,
-	oldObject *unstructured.Unstructured,
-	params *unstructured.Unstructured,
-	namespaceObj *unstructured.Unstructured,
-	authorizer authorizer.Authorizer,
-	userInfo user.Info,
-) (*EvaluationResult, error) {
+// The result carries the policy's audit annotations together with the
+// warnings of the validations failing under a Warn binding, whether or not
+// another validation denies the request.
+func (e *Evaluator) EvaluateValidating(in ValidatingInput) (*EvaluationResult, error) { //nolint:cyclop // Complexity is inherent in evaluating all aspects of a validating policy
+	policy, binding, request := in.Policy, in.Binding, in.Request
+	object, oldObject, params, namespaceObj := in.Object, in.OldObject, in.Params, in.Namespace
+	authorizer, userInfo := in.Authorizer, in.UserInfo
+
 	// Evaluate binding's namespaceSelector if present
 	if matched, err := e.matchesNamespaceSelector(binding, namespaceObj); err != nil {
 		return nil, fmt.Errorf("evaluate namespace selector: %w", err)
 	} else if !matched {
 		// Namespace selector doesn't match, policy doesn't apply
-		return &EvaluationResult{Allowed: true}, nil
+		return &EvaluationResult{Allowed: true, SkipReason: skipBindingNamespaceSelector}, nil
+	}
+
+	if reason := matchesPolicyResourceRules(policy, request); reason != "" {
+		return &EvaluationResult{Allowed: true, SkipReason: reason}, nil
+	}
+
+	if reason := matchesBindingResourceRules(binding, request); reason != "" {
+		return &EvaluationResult{Allowed: true, SkipReason: reason}, nil
 	}
 
 	// Convert admission request
@@ -734,6 +1301,11 @@ func (e *Evaluator) EvaluateValidating( //nolint:cyclop // Complexity is inheren
 
 	// Set up CEL variables
 	vars := e.setupValidatingVars(requestMap, object, oldObject, params, namespaceObj, authorizer, userInfo)
+	if err := e.bindExtraVariables(vars); err != nil {
+		return nil, err
+	}
+
+	e.bindPolicyVariables(vars, policy.Spec.Variables)
 
 	// Evaluate matchConditions if present
 	matched, err := e.evaluateMatchConditions(policy.Spec.MatchConditions, vars)
@@ -743,37 +1315,121 @@ func (e *Evaluator) EvaluateValidating( //nolint:cyclop // Complexity is inheren
 
 	if !matched {
 		// Policy doesn't match, allow
-		return &EvaluationResult{Allowed: true}, nil
+		return &EvaluationResult{Allowed: true, SkipReason: skipPolicyMatchConditions}, nil
 	}
 
 	// Evaluate audit annotations
-	auditAnnotations, err := e.evaluateAuditAnnotations(policy.Spec.AuditAnnotations, vars)
+	auditAnnotations, err := e.evaluateAuditAnnotations(policy.Name, policy.Spec.AuditAnnotations, vars)
 	if err != nil {
 		return nil, err
 	}
 
+	// Like the API server, every validation is evaluated: the request is denied
+	// by the first validation failing under Deny, and the response carries the
+	// audit annotations and the warnings of all of them, identical ones
+	// reported once.
+	allowed := &EvaluationResult{
+		Allowed:          true,
+		AuditAnnotations: auditAnnotations,
+	}
+
+	var denied *EvaluationResult
+
+	var warnings []string
+
 	// Evaluate validations
-	for _, validation := range policy.Spec.Validations {
-		result, err := e.evaluateExpression(validation.Expression, vars)
+	for i, validation := range policy.Spec.Validations {
+		failed, err := e.evaluateValidation(&validation, validationRef(policy, i), binding, auditAnnotations, vars)
 		if err != nil {
-			return nil, fmt.Errorf("evaluate validation expression %q: %w", validation.Expression, err)
+			// The API server reports the first denial, so errors of later
+			// validations do not change the response.
+			if denied != nil {
+				break
+			}
+
+			return nil, err
 		}
 
-		// If validation returns false, deny
-		allowed, ok := result.(bool)
-		if !ok {
-			return nil, fmt.Errorf("%w: %s returned %T", errValidationNonBoolean, validation.Expression, result)
+		if failed == nil {
+			continue
 		}
 
-		if !allowed {
-			return e.handleValidationFailure(&validation, binding, auditAnnotations, vars)
+		warnings = append(warnings, failed.Warnings...)
+
+		if !failed.Allowed && denied == nil {
+			failed.Status = deniedStatus(policy, binding, &validation, request, object, failed.Message)
+			denied = failed
+		}
+
+		if allowed.FailedValidation == nil {
+			allowed.FailedValidation = failed.FailedValidation
 		}
 	}
 
-	return &EvaluationResult{
-		Allowed:          true,
-		AuditAnnotations: auditAnnotations,
-	}, nil
+	result := allowed
+	if denied != nil {
+		result = denied
+	}
+
+	result.Warnings = warnings
+	e.finishWarnings(result)
+
+	return result, nil
+}
+
+// evaluateValidation evaluates validation, returning nil when it passes and
+// the outcome of its failure otherwise.
+func (e *Evaluator) evaluateValidation(
+	validation *admissionregv1.Validation,
+	ref ValidationRef,
+	binding *admissionregv1.ValidatingAdmissionPolicyBinding,
+	auditAnnotations map[string]string,
+	vars map[string]any,
+) (*EvaluationResult, error) {
+	result, err := e.evaluateExpression(validation.Expression, vars)
+	if err != nil {
+		return nil, fmt.Errorf("evaluate validation expression %q: %w", validation.Expression, err)
+	}
+
+	passed, ok := result.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s returned %T", errValidationNonBoolean, validation.Expression, result)
+	}
+
+	if passed {
+		return nil, nil //nolint:nilnil // nil means the validation passed
+	}
+
+	return e.handleValidationFailure(validation, ref, binding, auditAnnotations, vars)
+}
+
+// finishWarnings reports identical warnings of result once and truncates them
+// as the API server does, noting both.
+func (e *Evaluator) finishWarnings(result *EvaluationResult) {
+	if len(result.Warnings) == 0 {
+		return
+	}
+
+	warnings, repeated := dedupeWarnings(result.Warnings)
+	for _, warning := range warnings {
+		if count := repeated[warning]; count > 0 {
+			result.Notes = append(result.Notes, fmt.Sprintf(
+				"warning %q produced %d times, reported once as by the API server", warning, count))
+		}
+	}
+
+	result.Warnings = warnings
+
+	if e.keepLongWarnings {
+		return
+	}
+
+	var truncated bool
+	if result.Warnings, truncated = truncateWarnings(result.Warnings); truncated {
+		result.Notes = append(result.Notes, fmt.Sprintf(
+			"warnings truncated as by the API server: over %d characters in total, each is cut to %d and later ones are dropped",
+			MaxWarningsRunes, MaxWarningRunes))
+	}
 }
 
 // matchesNamespaceSelectorByLabelSelector checks if the namespace object's labels match the given label selector.
@@ -886,12 +1542,28 @@ func (e *Evaluator) evaluateExpression(expression string, vars map[string]any) (
 
 // evaluateExpressionRaw evaluates a CEL expression and returns the raw CEL value without unwrapping.
 func (e *Evaluator) evaluateExpressionRaw(expression string, vars map[string]any) (ref.Val, error) {
+	if e.timings != nil {
+		start := time.Now()
+		defer func() { e.recordTiming(expression, time.Since(start)) }()
+	}
+
 	ast, issues := e.env.Compile(expression)
 	if issues != nil && issues.Err() != nil {
 		return nil, fmt.Errorf("compile expression: %w", issues.Err())
 	}
 
-	prg, err := e.env.Program(ast)
+	if e.trace != nil {
+		e.trace.print(expression, ast)
+	}
+
+	// The base environment limits the cost to the API server's per-call
+	// limit; a later limit replaces it.
+	costLimit := e.costLimit
+	if costLimit == 0 {
+		costLimit = math.MaxUint64
+	}
+
+	prg, err := e.env.Program(ast, cel.CostLimit(costLimit))
 	if err != nil {
 		return nil, fmt.Errorf("create program: %w", err)
 	}
@@ -1049,12 +1721,14 @@ func (e *Evaluator) evaluateApplyConfigurationMutation(
 }
 
 // Follows the Kubernetes pattern from k8s.io/apiserver/pkg/admission/plugin/policy/mutating/patch/json_patch.go.
+// The result lists the applied operations and the parent maps created for
+// "add" operations when missing parents are enabled.
 func (e *Evaluator) applyJSONPatches(
 	patches []any,
 	object *unstructured.Unstructured,
-) (*unstructured.Unstructured, error) {
+) (*jsonPatchResult, error) {
 	if len(patches) == 0 {
-		return object.DeepCopy(), nil
+		return &jsonPatchResult{object: object.DeepCopy()}, nil
 	}
 
 	result := jsonpatch.Patch{}
@@ -1071,10 +1745,79 @@ func (e *Evaluator) applyJSONPatches(
 	}
 
 	if len(result) == 0 {
-		return object.DeepCopy(), nil
+		return &jsonPatchResult{object: object.DeepCopy()}, nil
+	}
+
+	ops, err := patchOperations(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []string
+
+	if e.createMissingParents {
+		object = object.DeepCopy()
+		created = createMissingParents(result, object.Object)
+	}
+
+	patchedObject, err := applyPatchOperations(result, object)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonPatchResult{object: patchedObject, ops: ops, created: created}, nil
+}
+
+// jsonPatchResult is the outcome of applying JSON Patch operations.
+type jsonPatchResult struct {
+	object *unstructured.Unstructured
+	ops    []PatchOperation
+	// created lists parent maps created for "add" operations.
+	created []string
+}
+
+// createMissingParents creates empty maps for missing intermediate path segments
+// of "add" operations and returns the JSON pointers of the created maps.
+// Segments that traverse lists or scalars are left for the patch to report.
+func createMissingParents(patch jsonpatch.Patch, object map[string]any) []string {
+	var created []string
+
+	for _, op := range patch {
+		if op.Kind() != "add" {
+			continue
+		}
+
+		path, err := op.Path()
+		if err != nil || !strings.HasPrefix(path, "/") {
+			continue
+		}
+
+		segments := strings.Split(path[1:], "/")
+		current := object
+
+		for i, segment := range segments[:len(segments)-1] {
+			key := strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+
+			next, ok := current[key]
+			if !ok {
+				child := map[string]any{}
+				current[key] = child
+				created = append(created, "/"+strings.Join(segments[:i+1], "/"))
+				current = child
+
+				continue
+			}
+
+			child, ok := next.(map[string]any)
+			if !ok {
+				break
+			}
+
+			current = child
+		}
 	}
 
-	return applyPatchOperations(result, object)
+	return created
 }
 
 func listerFromPatch(p any) (traits.Lister, bool) {
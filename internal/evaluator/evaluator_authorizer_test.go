@@ -105,7 +105,7 @@ func runMutatingTest(t *testing.T, policy *admissionv1beta1.MutatingAdmissionPol
 
 	userInfo := MockUserInfo(username, groups)
 
-	result, err := evaluator.EvaluateMutating(policy, nil, request, object, nil, nil, nil, auth, userInfo)
+	result, err := evaluator.EvaluateMutating(MutatingInput{Policy: policy, Request: request, Object: object, Authorizer: auth, UserInfo: userInfo})
 	if err != nil {
 		t.Fatalf("EvaluateMutating() error = %v", err)
 	}
@@ -114,16 +114,23 @@ func runMutatingTest(t *testing.T, policy *admissionv1beta1.MutatingAdmissionPol
 		t.Errorf("EvaluateMutating() Allowed = false, want true")
 	}
 
-	if result.PatchedObject == nil {
-		t.Fatal("EvaluateMutating() should return patched object")
+	if result.Mutated != expectedMutated {
+		t.Errorf("EvaluateMutating() Mutated = %v, want %v", result.Mutated, expectedMutated)
 	}
 
-	wantObject := expectedObject
 	if !expectedMutated {
-		wantObject = object
+		if result.PatchedObject != nil {
+			t.Errorf("EvaluateMutating() returned patched object for unchanged object: %v", result.PatchedObject.Object)
+		}
+
+		return
 	}
 
-	if diff := cmp.Diff(wantObject.Object, result.PatchedObject.Object); diff != "" {
+	if result.PatchedObject == nil {
+		t.Fatal("EvaluateMutating() should return patched object")
+	}
+
+	if diff := cmp.Diff(expectedObject.Object, result.PatchedObject.Object); diff != "" {
 		t.Errorf("Patched object mismatch (-want +got):\n%s", diff)
 	}
 }
@@ -346,6 +353,53 @@ func TestEvaluateValidating_WithAuthorizer(t *testing.T) {
 	}
 }
 
+func TestEvaluateValidating_AuthorizerErrorsAndReasons(t *testing.T) {
+	t.Parallel()
+
+	// Each check is costly, so bind the decision once.
+	decision := func(expression string) string {
+		return `[authorizer.group("").resource("pods").namespace(object.metadata.namespace).check("create")].all(d, ` + expression + `)`
+	}
+
+	tests := []struct {
+		name       string
+		config     AuthorizationMockConfig
+		expression string
+	}{
+		{
+			name:       "error",
+			config:     AuthorizationMockConfig{Resource: "pods", Verb: "create", Error: "webhook timeout"},
+			expression: decision(`d.errored() && d.error() == "webhook timeout" && !d.allowed()`),
+		},
+		{
+			name:       "error with decision",
+			config:     AuthorizationMockConfig{Resource: "pods", Verb: "create", Decision: "allow", Error: "partial failure"},
+			expression: decision(`d.errored() && d.allowed()`),
+		},
+		{
+			name:       "custom reason",
+			config:     AuthorizationMockConfig{Resource: "pods", Verb: "create", Decision: "allow", Reason: `RBAC: allowed by ClusterRoleBinding "admins"`},
+			expression: decision(`d.reason() == 'RBAC: allowed by ClusterRoleBinding "admins"' && !d.errored()`),
+		},
+		{
+			name:       "default reason",
+			config:     AuthorizationMockConfig{Resource: "pods", Verb: "create", Decision: "deny"},
+			expression: decision(`d.reason() == "mock decision" && d.error() == "" && !d.allowed()`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := makeValidatingPolicy("", tc.expression, "unexpected authorizer decision")
+			auth := NewMockAuthorizerFromConfig([]AuthorizationMockConfig{tc.config})
+
+			runValidatingTest(t, policy, makePodObject("test-pod", "default"), auth, "user", nil, true, "")
+		})
+	}
+}
+
 func runValidatingTest(t *testing.T, policy *admissionregv1.ValidatingAdmissionPolicy, object *unstructured.Unstructured, auth *MockAuthorizer, username string, groups []string, expectAllowed bool, expectMessage string) {
 	t.Helper()
 
@@ -363,7 +417,7 @@ func runValidatingTest(t *testing.T, policy *admissionregv1.ValidatingAdmissionP
 
 	userInfo := MockUserInfo(username, groups)
 
-	result, err := evaluator.EvaluateValidating(policy, nil, request, object, nil, nil, nil, auth, userInfo)
+	result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: policy, Request: request, Object: object, Authorizer: auth, UserInfo: userInfo})
 	if err != nil {
 		t.Fatalf("EvaluateValidating() error = %v", err)
 	}
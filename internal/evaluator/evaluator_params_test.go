@@ -330,7 +330,7 @@ func TestEvaluateMutating_WithParams(t *testing.T) {
 				Operation: admissionv1.Create,
 			}
 
-			result, err := evaluator.EvaluateMutating(tc.policy, nil, request, tc.object, nil, tc.params, nil, nil, nil)
+			result, err := evaluator.EvaluateMutating(MutatingInput{Policy: tc.policy, Request: request, Object: tc.object, Params: tc.params})
 			if err != nil {
 				t.Fatalf("EvaluateMutating() error = %v", err)
 			}
@@ -683,7 +683,7 @@ func TestEvaluateValidating_WithParams(t *testing.T) {
 				Operation: admissionv1.Create,
 			}
 
-			result, err := evaluator.EvaluateValidating(tc.policy, nil, request, tc.object, nil, tc.params, nil, nil, nil)
+			result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: tc.policy, Request: request, Object: tc.object, Params: tc.params})
 			if err != nil {
 				t.Fatalf("EvaluateValidating() error = %v", err)
 			}
@@ -33,6 +33,849 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestEvaluator_Timings(t *testing.T) {
+	t.Parallel()
+
+	plain, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := plain.evaluateExpression("1 + 1", nil); err != nil {
+		t.Fatalf("evaluateExpression() error = %v", err)
+	}
+
+	if got := plain.Timings(); got != nil {
+		t.Errorf("Timings() without WithTimings = %v, want nil", got)
+	}
+
+	timed, err := New(WithTimings())
+	if err != nil {
+		t.Fatalf("New(WithTimings()) error = %v", err)
+	}
+
+	for range 3 {
+		if _, err := timed.evaluateExpression("1 + 1", nil); err != nil {
+			t.Fatalf("evaluateExpression() error = %v", err)
+		}
+	}
+
+	if _, err := timed.evaluateExpression("true", nil); err != nil {
+		t.Fatalf("evaluateExpression() error = %v", err)
+	}
+
+	timings := timed.Timings()
+	if len(timings) != 2 {
+		t.Fatalf("Timings() returned %d entries, want 2", len(timings))
+	}
+
+	if timings[0].Expression != "1 + 1" || timings[0].Count != 3 {
+		t.Errorf("Timings()[0] = %+v, want expression %q with count 3", timings[0], "1 + 1")
+	}
+
+	if timings[1].Expression != "true" || timings[1].Count != 1 {
+		t.Errorf("Timings()[1] = %+v, want expression %q with count 1", timings[1], "true")
+	}
+
+	timed.ResetTimings()
+
+	if got := timed.Timings(); len(got) != 0 {
+		t.Errorf("Timings() after ResetTimings = %v, want empty", got)
+	}
+}
+
+func TestEvaluateMutating_CreateMissingParents(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionv1beta1.MutatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-policy"},
+		Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+			Mutations: []admissionv1beta1.Mutation{
+				{
+					PatchType: admissionv1beta1.PatchTypeJSONPatch,
+					JSONPatch: &admissionv1beta1.JSONPatch{
+						Expression: `[JSONPatch{op: "add", path: "/metadata/labels/app.kubernetes.io~1name", value: "web"}]`,
+					},
+				},
+			},
+		},
+	}
+
+	newObject := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]any{"name": "test-pod"},
+			},
+		}
+	}
+
+	strict, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := strict.EvaluateMutating(MutatingInput{Policy: policy, Object: newObject()}); err == nil {
+		t.Error("EvaluateMutating() without WithCreateMissingParents error = nil, want patch error")
+	}
+
+	lenient, err := New(WithCreateMissingParents())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	object := newObject()
+
+	result, err := lenient.EvaluateMutating(MutatingInput{Policy: policy, Object: object})
+	if err != nil {
+		t.Fatalf("EvaluateMutating() error = %v", err)
+	}
+
+	want := map[string]any{"app.kubernetes.io/name": "web"}
+	if diff := cmp.Diff(want, result.PatchedObject.Object["metadata"].(map[string]any)["labels"]); diff != "" {
+		t.Errorf("labels mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"created missing parent map /metadata/labels for JSONPatch add"}, result.Notes); diff != "" {
+		t.Errorf("Notes mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, ok := object.Object["metadata"].(map[string]any)["labels"]; ok {
+		t.Error("EvaluateMutating() modified the input object")
+	}
+}
+
+func TestEvaluateValidating_PolicyNameAuditAnnotationKeys(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "track-owner"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			AuditAnnotations: []admissionregv1.AuditAnnotation{
+				{Key: "owner", ValueExpression: "'platform'"},
+			},
+			Validations: []admissionregv1.Validation{{Expression: "true"}},
+		},
+	}
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "test-pod"},
+	}}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string
+	}{
+		{
+			name: "bare keys by default",
+			want: map[string]string{"owner": "platform"},
+		},
+		{
+			name: "policy name prefix",
+			opts: []Option{WithPolicyNameAuditAnnotationKeys()},
+			want: map[string]string{"track-owner/owner": "platform"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluator, err := New(tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: policy, Object: object})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, result.AuditAnnotations); diff != "" {
+				t.Errorf("AuditAnnotations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestEvaluateValidating_AuditOnlyPolicy pins down that a policy without
+// validations always allows, and still records its audit annotations when its
+// match conditions match.
+func TestEvaluateValidating_AuditOnlyPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "track-team"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			MatchConditions: []admissionregv1.MatchCondition{
+				{Name: "has-team", Expression: "has(object.metadata.labels) && 'team' in object.metadata.labels"},
+			},
+			AuditAnnotations: []admissionregv1.AuditAnnotation{
+				{Key: "team", ValueExpression: "object.metadata.labels.team"},
+			},
+		},
+	}
+	deny := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        "track-team",
+			ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Deny},
+		},
+	}
+
+	pod := func(labels map[string]any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "web", "labels": labels},
+		}}
+	}
+
+	tests := []struct {
+		name            string
+		binding         *admissionregv1.ValidatingAdmissionPolicyBinding
+		object          *unstructured.Unstructured
+		wantAnnotations map[string]string
+		wantSkipReason  string
+	}{
+		{
+			name:            "matching records annotations",
+			object:          pod(map[string]any{"team": "payments"}),
+			wantAnnotations: map[string]string{"team": "payments"},
+		},
+		{
+			name:            "deny binding still allows",
+			binding:         deny,
+			object:          pod(map[string]any{"team": "payments"}),
+			wantAnnotations: map[string]string{"team": "payments"},
+		},
+		{
+			name:           "not matching records nothing",
+			object:         pod(map[string]any{"app": "web"}),
+			wantSkipReason: skipPolicyMatchConditions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluator, err := New()
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{
+				Policy:  policy,
+				Binding: tt.binding,
+				Request: &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+				Object:  tt.object,
+			})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if !result.Allowed || result.FailedValidation != nil || len(result.Warnings) > 0 {
+				t.Errorf("EvaluateValidating() = %+v, want allowed without failure or warnings", result)
+			}
+
+			if diff := cmp.Diff(tt.wantAnnotations, result.AuditAnnotations); diff != "" {
+				t.Errorf("AuditAnnotations mismatch (-want +got):\n%s", diff)
+			}
+
+			if result.SkipReason != tt.wantSkipReason {
+				t.Errorf("SkipReason = %q, want %q", result.SkipReason, tt.wantSkipReason)
+			}
+		})
+	}
+}
+
+// TestEvaluateValidating_WarningsWithAuditAnnotations pins down that a
+// request allowed despite failing validations under Warn carries both their
+// warnings and the policy's audit annotations.
+func TestEvaluateValidating_WarningsWithAuditAnnotations(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-hygiene"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{
+				{Expression: "has(object.metadata.labels) && 'team' in object.metadata.labels", Message: "team label is required"},
+				{Expression: "'owner' in object.metadata.labels", Message: "owner label is recommended"},
+				{Expression: "'cost-center' in object.metadata.labels", Message: "cost-center label is recommended"},
+			},
+			AuditAnnotations: []admissionregv1.AuditAnnotation{
+				{Key: "team", ValueExpression: "object.metadata.labels.team"},
+			},
+		},
+	}
+	binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        "team-hygiene",
+			ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Warn},
+		},
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "settings", "labels": map[string]any{"team": "payments"}},
+	}}
+
+	evaluator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	result, err := evaluator.EvaluateValidating(ValidatingInput{
+		Policy:  policy,
+		Binding: binding,
+		Request: &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+		Object:  object,
+	})
+	if err != nil {
+		t.Fatalf("EvaluateValidating() error = %v", err)
+	}
+
+	if !result.Allowed {
+		t.Fatalf("EvaluateValidating() Allowed = false, want true (message %q)", result.Message)
+	}
+
+	if diff := cmp.Diff([]string{"owner label is recommended", "cost-center label is recommended"}, result.Warnings); diff != "" {
+		t.Errorf("Warnings mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(map[string]string{"team": "payments"}, result.AuditAnnotations); diff != "" {
+		t.Errorf("AuditAnnotations mismatch (-want +got):\n%s", diff)
+	}
+
+	if result.FailedValidation == nil || result.FailedValidation.Index != 1 {
+		t.Errorf("FailedValidation = %+v, want the first failing validation, 1", result.FailedValidation)
+	}
+}
+
+// TestEvaluateValidating_WarningsWithDeny checks that a denied result carries
+// the warnings of every failing validation, as the API server responds. The
+// API server rejects bindings with both Warn and Deny; kat reports them at load
+// time and still evaluates them.
+func TestEvaluateValidating_WarningsWithDeny(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-hygiene"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{
+				{Expression: "object.metadata.name != 'settings'", Message: "settings is a reserved name"},
+				{Expression: "'owner' in object.metadata.labels", Message: "owner label is recommended"},
+				{Expression: "object.metadata.annotations.missing == 'x'", Message: "missing annotation errors after the denial"},
+			},
+		},
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "settings", "labels": map[string]any{"team": "payments"}},
+	}}
+
+	tests := []struct {
+		name         string
+		actions      []admissionregv1.ValidationAction
+		wantAllowed  bool
+		wantMessage  string
+		wantWarnings []string
+	}{
+		{
+			name:         "warn and deny",
+			actions:      []admissionregv1.ValidationAction{admissionregv1.Warn, admissionregv1.Deny},
+			wantMessage:  "settings is a reserved name",
+			wantWarnings: []string{"settings is a reserved name", "owner label is recommended"},
+		},
+		{
+			name:        "deny",
+			actions:     []admissionregv1.ValidationAction{admissionregv1.Deny},
+			wantMessage: "settings is a reserved name",
+		},
+		{
+			name:         "audit and warn",
+			actions:      []admissionregv1.ValidationAction{admissionregv1.Audit, admissionregv1.Warn},
+			wantAllowed:  true,
+			wantWarnings: []string{"settings is a reserved name", "owner label is recommended"},
+		},
+	}
+
+	evaluator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+				Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+					PolicyName:        "team-hygiene",
+					ValidationActions: tt.actions,
+				},
+			}
+
+			// Drop the erroring validation where nothing denies before it.
+			testPolicy := policy.DeepCopy()
+			if tt.wantAllowed {
+				testPolicy.Spec.Validations = testPolicy.Spec.Validations[:2]
+			}
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{
+				Policy:  testPolicy,
+				Binding: binding,
+				Request: &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+				Object:  object,
+			})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if result.Allowed != tt.wantAllowed || result.Message != tt.wantMessage {
+				t.Errorf("EvaluateValidating() Allowed = %v, Message = %q, want %v, %q",
+					result.Allowed, result.Message, tt.wantAllowed, tt.wantMessage)
+			}
+
+			if diff := cmp.Diff(tt.wantWarnings, result.Warnings); diff != "" {
+				t.Errorf("Warnings mismatch (-want +got):\n%s", diff)
+			}
+
+			if result.FailedValidation == nil || result.FailedValidation.Index != 0 {
+				t.Errorf("FailedValidation = %+v, want the first failing validation, 0", result.FailedValidation)
+			}
+		})
+	}
+}
+
+func TestEvaluateValidating_OldObjectNullOnCreate(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "old-object-null"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{{
+				Expression: "request.operation == 'CREATE' ? oldObject == null : oldObject != null",
+			}},
+		},
+	}
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "test-pod"},
+	}}
+
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		oldObject *unstructured.Unstructured
+	}{
+		{
+			name:      "create binds null",
+			operation: admissionv1.Create,
+		},
+		{
+			name:      "update binds old object",
+			operation: admissionv1.Update,
+			oldObject: object.DeepCopy(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluator, err := New()
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{
+				Policy:    policy,
+				Request:   &admissionv1.AdmissionRequest{Operation: tt.operation},
+				Object:    object,
+				OldObject: tt.oldObject,
+			})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if !result.Allowed {
+				t.Errorf("Allowed = false, message %q", result.Message)
+			}
+		})
+	}
+}
+
+// TestEvaluateValidating_ObjectOnDelete pins down that object is null on
+// DELETE, as on the API server, unless WithDeleteObjectFallback binds it to
+// the deleted object.
+func TestEvaluateValidating_ObjectOnDelete(t *testing.T) {
+	t.Parallel()
+
+	deleted := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]any{"name": "payments", "labels": map[string]any{"protect": "true"}},
+	}}
+
+	const (
+		oldObjectStyle = "oldObject.metadata.labels.protect != 'true'"
+		nullGuardStyle = "object != null || oldObject.metadata.labels.protect != 'true'"
+		objectStyle    = "object.metadata.labels.protect != 'true'"
+	)
+
+	tests := []struct {
+		name        string
+		expression  string
+		fallback    bool
+		wantAllowed bool
+		wantErr     bool
+	}{
+		{name: "oldObject style denies", expression: oldObjectStyle},
+		{name: "oldObject style denies with fallback", expression: oldObjectStyle, fallback: true},
+		{name: "null guard denies", expression: nullGuardStyle},
+		{name: "null guard allows with fallback", expression: nullGuardStyle, fallback: true, wantAllowed: true},
+		{name: "object style fails", expression: objectStyle, wantErr: true},
+		{name: "object style denies with fallback", expression: objectStyle, fallback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []Option
+			if tt.fallback {
+				opts = append(opts, WithDeleteObjectFallback())
+			}
+
+			evaluator, err := New(opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			policy := &admissionregv1.ValidatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "delete-protection"},
+				Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+					Validations: []admissionregv1.Validation{{Expression: tt.expression, Message: "protected"}},
+				},
+			}
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{
+				Policy:    policy,
+				Request:   &admissionv1.AdmissionRequest{Operation: admissionv1.Delete, Name: "payments"},
+				OldObject: deleted,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateValidating() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if result.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v (message %q)", result.Allowed, tt.wantAllowed, result.Message)
+			}
+		})
+	}
+}
+
+func TestEvaluateTest_RequireGold(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionv1beta1.MutatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "noop"},
+		Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+			Mutations: []admissionv1beta1.Mutation{
+				{
+					PatchType: admissionv1beta1.PatchTypeJSONPatch,
+					JSONPatch: &admissionv1beta1.JSONPatch{Expression: `[]`},
+				},
+			},
+		},
+	}
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "test-pod"},
+	}}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		expected   *unstructured.Unstructured
+		patch      []PatchOperation
+		wantPassed bool
+	}{
+		{name: "missing gold passes by default", wantPassed: true},
+		{name: "missing gold fails when required", opts: []Option{WithRequireGold()}},
+		{name: "gold present when required", opts: []Option{WithRequireGold()}, expected: object, wantPassed: true},
+		{name: "patch present when required", opts: []Option{WithRequireGold()}, patch: []PatchOperation{}, wantPassed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluator, err := New(tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result := evaluator.EvaluateTest(policy, nil, nil, nil, MockTestCase{
+				Object:         object,
+				ExpectAllowed:  true,
+				ExpectedObject: tt.expected,
+				ExpectedPatch:  tt.patch,
+			})
+			if result.Passed != tt.wantPassed {
+				t.Errorf("EvaluateTest() passed = %v, want %v (message: %s)", result.Passed, tt.wantPassed, result.Message)
+			}
+		})
+	}
+}
+
+func TestEvaluateMutating_SkippedLeavesObjectUnchanged(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		matchConditions []admissionv1beta1.MatchCondition
+		patch           string
+		wantSkipReason  string
+		wantPatch       bool
+	}{
+		{
+			name:            "match conditions not met",
+			matchConditions: []admissionv1beta1.MatchCondition{{Name: "never", Expression: "false"}},
+			patch:           `[JSONPatch{op: "add", path: "/metadata/labels/team", value: "platform"}]`,
+			wantSkipReason:  skipPolicyMatchConditions,
+		},
+		{
+			name:      "mutation to the same value",
+			patch:     `[JSONPatch{op: "replace", path: "/metadata/labels/app", value: "web"}]`,
+			wantPatch: true,
+		},
+		{
+			name:  "empty patch list",
+			patch: `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := &admissionv1beta1.MutatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "add-team"},
+				Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+					MatchConditions: tt.matchConditions,
+					Mutations: []admissionv1beta1.Mutation{{
+						PatchType: admissionv1beta1.PatchTypeJSONPatch,
+						JSONPatch: &admissionv1beta1.JSONPatch{Expression: tt.patch},
+					}},
+				},
+			}
+
+			object := &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]any{
+					"name":   "web",
+					"labels": map[string]any{"app": "web"},
+				},
+				"spec": map[string]any{
+					"replicas": int64(2),
+					"template": map[string]any{
+						"spec": map[string]any{
+							"containers": []any{map[string]any{"name": "web", "image": "nginx:1.27"}},
+						},
+					},
+				},
+			}}
+			input := object.DeepCopy()
+
+			evaluator, err := New()
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result, err := evaluator.EvaluateMutating(MutatingInput{Policy: policy, Object: object})
+			if err != nil {
+				t.Fatalf("EvaluateMutating() error = %v", err)
+			}
+
+			if result.PatchedObject != nil || result.Mutated || result.SkipReason != tt.wantSkipReason {
+				t.Errorf("EvaluateMutating() = (patched %v, mutated %v, skip %q), want no mutation and skip %q",
+					result.PatchedObject, result.Mutated, result.SkipReason, tt.wantSkipReason)
+			}
+
+			if gotPatch := len(result.Patch) > 0; gotPatch != tt.wantPatch {
+				t.Errorf("EvaluateMutating() patch = %v, want operations %v", result.Patch, tt.wantPatch)
+			}
+
+			testResult := evaluator.EvaluateTest(policy, nil, nil, nil, MockTestCase{
+				Object:         object,
+				ExpectAllowed:  true,
+				ExpectedObject: input,
+			})
+			if !testResult.Passed {
+				t.Errorf("EvaluateTest() failed: %s", testResult.Message)
+			}
+
+			if diff := cmp.Diff(input.Object, testResult.Actual.Object.Object); diff != "" {
+				t.Errorf("Actual object mismatch (-want +got):\n%s", diff)
+			}
+
+			if testResult.Actual.Object == object {
+				t.Error("Actual object is the test input, want a copy")
+			}
+
+			if diff := cmp.Diff(input.Object, object.Object); diff != "" {
+				t.Errorf("Input object changed (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateTest_ExpectedPatch(t *testing.T) {
+	t.Parallel()
+
+	jsonPatchPolicy := &admissionv1beta1.MutatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "add-labels"},
+		Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+			Mutations: []admissionv1beta1.Mutation{
+				{
+					PatchType: admissionv1beta1.PatchTypeJSONPatch,
+					JSONPatch: &admissionv1beta1.JSONPatch{Expression: `[
+						JSONPatch{op: "add", path: "/metadata/labels", value: {}},
+						JSONPatch{op: "add", path: "/metadata/labels/team", value: "platform"}
+					]`},
+				},
+				{
+					PatchType: admissionv1beta1.PatchTypeJSONPatch,
+					JSONPatch: &admissionv1beta1.JSONPatch{Expression: `[JSONPatch{op: "add", path: "/spec/replicas", value: 2}]`},
+				},
+			},
+		},
+	}
+	applyPolicy := &admissionv1beta1.MutatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "apply-labels"},
+		Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+			Mutations: []admissionv1beta1.Mutation{
+				{
+					PatchType: admissionv1beta1.PatchTypeApplyConfiguration,
+					ApplyConfiguration: &admissionv1beta1.ApplyConfiguration{
+						Expression: `Object{metadata: Object.metadata{labels: {"team": "platform"}}}`,
+					},
+				},
+			},
+		},
+	}
+
+	jsonPatchOnly := func(name, expression string) *admissionv1beta1.MutatingAdmissionPolicy {
+		return &admissionv1beta1.MutatingAdmissionPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+				Mutations: []admissionv1beta1.Mutation{{
+					PatchType: admissionv1beta1.PatchTypeJSONPatch,
+					JSONPatch: &admissionv1beta1.JSONPatch{Expression: expression},
+				}},
+			},
+		}
+	}
+	emptyPatchPolicy := jsonPatchOnly("empty-patch", `[]`)
+	noOpPatchPolicy := jsonPatchOnly("no-op-patch", `[JSONPatch{op: "replace", path: "/metadata/name", value: "web"}]`)
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web"},
+		"spec":       map[string]any{},
+	}}
+
+	ops := []PatchOperation{
+		{Op: "add", Path: "/metadata/labels", Value: map[string]any{}},
+		{Op: "add", Path: "/metadata/labels/team", Value: "platform"},
+		{Op: "add", Path: "/spec/replicas", Value: 2},
+	}
+
+	tests := []struct {
+		name        string
+		policy      *admissionv1beta1.MutatingAdmissionPolicy
+		patch       []PatchOperation
+		wantPassed  bool
+		wantMessage string
+	}{
+		{name: "no expected patch", policy: jsonPatchPolicy, wantPassed: true},
+		{name: "matching operations", policy: jsonPatchPolicy, patch: ops, wantPassed: true},
+		{
+			name:        "operations in a different order",
+			policy:      jsonPatchPolicy,
+			patch:       []PatchOperation{ops[0], ops[2], ops[1]},
+			wantMessage: "patch does not match expected",
+		},
+		{
+			name:        "unexpected operations",
+			policy:      jsonPatchPolicy,
+			patch:       []PatchOperation{},
+			wantMessage: "patch does not match expected",
+		},
+		{
+			name:       "empty patch list asserts no operations",
+			policy:     emptyPatchPolicy,
+			patch:      []PatchOperation{},
+			wantPassed: true,
+		},
+		{
+			// The object is unchanged, but the operation is still emitted.
+			name:        "no-op operation is not an empty patch",
+			policy:      noOpPatchPolicy,
+			patch:       []PatchOperation{},
+			wantMessage: "patch does not match expected",
+		},
+		{
+			name:        "apply configuration has no operations",
+			policy:      applyPolicy,
+			patch:       []PatchOperation{},
+			wantMessage: "ApplyConfiguration",
+		},
+	}
+
+	evaluator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := evaluator.EvaluateTest(tt.policy, nil, nil, nil, MockTestCase{
+				Request:       &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+				Object:        object,
+				ExpectAllowed: true,
+				ExpectedPatch: tt.patch,
+			})
+			if result.Passed != tt.wantPassed {
+				t.Errorf("EvaluateTest() passed = %v, want %v (message: %s)", result.Passed, tt.wantPassed, result.Message)
+			}
+
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("EvaluateTest() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
 //nolint:gocognit,funlen,cyclop,maintidx // Test function
 func TestEvaluateMutating(t *testing.T) {
 	t.Parallel()
@@ -45,6 +888,7 @@ func TestEvaluateMutating(t *testing.T) {
 		expectedMutated bool // true if object should be mutated
 		expectedObject  *unstructured.Unstructured
 		expectedError   bool
+		expectedErrorIs error
 	}{
 		{
 			name: "add label when match conditions satisfied",
@@ -124,6 +968,42 @@ func TestEvaluateMutating(t *testing.T) {
 			},
 			expectedMutated: false,
 		},
+		{
+			name: "matched policy with empty patch does not report a patched object",
+			policy: &admissionv1beta1.MutatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy"},
+				Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+					Mutations: []admissionv1beta1.Mutation{
+						{
+							PatchType: admissionv1beta1.PatchTypeJSONPatch,
+							JSONPatch: &admissionv1beta1.JSONPatch{
+								Expression: `[]`,
+							},
+						},
+						{
+							PatchType: admissionv1beta1.PatchTypeJSONPatch,
+							JSONPatch: &admissionv1beta1.JSONPatch{
+								Expression: `[JSONPatch{op: "replace", path: "/spec/replicas", value: 3}]`,
+							},
+						},
+					},
+				},
+			},
+			object: &unstructured.Unstructured{
+				Object: map[string]any{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]any{
+						"name":      "test-deployment",
+						"namespace": "default",
+					},
+					"spec": map[string]any{
+						"replicas": int64(3),
+					},
+				},
+			},
+			expectedMutated: false,
+		},
 		{
 			name: "add multiple labels",
 			policy: &admissionv1beta1.MutatingAdmissionPolicy{
@@ -427,7 +1307,7 @@ func TestEvaluateMutating(t *testing.T) {
 							PatchType: admissionv1beta1.PatchTypeJSONPatch,
 							JSONPatch: &admissionv1beta1.JSONPatch{
 								// Adding a complex container with nested env vars
-								Expression: `[JSONPatch{op: "add", path: "/spec/containers", value: [{"name": "nginx", "image": "nginx:latest", "env": [{"name": "ENV", "value": "prod"}], "ports": [{"containerPort": 80}]}]}]`,
+								Expression: `[JSONPatch{op: "add", path: "/spec/containers", value: [{"name": dyn("nginx"), "image": dyn("nginx:latest"), "env": dyn([{"name": "ENV", "value": "prod"}]), "ports": dyn([{"containerPort": 80}])}]}]`,
 							},
 						},
 					},
@@ -522,7 +1402,7 @@ func TestEvaluateMutating(t *testing.T) {
 							PatchType: admissionv1beta1.PatchTypeApplyConfiguration,
 							ApplyConfiguration: &admissionv1beta1.ApplyConfiguration{
 								// Complex nested structure with arrays and objects
-								Expression: `Object{spec: Object.spec{template: Object.spec.template{spec: Object.spec.template.spec{containers: [{"name": "sidecar", "image": "sidecar:v1", "env": [{"name": "MODE", "value": "inject"}]}]}}}}`,
+								Expression: `Object{spec: Object.spec{template: Object.spec.template{spec: Object.spec.template.spec{containers: [Object.spec.template.spec.containers{name: "sidecar", image: "sidecar:v1", env: [Object.spec.template.spec.containers.env{name: "MODE", value: "inject"}]}]}}}}`,
 							},
 						},
 					},
@@ -565,7 +1445,7 @@ func TestEvaluateMutating(t *testing.T) {
 							PatchType: admissionv1beta1.PatchTypeApplyConfiguration,
 							ApplyConfiguration: &admissionv1beta1.ApplyConfiguration{
 								// Add volumes array with complex nested structure
-								Expression: `Object{spec: {"volumes": [{"name": "config", "configMap": {"name": "app-config", "items": [{"key": "config.yaml", "path": "config.yaml"}]}}]}}`,
+								Expression: `Object{spec: Object.spec{volumes: [Object.spec.volumes{name: "config", configMap: Object.spec.volumes.configMap{name: "app-config", items: [Object.spec.volumes.configMap.items{key: "config.yaml", path: "config.yaml"}]}}]}}`,
 							},
 						},
 					},
@@ -633,7 +1513,7 @@ func TestEvaluateMutating(t *testing.T) {
 								// Multiple patches in one mutation with nested values
 								Expression: `[
 									JSONPatch{op: "add", path: "/metadata/labels", value: {"tier": "backend", "version": "v1"}},
-									JSONPatch{op: "add", path: "/spec/strategy", value: {"type": "RollingUpdate", "rollingUpdate": {"maxSurge": "25%", "maxUnavailable": 0}}}
+									JSONPatch{op: "add", path: "/spec/strategy", value: Object.spec.strategy{type: "RollingUpdate", rollingUpdate: Object.spec.strategy.rollingUpdate{maxSurge: "25%", maxUnavailable: 0}}}
 								]`,
 							},
 						},
@@ -652,30 +1532,86 @@ func TestEvaluateMutating(t *testing.T) {
 					},
 				},
 			},
-			expectedMutated: true,
-			expectedObject: &unstructured.Unstructured{
+			expectedMutated: true,
+			expectedObject: &unstructured.Unstructured{
+				Object: map[string]any{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]any{
+						"name": "test-deployment",
+						"labels": map[string]any{
+							"tier":    "backend",
+							"version": "v1",
+						},
+					},
+					"spec": map[string]any{
+						"replicas": float64(3),
+						"strategy": map[string]any{
+							"type": "RollingUpdate",
+							"rollingUpdate": map[string]any{
+								"maxSurge":       "25%",
+								"maxUnavailable": float64(0),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "JSONPatch renaming the object is rejected",
+			policy: &admissionv1beta1.MutatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy"},
+				Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+					Mutations: []admissionv1beta1.Mutation{
+						{
+							PatchType: admissionv1beta1.PatchTypeJSONPatch,
+							JSONPatch: &admissionv1beta1.JSONPatch{
+								Expression: `[JSONPatch{op: "replace", path: "/metadata/name", value: "renamed"}]`,
+							},
+						},
+					},
+				},
+			},
+			object: &unstructured.Unstructured{
+				Object: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]any{
+						"name":      "test-pod",
+						"namespace": "default",
+					},
+				},
+			},
+			expectedError:   true,
+			expectedErrorIs: errImmutableFieldChanged,
+		},
+		{
+			name: "ApplyConfiguration changing the namespace is rejected",
+			policy: &admissionv1beta1.MutatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy"},
+				Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+					Mutations: []admissionv1beta1.Mutation{
+						{
+							PatchType: admissionv1beta1.PatchTypeApplyConfiguration,
+							ApplyConfiguration: &admissionv1beta1.ApplyConfiguration{
+								Expression: `Object{metadata: Object.metadata{namespace: "other"}}`,
+							},
+						},
+					},
+				},
+			},
+			object: &unstructured.Unstructured{
 				Object: map[string]any{
-					"apiVersion": "apps/v1",
-					"kind":       "Deployment",
+					"apiVersion": "v1",
+					"kind":       "Pod",
 					"metadata": map[string]any{
-						"name": "test-deployment",
-						"labels": map[string]any{
-							"tier":    "backend",
-							"version": "v1",
-						},
-					},
-					"spec": map[string]any{
-						"replicas": float64(3),
-						"strategy": map[string]any{
-							"type": "RollingUpdate",
-							"rollingUpdate": map[string]any{
-								"maxSurge":       "25%",
-								"maxUnavailable": float64(0),
-							},
-						},
+						"name":      "test-pod",
+						"namespace": "default",
 					},
 				},
 			},
+			expectedError:   true,
+			expectedErrorIs: errImmutableFieldChanged,
 		},
 	}
 
@@ -696,13 +1632,17 @@ func TestEvaluateMutating(t *testing.T) {
 				Operation: admissionv1.Create,
 			}
 
-			result, err := evaluator.EvaluateMutating(tc.policy, nil, request, tc.object, tc.oldObject, nil, nil, nil, nil)
+			result, err := evaluator.EvaluateMutating(MutatingInput{Policy: tc.policy, Request: request, Object: tc.object, OldObject: tc.oldObject})
 
 			if tc.expectedError {
 				if err == nil {
 					t.Fatalf("EvaluateMutating() expected error but got none")
 				}
 
+				if tc.expectedErrorIs != nil && !errors.Is(err, tc.expectedErrorIs) {
+					t.Errorf("EvaluateMutating() error = %v, want %v", err, tc.expectedErrorIs)
+				}
+
 				return
 			}
 
@@ -714,6 +1654,10 @@ func TestEvaluateMutating(t *testing.T) {
 				t.Errorf("EvaluateMutating() Allowed = false, want true")
 			}
 
+			if result.Mutated != tc.expectedMutated {
+				t.Errorf("EvaluateMutating() Mutated = %v, want %v", result.Mutated, tc.expectedMutated)
+			}
+
 			if !tc.expectedMutated {
 				if result.PatchedObject != nil {
 					t.Error("EvaluateMutating() should not return patched object when no mutation applied")
@@ -930,7 +1874,7 @@ func TestEvaluateValidating(t *testing.T) {
 				Operation: admissionv1.Create,
 			}
 
-			result, err := evaluator.EvaluateValidating(tc.policy, nil, request, tc.object, tc.oldObject, nil, nil, nil, nil)
+			result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: tc.policy, Request: request, Object: tc.object, OldObject: tc.oldObject})
 			if err != nil {
 				t.Fatalf("EvaluateValidating() error = %v", err)
 			}
@@ -1001,7 +1945,7 @@ func TestEvaluateValidating_MultipleValidations(t *testing.T) {
 		Operation: admissionv1.Create,
 	}
 
-	result, err := evaluator.EvaluateValidating(policy, nil, request, object, nil, nil, nil, nil, nil)
+	result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: policy, Request: request, Object: object})
 	if err != nil {
 		t.Fatalf("EvaluateValidating() error = %v", err)
 	}
@@ -1350,7 +2294,7 @@ func TestRealPolicy_RequireOwnerLabel(t *testing.T) {
 				t.Fatalf("New() error = %v", err)
 			}
 
-			result, err := evaluator.EvaluateValidating(policy, nil, request, tc.object, nil, nil, nil, nil, nil)
+			result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: policy, Request: request, Object: tc.object})
 			if err != nil {
 				t.Fatalf("EvaluateValidating() error = %v", err)
 			}
@@ -1489,7 +2433,7 @@ object.spec.containers.all(container,
 				t.Fatalf("New() error = %v", err)
 			}
 
-			result, err := evaluator.EvaluateValidating(policy, nil, request, tc.object, nil, nil, nil, nil, nil)
+			result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: policy, Request: request, Object: tc.object})
 			if err != nil {
 				t.Fatalf("EvaluateValidating() error = %v", err)
 			}
@@ -1513,12 +2457,16 @@ type MockTestCase struct {
 	NamespaceObj           *unstructured.Unstructured
 	UserInfo               user.Info
 	ExpectAllowed          bool
+	ExpectAnyAllowed       bool
 	ExpectMessage          string
 	ExpectWarnings         []string
 	ExpectAuditAnnotations map[string]string
 	ExpectedObject         *unstructured.Unstructured
 	Error                  error
 	Authorizer             []AuthorizationMockConfig
+	RecordedResponse       *RecordedResponse
+	ExpectedPatch          []PatchOperation
+	ExpectStatus           *metav1.Status
 }
 
 func (m MockTestCase) GetRequest() *admissionv1.AdmissionRequest     { return m.Request }
@@ -1528,12 +2476,16 @@ func (m MockTestCase) GetParams() *unstructured.Unstructured         { return m.
 func (m MockTestCase) GetNamespaceObj() *unstructured.Unstructured   { return m.NamespaceObj }
 func (m MockTestCase) GetUserInfo() user.Info                        { return m.UserInfo }
 func (m MockTestCase) GetExpectAllowed() bool                        { return m.ExpectAllowed }
+func (m MockTestCase) GetExpectAnyAllowed() bool                     { return m.ExpectAnyAllowed }
 func (m MockTestCase) GetExpectMessage() string                      { return m.ExpectMessage }
 func (m MockTestCase) GetExpectWarnings() []string                   { return m.ExpectWarnings }
 func (m MockTestCase) GetExpectAuditAnnotations() map[string]string  { return m.ExpectAuditAnnotations }
 func (m MockTestCase) GetExpectedObject() *unstructured.Unstructured { return m.ExpectedObject }
 func (m MockTestCase) GetError() error                               { return m.Error }
 func (m MockTestCase) GetAuthorizer() []AuthorizationMockConfig      { return m.Authorizer }
+func (m MockTestCase) GetRecordedResponse() *RecordedResponse        { return m.RecordedResponse }
+func (m MockTestCase) GetExpectedPatch() []PatchOperation            { return m.ExpectedPatch }
+func (m MockTestCase) GetExpectStatus() *metav1.Status               { return m.ExpectStatus }
 
 //nolint:funlen,maintidx // Test function
 func TestEvaluator_EvaluateTest(t *testing.T) {
@@ -1696,6 +2648,65 @@ func TestEvaluator_EvaluateTest(t *testing.T) {
 			wantPassed:  false,
 			wantMessage: "mutated object does not match expected",
 		},
+		{
+			name: "Chain - Mutated Object Passes Validation",
+			mutatingPolicy: &admissionv1beta1.MutatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "add-label"},
+				Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+					Mutations: []admissionv1beta1.Mutation{
+						{
+							PatchType: admissionv1beta1.PatchTypeJSONPatch,
+							JSONPatch: &admissionv1beta1.JSONPatch{
+								Expression: `[JSONPatch{op: "add", path: "/metadata/labels", value: {"foo": "bar"}}]`,
+							},
+						},
+					},
+				},
+			},
+			validatingPolicy: &admissionregv1.ValidatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "require-label"},
+				Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+					Validations: []admissionregv1.Validation{
+						{Expression: `has(object.metadata.labels) && object.metadata.labels.foo == "bar"`, Message: "missing foo"},
+					},
+				},
+			},
+			testCase: MockTestCase{
+				Object:        validPod,
+				ExpectAllowed: true,
+			},
+			wantPassed: true,
+		},
+		{
+			name: "Chain - Mutated Object Denied",
+			mutatingPolicy: &admissionv1beta1.MutatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "add-label"},
+				Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+					Mutations: []admissionv1beta1.Mutation{
+						{
+							PatchType: admissionv1beta1.PatchTypeJSONPatch,
+							JSONPatch: &admissionv1beta1.JSONPatch{
+								Expression: `[JSONPatch{op: "add", path: "/metadata/labels", value: {"foo": "baz"}}]`,
+							},
+						},
+					},
+				},
+			},
+			validatingPolicy: &admissionregv1.ValidatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "require-label"},
+				Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+					Validations: []admissionregv1.Validation{
+						{Expression: `has(object.metadata.labels) && object.metadata.labels.foo == "bar"`, Message: "missing foo"},
+					},
+				},
+			},
+			testCase: MockTestCase{
+				Object:        validPod,
+				ExpectAllowed: true,
+			},
+			wantPassed:  false,
+			wantMessage: "expected allowed=true, got allowed=false",
+		},
 		{
 			name: "Audit Annotation Mismatch",
 			validatingPolicy: &admissionregv1.ValidatingAdmissionPolicy{
@@ -1796,10 +2807,55 @@ func TestEvaluator_EvaluateTest(t *testing.T) {
 			testCase: MockTestCase{
 				Object:         validPod,
 				ExpectAllowed:  true,
-				ExpectWarnings: []string{"warn1", "extra_warning"},
+				ExpectWarnings: []string{"warn1", "warn2", "extra_warning"},
 			},
 			wantPassed:  false,
-			wantMessage: "expected 2 warnings, got 1",
+			wantMessage: "expected 3 warnings, got 2",
+		},
+		{
+			name: "Warnings Of All Failed Validations",
+			validatingPolicy: &admissionregv1.ValidatingAdmissionPolicy{
+				Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+					Validations: []admissionregv1.Validation{
+						{Expression: "false", Message: "warn1"},
+						{Expression: "true", Message: "not reported"},
+						{Expression: "false", Message: "warn2"},
+					},
+				},
+			},
+			validatingBinding: &admissionregv1.ValidatingAdmissionPolicyBinding{
+				Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+					ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Warn},
+				},
+			},
+			testCase: MockTestCase{
+				Object:         validPod,
+				ExpectAllowed:  true,
+				ExpectWarnings: []string{"warn1", "warn2"},
+			},
+			wantPassed: true,
+		},
+		{
+			name: "Identical Warnings Deduplicated",
+			validatingPolicy: &admissionregv1.ValidatingAdmissionPolicy{
+				Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+					Validations: []admissionregv1.Validation{
+						{Expression: "false", Message: "image is deprecated"},
+						{Expression: "false", MessageExpression: "'image is ' + 'deprecated'"},
+					},
+				},
+			},
+			validatingBinding: &admissionregv1.ValidatingAdmissionPolicyBinding{
+				Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+					ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Warn},
+				},
+			},
+			testCase: MockTestCase{
+				Object:         validPod,
+				ExpectAllowed:  true,
+				ExpectWarnings: []string{"image is deprecated"},
+			},
+			wantPassed: true,
 		},
 		{
 			name: "Mutating Policy Evaluation Error",
@@ -1842,3 +2898,248 @@ func TestEvaluator_EvaluateTest(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateTest_PolicyIdentity(t *testing.T) {
+	t.Parallel()
+
+	evaluator, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create evaluator: %v", err)
+	}
+
+	pod := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": "test-pod", "namespace": "default"},
+		},
+	}
+
+	mutatingPolicy := &admissionv1beta1.MutatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "add-label"},
+	}
+	mutatingBinding := &admissionv1beta1.MutatingAdmissionPolicyBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "add-label-binding"},
+	}
+	validatingPolicy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "require-label"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{{Expression: "true"}},
+		},
+	}
+	validatingBinding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "require-label-binding"},
+	}
+
+	tests := []struct {
+		name              string
+		mutatingPolicy    *admissionv1beta1.MutatingAdmissionPolicy
+		mutatingBinding   *admissionv1beta1.MutatingAdmissionPolicyBinding
+		validatingPolicy  *admissionregv1.ValidatingAdmissionPolicy
+		validatingBinding *admissionregv1.ValidatingAdmissionPolicyBinding
+		want              [3]string
+	}{
+		{
+			name:              "validating",
+			validatingPolicy:  validatingPolicy,
+			validatingBinding: validatingBinding,
+			want:              [3]string{"require-label", PolicyKindValidating, "require-label-binding"},
+		},
+		{
+			name:             "validating without binding",
+			validatingPolicy: validatingPolicy,
+			want:             [3]string{"require-label", PolicyKindValidating, ""},
+		},
+		{
+			name:            "mutating",
+			mutatingPolicy:  mutatingPolicy,
+			mutatingBinding: mutatingBinding,
+			want:            [3]string{"add-label", PolicyKindMutating, "add-label-binding"},
+		},
+		{
+			name:              "chained reports the mutating policy",
+			mutatingPolicy:    mutatingPolicy,
+			mutatingBinding:   mutatingBinding,
+			validatingPolicy:  validatingPolicy,
+			validatingBinding: validatingBinding,
+			want:              [3]string{"add-label", PolicyKindMutating, "add-label-binding"},
+		},
+		{
+			name: "no policy",
+			want: [3]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := evaluator.EvaluateTest(tc.mutatingPolicy, tc.mutatingBinding, tc.validatingPolicy, tc.validatingBinding,
+				MockTestCase{Object: pod, ExpectAllowed: true})
+
+			got := [3]string{result.PolicyName, result.PolicyKind, result.BindingName}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("EvaluateTest() policy, kind, binding mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateTest_AssertNoUnexpectedWarnings(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{
+				{Expression: "false", Message: "deprecated"},
+			},
+		},
+	}
+	binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+			ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Warn},
+		},
+	}
+	object := &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "test-pod"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		warnings    []string
+		wantPassed  bool
+		wantMessage string
+	}{
+		{
+			name:       "default ignores unexpected warnings",
+			wantPassed: true,
+		},
+		{
+			name:        "strict fails on unexpected warnings",
+			opts:        []Option{WithAssertNoUnexpectedWarnings()},
+			wantPassed:  false,
+			wantMessage: `expected no warnings, got ["deprecated"]`,
+		},
+		{
+			name:       "strict passes when warnings are expected",
+			opts:       []Option{WithAssertNoUnexpectedWarnings()},
+			warnings:   []string{"deprecated"},
+			wantPassed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := New(tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result := e.EvaluateTest(nil, nil, policy, binding, MockTestCase{
+				Object:         object,
+				ExpectAllowed:  true,
+				ExpectWarnings: tt.warnings,
+			})
+
+			if result.Passed != tt.wantPassed {
+				t.Errorf("EvaluateTest() Passed = %v, want %v (message: %s)", result.Passed, tt.wantPassed, result.Message)
+			}
+
+			if result.Message != tt.wantMessage {
+				t.Errorf("EvaluateTest() Message = %q, want %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestEvaluateTest_AnyAllowed(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "track-owner"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			AuditAnnotations: []admissionregv1.AuditAnnotation{
+				{Key: "owner", ValueExpression: "'platform'"},
+			},
+			Validations: []admissionregv1.Validation{
+				{Expression: "object.metadata.name != 'blocked'", Message: "blocked"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		podName     string
+		anyAllowed  bool
+		annotations map[string]string
+		wantPassed  bool
+		wantMessage string
+	}{
+		{
+			name:        "denial passes",
+			podName:     "blocked",
+			anyAllowed:  true,
+			annotations: map[string]string{"owner": "platform"},
+			wantPassed:  true,
+		},
+		{
+			name:        "allowed passes",
+			podName:     "web",
+			anyAllowed:  true,
+			annotations: map[string]string{"owner": "platform"},
+			wantPassed:  true,
+		},
+		{
+			name:        "wrong annotation fails",
+			podName:     "blocked",
+			anyAllowed:  true,
+			annotations: map[string]string{"owner": "team-a"},
+			wantPassed:  false,
+			wantMessage: "audit annotations do not match expected:",
+		},
+		{
+			name:        "denial fails without any",
+			podName:     "blocked",
+			annotations: map[string]string{"owner": "platform"},
+			wantPassed:  false,
+			wantMessage: "expected allowed=true, got allowed=false: validation[0] failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := New()
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result := e.EvaluateTest(nil, nil, policy, nil, MockTestCase{
+				Object: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata":   map[string]any{"name": tt.podName},
+				}},
+				ExpectAllowed:          true,
+				ExpectAnyAllowed:       tt.anyAllowed,
+				ExpectAuditAnnotations: tt.annotations,
+			})
+
+			if result.Passed != tt.wantPassed {
+				t.Errorf("EvaluateTest() Passed = %v, want %v (message: %s)", result.Passed, tt.wantPassed, result.Message)
+			}
+
+			if !strings.HasPrefix(result.Message, tt.wantMessage) || (tt.wantMessage == "") != (result.Message == "") {
+				t.Errorf("EvaluateTest() Message = %q, want prefix %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package evaluator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/google/cel-go/cel"
+	plugin "k8s.io/apiserver/pkg/admission/plugin/cel"
+)
+
+var (
+	errExtraVariableName      = errors.New("not a valid CEL identifier")
+	errExtraVariableReserved  = errors.New("clashes with a standard variable")
+	errExtraVariableDuplicate = errors.New("declared more than once")
+)
+
+//nolint:gochecknoglobals // Compiled once
+var celIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ExtraVariable is a CEL variable declared in addition to the standard ones,
+// for API servers that bind variables of their own.
+type ExtraVariable struct {
+	Name string
+	// Expression is evaluated for every request, after the standard variables
+	// and the preceding extra variables are bound. When empty, Value is bound.
+	Expression string
+	Value      any
+}
+
+// WithExtraVariables declares vars in the CEL environment and binds them when
+// evaluating every expression, in order.
+func WithExtraVariables(vars ...ExtraVariable) Option {
+	return func(e *Evaluator) {
+		e.extraVariables = append(e.extraVariables, vars...)
+	}
+}
+
+// extraVariableOptions validates the extra variables and returns their declarations.
+func extraVariableOptions(vars []ExtraVariable) ([]cel.EnvOption, error) {
+	reserved := map[string]bool{
+		plugin.ObjectVarName:     true,
+		plugin.OldObjectVarName:  true,
+		plugin.RequestVarName:    true,
+		plugin.ParamsVarName:     true,
+		plugin.NamespaceVarName:  true,
+		plugin.AuthorizerVarName: true,
+		"variables":              true,
+	}
+	declared := map[string]bool{}
+
+	opts := make([]cel.EnvOption, 0, len(vars))
+
+	for _, v := range vars {
+		switch {
+		case !celIdentifier.MatchString(v.Name):
+			return nil, fmt.Errorf("extra variable %q: %w", v.Name, errExtraVariableName)
+		case reserved[v.Name]:
+			return nil, fmt.Errorf("extra variable %q: %w", v.Name, errExtraVariableReserved)
+		case declared[v.Name]:
+			return nil, fmt.Errorf("extra variable %q: %w", v.Name, errExtraVariableDuplicate)
+		}
+
+		declared[v.Name] = true
+
+		opts = append(opts, cel.Variable(v.Name, cel.DynType))
+	}
+
+	return opts, nil
+}
+
+// compileExtraVariables reports syntax and type errors of the extra variable
+// expressions when the evaluator is created rather than on first use.
+func (e *Evaluator) compileExtraVariables() error {
+	for _, v := range e.extraVariables {
+		if v.Expression == "" {
+			continue
+		}
+
+		if _, issues := e.env.Compile(v.Expression); issues != nil && issues.Err() != nil {
+			return fmt.Errorf("extra variable %s: compile expression: %w", v.Name, issues.Err())
+		}
+	}
+
+	return nil
+}
+
+// bindExtraVariables adds the extra variables to vars.
+func (e *Evaluator) bindExtraVariables(vars map[string]any) error {
+	for _, v := range e.extraVariables {
+		if v.Expression == "" {
+			vars[v.Name] = v.Value
+
+			continue
+		}
+
+		value, err := e.evaluateExpressionRaw(v.Expression, vars)
+		if err != nil {
+			return fmt.Errorf("extra variable %s: %w", v.Name, err)
+		}
+
+		vars[v.Name] = value
+	}
+
+	return nil
+}
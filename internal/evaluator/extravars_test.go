@@ -0,0 +1,68 @@
+package evaluator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNew_ExtraVariables(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		vars    []ExtraVariable
+		wantErr error
+	}{
+		{name: "expression and value", vars: []ExtraVariable{{Name: "tenant", Expression: "'acme'"}, {Name: "cluster", Value: map[string]any{}}}},
+		{name: "invalid name", vars: []ExtraVariable{{Name: "my-var", Value: 1}}, wantErr: errExtraVariableName},
+		{name: "standard variable", vars: []ExtraVariable{{Name: "object", Value: 1}}, wantErr: errExtraVariableReserved},
+		{name: "policy variables", vars: []ExtraVariable{{Name: "variables", Value: 1}}, wantErr: errExtraVariableReserved},
+		{name: "duplicate", vars: []ExtraVariable{{Name: "x", Value: 1}, {Name: "x", Value: 2}}, wantErr: errExtraVariableDuplicate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(WithExtraVariables(tt.vars...))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_ExtraVariableSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(WithExtraVariables(ExtraVariable{Name: "x", Expression: "1 +"})); err == nil {
+		t.Error("New() error = nil, want compile error")
+	}
+}
+
+func TestBindExtraVariables(t *testing.T) {
+	t.Parallel()
+
+	e, err := New(WithExtraVariables(
+		ExtraVariable{Name: "cluster", Value: map[string]any{"region": "eu-west-1"}},
+		ExtraVariable{Name: "tenant", Expression: "object.metadata.namespace.split('-')[0]"},
+		ExtraVariable{Name: "owner", Expression: "tenant + '@' + cluster.region"},
+	))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	vars := map[string]any{"object": map[string]any{"metadata": map[string]any{"namespace": "acme-web"}}}
+	if err := e.bindExtraVariables(vars); err != nil {
+		t.Fatalf("bindExtraVariables() error = %v", err)
+	}
+
+	got, err := e.evaluateExpression("owner", vars)
+	if err != nil {
+		t.Fatalf("evaluateExpression() error = %v", err)
+	}
+
+	if got != "acme@eu-west-1" {
+		t.Errorf("owner = %v, want %q", got, "acme@eu-west-1")
+	}
+}
@@ -0,0 +1,135 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// managedDeployment returns a deployment with managedFields, converted from
+// the typed object as client-go users would.
+func managedDeployment(t *testing.T, managers ...string) *unstructured.Unstructured {
+	t.Helper()
+
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	}
+
+	for _, manager := range managers {
+		deployment.ManagedFields = append(deployment.ManagedFields, metav1.ManagedFieldsEntry{
+			Manager:    manager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "apps/v1",
+			Time:       &metav1.Time{Time: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)},
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		})
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deployment)
+	if err != nil {
+		t.Fatalf("ToUnstructured() error = %v", err)
+	}
+
+	return &unstructured.Unstructured{Object: object}
+}
+
+func TestEvaluateValidating_ManagedFields(t *testing.T) {
+	t.Parallel()
+
+	eval, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "single-manager"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{
+				{
+					Expression: `object.metadata.managedFields.size() <= 1`,
+					MessageExpression: `'managed by ' + object.metadata.managedFields.map(f, f.manager).join(', ') +
+						' since ' + object.metadata.managedFields[0].time`,
+				},
+				{Expression: `object.metadata.managedFields.all(f, 'f:spec' in f.fieldsV1)`},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		managers    []string
+		wantAllowed bool
+		wantMessage string
+	}{
+		{name: "single manager", managers: []string{"kubectl"}, wantAllowed: true},
+		{name: "two managers", managers: []string{"kubectl", "argocd"}, wantMessage: "managed by kubectl, argocd since 2025-01-15T10:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := &admissionv1.AdmissionRequest{Operation: admissionv1.Create}
+
+			result, err := eval.EvaluateValidating(ValidatingInput{Policy: policy, Request: request, Object: managedDeployment(t, tt.managers...)})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if result.Allowed != tt.wantAllowed || result.Message != tt.wantMessage {
+				t.Errorf("EvaluateValidating() = (%v, %q), want (%v, %q)", result.Allowed, result.Message, tt.wantAllowed, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestEvaluateMutating_ManagedFields(t *testing.T) {
+	t.Parallel()
+
+	eval, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	policy := &admissionv1beta1.MutatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "label-managers"},
+		Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+			Mutations: []admissionv1beta1.Mutation{{
+				PatchType: admissionv1beta1.PatchTypeJSONPatch,
+				JSONPatch: &admissionv1beta1.JSONPatch{
+					Expression: `[JSONPatch{op: "add", path: "/metadata/labels", value: {"managers": string(object.metadata.managedFields.size())}}]`,
+				},
+			}},
+		},
+	}
+
+	object := managedDeployment(t, "kubectl")
+	request := &admissionv1.AdmissionRequest{Operation: admissionv1.Create}
+
+	result, err := eval.EvaluateMutating(MutatingInput{Policy: policy, Request: request, Object: object})
+	if err != nil {
+		t.Fatalf("EvaluateMutating() error = %v", err)
+	}
+
+	if got := result.PatchedObject.GetLabels()["managers"]; got != "1" {
+		t.Errorf("managers label = %q, want %q", got, "1")
+	}
+
+	// The patch round-trips the object through JSON; the managedFields must
+	// survive it unchanged.
+	want := object.GetManagedFields()
+	got := result.PatchedObject.GetManagedFields()
+
+	if len(got) != 1 || !got[0].Time.Equal(want[0].Time) || string(got[0].FieldsV1.Raw) != string(want[0].FieldsV1.Raw) {
+		t.Errorf("managedFields after mutation = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package evaluator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+)
+
+// Reasons reported when a policy is not applied to a request.
+const (
+	skipBindingNamespaceSelector = "binding namespaceSelector does not match"
+	skipBindingResourceRules     = "binding resourceRules do not match"
+	skipBindingExcludeRules      = "binding excludeResourceRules match"
+	skipPolicyResourceRules      = "policy matchConstraints resourceRules do not match"
+	skipPolicyExcludeRules       = "policy matchConstraints excludeResourceRules match"
+	skipPolicyMatchConditions    = "policy matchConditions do not match"
+)
+
+// matchesPolicyResourceRules checks the request against a validating policy's
+// matchConstraints resourceRules and excludeResourceRules. It returns an empty
+// string when the policy applies, or the reason it does not.
+func matchesPolicyResourceRules(policy *admissionregv1.ValidatingAdmissionPolicy, request *admissionv1.AdmissionRequest) string {
+	if policy == nil || policy.Spec.MatchConstraints == nil {
+		return ""
+	}
+
+	return matchResourceRules(
+		policy.Spec.MatchConstraints.ResourceRules,
+		policy.Spec.MatchConstraints.ExcludeResourceRules,
+		request,
+		skipPolicyResourceRules,
+		skipPolicyExcludeRules,
+	)
+}
+
+// matchesPolicyResourceRulesV1Beta1 is matchesPolicyResourceRules for mutating policies.
+func matchesPolicyResourceRulesV1Beta1(policy *admissionv1beta1.MutatingAdmissionPolicy, request *admissionv1.AdmissionRequest) string {
+	if policy == nil || policy.Spec.MatchConstraints == nil {
+		return ""
+	}
+
+	return matchResourceRules(
+		namedRulesV1Beta1(policy.Spec.MatchConstraints.ResourceRules),
+		namedRulesV1Beta1(policy.Spec.MatchConstraints.ExcludeResourceRules),
+		request,
+		skipPolicyResourceRules,
+		skipPolicyExcludeRules,
+	)
+}
+
+// matchesBindingResourceRules checks the request against a validating binding's
+// resourceRules and excludeResourceRules. It returns an empty string when the
+// binding applies, or the reason it does not.
+func matchesBindingResourceRules(binding *admissionregv1.ValidatingAdmissionPolicyBinding, request *admissionv1.AdmissionRequest) string {
+	if binding == nil || binding.Spec.MatchResources == nil {
+		return ""
+	}
+
+	return matchResourceRules(
+		binding.Spec.MatchResources.ResourceRules,
+		binding.Spec.MatchResources.ExcludeResourceRules,
+		request,
+		skipBindingResourceRules,
+		skipBindingExcludeRules,
+	)
+}
+
+// matchesBindingResourceRulesV1Beta1 is matchesBindingResourceRules for mutating bindings.
+func matchesBindingResourceRulesV1Beta1(binding *admissionv1beta1.MutatingAdmissionPolicyBinding, request *admissionv1.AdmissionRequest) string {
+	if binding == nil || binding.Spec.MatchResources == nil {
+		return ""
+	}
+
+	return matchResourceRules(
+		namedRulesV1Beta1(binding.Spec.MatchResources.ResourceRules),
+		namedRulesV1Beta1(binding.Spec.MatchResources.ExcludeResourceRules),
+		request,
+		skipBindingResourceRules,
+		skipBindingExcludeRules,
+	)
+}
+
+func matchResourceRules(include, exclude []admissionregv1.NamedRuleWithOperations, request *admissionv1.AdmissionRequest, skipInclude, skipExclude string) string {
+	// Without a request there is nothing to match against.
+	if request == nil {
+		return ""
+	}
+
+	if len(include) > 0 && !slices.ContainsFunc(include, func(rule admissionregv1.NamedRuleWithOperations) bool {
+		return matchesNamedRule(rule, request)
+	}) {
+		return fmt.Sprintf("%s %s", skipInclude, describeRequest(request))
+	}
+
+	if slices.ContainsFunc(exclude, func(rule admissionregv1.NamedRuleWithOperations) bool {
+		return matchesNamedRule(rule, request)
+	}) {
+		return fmt.Sprintf("%s %s", skipExclude, describeRequest(request))
+	}
+
+	return ""
+}
+
+func namedRulesV1Beta1(rules []admissionv1beta1.NamedRuleWithOperations) []admissionregv1.NamedRuleWithOperations {
+	result := make([]admissionregv1.NamedRuleWithOperations, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, admissionregv1.NamedRuleWithOperations{
+			ResourceNames:      rule.ResourceNames,
+			RuleWithOperations: rule.RuleWithOperations,
+		})
+	}
+
+	return result
+}
+
+// matchesNamedRule reports whether the request matches the rule and, when the
+// rule lists resourceNames, whether the request's name is one of them.
+func matchesNamedRule(rule admissionregv1.NamedRuleWithOperations, request *admissionv1.AdmissionRequest) bool {
+	if len(rule.ResourceNames) > 0 && !slices.Contains(rule.ResourceNames, request.Name) {
+		return false
+	}
+
+	return matchesRule(rule.RuleWithOperations, request)
+}
+
+// matchesRule reports whether the request's operation, group/version/resource
+// and scope match the rule, following the API server's rule matching.
+func matchesRule(rule admissionregv1.RuleWithOperations, request *admissionv1.AdmissionRequest) bool {
+	return matchesOperation(rule.Operations, request.Operation) &&
+		matchesValue(rule.APIGroups, request.Resource.Group) &&
+		matchesValue(rule.APIVersions, request.Resource.Version) &&
+		matchesResource(rule.Resources, request.Resource.Resource, request.SubResource) &&
+		matchesScope(rule.Scope, request.Namespace)
+}
+
+// matchesScope compares the rule's scope with the request's. Without discovery
+// the request is taken to be namespaced when it has a namespace.
+func matchesScope(scope *admissionregv1.ScopeType, namespace string) bool {
+	if scope == nil {
+		return true
+	}
+
+	switch *scope {
+	case admissionregv1.NamespacedScope:
+		return namespace != ""
+	case admissionregv1.ClusterScope:
+		return namespace == ""
+	case admissionregv1.AllScopes:
+		return true
+	default:
+		return true
+	}
+}
+
+func matchesOperation(operations []admissionregv1.OperationType, operation admissionv1.Operation) bool {
+	for _, op := range operations {
+		if op == admissionregv1.OperationAll || string(op) == string(operation) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesValue(values []string, value string) bool {
+	return slices.Contains(values, "*") || slices.Contains(values, value)
+}
+
+func matchesResource(resources []string, resource, subResource string) bool {
+	for _, candidate := range resources {
+		res, sub, hasSub := strings.Cut(candidate, "/")
+
+		switch {
+		case candidate == "*/*":
+			return true
+		case !hasSub:
+			if subResource == "" && (res == "*" || res == resource) {
+				return true
+			}
+		case (res == "*" || res == resource) && (sub == "*" || sub == subResource):
+			return true
+		}
+	}
+
+	return false
+}
+
+// describeRequest formats the request's resource and operation for skip reasons.
+func describeRequest(request *admissionv1.AdmissionRequest) string {
+	resource := request.Resource.Resource
+	if request.SubResource != "" {
+		resource += "/" + request.SubResource
+	}
+
+	group := request.Resource.Group
+	if group == "" {
+		group = "core"
+	}
+
+	return fmt.Sprintf("(%s %s/%s %s)", request.Operation, group, request.Resource.Version, resource)
+}
@@ -0,0 +1,442 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func deploymentRule(operations ...admissionregv1.OperationType) admissionregv1.NamedRuleWithOperations {
+	return admissionregv1.NamedRuleWithOperations{
+		RuleWithOperations: admissionregv1.RuleWithOperations{
+			Operations: operations,
+			Rule: admissionregv1.Rule{
+				APIGroups:   []string{"apps"},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"deployments"},
+			},
+		},
+	}
+}
+
+func scopedRule(scope admissionregv1.ScopeType) admissionregv1.RuleWithOperations {
+	return admissionregv1.RuleWithOperations{
+		Operations: []admissionregv1.OperationType{admissionregv1.OperationAll},
+		Rule: admissionregv1.Rule{
+			APIGroups:   []string{"*"},
+			APIVersions: []string{"*"},
+			Resources:   []string{"*"},
+			Scope:       &scope,
+		},
+	}
+}
+
+func TestMatchesRule(t *testing.T) {
+	t.Parallel()
+
+	rule := func(resources ...string) admissionregv1.RuleWithOperations {
+		return admissionregv1.RuleWithOperations{
+			Operations: []admissionregv1.OperationType{admissionregv1.OperationAll},
+			Rule: admissionregv1.Rule{
+				APIGroups:   []string{"*"},
+				APIVersions: []string{"*"},
+				Resources:   resources,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		rule        admissionregv1.RuleWithOperations
+		operation   admissionv1.Operation
+		resource    metav1.GroupVersionResource
+		subResource string
+		namespace   string
+		want        bool
+	}{
+		{
+			name:      "exact match",
+			rule:      deploymentRule(admissionregv1.Create).RuleWithOperations,
+			operation: admissionv1.Create,
+			resource:  metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			want:      true,
+		},
+		{
+			name:      "operation mismatch",
+			rule:      deploymentRule(admissionregv1.Create).RuleWithOperations,
+			operation: admissionv1.Update,
+			resource:  metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			want:      false,
+		},
+		{
+			name:      "group mismatch",
+			rule:      deploymentRule(admissionregv1.Create).RuleWithOperations,
+			operation: admissionv1.Create,
+			resource:  metav1.GroupVersionResource{Group: "", Version: "v1", Resource: "deployments"},
+			want:      false,
+		},
+		{
+			name:      "wildcard resource",
+			rule:      rule("*"),
+			operation: admissionv1.Delete,
+			resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			want:      true,
+		},
+		{
+			name:        "wildcard resource excludes subresources",
+			rule:        rule("*"),
+			operation:   admissionv1.Connect,
+			resource:    metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			subResource: "exec",
+			want:        false,
+		},
+		{
+			name:        "explicit subresource",
+			rule:        rule("pods/exec"),
+			operation:   admissionv1.Connect,
+			resource:    metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			subResource: "exec",
+			want:        true,
+		},
+		{
+			name:        "all resources and subresources",
+			rule:        rule("*/*"),
+			operation:   admissionv1.Connect,
+			resource:    metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			subResource: "attach",
+			want:        true,
+		},
+		{
+			name:      "namespaced scope matches namespaced request",
+			rule:      scopedRule(admissionregv1.NamespacedScope),
+			operation: admissionv1.Create,
+			resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			namespace: "default",
+			want:      true,
+		},
+		{
+			name:      "namespaced scope skips cluster request",
+			rule:      scopedRule(admissionregv1.NamespacedScope),
+			operation: admissionv1.Create,
+			resource:  metav1.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+			want:      false,
+		},
+		{
+			name:      "cluster scope skips namespaced request",
+			rule:      scopedRule(admissionregv1.ClusterScope),
+			operation: admissionv1.Create,
+			resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			namespace: "default",
+			want:      false,
+		},
+		{
+			name:      "all scopes match cluster request",
+			rule:      scopedRule(admissionregv1.AllScopes),
+			operation: admissionv1.Create,
+			resource:  metav1.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := &admissionv1.AdmissionRequest{
+				Operation:   tt.operation,
+				Resource:    tt.resource,
+				SubResource: tt.subResource,
+				Namespace:   tt.namespace,
+			}
+
+			if got := matchesRule(tt.rule, request); got != tt.want {
+				t.Errorf("matchesRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_BindingResourceRules(t *testing.T) {
+	t.Parallel()
+
+	evaluator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	podRequest := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+	}
+	deploymentRequest := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource:  metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{"metadata": map[string]any{"name": "test"}}}
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{{Expression: "false", Message: "denied"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		matchRes   *admissionregv1.MatchResources
+		request    *admissionv1.AdmissionRequest
+		wantAllow  bool
+		wantReason string
+	}{
+		{
+			name:      "no rules applies to everything",
+			request:   podRequest,
+			wantAllow: false,
+		},
+		{
+			name: "included resource",
+			matchRes: &admissionregv1.MatchResources{
+				ResourceRules: []admissionregv1.NamedRuleWithOperations{deploymentRule(admissionregv1.Create)},
+			},
+			request:   deploymentRequest,
+			wantAllow: false,
+		},
+		{
+			name: "resource outside rules is skipped",
+			matchRes: &admissionregv1.MatchResources{
+				ResourceRules: []admissionregv1.NamedRuleWithOperations{deploymentRule(admissionregv1.Create)},
+			},
+			request:    podRequest,
+			wantAllow:  true,
+			wantReason: skipBindingResourceRules,
+		},
+		{
+			name: "excluded resource is skipped",
+			matchRes: &admissionregv1.MatchResources{
+				ExcludeResourceRules: []admissionregv1.NamedRuleWithOperations{deploymentRule(admissionregv1.OperationAll)},
+			},
+			request:    deploymentRequest,
+			wantAllow:  true,
+			wantReason: skipBindingExcludeRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+				Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+					MatchResources:    tt.matchRes,
+					ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Deny},
+				},
+			}
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: policy, Binding: binding, Request: tt.request, Object: object})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if result.Allowed != tt.wantAllow {
+				t.Errorf("EvaluateValidating() Allowed = %v, want %v", result.Allowed, tt.wantAllow)
+			}
+
+			if tt.wantReason == "" && result.SkipReason != "" || !strings.HasPrefix(result.SkipReason, tt.wantReason) {
+				t.Errorf("EvaluateValidating() SkipReason = %q, want prefix %q", result.SkipReason, tt.wantReason)
+			}
+
+			mutBinding := &admissionv1beta1.MutatingAdmissionPolicyBinding{}
+			if tt.matchRes != nil {
+				mutBinding.Spec.MatchResources = &admissionv1beta1.MatchResources{
+					ResourceRules:        convertNamedRules(tt.matchRes.ResourceRules),
+					ExcludeResourceRules: convertNamedRules(tt.matchRes.ExcludeResourceRules),
+				}
+			}
+
+			// Mutating bindings share the same rule matching.
+			if got := matchesBindingResourceRulesV1Beta1(mutBinding, tt.request); got != result.SkipReason {
+				t.Errorf("matchesBindingResourceRulesV1Beta1() = %q, want %q", got, result.SkipReason)
+			}
+		})
+	}
+}
+
+func TestEvaluate_PolicyMatchConstraintsScope(t *testing.T) {
+	t.Parallel()
+
+	evaluator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			MatchConstraints: &admissionregv1.MatchResources{
+				ResourceRules: []admissionregv1.NamedRuleWithOperations{
+					{RuleWithOperations: scopedRule(admissionregv1.NamespacedScope)},
+				},
+			},
+			Validations: []admissionregv1.Validation{{Expression: "false", Message: "denied"}},
+		},
+	}
+	binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+			ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Deny},
+		},
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{"metadata": map[string]any{"name": "test"}}}
+
+	tests := []struct {
+		name       string
+		request    *admissionv1.AdmissionRequest
+		wantAllow  bool
+		wantReason string
+	}{
+		{
+			name: "pod in a namespace matches",
+			request: &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+				Namespace: "default",
+			},
+			wantAllow: false,
+		},
+		{
+			name: "cluster role does not match",
+			request: &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  metav1.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+			},
+			wantAllow:  true,
+			wantReason: skipPolicyResourceRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: policy, Binding: binding, Request: tt.request, Object: object})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if result.Allowed != tt.wantAllow {
+				t.Errorf("EvaluateValidating() Allowed = %v, want %v", result.Allowed, tt.wantAllow)
+			}
+
+			if !strings.HasPrefix(result.SkipReason, tt.wantReason) || (tt.wantReason == "" && result.SkipReason != "") {
+				t.Errorf("EvaluateValidating() SkipReason = %q, want prefix %q", result.SkipReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEvaluate_PolicyMatchConstraintsResourceNames(t *testing.T) {
+	t.Parallel()
+
+	evaluator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	rule := admissionregv1.NamedRuleWithOperations{
+		ResourceNames: []string{"app-config"},
+		RuleWithOperations: admissionregv1.RuleWithOperations{
+			Operations: []admissionregv1.OperationType{admissionregv1.Create, admissionregv1.Update},
+			Rule: admissionregv1.Rule{
+				APIGroups:   []string{""},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"configmaps"},
+			},
+		},
+	}
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			MatchConstraints: &admissionregv1.MatchResources{
+				ResourceRules: []admissionregv1.NamedRuleWithOperations{rule},
+			},
+			Validations: []admissionregv1.Validation{{Expression: "false", Message: "denied"}},
+		},
+	}
+	binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+			ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Deny},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		configMap  string
+		wantAllow  bool
+		wantReason string
+	}{
+		{
+			name:      "named config map matches",
+			configMap: "app-config",
+			wantAllow: false,
+		},
+		{
+			name:       "other config map is skipped",
+			configMap:  "other-config",
+			wantAllow:  true,
+			wantReason: skipPolicyResourceRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+				Name:      tt.configMap,
+				Namespace: "default",
+			}
+			object := &unstructured.Unstructured{Object: map[string]any{"metadata": map[string]any{"name": tt.configMap}}}
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{Policy: policy, Binding: binding, Request: request, Object: object})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if result.Allowed != tt.wantAllow {
+				t.Errorf("EvaluateValidating() Allowed = %v, want %v", result.Allowed, tt.wantAllow)
+			}
+
+			if !strings.HasPrefix(result.SkipReason, tt.wantReason) || (tt.wantReason == "" && result.SkipReason != "") {
+				t.Errorf("EvaluateValidating() SkipReason = %q, want prefix %q", result.SkipReason, tt.wantReason)
+			}
+
+			mutPolicy := &admissionv1beta1.MutatingAdmissionPolicy{
+				Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+					MatchConstraints: &admissionv1beta1.MatchResources{
+						ResourceRules: convertNamedRules(policy.Spec.MatchConstraints.ResourceRules),
+					},
+				},
+			}
+
+			// Mutating policies share the same rule matching.
+			if got := matchesPolicyResourceRulesV1Beta1(mutPolicy, request); got != result.SkipReason {
+				t.Errorf("matchesPolicyResourceRulesV1Beta1() = %q, want %q", got, result.SkipReason)
+			}
+		})
+	}
+}
+
+func convertNamedRules(rules []admissionregv1.NamedRuleWithOperations) []admissionv1beta1.NamedRuleWithOperations {
+	result := make([]admissionv1beta1.NamedRuleWithOperations, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, admissionv1beta1.NamedRuleWithOperations{
+			ResourceNames:      rule.ResourceNames,
+			RuleWithOperations: rule.RuleWithOperations,
+		})
+	}
+
+	return result
+}
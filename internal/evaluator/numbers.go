@@ -0,0 +1,36 @@
+package evaluator
+
+import "math"
+
+// celNumbers returns a copy of value with the whole numbers the JSON decoding
+// of test fixtures makes float64 as int64, the type the API server binds
+// integer fields such as spec.replicas as. Without it, expressions like
+// "%d".format([object.spec.replicas]) or object.spec.replicas + 1 fail with
+// double operands where they work on a cluster. Fractional numbers stay
+// float64.
+func celNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = celNumbers(item)
+		}
+
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = celNumbers(item)
+		}
+
+		return result
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCelNumbers(t *testing.T) {
+	t.Parallel()
+
+	object := map[string]any{
+		"spec": map[string]any{
+			"replicas": float64(5),
+			"ratio":    0.5,
+			"ports":    []any{float64(80), float64(443)},
+			"name":     "web",
+		},
+	}
+
+	want := map[string]any{
+		"spec": map[string]any{
+			"replicas": int64(5),
+			"ratio":    0.5,
+			"ports":    []any{int64(80), int64(443)},
+			"name":     "web",
+		},
+	}
+
+	if diff := cmp.Diff(want, celNumbers(object)); diff != "" {
+		t.Errorf("celNumbers() mismatch (-want +got):\n%s", diff)
+	}
+
+	if object["spec"].(map[string]any)["replicas"] != float64(5) {
+		t.Error("celNumbers() modified its argument")
+	}
+}
+
+// TestEvaluateValidating_FormatNumbers pins down that messageExpressions
+// formatting fixture numbers with the format library produce the message the
+// API server does.
+func TestEvaluateValidating_FormatNumbers(t *testing.T) {
+	t.Parallel()
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web"},
+		"spec":       map[string]any{"replicas": float64(5), "cpu": 1.5},
+	}}
+	params := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "ReplicaLimit",
+		"metadata":   map[string]any{"name": "limits"},
+		"max":        float64(3),
+	}}
+
+	tests := []struct {
+		name              string
+		messageExpression string
+		want              string
+	}{
+		{
+			name:              "decimal",
+			messageExpression: `"found %d replicas, max %d".format([object.spec.replicas, params.max])`,
+			want:              "found 5 replicas, max 3",
+		},
+		{
+			name:              "string",
+			messageExpression: `"found %s replicas".format([object.spec.replicas])`,
+			want:              "found 5 replicas",
+		},
+		{
+			name:              "arithmetic",
+			messageExpression: `"scale down by %d".format([object.spec.replicas - params.max])`,
+			want:              "scale down by 2",
+		},
+		{
+			name:              "fractional stays double",
+			messageExpression: `"cpu %.1f".format([object.spec.cpu])`,
+			want:              "cpu 1.5",
+		},
+		{
+			name:              "trailing number",
+			messageExpression: `"max replicas is " + string(params.max)`,
+			want:              "max replicas is 3",
+		},
+	}
+
+	evaluator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := &admissionregv1.ValidatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "replica-limit"},
+				Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+					Validations: []admissionregv1.Validation{{
+						Expression:        "object.spec.replicas <= params.max",
+						MessageExpression: tt.messageExpression,
+					}},
+				},
+			}
+
+			result, err := evaluator.EvaluateValidating(ValidatingInput{
+				Policy:  policy,
+				Request: &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+				Object:  object,
+				Params:  params,
+			})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if result.Message != tt.want {
+				t.Errorf("Message = %q, want %q", result.Message, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// PatchOperation is an RFC 6902 JSON Patch operation. Value is always
+// compared, so that an explicit null differs from a missing value.
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value"`
+}
+
+// patchOperations converts applied JSON Patch operations to PatchOperations.
+func patchOperations(patch jsonpatch.Patch) ([]PatchOperation, error) {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("marshal patch: %w", err)
+	}
+
+	var ops []PatchOperation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("unmarshal patch: %w", err)
+	}
+
+	return ops, nil
+}
+
+// checkPatch compares the expected JSON Patch operations with those emitted by
+// the policy, in order. It returns an empty string when they match or no
+// patch is expected.
+func checkPatch(expected []PatchOperation, actual *TestOutcome) string {
+	if expected == nil {
+		return ""
+	}
+
+	if actual.AppliedConfiguration {
+		return "expected JSON Patch operations, but the policy uses ApplyConfiguration mutations, which produce none"
+	}
+
+	// Compare as JSON so that numbers decoded from YAML and CEL compare equal.
+	expectedJSON, errExpected := json.MarshalIndent(expected, "", "  ")
+	actualJSON, errActual := json.MarshalIndent(nonNilPatch(actual.Patch), "", "  ")
+
+	if errExpected != nil || errActual != nil {
+		return fmt.Sprintf("compare patch: %v", errorsOr(errExpected, errActual))
+	}
+
+	if string(expectedJSON) == string(actualJSON) {
+		return ""
+	}
+
+	return "patch does not match expected:\n" + getDiff(string(expectedJSON)+"\n", string(actualJSON)+"\n")
+}
+
+func nonNilPatch(patch []PatchOperation) []PatchOperation {
+	if patch == nil {
+		return []PatchOperation{}
+	}
+
+	return patch
+}
+
+func errorsOr(a, b error) error {
+	if a != nil {
+		return a
+	}
+
+	return b
+}
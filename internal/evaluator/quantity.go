@@ -0,0 +1,136 @@
+package evaluator
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// quantityMaps are the keys of maps whose values are resource quantities, such
+// as resources.requests, ResourceQuota hard and used, and node capacity.
+//
+//nolint:gochecknoglobals // Static lookup table
+var quantityMaps = map[string]bool{
+	"requests":    true,
+	"limits":      true,
+	"hard":        true,
+	"used":        true,
+	"capacity":    true,
+	"allocatable": true,
+	"overhead":    true,
+}
+
+// quantityFields are the keys of single resource quantity values.
+//
+//nolint:gochecknoglobals // Static lookup table
+var quantityFields = map[string]bool{
+	"sizeLimit": true,
+}
+
+// WithQuantityComparison compares resource quantities in expected objects
+// semantically, so that an expected "1024Mi" matches an actual "1Gi" and "0.5"
+// CPU matches "500m".
+func WithQuantityComparison() Option {
+	return func(e *Evaluator) {
+		e.compareQuantities = true
+	}
+}
+
+// equateQuantities returns a copy of expected in which every quantity equal to
+// the quantity at the same path of actual is replaced by the actual value, and
+// the paths of the replaced values. Values that do not parse as quantities are
+// left alone and so still compare textually.
+func equateQuantities(expected, actual map[string]any) (map[string]any, []string) {
+	var equated []string
+
+	out, _ := equateQuantitiesAt(expected, actual, "", false, &equated).(map[string]any)
+	slices.Sort(equated)
+
+	return out, equated
+}
+
+func equateQuantitiesAt(expected, actual any, path string, inQuantityMap bool, equated *[]string) any {
+	switch exp := expected.(type) {
+	case map[string]any:
+		act, _ := actual.(map[string]any)
+		out := make(map[string]any, len(exp))
+
+		for key, value := range exp {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+
+			actValue, ok := act[key]
+			if ok && (inQuantityMap || quantityFields[key]) && !reflect.DeepEqual(value, actValue) && quantitiesEqual(value, actValue) {
+				out[key] = actValue
+				*equated = append(*equated, fmt.Sprintf("%s (%v == %v)", keyPath, value, actValue))
+
+				continue
+			}
+
+			out[key] = equateQuantitiesAt(value, actValue, keyPath, quantityMaps[key], equated)
+		}
+
+		return out
+	case []any:
+		act, _ := actual.([]any)
+		out := make([]any, len(exp))
+
+		for i, value := range exp {
+			var actValue any
+			if i < len(act) {
+				actValue = act[i]
+			}
+
+			out[i] = equateQuantitiesAt(value, actValue, fmt.Sprintf("%s[%d]", path, i), false, equated)
+		}
+
+		return out
+	default:
+		return expected
+	}
+}
+
+// quantitiesEqual reports whether a and b both parse as resource quantities of
+// the same value.
+func quantitiesEqual(a, b any) bool {
+	qa, ok := parseQuantity(a)
+	if !ok {
+		return false
+	}
+
+	qb, ok := parseQuantity(b)
+	if !ok {
+		return false
+	}
+
+	return qa.Cmp(qb) == 0
+}
+
+// parseQuantity parses a quantity written as a string or, as YAML allows for
+// e.g. "cpu: 1", as a number.
+func parseQuantity(value any) (resource.Quantity, bool) {
+	var s string
+
+	switch v := value.(type) {
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return resource.Quantity{}, false
+	}
+
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+
+	return q, true
+}
@@ -0,0 +1,167 @@
+package evaluator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestEquateQuantities(t *testing.T) {
+	t.Parallel()
+
+	resources := func(limits map[string]any) map[string]any {
+		return map[string]any{"spec": map[string]any{"containers": []any{
+			map[string]any{"name": "app", "resources": map[string]any{"limits": limits}},
+		}}}
+	}
+
+	tests := []struct {
+		name        string
+		expected    map[string]any
+		actual      map[string]any
+		wantEqual   bool
+		wantEquated []string
+	}{
+		{
+			name:        "binary and decimal suffixes",
+			expected:    resources(map[string]any{"memory": "1024Mi"}),
+			actual:      resources(map[string]any{"memory": "1Gi"}),
+			wantEqual:   true,
+			wantEquated: []string{"spec.containers[0].resources.limits.memory (1024Mi == 1Gi)"},
+		},
+		{
+			name:        "millicores",
+			expected:    resources(map[string]any{"cpu": "0.5"}),
+			actual:      resources(map[string]any{"cpu": "500m"}),
+			wantEqual:   true,
+			wantEquated: []string{"spec.containers[0].resources.limits.cpu (0.5 == 500m)"},
+		},
+		{
+			name:        "number and string",
+			expected:    resources(map[string]any{"cpu": int64(1)}),
+			actual:      resources(map[string]any{"cpu": "1000m"}),
+			wantEqual:   true,
+			wantEquated: []string{"spec.containers[0].resources.limits.cpu (1 == 1000m)"},
+		},
+		{
+			name:      "different quantities",
+			expected:  resources(map[string]any{"memory": "1Gi"}),
+			actual:    resources(map[string]any{"memory": "1G"}),
+			wantEqual: false,
+		},
+		{
+			name:      "identical values are not reported",
+			expected:  resources(map[string]any{"memory": "1Gi"}),
+			actual:    resources(map[string]any{"memory": "1Gi"}),
+			wantEqual: true,
+		},
+		{
+			name:      "unparseable values compare as text",
+			expected:  resources(map[string]any{"memory": "lots"}),
+			actual:    resources(map[string]any{"memory": "plenty"}),
+			wantEqual: false,
+		},
+		{
+			name:        "size limit",
+			expected:    map[string]any{"emptyDir": map[string]any{"sizeLimit": "1000M"}},
+			actual:      map[string]any{"emptyDir": map[string]any{"sizeLimit": "1G"}},
+			wantEqual:   true,
+			wantEquated: []string{"emptyDir.sizeLimit (1000M == 1G)"},
+		},
+		{
+			name:      "fields outside quantity maps compare as text",
+			expected:  map[string]any{"spec": map[string]any{"replicas": "1k"}},
+			actual:    map[string]any{"spec": map[string]any{"replicas": "1000"}},
+			wantEqual: false,
+		},
+		{
+			name:      "missing actual field",
+			expected:  resources(map[string]any{"memory": "1Gi"}),
+			actual:    resources(map[string]any{}),
+			wantEqual: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, equated := equateQuantities(tt.expected, tt.actual)
+
+			if gotEqual := reflect.DeepEqual(got, tt.actual); gotEqual != tt.wantEqual {
+				t.Errorf("equateQuantities() equal = %v, want %v\ngot:    %v\nactual: %v", gotEqual, tt.wantEqual, got, tt.actual)
+			}
+
+			if !reflect.DeepEqual(equated, tt.wantEquated) {
+				t.Errorf("equateQuantities() equated = %q, want %q", equated, tt.wantEquated)
+			}
+		})
+	}
+}
+
+func TestCheckMutatedObject_CompareQuantities(t *testing.T) {
+	t.Parallel()
+
+	object := func(memory, image string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{"spec": map[string]any{"containers": []any{
+			map[string]any{"image": image, "resources": map[string]any{"requests": map[string]any{"memory": memory}}},
+		}}}}
+	}
+
+	tests := []struct {
+		name              string
+		compareQuantities bool
+		expected          *unstructured.Unstructured
+		wantMessage       []string
+	}{
+		{
+			name:        "textual comparison by default",
+			expected:    object("1024Mi", "nginx"),
+			wantMessage: []string{"memory: 1024Mi", "memory: 1Gi"},
+		},
+		{
+			name:              "equivalent quantities",
+			compareQuantities: true,
+			expected:          object("1024Mi", "nginx"),
+		},
+		{
+			name:              "diff states applied equivalence",
+			compareQuantities: true,
+			expected:          object("1024Mi", "busybox"),
+			wantMessage: []string{
+				"image: busybox",
+				"quantity equivalence applied to:\n  spec.containers[0].resources.requests.memory (1024Mi == 1Gi)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expected := &TestExpectation{Object: tt.expected, CompareQuantities: tt.compareQuantities}
+			actual := &TestOutcome{Object: object("1Gi", "nginx")}
+
+			result := checkMutatedObject(expected, actual)
+			if len(tt.wantMessage) == 0 {
+				if result != nil {
+					t.Errorf("checkMutatedObject() = %q, want match", result.Message)
+				}
+
+				return
+			}
+
+			if result == nil {
+				t.Fatal("checkMutatedObject() = nil, want mismatch")
+			}
+
+			for _, want := range tt.wantMessage {
+				if !strings.Contains(result.Message, want) {
+					t.Errorf("checkMutatedObject() message = %q, want it to contain %q", result.Message, want)
+				}
+			}
+		})
+	}
+}
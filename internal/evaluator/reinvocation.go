@@ -0,0 +1,57 @@
+package evaluator
+
+import (
+	"fmt"
+	"reflect"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// WithReinvocationCheck reapplies mutating policies with reinvocationPolicy
+// IfNeeded to the object they produced, as the API server does when a later
+// admission plugin changes the object, and fails tests whose object changes
+// again. It catches mutations that are not guarded, such as appending a
+// container without checking whether it is already present.
+func WithReinvocationCheck() Option {
+	return func(e *Evaluator) {
+		e.checkReinvocation = true
+	}
+}
+
+// reinvocationFailure reapplies policy to the mutated object and returns a
+// failure message when the second application changes it, or "" when it is
+// stable or the policy is not reinvoked.
+func (e *Evaluator) reinvocationFailure(
+	policy *admissionv1beta1.MutatingAdmissionPolicy,
+	binding *admissionv1beta1.MutatingAdmissionPolicyBinding,
+	testCase TestCase,
+	mutated *unstructured.Unstructured,
+) string {
+	if policy.Spec.ReinvocationPolicy != admissionregv1.IfNeededReinvocationPolicy {
+		return ""
+	}
+
+	var auth authorizer.Authorizer
+	if configs := testCase.GetAuthorizer(); len(configs) > 0 {
+		auth = NewMockAuthorizerFromConfig(configs)
+	}
+
+	input := mutatingInput(policy, binding, testCase, auth)
+	input.Object = mutated
+
+	reinvoked, err := e.EvaluateMutating(input)
+	if err != nil {
+		return fmt.Sprintf("reinvocation error: %v", err)
+	}
+
+	if reinvoked.PatchedObject == nil || reflect.DeepEqual(reinvoked.PatchedObject.Object, mutated.Object) {
+		return ""
+	}
+
+	diff := yamlDiff(mutated.Object, reinvoked.PatchedObject.Object, "Invoked", "Reinvoked")
+
+	return "mutation is not stable under reinvocation (reinvocationPolicy: IfNeeded):\n" + diff
+}
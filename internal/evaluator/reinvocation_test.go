@@ -0,0 +1,98 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestEvaluateTest_ReinvocationCheck(t *testing.T) {
+	t.Parallel()
+
+	guardedLabel := `has(object.metadata.labels) ? [] : [JSONPatch{op: 'add', path: '/metadata/labels', value: {'injected': 'true'}}]`
+	unguardedContainer := `[JSONPatch{op: 'add', path: '/spec/containers/-', value: {'name': 'proxy'}}]`
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "test-pod"},
+		"spec":       map[string]any{"containers": []any{map[string]any{"name": "app"}}},
+	}}
+
+	tests := []struct {
+		name        string
+		expression  string
+		reinvoke    admissionregv1.ReinvocationPolicyType
+		opts        []Option
+		wantPassed  bool
+		wantMessage string
+	}{
+		{
+			name:       "unguarded passes without the check",
+			expression: unguardedContainer,
+			reinvoke:   admissionregv1.IfNeededReinvocationPolicy,
+			wantPassed: true,
+		},
+		{
+			name:        "unguarded fails with the check",
+			expression:  unguardedContainer,
+			reinvoke:    admissionregv1.IfNeededReinvocationPolicy,
+			opts:        []Option{WithReinvocationCheck()},
+			wantMessage: "mutation is not stable under reinvocation",
+		},
+		{
+			name:       "guarded passes with the check",
+			expression: guardedLabel,
+			reinvoke:   admissionregv1.IfNeededReinvocationPolicy,
+			opts:       []Option{WithReinvocationCheck()},
+			wantPassed: true,
+		},
+		{
+			name:       "never reinvoked",
+			expression: unguardedContainer,
+			reinvoke:   admissionregv1.NeverReinvocationPolicy,
+			opts:       []Option{WithReinvocationCheck()},
+			wantPassed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := &admissionv1beta1.MutatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "inject"},
+				Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+					ReinvocationPolicy: tt.reinvoke,
+					Mutations: []admissionv1beta1.Mutation{
+						{
+							PatchType: admissionv1beta1.PatchTypeJSONPatch,
+							JSONPatch: &admissionv1beta1.JSONPatch{Expression: tt.expression},
+						},
+					},
+				},
+			}
+
+			evaluator, err := New(tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result := evaluator.EvaluateTest(policy, nil, nil, nil, MockTestCase{
+				Object:        object,
+				ExpectAllowed: true,
+			})
+			if result.Passed != tt.wantPassed {
+				t.Fatalf("EvaluateTest() passed = %v, want %v (message: %s)", result.Passed, tt.wantPassed, result.Message)
+			}
+
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("EvaluateTest() message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
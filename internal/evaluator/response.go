@@ -0,0 +1,45 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecordedResponse is an admission response recorded from a real cluster
+// (a ".response.yaml" file). Tests with a recorded response must also agree
+// with it, so cluster behavior can be replayed without cluster access.
+type RecordedResponse struct {
+	Allowed  bool     `json:"allowed"`
+	Message  string   `json:"message,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// checkRecordedResponse compares the evaluated outcome with a recorded cluster
+// response. The API server wraps policy messages and warnings with the policy
+// and binding names, so a recorded message or warning matches when it
+// contains the evaluated one.
+func checkRecordedResponse(recorded *RecordedResponse, actual *TestOutcome) string {
+	if recorded == nil {
+		return ""
+	}
+
+	if recorded.Allowed != actual.Allowed {
+		return fmt.Sprintf("recorded cluster response has allowed=%v, got allowed=%v", recorded.Allowed, actual.Allowed)
+	}
+
+	if !recorded.Allowed && !strings.Contains(recorded.Message, actual.Message) {
+		return fmt.Sprintf("recorded cluster message %q does not contain %q", recorded.Message, actual.Message)
+	}
+
+	if len(recorded.Warnings) != len(actual.Warnings) {
+		return fmt.Sprintf("recorded cluster response has %d warnings, got %d", len(recorded.Warnings), len(actual.Warnings))
+	}
+
+	for i, warning := range actual.Warnings {
+		if !strings.Contains(recorded.Warnings[i], warning) {
+			return fmt.Sprintf("recorded cluster warning[%d] %q does not contain %q", i, recorded.Warnings[i], warning)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,81 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckRecordedResponse(t *testing.T) {
+	t.Parallel()
+
+	const denied = `pods "web" is forbidden: ValidatingAdmissionPolicy 'p' with binding 'b' denied request: missing label`
+
+	tests := []struct {
+		name     string
+		recorded *RecordedResponse
+		actual   TestOutcome
+		wantMsg  string
+	}{
+		{
+			name:   "no recorded response",
+			actual: TestOutcome{Allowed: true},
+		},
+		{
+			name:     "allowed matches",
+			recorded: &RecordedResponse{Allowed: true},
+			actual:   TestOutcome{Allowed: true},
+		},
+		{
+			name:     "allowed mismatch",
+			recorded: &RecordedResponse{Allowed: true},
+			actual:   TestOutcome{Allowed: false, Message: "missing label"},
+			wantMsg:  "recorded cluster response has allowed=true, got allowed=false",
+		},
+		{
+			name:     "denial message wrapped by the API server",
+			recorded: &RecordedResponse{Allowed: false, Message: denied},
+			actual:   TestOutcome{Allowed: false, Message: "missing label"},
+		},
+		{
+			name:     "denial message mismatch",
+			recorded: &RecordedResponse{Allowed: false, Message: denied},
+			actual:   TestOutcome{Allowed: false, Message: "missing annotation"},
+			wantMsg:  "does not contain \"missing annotation\"",
+		},
+		{
+			name: "warnings wrapped by the API server",
+			recorded: &RecordedResponse{
+				Allowed:  true,
+				Warnings: []string{"Validation failed for ValidatingAdmissionPolicy 'p' with binding 'b': deprecated"},
+			},
+			actual: TestOutcome{Allowed: true, Warnings: []string{"deprecated"}},
+		},
+		{
+			name:     "warning count mismatch",
+			recorded: &RecordedResponse{Allowed: true, Warnings: []string{"deprecated"}},
+			actual:   TestOutcome{Allowed: true},
+			wantMsg:  "recorded cluster response has 1 warnings, got 0",
+		},
+		{
+			name:     "warning mismatch",
+			recorded: &RecordedResponse{Allowed: true, Warnings: []string{"deprecated"}},
+			actual:   TestOutcome{Allowed: true, Warnings: []string{"removed"}},
+			wantMsg:  "recorded cluster warning[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := checkRecordedResponse(tt.recorded, &tt.actual)
+			if tt.wantMsg == "" && got != "" {
+				t.Errorf("checkRecordedResponse() = %q, want no mismatch", got)
+			}
+
+			if !strings.Contains(got, tt.wantMsg) {
+				t.Errorf("checkRecordedResponse() = %q, want it to contain %q", got, tt.wantMsg)
+			}
+		})
+	}
+}
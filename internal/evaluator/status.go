@@ -0,0 +1,129 @@
+package evaluator
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// deniedStatus returns the status the API server responds with when
+// validation of policy denies a request: a Forbidden error whose reason and
+// code are those of the validation (Invalid and 422 by default), with the
+// policy message as its single cause.
+func deniedStatus(
+	policy *admissionregv1.ValidatingAdmissionPolicy,
+	binding *admissionregv1.ValidatingAdmissionPolicyBinding,
+	validation *admissionregv1.Validation,
+	request *admissionv1.AdmissionRequest,
+	object *unstructured.Unstructured,
+	message string,
+) *metav1.Status {
+	if binding != nil {
+		message = fmt.Sprintf("ValidatingAdmissionPolicy '%s' with binding '%s' denied request: %s", policy.Name, binding.Name, message)
+	} else {
+		message = fmt.Sprintf("ValidatingAdmissionPolicy '%s' denied request: %s", policy.Name, message)
+	}
+
+	var resource schema.GroupResource
+	if request != nil {
+		resource = schema.GroupResource{Group: request.Resource.Group, Resource: request.Resource.Resource}
+	}
+
+	name := deniedObjectName(request, object)
+
+	reason := metav1.StatusReasonInvalid
+	if validation.Reason != nil && *validation.Reason != "" {
+		reason = *validation.Reason
+	}
+
+	statusMessage := fmt.Sprintf("%s %q is forbidden: %s", resource, name, message)
+	if resource.Empty() {
+		statusMessage = "forbidden: " + message
+	}
+
+	return &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: statusMessage,
+		Reason:  reason,
+		Details: &metav1.StatusDetails{
+			Name:   name,
+			Group:  resource.Group,
+			Kind:   resource.Resource,
+			Causes: []metav1.StatusCause{{Message: message}},
+		},
+		Code: reasonToCode(reason),
+	}
+}
+
+// deniedObjectName returns the name the API server reports for a denied
+// request: the request name, else the object name or generateName, else
+// "Unknown".
+func deniedObjectName(request *admissionv1.AdmissionRequest, object *unstructured.Unstructured) string {
+	switch {
+	case request != nil && request.Name != "":
+		return request.Name
+	case object != nil && object.GetName() != "":
+		return object.GetName()
+	case object != nil && object.GetGenerateName() != "":
+		return object.GetGenerateName()
+	default:
+		return "Unknown"
+	}
+}
+
+func reasonToCode(reason metav1.StatusReason) int32 {
+	switch reason {
+	case metav1.StatusReasonForbidden:
+		return http.StatusForbidden
+	case metav1.StatusReasonUnauthorized:
+		return http.StatusUnauthorized
+	case metav1.StatusReasonRequestEntityTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// checkStatus compares the expected status of a denial (a ".status.yaml"
+// file) with the synthesized one. The kind and apiVersion of the expected
+// status are ignored.
+func checkStatus(expected, actual *metav1.Status) string {
+	if expected == nil {
+		return ""
+	}
+
+	if actual == nil {
+		return "expected a denial status, got none"
+	}
+
+	want := expected.DeepCopy()
+	want.TypeMeta = metav1.TypeMeta{}
+
+	if reflect.DeepEqual(want, actual) {
+		return ""
+	}
+
+	expectedYAML, err := yaml.Marshal(want)
+	if err != nil {
+		expectedYAML = []byte(fmt.Sprintf("%+v", want))
+	}
+
+	actualYAML, err := yaml.Marshal(actual)
+	if err != nil {
+		actualYAML = []byte(fmt.Sprintf("%+v", actual))
+	}
+
+	diff := getDiff(string(expectedYAML), string(actualYAML))
+	if diff == "" {
+		diff = fmt.Sprintf("Expected:\n%s\nActual:\n%s", expectedYAML, actualYAML)
+	}
+
+	return "status does not match expected:\n" + diff
+}
@@ -0,0 +1,121 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDeniedStatus(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{ObjectMeta: metav1.ObjectMeta{Name: "replica-limit"}}
+	binding := &admissionregv1.ValidatingAdmissionPolicyBinding{ObjectMeta: metav1.ObjectMeta{Name: "replica-limit-binding"}}
+	request := &admissionv1.AdmissionRequest{Resource: metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}}
+	forbidden := metav1.StatusReasonForbidden
+
+	object := func(name, generateName string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetName(name)
+		u.SetGenerateName(generateName)
+
+		return u
+	}
+
+	tests := []struct {
+		name       string
+		binding    *admissionregv1.ValidatingAdmissionPolicyBinding
+		validation admissionregv1.Validation
+		object     *unstructured.Unstructured
+		want       *metav1.Status
+	}{
+		{
+			name:    "invalid by default",
+			binding: binding,
+			object:  object("web", ""),
+			want: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: `deployments.apps "web" is forbidden: ValidatingAdmissionPolicy 'replica-limit' with binding 'replica-limit-binding' denied request: too many`,
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    422,
+				Details: &metav1.StatusDetails{
+					Name:   "web",
+					Group:  "apps",
+					Kind:   "deployments",
+					Causes: []metav1.StatusCause{{Message: "ValidatingAdmissionPolicy 'replica-limit' with binding 'replica-limit-binding' denied request: too many"}},
+				},
+			},
+		},
+		{
+			name:       "validation reason and generated name",
+			validation: admissionregv1.Validation{Reason: &forbidden},
+			object:     object("", "web-"),
+			want: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: `deployments.apps "web-" is forbidden: ValidatingAdmissionPolicy 'replica-limit' denied request: too many`,
+				Reason:  metav1.StatusReasonForbidden,
+				Code:    403,
+				Details: &metav1.StatusDetails{
+					Name:   "web-",
+					Group:  "apps",
+					Kind:   "deployments",
+					Causes: []metav1.StatusCause{{Message: "ValidatingAdmissionPolicy 'replica-limit' denied request: too many"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := deniedStatus(policy, tt.binding, &tt.validation, request, tt.object, "too many")
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("deniedStatus() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	t.Parallel()
+
+	actual := &metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonInvalid, Code: 422}
+
+	tests := []struct {
+		name     string
+		expected *metav1.Status
+		actual   *metav1.Status
+		want     string
+	}{
+		{name: "no expectation", actual: actual},
+		{
+			name:     "match ignoring kind",
+			expected: &metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusFailure, Reason: metav1.StatusReasonInvalid, Code: 422},
+			actual:   actual,
+		},
+		{name: "allowed", expected: actual, want: "expected a denial status, got none"},
+		{
+			name:     "mismatch",
+			expected: &metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonForbidden, Code: 403},
+			actual:   actual,
+			want:     "status does not match expected:\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := checkStatus(tt.expected, tt.actual)
+			if tt.want == "" && got != "" || !strings.HasPrefix(got, tt.want) {
+				t.Errorf("checkStatus() = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/debug"
+)
+
+// celTrace writes the AST of each expression the first time it is compiled.
+type celTrace struct {
+	out io.Writer
+
+	mu     sync.Mutex
+	traced map[string]bool
+}
+
+// WithCELTrace writes each expression to w the first time it is compiled,
+// with its output type and its AST as printed by cel-go, macros expanded, to
+// show how it parsed.
+func WithCELTrace(w io.Writer) Option {
+	return func(e *Evaluator) {
+		e.trace = &celTrace{out: w, traced: make(map[string]bool)}
+	}
+}
+
+// print writes the AST of expression unless it was already written.
+func (t *celTrace) print(expression string, ast *cel.Ast) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.traced[expression] {
+		return
+	}
+
+	t.traced[expression] = true
+
+	fmt.Fprintf(t.out, "=== CEL %s\n", strings.Join(strings.Fields(expression), " "))
+	fmt.Fprintf(t.out, "    type: %s\n", ast.OutputType())
+
+	for line := range strings.Lines(debug.ToDebugString(ast.NativeRep().Expr())) {
+		fmt.Fprintf(t.out, "    %s", line)
+	}
+
+	fmt.Fprintln(t.out)
+}
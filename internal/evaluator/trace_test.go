@@ -0,0 +1,70 @@
+package evaluator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestWithCELTrace(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	eval, err := New(WithCELTrace(&out))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicas"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{{Expression: "1 + 2 * 3 == 7 &&\n  object.spec.replicas <= 5"}},
+		},
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web"},
+		"spec":       map[string]any{"replicas": int64(3)},
+	}}
+
+	// Expressions are traced once, however often they are evaluated.
+	for range 2 {
+		if _, err := eval.EvaluateValidating(ValidatingInput{
+			Policy:  policy,
+			Request: &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+			Object:  object,
+		}); err != nil {
+			t.Fatalf("EvaluateValidating() error = %v", err)
+		}
+	}
+
+	want := `=== CEL 1 + 2 * 3 == 7 && object.spec.replicas <= 5
+    type: bool
+    _&&_(
+      _==_(
+        _+_(
+          1,
+          _*_(
+            2,
+            3
+          )
+        ),
+        7
+      ),
+      _<=_(
+        object.spec.replicas,
+        5
+      )
+    )
+`
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("trace mismatch (-want +got):\n%s", diff)
+	}
+}
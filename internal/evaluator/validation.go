@@ -0,0 +1,40 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+)
+
+// ValidationNamesAnnotation names the validations of a policy, which the API has
+// no field for. Its value is a comma-separated list aligned with spec.validations;
+// empty entries leave a validation unnamed.
+const ValidationNamesAnnotation = "kat/validation-names"
+
+// ValidationRef identifies a validation of a policy.
+type ValidationRef struct {
+	Index int
+	Name  string // From ValidationNamesAnnotation; empty when unnamed
+}
+
+// String returns "validation 'name'", or "validation[index]" for unnamed validations.
+func (v ValidationRef) String() string {
+	if v.Name != "" {
+		return fmt.Sprintf("validation '%s'", v.Name)
+	}
+
+	return fmt.Sprintf("validation[%d]", v.Index)
+}
+
+// validationRef returns the reference of the validation at index.
+func validationRef(policy *admissionregv1.ValidatingAdmissionPolicy, index int) ValidationRef {
+	ref := ValidationRef{Index: index}
+
+	names := strings.Split(policy.Annotations[ValidationNamesAnnotation], ",")
+	if index < len(names) {
+		ref.Name = strings.TrimSpace(names[index])
+	}
+
+	return ref
+}
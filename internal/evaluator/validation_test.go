@@ -0,0 +1,102 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidationRef(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		index       int
+		want        string
+	}{
+		{
+			name:  "no annotation",
+			index: 2,
+			want:  "validation[2]",
+		},
+		{
+			name:        "named",
+			annotations: map[string]string{ValidationNamesAnnotation: "owner, replica-limit"},
+			index:       1,
+			want:        "validation 'replica-limit'",
+		},
+		{
+			name:        "empty entry",
+			annotations: map[string]string{ValidationNamesAnnotation: ",replica-limit"},
+			index:       0,
+			want:        "validation[0]",
+		},
+		{
+			name:        "fewer names than validations",
+			annotations: map[string]string{ValidationNamesAnnotation: "owner"},
+			index:       1,
+			want:        "validation[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := &admissionregv1.ValidatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+			}
+
+			if got := validationRef(policy, tt.index).String(); got != tt.want {
+				t.Errorf("validationRef().String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateValidating_FailedValidation(t *testing.T) {
+	t.Parallel()
+
+	eval, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "replicas",
+			Annotations: map[string]string{ValidationNamesAnnotation: "has-spec,replica-limit"},
+		},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{
+				{Expression: "has(object.spec)"},
+				{Expression: "object.spec.replicas <= 5", Message: "too many replicas"},
+			},
+		},
+	}
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec":       map[string]any{"replicas": int64(10)},
+	}}
+
+	result, err := eval.EvaluateValidating(ValidatingInput{
+		Policy:  policy,
+		Request: &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+		Object:  object,
+	})
+	if err != nil {
+		t.Fatalf("EvaluateValidating() error = %v", err)
+	}
+
+	want := &ValidationRef{Index: 1, Name: "replica-limit"}
+	if diff := cmp.Diff(want, result.FailedValidation); diff != "" {
+		t.Errorf("FailedValidation mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,43 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	plugin "k8s.io/apiserver/pkg/admission/plugin/cel"
+	"k8s.io/apiserver/pkg/cel/lazy"
+)
+
+// bindPolicyVariables binds the spec.variables of a policy as the "variables"
+// map. Like the API server, each variable is evaluated when first accessed,
+// at most once, so variables may reference the ones declared before them and
+// a failing variable only fails the expressions using it.
+func (e *Evaluator) bindPolicyVariables(vars map[string]any, variables []admissionregv1.Variable) {
+	values := lazy.NewMapValue(types.NewObjectType(plugin.VariablesTypeName))
+
+	for _, variable := range variables {
+		values.Append(variable.Name, func(*lazy.MapValue) ref.Val {
+			value, err := e.evaluateExpressionRaw(variable.Expression, vars)
+			if err != nil {
+				return types.WrapErr(fmt.Errorf("variable %q: %w", variable.Name, err))
+			}
+
+			return value
+		})
+	}
+
+	vars[plugin.VariableVarName] = values
+}
+
+// v1Variables converts the spec.variables of a mutating policy.
+func v1Variables(variables []admissionv1beta1.Variable) []admissionregv1.Variable {
+	converted := make([]admissionregv1.Variable, len(variables))
+	for i, variable := range variables {
+		converted[i] = admissionregv1.Variable{Name: variable.Name, Expression: variable.Expression}
+	}
+
+	return converted
+}
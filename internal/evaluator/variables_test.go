@@ -0,0 +1,129 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNew_VariablesDeclared(t *testing.T) {
+	t.Parallel()
+
+	eval, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, issues := eval.env.Compile(`variables.foo == "bar"`); issues != nil && issues.Err() != nil {
+		t.Errorf("Compile() error = %v", issues.Err())
+	}
+}
+
+func TestEvaluateValidating_Variables(t *testing.T) {
+	t.Parallel()
+
+	eval, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	variables := []admissionregv1.Variable{
+		{Name: "team", Expression: `object.metadata.name.split('-')[0]`},
+		{Name: "label", Expression: `'team-' + variables.team`},
+		{Name: "missing", Expression: `object.spec.missing`},
+	}
+
+	tests := []struct {
+		name        string
+		expression  string
+		wantAllowed bool
+		wantErr     string
+	}{
+		{name: "variable", expression: `variables.team == 'web'`, wantAllowed: true},
+		{name: "variable using a variable", expression: `variables.label == 'team-web'`, wantAllowed: true},
+		{name: "denied", expression: `variables.team == 'db'`},
+		{name: "failing variable", expression: `variables.missing == 1`, wantErr: `variable "missing"`},
+	}
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "web-0"},
+	}}
+	request := &admissionv1.AdmissionRequest{Operation: admissionv1.Create}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := &admissionregv1.ValidatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-variables"},
+				Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+					// The failing variable is only evaluated when used.
+					Variables:   variables,
+					Validations: []admissionregv1.Validation{{Expression: tt.expression}},
+				},
+			}
+
+			result, err := eval.EvaluateValidating(ValidatingInput{Policy: policy, Request: request, Object: object})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("EvaluateValidating() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if result.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v", result.Allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestEvaluateMutating_Variables(t *testing.T) {
+	t.Parallel()
+
+	eval, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	policy := &admissionv1beta1.MutatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "label-team"},
+		Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+			Variables: []admissionv1beta1.Variable{{Name: "team", Expression: `object.metadata.name.split('-')[0]`}},
+			Mutations: []admissionv1beta1.Mutation{{
+				PatchType: admissionv1beta1.PatchTypeJSONPatch,
+				JSONPatch: &admissionv1beta1.JSONPatch{
+					Expression: `[JSONPatch{op: "add", path: "/metadata/labels", value: {"team": variables.team}}]`,
+				},
+			}},
+		},
+	}
+
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "web-0"},
+	}}
+	request := &admissionv1.AdmissionRequest{Operation: admissionv1.Create}
+
+	result, err := eval.EvaluateMutating(MutatingInput{Policy: policy, Request: request, Object: object})
+	if err != nil {
+		t.Fatalf("EvaluateMutating() error = %v", err)
+	}
+
+	if got := result.PatchedObject.GetLabels()["team"]; got != "web" {
+		t.Errorf("team label = %q, want %q", got, "web")
+	}
+}
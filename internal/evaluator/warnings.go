@@ -0,0 +1,93 @@
+package evaluator
+
+import "unicode/utf8"
+
+// Limits the API server applies to the warnings of a response, as in
+// k8s.io/apiserver/pkg/endpoints/filters/warning.go.
+const (
+	// MaxWarningsRunes is the total length of the warnings of a response
+	// beyond which the API server truncates them.
+	MaxWarningsRunes = 4 * 1024
+	// MaxWarningRunes is the length each warning is cut to once truncating.
+	MaxWarningRunes = 256
+)
+
+// dedupeWarnings drops repeated warnings, keeping the first occurrence of
+// each in order, as the warning recorder of the API server does. It returns
+// how often each repeated warning was produced.
+func dedupeWarnings(warnings []string) ([]string, map[string]int) {
+	var (
+		kept     []string
+		repeated map[string]int
+		seen     = make(map[string]int, len(warnings))
+	)
+
+	for _, text := range warnings {
+		seen[text]++
+		if seen[text] == 1 {
+			kept = append(kept, text)
+
+			continue
+		}
+
+		if repeated == nil {
+			repeated = make(map[string]int)
+		}
+
+		repeated[text] = seen[text]
+	}
+
+	return kept, repeated
+}
+
+// truncateWarnings applies the limits of the API server to the warnings of a
+// response: once they exceed MaxWarningsRunes in total, every warning is cut
+// to MaxWarningRunes and those added after the total is reached again are
+// dropped. It reports whether the warnings were truncated.
+func truncateWarnings(warnings []string) ([]string, bool) {
+	var (
+		kept       []string
+		written    int
+		truncating bool
+	)
+
+	for i, text := range warnings {
+		if truncating && written >= MaxWarningsRunes {
+			break
+		}
+
+		if truncating {
+			text = truncateWarning(text)
+		}
+
+		length := utf8.RuneCountInString(text)
+		if written+length <= MaxWarningsRunes || truncating {
+			written += length
+			kept = append(kept, text)
+
+			continue
+		}
+
+		// Start truncating and replay the warnings so far, this one included.
+		truncating = true
+		written = 0
+		kept = kept[:0]
+
+		for _, replayed := range warnings[:i+1] {
+			replayed = truncateWarning(replayed)
+			written += utf8.RuneCountInString(replayed)
+			kept = append(kept, replayed)
+		}
+	}
+
+	return kept, truncating
+}
+
+// truncateWarning cuts text to MaxWarningRunes on a rune boundary.
+func truncateWarning(text string) string {
+	if utf8.RuneCountInString(text) <= MaxWarningRunes {
+		return text
+	}
+
+	return string([]rune(text)[:MaxWarningRunes])
+}
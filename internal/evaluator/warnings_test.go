@@ -0,0 +1,218 @@
+package evaluator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTruncateWarnings(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", MaxWarningRunes+1)
+
+	tests := []struct {
+		name          string
+		warnings      []string
+		want          []string
+		wantTruncated bool
+	}{
+		{
+			name:     "short warnings",
+			warnings: []string{"replicas above 3", long},
+			want:     []string{"replicas above 3", long},
+		},
+		{
+			name:     "exactly the total",
+			warnings: []string{strings.Repeat("a", MaxWarningsRunes)},
+			want:     []string{strings.Repeat("a", MaxWarningsRunes)},
+		},
+		{
+			name:          "one rune over the total",
+			warnings:      []string{strings.Repeat("a", MaxWarningsRunes+1)},
+			want:          []string{strings.Repeat("a", MaxWarningRunes)},
+			wantTruncated: true,
+		},
+		{
+			name:          "earlier warnings are cut once the total is exceeded",
+			warnings:      []string{long, strings.Repeat("b", MaxWarningsRunes)},
+			want:          []string{long[:MaxWarningRunes], strings.Repeat("b", MaxWarningRunes)},
+			wantTruncated: true,
+		},
+		{
+			name:          "warnings beyond the total are dropped",
+			warnings:      slices.Repeat([]string{strings.Repeat("c", MaxWarningsRunes)}, 17),
+			want:          slices.Repeat([]string{strings.Repeat("c", MaxWarningRunes)}, MaxWarningsRunes/MaxWarningRunes),
+			wantTruncated: true,
+		},
+		{
+			name:          "cut on a rune boundary",
+			warnings:      []string{strings.Repeat("é", MaxWarningsRunes+1)},
+			want:          []string{strings.Repeat("é", MaxWarningRunes)},
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, truncated := truncateWarnings(tt.warnings)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("truncateWarnings() mismatch (-want +got):\n%s", diff)
+			}
+
+			if truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestEvaluateValidating_WarningTruncation(t *testing.T) {
+	t.Parallel()
+
+	message := strings.Repeat("x", MaxWarningsRunes+1)
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "long-warning"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{{Expression: "false", Message: message}},
+		},
+	}
+	binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+			ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Warn},
+		},
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "web"},
+	}}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		want      string
+		wantNotes int
+	}{
+		{name: "truncated", want: message[:MaxWarningRunes], wantNotes: 1},
+		{name: "truncation disabled", opts: []Option{WithoutWarningTruncation()}, want: message},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := New(tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result, err := eval.EvaluateValidating(ValidatingInput{
+				Policy:  policy,
+				Binding: binding,
+				Request: &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+				Object:  object,
+			})
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if diff := cmp.Diff([]string{tt.want}, result.Warnings); diff != "" {
+				t.Errorf("Warnings mismatch (-want +got):\n%s", diff)
+			}
+
+			if len(result.Notes) != tt.wantNotes {
+				t.Errorf("Notes = %q, want %d note(s)", result.Notes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestDedupeWarnings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		warnings     []string
+		want         []string
+		wantRepeated map[string]int
+	}{
+		{name: "none"},
+		{name: "distinct", warnings: []string{"b", "a"}, want: []string{"b", "a"}},
+		{
+			name:         "repeated keep the first occurrence",
+			warnings:     []string{"b", "a", "b", "c", "b", "a"},
+			want:         []string{"b", "a", "c"},
+			wantRepeated: map[string]int{"a": 2, "b": 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, repeated := dedupeWarnings(tt.warnings)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("dedupeWarnings() mismatch (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tt.wantRepeated, repeated); diff != "" {
+				t.Errorf("repeated mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateTest_DuplicateWarnings(t *testing.T) {
+	t.Parallel()
+
+	policy := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deprecations"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			Validations: []admissionregv1.Validation{
+				{Expression: "!has(object.spec.hostNetwork)", Message: "pod uses deprecated fields"},
+				{Expression: "object.metadata.name != 'web'", Message: "web is reserved"},
+				{Expression: "!has(object.spec.serviceAccount)", Message: "pod uses deprecated fields"},
+			},
+		},
+	}
+	binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+			ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Warn},
+		},
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "web"},
+		"spec":       map[string]any{"hostNetwork": true, "serviceAccount": "default"},
+	}}
+
+	eval, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	result := eval.EvaluateTest(nil, nil, policy, binding, MockTestCase{
+		Object:         object,
+		ExpectAllowed:  true,
+		ExpectWarnings: []string{"pod uses deprecated fields", "web is reserved"},
+	})
+	if !result.Passed {
+		t.Fatalf("EvaluateTest() Passed = false, want true (message: %s)", result.Message)
+	}
+
+	wantNotes := []string{`warning "pod uses deprecated fields" produced 2 times, reported once as by the API server`}
+	if diff := cmp.Diff(wantNotes, result.Notes); diff != "" {
+		t.Errorf("Notes mismatch (-want +got):\n%s", diff)
+	}
+}
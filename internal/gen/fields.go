@@ -0,0 +1,266 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+)
+
+// listElement is the path segment for the elements of a list.
+const listElement = "[]"
+
+// fieldPaths parses a CEL expression and returns the paths of the object fields it
+// references, sorted. Fields reached through comprehension variables end up under a
+// "[]" segment, and references to policy variables are followed into their expressions.
+func fieldPaths(expression string, variables map[string]string) ([][]string, error) {
+	env, err := cel.NewEnv(cel.OptionalTypes())
+	if err != nil {
+		return nil, fmt.Errorf("create CEL environment: %w", err)
+	}
+
+	w := &fieldWalker{
+		env:       env,
+		variables: variables,
+		parsed:    map[string]ast.Expr{},
+		visited:   map[string]bool{},
+		resolving: map[string]bool{},
+		bindings:  map[string][]string{},
+		paths:     map[string][]string{},
+	}
+
+	if err := w.walkExpression(expression); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(w.paths))
+	for key := range w.paths {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	paths := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		paths = append(paths, w.paths[key])
+	}
+
+	return paths, nil
+}
+
+// fieldWalker collects object field paths from CEL ASTs.
+type fieldWalker struct {
+	env       *cel.Env
+	variables map[string]string   // Policy variable expressions by name
+	parsed    map[string]ast.Expr // Parsed policy variable expressions
+	visited   map[string]bool     // Policy variables already walked
+	resolving map[string]bool     // Policy variables being resolved to a path
+	bindings  map[string][]string // Comprehension variables bound to object paths
+	paths     map[string][]string // Collected paths keyed by their joined form
+}
+
+func (w *fieldWalker) walkExpression(expression string) error {
+	expr, err := w.parse(expression)
+	if err != nil {
+		return err
+	}
+
+	return w.walk(expr)
+}
+
+func (w *fieldWalker) parse(expression string) (ast.Expr, error) {
+	parsed, issues := w.env.Parse(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("parse %q: %w", expression, issues.Err())
+	}
+
+	return parsed.NativeRep().Expr(), nil
+}
+
+// variable returns the parsed expression of a policy variable.
+func (w *fieldWalker) variable(name string) (ast.Expr, bool, error) {
+	if expr, ok := w.parsed[name]; ok {
+		return expr, true, nil
+	}
+
+	expression, ok := w.variables[name]
+	if !ok {
+		return nil, false, nil
+	}
+
+	expr, err := w.parse(expression)
+	if err != nil {
+		return nil, false, err
+	}
+
+	w.parsed[name] = expr
+
+	return expr, true, nil
+}
+
+func (w *fieldWalker) walk(expr ast.Expr) error {
+	if path, ok := w.path(expr); ok {
+		if len(path) > 0 {
+			w.paths[strings.Join(path, "\x00")] = path
+		}
+
+		return nil
+	}
+
+	switch expr.Kind() {
+	case ast.SelectKind:
+		return w.walkSelect(expr.AsSelect())
+	case ast.CallKind:
+		call := expr.AsCall()
+		if call.IsMemberFunction() {
+			if err := w.walk(call.Target()); err != nil {
+				return err
+			}
+		}
+
+		return w.walkAll(call.Args())
+	case ast.ComprehensionKind:
+		return w.walkComprehension(expr.AsComprehension())
+	case ast.ListKind:
+		return w.walkAll(expr.AsList().Elements())
+	case ast.MapKind:
+		for _, entry := range expr.AsMap().Entries() {
+			if err := w.walkAll([]ast.Expr{entry.AsMapEntry().Key(), entry.AsMapEntry().Value()}); err != nil {
+				return err
+			}
+		}
+	case ast.StructKind:
+		for _, field := range expr.AsStruct().Fields() {
+			if err := w.walk(field.AsStructField().Value()); err != nil {
+				return err
+			}
+		}
+	default:
+	}
+
+	return nil
+}
+
+func (w *fieldWalker) walkAll(exprs []ast.Expr) error {
+	for _, expr := range exprs {
+		if err := w.walk(expr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkSelect follows "variables.<name>" into the variable's expression.
+func (w *fieldWalker) walkSelect(sel ast.SelectExpr) error {
+	operand := sel.Operand()
+	if operand.Kind() != ast.IdentKind || operand.AsIdent() != "variables" {
+		return w.walk(operand)
+	}
+
+	name := sel.FieldName()
+	if w.visited[name] {
+		return nil
+	}
+
+	w.visited[name] = true
+
+	expr, ok, err := w.variable(name)
+	if !ok || err != nil {
+		return err
+	}
+
+	return w.walk(expr)
+}
+
+func (w *fieldWalker) walkComprehension(comp ast.ComprehensionExpr) error {
+	if err := w.walk(comp.IterRange()); err != nil {
+		return err
+	}
+
+	previous, shadowed := w.bindings[comp.IterVar()]
+	if path, ok := w.path(comp.IterRange()); ok {
+		w.bindings[comp.IterVar()] = append(append([]string(nil), path...), listElement)
+	} else {
+		delete(w.bindings, comp.IterVar())
+	}
+
+	defer func() {
+		if shadowed {
+			w.bindings[comp.IterVar()] = previous
+		} else {
+			delete(w.bindings, comp.IterVar())
+		}
+	}()
+
+	return w.walkAll([]ast.Expr{comp.AccuInit(), comp.LoopCondition(), comp.LoopStep(), comp.Result()})
+}
+
+// path resolves field selections (including optional ones) and constant string
+// indexes rooted at "object", at a bound comprehension variable, or at a policy
+// variable that is itself such a path.
+func (w *fieldWalker) path(expr ast.Expr) ([]string, bool) {
+	switch expr.Kind() {
+	case ast.IdentKind:
+		if expr.AsIdent() == "object" {
+			return []string{}, true
+		}
+
+		path, ok := w.bindings[expr.AsIdent()]
+
+		return path, ok
+	case ast.SelectKind:
+		sel := expr.AsSelect()
+
+		if operand := sel.Operand(); operand.Kind() == ast.IdentKind && operand.AsIdent() == "variables" {
+			name := sel.FieldName()
+
+			variable, ok, _ := w.variable(name)
+			if !ok || w.resolving[name] {
+				return nil, false
+			}
+
+			w.resolving[name] = true
+			defer delete(w.resolving, name)
+
+			return w.path(variable)
+		}
+
+		parent, ok := w.path(sel.Operand())
+		if !ok {
+			return nil, false
+		}
+
+		return append(append([]string(nil), parent...), sel.FieldName()), true
+	case ast.CallKind:
+		call := expr.AsCall()
+		switch call.FunctionName() {
+		case operators.Index, operators.OptIndex, operators.OptSelect:
+		default:
+			return nil, false
+		}
+
+		args := call.Args()
+		if len(args) != 2 || args[1].Kind() != ast.LiteralKind {
+			return nil, false
+		}
+
+		key, ok := args[1].AsLiteral().(types.String)
+		if !ok {
+			return nil, false
+		}
+
+		parent, ok := w.path(args[0])
+		if !ok {
+			return nil, false
+		}
+
+		return append(append([]string(nil), parent...), string(key)), true
+	default:
+		return nil, false
+	}
+}
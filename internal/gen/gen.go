@@ -0,0 +1,218 @@
+// Package gen scaffolds test files for the validations of admission policies.
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	errNoValidations = errors.New("no validations to generate tests for")
+	errFilesExist    = errors.New("refusing to overwrite existing files")
+)
+
+// placeholder is the value of every stubbed field.
+const placeholder = "TODO"
+
+// File is a generated file; Path is relative to the suite directory.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Generate returns skeleton tests for every validation of the policies:
+// "<policy>.<n>.allow.object.yaml" and "<policy>.<n>.deny.object.yaml" with the
+// object fields the validation references set to "TODO", and
+// "<policy>.<n>.deny.message.txt" with the validation message. Validations
+// whose message comes from a messageExpression get no message file.
+func Generate(policies []*admissionregv1.ValidatingAdmissionPolicy) ([]File, error) {
+	var files []File
+
+	for _, policy := range policies {
+		variables := make(map[string]string, len(policy.Spec.Variables))
+		for _, variable := range policy.Spec.Variables {
+			variables[variable.Name] = variable.Expression
+		}
+
+		for i, validation := range policy.Spec.Validations {
+			paths, err := fieldPaths(validation.Expression, variables)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s: validation[%d]: %w", policy.Name, i, err)
+			}
+
+			prefix := filepath.Join("tests", policy.Name+"."+strconv.Itoa(i))
+
+			for _, outcome := range []string{"allow", "deny"} {
+				content, err := stubObject(policy, fmt.Sprintf("%s-%d-%s", policy.Name, i, outcome), paths)
+				if err != nil {
+					return nil, fmt.Errorf("policy %s: validation[%d]: %w", policy.Name, i, err)
+				}
+
+				files = append(files, File{Path: prefix + "." + outcome + ".object.yaml", Content: content})
+			}
+
+			if message, ok := validationMessage(&validation); ok {
+				files = append(files, File{Path: prefix + ".deny.message.txt", Content: []byte(message + "\n")})
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, errNoValidations
+	}
+
+	return files, nil
+}
+
+// Write creates the files in dir. It writes nothing when any of them exists.
+func Write(dir string, files []File) ([]string, error) {
+	var existing []string
+
+	for _, file := range files {
+		path := filepath.Join(dir, file.Path)
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("stat %s: %w", path, err)
+		}
+	}
+
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("%w: %s", errFilesExist, strings.Join(existing, ", "))
+	}
+
+	written := make([]string, 0, len(files))
+
+	for _, file := range files {
+		path := filepath.Join(dir, file.Path)
+
+		//nolint:gosec // Generated tests are committed alongside the policy.
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return written, fmt.Errorf("create directory: %w", err)
+		}
+
+		//nolint:gosec // Generated tests are committed alongside the policy.
+		if err := os.WriteFile(path, file.Content, 0o644); err != nil {
+			return written, fmt.Errorf("write %s: %w", path, err)
+		}
+
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// validationMessage returns the message a failing validation is expected to report,
+// matching the evaluator's default for validations without a message.
+func validationMessage(validation *admissionregv1.Validation) (string, bool) {
+	switch {
+	case validation.MessageExpression != "":
+		return "", false
+	case validation.Message != "":
+		return validation.Message, true
+	default:
+		return "validation failed: " + validation.Expression, true
+	}
+}
+
+// stubObject builds an object of the kind the policy matches with every path set to
+// the placeholder.
+func stubObject(policy *admissionregv1.ValidatingAdmissionPolicy, name string, paths [][]string) ([]byte, error) {
+	gvk := matchedKind(policy)
+
+	var obj any = map[string]any{
+		"apiVersion": gvk.GroupVersion().String(),
+		"kind":       gvk.Kind,
+		"metadata":   map[string]any{"name": name},
+	}
+
+	for _, path := range paths {
+		obj = insertPath(obj, path)
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("marshal object: %w", err)
+	}
+
+	return data, nil
+}
+
+// insertPath sets path in node to the placeholder, keeping values already present.
+// A "[]" segment stands for the elements of a list.
+func insertPath(node any, path []string) any {
+	if len(path) == 0 {
+		if node == nil {
+			return placeholder
+		}
+
+		return node
+	}
+
+	if path[0] == listElement {
+		var elem any
+		if list, ok := node.([]any); ok && len(list) > 0 {
+			elem = list[0]
+		}
+
+		return []any{insertPath(elem, path[1:])}
+	}
+
+	fields, ok := node.(map[string]any)
+	if !ok {
+		fields = map[string]any{}
+	}
+
+	fields[path[0]] = insertPath(fields[path[0]], path[1:])
+
+	return fields
+}
+
+// matchedKind returns the kind of the first resource matched by the policy, or
+// placeholders when it cannot be determined.
+func matchedKind(policy *admissionregv1.ValidatingAdmissionPolicy) schema.GroupVersionKind {
+	gvk := schema.GroupVersionKind{Version: placeholder, Kind: placeholder}
+
+	if policy.Spec.MatchConstraints == nil || len(policy.Spec.MatchConstraints.ResourceRules) == 0 {
+		return gvk
+	}
+
+	rule := policy.Spec.MatchConstraints.ResourceRules[0]
+	if len(rule.APIGroups) == 0 || len(rule.APIVersions) == 0 || len(rule.Resources) == 0 ||
+		rule.APIGroups[0] == "*" || rule.APIVersions[0] == "*" {
+		return gvk
+	}
+
+	gvk.Group, gvk.Version = rule.APIGroups[0], rule.APIVersions[0]
+	resource, _, _ := strings.Cut(rule.Resources[0], "/")
+
+	var kinds []string
+
+	for known := range scheme.Scheme.AllKnownTypes() {
+		if known.Group != gvk.Group || known.Version != gvk.Version {
+			continue
+		}
+
+		if plural, _ := meta.UnsafeGuessKindToResource(known); plural.Resource == resource {
+			kinds = append(kinds, known.Kind)
+		}
+	}
+
+	if len(kinds) > 0 {
+		sort.Strings(kinds)
+		gvk.Kind = kinds[0]
+	}
+
+	return gvk
+}
@@ -0,0 +1,205 @@
+package gen
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+//nolint:gochecknoglobals // Test flag
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	policySet, err := loader.LoadPolicySet(filepath.Join("testdata", "suite"))
+	if err != nil {
+		t.Fatalf("LoadPolicySet() error = %v", err)
+	}
+
+	files, err := Generate(policySet.ValidatingPolicies)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	if _, err := Write(dir, files); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	goldenDir := filepath.Join("testdata", "golden")
+
+	if *update {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Write(goldenDir, files); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diff := cmp.Diff(readTree(t, goldenDir), readTree(t, dir)); diff != "" {
+		t.Errorf("generated tree mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// readTree returns the contents of the files under dir by relative path.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	tree := map[string]string{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		tree[filepath.ToSlash(rel)] = string(data)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read tree %s: %v", dir, err)
+	}
+
+	return tree
+}
+
+func TestGenerate_NoValidations(t *testing.T) {
+	t.Parallel()
+
+	_, err := Generate([]*admissionregv1.ValidatingAdmissionPolicy{{}})
+	if !errors.Is(err, errNoValidations) {
+		t.Errorf("Generate() error = %v, want %v", err, errNoValidations)
+	}
+}
+
+func TestWrite_RefusesOverwrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := []File{
+		{Path: "tests/p.0.allow.object.yaml", Content: []byte("new")},
+		{Path: "tests/p.0.deny.object.yaml", Content: []byte("new")},
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "tests"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := filepath.Join(dir, "tests", "p.0.deny.object.yaml")
+	if err := os.WriteFile(existing, []byte("edited"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := Write(dir, files)
+	if !errors.Is(err, errFilesExist) || !strings.Contains(err.Error(), existing) {
+		t.Fatalf("Write() error = %v, want %v naming %s", err, errFilesExist, existing)
+	}
+
+	if len(written) != 0 {
+		t.Errorf("Write() wrote %v, want nothing", written)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "tests", "p.0.allow.object.yaml")); !os.IsNotExist(err) {
+		t.Errorf("allow file was created despite the conflict")
+	}
+
+	if data, _ := os.ReadFile(existing); string(data) != "edited" {
+		t.Errorf("existing file was overwritten: %q", data)
+	}
+}
+
+func TestFieldPaths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expression string
+		variables  map[string]string
+		want       []string
+	}{
+		{
+			name:       "select",
+			expression: "object.spec.replicas <= 5",
+			want:       []string{"spec.replicas"},
+		},
+		{
+			name:       "has macro",
+			expression: "has(object.metadata.labels) && has(object.spec.template.spec)",
+			want:       []string{"metadata.labels", "spec.template.spec"},
+		},
+		{
+			name:       "string index",
+			expression: "object.metadata.labels['app.kubernetes.io/name'] == 'web'",
+			want:       []string{"metadata.labels.app.kubernetes.io/name"},
+		},
+		{
+			name:       "comprehension variable",
+			expression: "object.spec.containers.all(c, c.securityContext.privileged == false)",
+			want:       []string{"spec.containers", "spec.containers.[].securityContext.privileged"},
+		},
+		{
+			name:       "policy variable",
+			expression: "variables.replicas > 1",
+			variables:  map[string]string{"replicas": "object.spec.replicas"},
+			want:       []string{"spec.replicas"},
+		},
+		{
+			name:       "comprehension over policy variable",
+			expression: "variables.containers.all(c, has(c.image))",
+			variables:  map[string]string{"containers": "object.spec.containers"},
+			want:       []string{"spec.containers", "spec.containers.[].image"},
+		},
+		{
+			name:       "self-referencing variable",
+			expression: "variables.loop == 1",
+			variables:  map[string]string{"loop": "variables.loop + object.spec.replicas"},
+			want:       []string{"spec.replicas"},
+		},
+		{
+			name:       "other roots ignored",
+			expression: "request.operation == 'CREATE' && params.data.limit == oldObject.spec.limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paths, err := fieldPaths(tt.expression, tt.variables)
+			if err != nil {
+				t.Fatalf("fieldPaths() error = %v", err)
+			}
+
+			var got []string
+			for _, path := range paths {
+				got = append(got, strings.Join(path, "."))
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("fieldPaths() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
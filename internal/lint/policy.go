@@ -0,0 +1,138 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+)
+
+// Finding is an unguarded access in an expression of a policy.
+type Finding struct {
+	// Policy is the kind and name of the policy, e.g.
+	// `ValidatingAdmissionPolicy "require-team"`.
+	Policy string
+	// Field is the path of the expression in the policy, e.g.
+	// "spec.validations[0].expression".
+	Field      string
+	Expression string
+	Access     Access
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: unguarded access %s in: %s",
+		f.Policy, f.Field, f.Access, strings.Join(strings.Fields(f.Expression), " "))
+}
+
+// expression is a CEL expression and its path in a policy.
+type expression struct {
+	field string
+	text  string
+}
+
+// ValidatingPolicy returns the unguarded accesses in the expressions of
+// policy. Expressions that do not parse are skipped; compiling them reports
+// the error.
+func ValidatingPolicy(policy *admissionregv1.ValidatingAdmissionPolicy) []Finding {
+	return findings(fmt.Sprintf("ValidatingAdmissionPolicy %q", policy.Name), validatingExpressions(policy))
+}
+
+// MutatingPolicy returns the unguarded accesses in the expressions of policy.
+func MutatingPolicy(policy *admissionv1beta1.MutatingAdmissionPolicy) []Finding {
+	return findings(fmt.Sprintf("MutatingAdmissionPolicy %q", policy.Name), mutatingExpressions(policy))
+}
+
+// ValidatingParamsReaders returns the paths of the expressions of policy that
+// read the params variable, e.g. "spec.validations[0].expression".
+func ValidatingParamsReaders(policy *admissionregv1.ValidatingAdmissionPolicy) []string {
+	return paramsReaders(validatingExpressions(policy))
+}
+
+// MutatingParamsReaders returns the paths of the expressions of policy that
+// read the params variable.
+func MutatingParamsReaders(policy *admissionv1beta1.MutatingAdmissionPolicy) []string {
+	return paramsReaders(mutatingExpressions(policy))
+}
+
+func validatingExpressions(policy *admissionregv1.ValidatingAdmissionPolicy) []expression {
+	spec := &policy.Spec
+
+	var expressions []expression
+
+	for i, c := range spec.MatchConditions {
+		expressions = append(expressions, expression{fmt.Sprintf("spec.matchConditions[%d].expression", i), c.Expression})
+	}
+
+	for i, v := range spec.Variables {
+		expressions = append(expressions, expression{fmt.Sprintf("spec.variables[%d].expression", i), v.Expression})
+	}
+
+	for i, v := range spec.Validations {
+		expressions = append(expressions, expression{fmt.Sprintf("spec.validations[%d].expression", i), v.Expression})
+		if v.MessageExpression != "" {
+			expressions = append(expressions, expression{fmt.Sprintf("spec.validations[%d].messageExpression", i), v.MessageExpression})
+		}
+	}
+
+	for i, a := range spec.AuditAnnotations {
+		expressions = append(expressions, expression{fmt.Sprintf("spec.auditAnnotations[%d].valueExpression", i), a.ValueExpression})
+	}
+
+	return expressions
+}
+
+func mutatingExpressions(policy *admissionv1beta1.MutatingAdmissionPolicy) []expression {
+	spec := &policy.Spec
+
+	var expressions []expression
+
+	for i, c := range spec.MatchConditions {
+		expressions = append(expressions, expression{fmt.Sprintf("spec.matchConditions[%d].expression", i), c.Expression})
+	}
+
+	for i, v := range spec.Variables {
+		expressions = append(expressions, expression{fmt.Sprintf("spec.variables[%d].expression", i), v.Expression})
+	}
+
+	for i, m := range spec.Mutations {
+		if m.JSONPatch != nil {
+			expressions = append(expressions, expression{fmt.Sprintf("spec.mutations[%d].jsonPatch.expression", i), m.JSONPatch.Expression})
+		}
+
+		if m.ApplyConfiguration != nil {
+			expressions = append(expressions, expression{fmt.Sprintf("spec.mutations[%d].applyConfiguration.expression", i), m.ApplyConfiguration.Expression})
+		}
+	}
+
+	return expressions
+}
+
+func findings(policy string, expressions []expression) []Finding {
+	var out []Finding
+
+	for _, e := range expressions {
+		accesses, err := UnguardedAccesses(e.text)
+		if err != nil {
+			continue
+		}
+
+		for _, access := range accesses {
+			out = append(out, Finding{Policy: policy, Field: e.field, Expression: e.text, Access: access})
+		}
+	}
+
+	return out
+}
+
+func paramsReaders(expressions []expression) []string {
+	var fields []string
+
+	for _, e := range expressions {
+		if reads, err := ReadsVariable(e.text, "params"); err == nil && reads {
+			fields = append(fields, e.field)
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,377 @@
+// Package lint statically analyzes the CEL expressions of admission policies
+// for accesses that fail at runtime on objects missing a field.
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+)
+
+// AllowUnguarded is the comment that suppresses unguarded access findings
+// for the expression containing it, e.g. "object.spec.x // kat:allow-unguarded".
+const AllowUnguarded = "kat:allow-unguarded"
+
+// roots are the variables whose fields may be absent at admission time.
+//
+//nolint:gochecknoglobals // Static lookup table
+var roots = map[string]bool{
+	"object":    true,
+	"oldObject": true,
+	"params":    true,
+}
+
+// alwaysPresent are the paths the API server sets on every object.
+//
+//nolint:gochecknoglobals // Static lookup table
+var alwaysPresent = map[string]bool{
+	"object.apiVersion":       true,
+	"object.kind":             true,
+	"object.metadata":         true,
+	"object.metadata.name":    true,
+	"oldObject.apiVersion":    true,
+	"oldObject.kind":          true,
+	"oldObject.metadata":      true,
+	"oldObject.metadata.name": true,
+}
+
+// Access is a select chain on object, oldObject or params with a field that
+// no has() check or optional access guards.
+type Access struct {
+	// Path is the accessed path, e.g. "object.metadata.labels.owner".
+	Path string
+	// Unguarded is the first prefix of Path that may be absent, e.g.
+	// "object.metadata.labels".
+	Unguarded string
+}
+
+func (a Access) String() string {
+	if a.Path == a.Unguarded {
+		return a.Path
+	}
+
+	return fmt.Sprintf("%s (%s may be absent)", a.Path, a.Unguarded)
+}
+
+// UnguardedAccesses returns the field accesses of expression on object,
+// oldObject and params that are not dominated by a has() check or an
+// optional access such as "object.?spec". A has() check guards the other
+// operands of && and ||, and the branches of a conditional:
+//
+//	has(object.spec.replicas) && object.spec.replicas > 1
+//	!has(object.spec.replicas) || object.spec.replicas > 1
+//	has(object.spec.replicas) ? object.spec.replicas : 1
+//
+// The analysis is syntactic and does not know which fields a schema
+// requires or defaults, so it reports fields that are in fact always set,
+// except apiVersion, kind, metadata and metadata.name. Such findings are
+// suppressed by a comment containing AllowUnguarded in the expression.
+func UnguardedAccesses(expression string) ([]Access, error) {
+	if strings.Contains(expression, AllowUnguarded) {
+		return nil, nil
+	}
+
+	env, err := cel.NewEnv(cel.OptionalTypes())
+	if err != nil {
+		return nil, fmt.Errorf("create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Parse(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("parse expression: %w", issues.Err())
+	}
+
+	w := &walker{seen: map[string]bool{}}
+	w.walk(ast.NativeRep().Expr(), nil)
+
+	return w.accesses, nil
+}
+
+type walker struct {
+	accesses []Access
+	seen     map[string]bool
+}
+
+// walk visits e with the paths guarded by the enclosing expressions.
+func (w *walker) walk(e celast.Expr, guarded []string) {
+	if root, fields, safeFrom, ok := selectChain(e); ok && roots[root] && len(fields) > 0 {
+		w.check(root, fields, safeFrom, guarded)
+
+		return
+	}
+
+	if e.Kind() == celast.CallKind && w.walkLogic(e.AsCall(), guarded) {
+		return
+	}
+
+	for _, child := range children(e) {
+		w.walk(child, guarded)
+	}
+}
+
+// walkLogic walks the operands of the logical operators, which guard each
+// other, and reports whether call is one.
+func (w *walker) walkLogic(call celast.CallExpr, guarded []string) bool {
+	args := call.Args()
+
+	switch call.FunctionName() {
+	case operators.LogicalAnd, operators.LogicalOr:
+		// CEL's && and || are commutative: an error on one side is absorbed
+		// when the other side decides the result, so guards apply both ways.
+		guards := positiveGuards
+		if call.FunctionName() == operators.LogicalOr {
+			guards = negativeGuards
+		}
+
+		for i, arg := range args {
+			scope := slices.Clone(guarded)
+
+			for j, other := range args {
+				if i != j {
+					scope = append(scope, guards(other)...)
+				}
+			}
+
+			w.walk(arg, scope)
+		}
+
+		return true
+	case operators.Conditional:
+		w.walk(args[0], guarded)
+		w.walk(args[1], append(slices.Clone(guarded), positiveGuards(args[0])...))
+		w.walk(args[2], append(slices.Clone(guarded), negativeGuards(args[0])...))
+
+		return true
+	default:
+		return false
+	}
+}
+
+// check records the first field of root.fields before safeFrom that is
+// neither always present nor guarded.
+func (w *walker) check(root string, fields []string, safeFrom int, guarded []string) {
+	for i := range min(safeFrom, len(fields)) {
+		prefix := joinPath(root, fields[:i+1])
+		if alwaysPresent[prefix] || slices.Contains(guarded, prefix) {
+			continue
+		}
+
+		path := joinPath(root, fields)
+		if !w.seen[path] {
+			w.seen[path] = true
+			w.accesses = append(w.accesses, Access{Path: path, Unguarded: prefix})
+		}
+
+		return
+	}
+}
+
+// ReadsVariable reports whether expression refers to the variable name, such
+// as "params".
+func ReadsVariable(expression, name string) (bool, error) {
+	env, err := cel.NewEnv(cel.OptionalTypes())
+	if err != nil {
+		return false, fmt.Errorf("create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Parse(expression)
+	if issues.Err() != nil {
+		return false, fmt.Errorf("parse expression: %w", issues.Err())
+	}
+
+	return readsIdent(ast.NativeRep().Expr(), name), nil
+}
+
+func readsIdent(e celast.Expr, name string) bool {
+	if e.Kind() == celast.IdentKind {
+		return e.AsIdent() == name
+	}
+
+	return slices.ContainsFunc(children(e), func(child celast.Expr) bool { return readsIdent(child, name) })
+}
+
+// children returns the direct subexpressions of e.
+func children(e celast.Expr) []celast.Expr {
+	switch e.Kind() {
+	case celast.SelectKind:
+		return []celast.Expr{e.AsSelect().Operand()}
+	case celast.CallKind:
+		call := e.AsCall()
+		if call.IsMemberFunction() {
+			return append([]celast.Expr{call.Target()}, call.Args()...)
+		}
+
+		return call.Args()
+	case celast.ComprehensionKind:
+		c := e.AsComprehension()
+
+		return []celast.Expr{c.IterRange(), c.AccuInit(), c.LoopCondition(), c.LoopStep(), c.Result()}
+	case celast.ListKind:
+		return e.AsList().Elements()
+	case celast.MapKind:
+		var out []celast.Expr
+		for _, entry := range e.AsMap().Entries() {
+			out = append(out, entry.AsMapEntry().Key(), entry.AsMapEntry().Value())
+		}
+
+		return out
+	case celast.StructKind:
+		var out []celast.Expr
+		for _, f := range e.AsStruct().Fields() {
+			out = append(out, f.AsStructField().Value())
+		}
+
+		return out
+	default:
+		return nil
+	}
+}
+
+// positiveGuards returns the paths known to be present when e is true.
+func positiveGuards(e celast.Expr) []string {
+	switch e.Kind() {
+	case celast.SelectKind:
+		sel := e.AsSelect()
+		if !sel.IsTestOnly() {
+			return nil
+		}
+
+		return chainPrefixes(sel.Operand(), sel.FieldName())
+	case celast.CallKind:
+		call := e.AsCall()
+		args := call.Args()
+
+		switch call.FunctionName() {
+		case operators.LogicalAnd:
+			var guards []string
+			for _, arg := range args {
+				guards = append(guards, positiveGuards(arg)...)
+			}
+
+			return guards
+		case operators.LogicalNot:
+			return negativeGuards(args[0])
+		case operators.In:
+			if key, ok := stringLiteral(args[0]); ok {
+				return chainPrefixes(args[1], key)
+			}
+		}
+	default:
+	}
+
+	return nil
+}
+
+// negativeGuards returns the paths known to be present when e is false.
+func negativeGuards(e celast.Expr) []string {
+	if e.Kind() != celast.CallKind {
+		return nil
+	}
+
+	call := e.AsCall()
+
+	switch call.FunctionName() {
+	case operators.LogicalOr:
+		var guards []string
+		for _, arg := range call.Args() {
+			guards = append(guards, negativeGuards(arg)...)
+		}
+
+		return guards
+	case operators.LogicalNot:
+		return positiveGuards(call.Args()[0])
+	default:
+		return nil
+	}
+}
+
+// chainPrefixes returns every prefix of the select chain operand.field that
+// is rooted in a variable, from the first field on.
+func chainPrefixes(operand celast.Expr, field string) []string {
+	root, fields, _, ok := selectChain(operand)
+	if !ok {
+		return nil
+	}
+
+	fields = append(fields, field)
+	prefixes := make([]string, 0, len(fields))
+
+	for i := range fields {
+		prefixes = append(prefixes, joinPath(root, fields[:i+1]))
+	}
+
+	return prefixes
+}
+
+// selectChain decomposes e into a variable and the fields, possibly none,
+// selected from it by field selection or indexing with a string literal. Fields from safeFrom
+// on follow an optional access and so never fail.
+func selectChain(e celast.Expr) (root string, fields []string, safeFrom int, ok bool) {
+	var reversed []string
+
+	optionalAt := -1
+
+	for {
+		switch e.Kind() {
+		case celast.IdentKind:
+			slices.Reverse(reversed)
+
+			safeFrom = len(reversed)
+			if optionalAt >= 0 {
+				safeFrom = len(reversed) - 1 - optionalAt
+			}
+
+			return e.AsIdent(), reversed, safeFrom, true
+		case celast.SelectKind:
+			sel := e.AsSelect()
+			if sel.IsTestOnly() {
+				return "", nil, 0, false
+			}
+
+			reversed = append(reversed, sel.FieldName())
+			e = sel.Operand()
+		case celast.CallKind:
+			call := e.AsCall()
+			args := call.Args()
+
+			switch call.FunctionName() {
+			case operators.Index, operators.OptIndex, operators.OptSelect:
+			default:
+				return "", nil, 0, false
+			}
+
+			key, isString := stringLiteral(args[1])
+			if !isString {
+				return "", nil, 0, false
+			}
+
+			if call.FunctionName() != operators.Index {
+				optionalAt = len(reversed)
+			}
+
+			reversed = append(reversed, key)
+			e = args[0]
+		default:
+			return "", nil, 0, false
+		}
+	}
+}
+
+func stringLiteral(e celast.Expr) (string, bool) {
+	if e.Kind() != celast.LiteralKind {
+		return "", false
+	}
+
+	s, ok := e.AsLiteral().(types.String)
+
+	return string(s), ok
+}
+
+func joinPath(root string, fields []string) string {
+	return root + "." + strings.Join(fields, ".")
+}
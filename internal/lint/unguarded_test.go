@@ -0,0 +1,116 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUnguardedAccesses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expression string
+		want       []string
+	}{
+		{name: "always present fields", expression: "object.metadata.name == 'x' && object.kind == 'Pod'"},
+		{name: "unguarded field", expression: "object.spec.replicas > 1", want: []string{"object.spec.replicas (object.spec may be absent)"}},
+		{name: "unguarded leaf", expression: "has(object.spec) && object.spec.replicas > 1", want: []string{"object.spec.replicas"}},
+		{name: "has guards conjunction", expression: "has(object.spec.replicas) && object.spec.replicas > 1", want: []string{"object.spec"}},
+		{name: "has of guarded parent", expression: "has(object.spec) && has(object.spec.replicas) && object.spec.replicas > 1"},
+		{name: "guard after access", expression: "object.spec.replicas > 1 && has(object.spec) && has(object.spec.replicas)"},
+		{name: "negated has guards disjunction", expression: "!has(object.spec) || !has(object.spec.replicas) || object.spec.replicas > 1"},
+		{name: "has guards conditional branch", expression: "has(object.spec) && has(object.spec.replicas) ? object.spec.replicas : 1"},
+		{
+			name:       "negated condition guards else branch",
+			expression: "!has(object.spec) ? 0 : object.spec.replicas",
+			want:       []string{"object.spec.replicas"},
+		},
+		{name: "in guards map key", expression: "has(object.metadata.labels) && 'team' in object.metadata.labels && object.metadata.labels.team != ''"},
+		{name: "index with string literal", expression: "object.metadata.labels['team'] != ''", want: []string{"object.metadata.labels.team (object.metadata.labels may be absent)"}},
+		{name: "optional select", expression: "object.?spec.?replicas.orValue(1) > 1"},
+		{name: "fields before optional select", expression: "object.spec.?replicas.orValue(1) > 1", want: []string{"object.spec.replicas (object.spec may be absent)"}},
+		{name: "old object and params", expression: "oldObject.spec.x == params.data.x", want: []string{"oldObject.spec.x (oldObject.spec may be absent)", "params.data.x (params.data may be absent)"}},
+		{name: "other variables are not checked", expression: "request.userInfo.username != '' && namespaceObject.metadata.labels.env != ''"},
+		{
+			name:       "comprehension over unguarded field",
+			expression: "object.spec.containers.all(c, has(c.image))",
+			want:       []string{"object.spec.containers (object.spec may be absent)"},
+		},
+		{name: "guard in enclosing scope of comprehension", expression: "!has(object.spec) || !has(object.spec.containers) || object.spec.containers.all(c, c.image != '')"},
+		{name: "repeated access reported once", expression: "object.spec.x + object.spec.x", want: []string{"object.spec.x (object.spec may be absent)"}},
+		{name: "suppressed by comment", expression: "object.spec.replicas > 1 // kat:allow-unguarded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accesses, err := UnguardedAccesses(tt.expression)
+			if err != nil {
+				t.Fatalf("UnguardedAccesses() error = %v", err)
+			}
+
+			var got []string
+			for _, access := range accesses {
+				got = append(got, access.String())
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("UnguardedAccesses(%q) mismatch (-want +got):\n%s", tt.expression, diff)
+			}
+		})
+	}
+}
+
+func TestUnguardedAccesses_ParseError(t *testing.T) {
+	t.Parallel()
+
+	if _, err := UnguardedAccesses("object.spec.("); err == nil {
+		t.Error("UnguardedAccesses() error = nil, want parse error")
+	}
+}
+
+func TestPolicyFindings(t *testing.T) {
+	t.Parallel()
+
+	validating := &admissionregv1.ValidatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicas"},
+		Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+			MatchConditions: []admissionregv1.MatchCondition{{Name: "scaled", Expression: "object.spec.replicas > 0"}},
+			Validations: []admissionregv1.Validation{{
+				Expression:        "has(object.spec) && has(object.spec.replicas) && object.spec.replicas < 10",
+				MessageExpression: "'too many replicas: ' + string(object.spec.replicas)",
+			}},
+			AuditAnnotations: []admissionregv1.AuditAnnotation{{Key: "invalid", ValueExpression: "object.spec.("}},
+		},
+	}
+
+	mutating := &admissionv1beta1.MutatingAdmissionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+		Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+			Mutations: []admissionv1beta1.Mutation{{
+				PatchType:          admissionv1beta1.PatchTypeApplyConfiguration,
+				ApplyConfiguration: &admissionv1beta1.ApplyConfiguration{Expression: "Object{spec: Object.spec{replicas: params.spec.replicas}}"},
+			}},
+		},
+	}
+
+	var got []string
+	for _, finding := range append(ValidatingPolicy(validating), MutatingPolicy(mutating)...) {
+		got = append(got, finding.String())
+	}
+
+	want := []string{
+		`ValidatingAdmissionPolicy "replicas": spec.matchConditions[0].expression: unguarded access object.spec.replicas (object.spec may be absent) in: object.spec.replicas > 0`,
+		`ValidatingAdmissionPolicy "replicas": spec.validations[0].messageExpression: unguarded access object.spec.replicas (object.spec may be absent) in: 'too many replicas: ' + string(object.spec.replicas)`,
+		`MutatingAdmissionPolicy "defaults": spec.mutations[0].applyConfiguration.expression: unguarded access params.spec.replicas (params.spec may be absent) in: Object{spec: Object.spec{replicas: params.spec.replicas}}`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("findings mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,191 @@
+package loader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	errNotAdmissionReview         = errors.New("not an AdmissionReview")
+	errUnsupportedAdmissionReview = errors.New("unsupported AdmissionReview version")
+	errAdmissionReviewNoRequest   = errors.New("AdmissionReview has no request")
+	errAdmissionReviewConflict    = errors.New("AdmissionReview cannot be combined with request, object, or oldObject files")
+)
+
+// admissionReviewSuffixes are the file suffixes of captured AdmissionReview payloads.
+//
+//nolint:gochecknoglobals // Static list
+var admissionReviewSuffixes = []string{".admissionreview.json", ".admissionreview.yaml", ".admissionreview.yml"}
+
+// isAdmissionReviewFile reports whether name is a captured AdmissionReview.
+func isAdmissionReviewFile(name string) bool {
+	for _, suffix := range admissionReviewSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAdmissionReviewFiles rejects tests that mix a captured AdmissionReview with files
+// that also describe the request; the review is the complete request.
+func checkAdmissionReviewFiles(filePaths []string) error {
+	var hasReview, hasRequest bool
+
+	for _, filePath := range filePaths {
+		switch {
+		case isAdmissionReviewFile(filePath):
+			hasReview = true
+		case hasYAMLSuffix(filePath, ".request"),
+			hasYAMLSuffix(filePath, ".object"),
+			hasYAMLSuffix(filePath, ".oldObject"):
+			hasRequest = true
+		}
+	}
+
+	if hasReview && hasRequest {
+		return errAdmissionReviewConflict
+	}
+
+	return nil
+}
+
+// admissionReviewHeader holds the fields used to pick the AdmissionReview version.
+type admissionReviewHeader struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+// parseAdmissionReview extracts the AdmissionRequest of a captured admission.k8s.io/v1
+// or v1beta1 AdmissionReview (JSON or YAML). The embedded object and oldObject become the
+// test objects; expected outcomes come from the usual files next to the review.
+func parseAdmissionReview(testReq *testRequest, data []byte) error {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return fmt.Errorf("convert AdmissionReview to JSON: %w", err)
+	}
+
+	req, err := decodeAdmissionRequest(jsonData)
+	if err != nil {
+		return err
+	}
+
+	if req.UID == "" {
+		req.UID = types.UID("test-" + testReq.Name)
+	}
+
+	if testReq.Object, err = rawToUnstructured(req.Object, "object"); err != nil {
+		return err
+	}
+
+	if testReq.OldObject, err = rawToUnstructured(req.OldObject, "oldObject"); err != nil {
+		return err
+	}
+
+	if req.UserInfo.Username != "" || len(req.UserInfo.Groups) > 0 {
+		userInfo := req.UserInfo
+		testReq.UserInfo = &userInfo
+	}
+
+	testReq.Request = req
+	testReq.NamespaceName = req.Namespace
+
+	prefix := filepath.Join(filepath.Dir(testReq.FilePath), testBaseName(filepath.Base(testReq.FilePath)))
+
+	if err := loadGoldFileAt(testReq, yamlFilePath(prefix, ".gold")); err != nil {
+		return err
+	}
+
+	return loadMessageFileAt(testReq, prefix+".message.txt")
+}
+
+func decodeAdmissionRequest(data []byte) (*admissionv1.AdmissionRequest, error) {
+	var header admissionReviewHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("unmarshal AdmissionReview: %w", err)
+	}
+
+	if header.Kind != "AdmissionReview" {
+		return nil, fmt.Errorf("%w: kind %q", errNotAdmissionReview, header.Kind)
+	}
+
+	switch header.APIVersion {
+	case admissionv1.SchemeGroupVersion.String():
+		var review admissionv1.AdmissionReview
+		if err := json.Unmarshal(data, &review); err != nil {
+			return nil, fmt.Errorf("unmarshal AdmissionReview: %w", err)
+		}
+
+		if review.Request == nil {
+			return nil, errAdmissionReviewNoRequest
+		}
+
+		return review.Request, nil
+	case admissionv1beta1.SchemeGroupVersion.String():
+		var review admissionv1beta1.AdmissionReview
+		if err := json.Unmarshal(data, &review); err != nil {
+			return nil, fmt.Errorf("unmarshal AdmissionReview: %w", err)
+		}
+
+		if review.Request == nil {
+			return nil, errAdmissionReviewNoRequest
+		}
+
+		return convertV1Beta1Request(review.Request), nil
+	default:
+		return nil, fmt.Errorf("%w: %q, expected %s or %s", errUnsupportedAdmissionReview, header.APIVersion,
+			admissionv1.SchemeGroupVersion, admissionv1beta1.SchemeGroupVersion)
+	}
+}
+
+// convertV1Beta1Request converts a v1beta1 AdmissionRequest to v1; the two versions
+// carry the same fields.
+func convertV1Beta1Request(req *admissionv1beta1.AdmissionRequest) *admissionv1.AdmissionRequest {
+	return &admissionv1.AdmissionRequest{
+		UID:                req.UID,
+		Kind:               req.Kind,
+		Resource:           req.Resource,
+		SubResource:        req.SubResource,
+		RequestKind:        req.RequestKind,
+		RequestResource:    req.RequestResource,
+		RequestSubResource: req.RequestSubResource,
+		Name:               req.Name,
+		Namespace:          req.Namespace,
+		Operation:          admissionv1.Operation(req.Operation),
+		UserInfo:           req.UserInfo,
+		Object:             req.Object,
+		OldObject:          req.OldObject,
+		DryRun:             req.DryRun,
+		Options:            req.Options,
+	}
+}
+
+// rawToUnstructured decodes an embedded object of an AdmissionRequest. An absent or
+// null object yields nil.
+func rawToUnstructured(raw runtime.RawExtension, field string) (*unstructured.Unstructured, error) {
+	if len(raw.Raw) == 0 || string(raw.Raw) == "null" {
+		return nil, nil //nolint:nilnil // absent objects are valid, e.g. oldObject on CREATE
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw.Raw, &obj); err != nil {
+		return nil, fmt.Errorf("%s: unmarshal embedded object: %w", field, err)
+	}
+
+	if err := validateWithScheme(obj, field, nil); err != nil {
+		return nil, err
+	}
+
+	return &unstructured.Unstructured{Object: obj}, nil
+}
@@ -0,0 +1,156 @@
+package loader
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+func TestParseAdmissionReview(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		data          string
+		wantErr       error
+		wantOperation admissionv1.Operation
+		wantUID       string
+		wantUser      string
+		wantObject    bool
+		wantOldObject bool
+	}{
+		{
+			name: "v1 json",
+			data: `{"apiVersion": "admission.k8s.io/v1", "kind": "AdmissionReview", "request": {
+  "uid": "abc", "operation": "CREATE", "namespace": "default",
+  "kind": {"group": "", "version": "v1", "kind": "Pod"},
+  "resource": {"group": "", "version": "v1", "resource": "pods"},
+  "userInfo": {"username": "jane"},
+  "object": {"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "p"}},
+  "oldObject": null}}`,
+			wantOperation: admissionv1.Create,
+			wantUID:       "abc",
+			wantUser:      "jane",
+			wantObject:    true,
+		},
+		{
+			name: "v1beta1 yaml",
+			data: `apiVersion: admission.k8s.io/v1beta1
+kind: AdmissionReview
+request:
+  operation: DELETE
+  kind: {group: "", version: v1, kind: ConfigMap}
+  oldObject: {apiVersion: v1, kind: ConfigMap, metadata: {name: c}}
+`,
+			wantOperation: admissionv1.Delete,
+			wantUID:       "test-t.yaml",
+			wantOldObject: true,
+		},
+		{
+			name:    "not a review",
+			data:    "apiVersion: v1\nkind: Pod\n",
+			wantErr: errNotAdmissionReview,
+		},
+		{
+			name:    "unsupported version",
+			data:    "apiVersion: admission.k8s.io/v2\nkind: AdmissionReview\nrequest: {}\n",
+			wantErr: errUnsupportedAdmissionReview,
+		},
+		{
+			name:    "response only",
+			data:    "apiVersion: admission.k8s.io/v1\nkind: AdmissionReview\nresponse: {allowed: true}\n",
+			wantErr: errAdmissionReviewNoRequest,
+		},
+		{
+			name: "embedded object without kind",
+			data: `apiVersion: admission.k8s.io/v1
+kind: AdmissionReview
+request:
+  operation: CREATE
+  object: {apiVersion: v1, metadata: {name: p}}
+`,
+			wantErr: errKindRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testReq := &testRequest{
+				Name:     "t.yaml",
+				FilePath: filepath.Join(t.TempDir(), "t.admissionreview.yaml"),
+			}
+
+			err := parseAdmissionReview(testReq, []byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseAdmissionReview() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got := testReq.Request.Operation; got != tt.wantOperation {
+				t.Errorf("Operation = %q, want %q", got, tt.wantOperation)
+			}
+
+			if got := string(testReq.Request.UID); got != tt.wantUID {
+				t.Errorf("UID = %q, want %q", got, tt.wantUID)
+			}
+
+			gotUser := ""
+			if testReq.UserInfo != nil {
+				gotUser = testReq.UserInfo.Username
+			}
+
+			if gotUser != tt.wantUser {
+				t.Errorf("UserInfo.Username = %q, want %q", gotUser, tt.wantUser)
+			}
+
+			if got := testReq.Object != nil; got != tt.wantObject {
+				t.Errorf("has object = %v, want %v", got, tt.wantObject)
+			}
+
+			if got := testReq.OldObject != nil; got != tt.wantOldObject {
+				t.Errorf("has oldObject = %v, want %v", got, tt.wantOldObject)
+			}
+		})
+	}
+}
+
+func TestCheckAdmissionReviewFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		filePaths []string
+		wantErr   error
+	}{
+		{
+			name:      "review with expectations",
+			filePaths: []string{"t.admissionreview.json", "t.warnings.txt", "t.authorizer.yaml"},
+		},
+		{
+			name:      "review with object",
+			filePaths: []string{"t.admissionreview.yaml", "t.object.yaml"},
+			wantErr:   errAdmissionReviewConflict,
+		},
+		{
+			name:      "request without review",
+			filePaths: []string{"t.request.yaml", "t.object.yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := checkAdmissionReviewFiles(tt.filePaths); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkAdmissionReviewFiles() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
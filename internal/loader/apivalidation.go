@@ -0,0 +1,324 @@
+package loader
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/api/validate/content"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Limits the API server enforces on admission policies.
+const (
+	maxMatchConditions            = 64
+	maxAuditAnnotationValueLength = 5 * 1024
+)
+
+//nolint:gochecknoglobals // Static lookup table
+var supportedValidationReasons = []metav1.StatusReason{
+	metav1.StatusReasonUnauthorized,
+	metav1.StatusReasonForbidden,
+	metav1.StatusReasonInvalid,
+	metav1.StatusReasonRequestEntityTooLarge,
+}
+
+// validatePolicySet reports the policies and bindings of ps the API server
+// would reject on create, such as policies with more than 64 matchConditions
+// or without validations. It reimplements the documented API constraints;
+// CEL expressions are checked when they are compiled. Each problem starts with
+// the file and line of the rejected field when the resource was loaded from a
+// file.
+func validatePolicySet(ps *PolicySet) []error {
+	var errs []error
+
+	report := func(kind, name string, fieldErrs field.ErrorList) {
+		for _, fieldErr := range fieldErrs {
+			err := fmt.Errorf("%s %q %w: %w", kind, name, ErrInvalidResource, fieldErr)
+			if location := ps.location(kind, name, fieldErr.Field); location != "" {
+				err = fmt.Errorf("%s: %w", location, err)
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	for _, policy := range ps.ValidatingPolicies {
+		report("ValidatingAdmissionPolicy", policy.Name, validateValidatingPolicy(policy))
+	}
+
+	for _, binding := range ps.ValidatingBindings {
+		report("ValidatingAdmissionPolicyBinding", binding.Name, validateValidatingBinding(binding))
+	}
+
+	for _, policy := range ps.MutatingPolicies {
+		report("MutatingAdmissionPolicy", policy.Name, validateMutatingPolicy(policy))
+	}
+
+	for _, binding := range ps.MutatingBindings {
+		report("MutatingAdmissionPolicyBinding", binding.Name, validatePolicyName(binding.Spec.PolicyName))
+	}
+
+	return errs
+}
+
+func validateValidatingPolicy(policy *admissionregv1.ValidatingAdmissionPolicy) field.ErrorList {
+	spec := &policy.Spec
+	specPath := field.NewPath("spec")
+
+	var errs field.ErrorList
+
+	if len(spec.Validations) == 0 && len(spec.AuditAnnotations) == 0 {
+		errs = append(errs, field.Required(specPath.Child("validations"), "validations or auditAnnotations must contain at least one item"))
+	}
+
+	conditions := make([]matchCondition, 0, len(spec.MatchConditions))
+	for _, c := range spec.MatchConditions {
+		conditions = append(conditions, matchCondition(c))
+	}
+
+	variables := make([]variable, 0, len(spec.Variables))
+	for _, v := range spec.Variables {
+		variables = append(variables, variable(v))
+	}
+
+	errs = append(errs, validateMatchConditions(conditions, specPath.Child("matchConditions"))...)
+	errs = append(errs, validateVariables(variables, specPath.Child("variables"))...)
+
+	for i, validation := range spec.Validations {
+		errs = append(errs, validateValidation(&validation, specPath.Child("validations").Index(i))...)
+	}
+
+	keys := sets.New[string]()
+
+	for i, annotation := range spec.AuditAnnotations {
+		annotationPath := specPath.Child("auditAnnotations").Index(i)
+		errs = append(errs, validateAuditAnnotation(policy.Name, annotation, annotationPath)...)
+
+		if keys.Has(annotation.Key) {
+			errs = append(errs, field.Duplicate(annotationPath.Child("key"), annotation.Key))
+		}
+
+		keys.Insert(annotation.Key)
+	}
+
+	return errs
+}
+
+func validateValidation(validation *admissionregv1.Validation, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if strings.TrimSpace(validation.Expression) == "" {
+		errs = append(errs, field.Required(fldPath.Child("expression"), ""))
+	}
+
+	switch {
+	case validation.Message != "" && strings.TrimSpace(validation.Message) == "":
+		errs = append(errs, field.Invalid(fldPath.Child("message"), validation.Message, "message must be non-empty if specified"))
+	case strings.ContainsAny(strings.TrimSpace(validation.Message), "\r\n"):
+		errs = append(errs, field.Invalid(fldPath.Child("message"), validation.Message, "message must not contain line breaks"))
+	}
+
+	if validation.MessageExpression != "" && strings.TrimSpace(validation.MessageExpression) == "" {
+		errs = append(errs, field.Invalid(fldPath.Child("messageExpression"), validation.MessageExpression, "must be non-empty if specified"))
+	}
+
+	if validation.Reason != nil && !slices.Contains(supportedValidationReasons, *validation.Reason) {
+		errs = append(errs, field.NotSupported(fldPath.Child("reason"), *validation.Reason, supportedValidationReasons))
+	}
+
+	return errs
+}
+
+// validateAuditAnnotation checks an audit annotation. Its key is combined with
+// the policy name as "<policy-name>/<key>", which must be a qualified name.
+func validateAuditAnnotation(policyName string, annotation admissionregv1.AuditAnnotation, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if annotation.Key == "" {
+		errs = append(errs, field.Required(fldPath.Child("key"), ""))
+	} else {
+		for _, msg := range content.IsLabelKey(policyName + "/" + annotation.Key) {
+			errs = append(errs, field.Invalid(fldPath.Child("key"), annotation.Key, msg))
+		}
+	}
+
+	switch {
+	case strings.TrimSpace(annotation.ValueExpression) == "":
+		errs = append(errs, field.Required(fldPath.Child("valueExpression"), ""))
+	case len(annotation.ValueExpression) > maxAuditAnnotationValueLength:
+		errs = append(errs, field.TooLong(fldPath.Child("valueExpression"), "", maxAuditAnnotationValueLength))
+	}
+
+	return errs
+}
+
+func validateValidatingBinding(binding *admissionregv1.ValidatingAdmissionPolicyBinding) field.ErrorList {
+	errs := validatePolicyName(binding.Spec.PolicyName)
+	actionsPath := field.NewPath("spec", "validationActions")
+
+	if len(binding.Spec.ValidationActions) == 0 {
+		return append(errs, field.Required(actionsPath, "at least one validation action is required"))
+	}
+
+	supported := []admissionregv1.ValidationAction{admissionregv1.Deny, admissionregv1.Warn, admissionregv1.Audit}
+	actions := sets.New[admissionregv1.ValidationAction]()
+
+	for i, action := range binding.Spec.ValidationActions {
+		switch {
+		case !slices.Contains(supported, action):
+			errs = append(errs, field.NotSupported(actionsPath.Index(i), action, supported))
+		case actions.Has(action):
+			errs = append(errs, field.Duplicate(actionsPath.Index(i), action))
+		}
+
+		actions.Insert(action)
+	}
+
+	if actions.Has(admissionregv1.Deny) && actions.Has(admissionregv1.Warn) {
+		errs = append(errs, field.Invalid(actionsPath, binding.Spec.ValidationActions, "must not contain both Deny and Warn (repeating the same validation failure information in the API response and headers serves no purpose)"))
+	}
+
+	return errs
+}
+
+func validateMutatingPolicy(policy *admissionv1beta1.MutatingAdmissionPolicy) field.ErrorList {
+	spec := &policy.Spec
+	specPath := field.NewPath("spec")
+
+	var errs field.ErrorList
+
+	if len(spec.Mutations) == 0 {
+		errs = append(errs, field.Required(specPath.Child("mutations"), "mutations must contain at least one item"))
+	}
+
+	conditions := make([]matchCondition, 0, len(spec.MatchConditions))
+	for _, c := range spec.MatchConditions {
+		conditions = append(conditions, matchCondition(c))
+	}
+
+	variables := make([]variable, 0, len(spec.Variables))
+	for _, v := range spec.Variables {
+		variables = append(variables, variable(v))
+	}
+
+	errs = append(errs, validateMatchConditions(conditions, specPath.Child("matchConditions"))...)
+	errs = append(errs, validateVariables(variables, specPath.Child("variables"))...)
+
+	for i, mutation := range spec.Mutations {
+		errs = append(errs, validateMutation(&mutation, specPath.Child("mutations").Index(i))...)
+	}
+
+	return errs
+}
+
+func validateMutation(mutation *admissionv1beta1.Mutation, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	switch mutation.PatchType {
+	case admissionv1beta1.PatchTypeJSONPatch:
+		if mutation.JSONPatch == nil || strings.TrimSpace(mutation.JSONPatch.Expression) == "" {
+			errs = append(errs, field.Required(fldPath.Child("jsonPatch", "expression"), "required when patchType is JSONPatch"))
+		}
+
+		if mutation.ApplyConfiguration != nil {
+			errs = append(errs, field.Forbidden(fldPath.Child("applyConfiguration"), "must not be set when patchType is JSONPatch"))
+		}
+	case admissionv1beta1.PatchTypeApplyConfiguration:
+		if mutation.ApplyConfiguration == nil || strings.TrimSpace(mutation.ApplyConfiguration.Expression) == "" {
+			errs = append(errs, field.Required(fldPath.Child("applyConfiguration", "expression"), "required when patchType is ApplyConfiguration"))
+		}
+
+		if mutation.JSONPatch != nil {
+			errs = append(errs, field.Forbidden(fldPath.Child("jsonPatch"), "must not be set when patchType is ApplyConfiguration"))
+		}
+	case "":
+		errs = append(errs, field.Required(fldPath.Child("patchType"), ""))
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("patchType"), mutation.PatchType,
+			[]admissionv1beta1.PatchType{admissionv1beta1.PatchTypeJSONPatch, admissionv1beta1.PatchTypeApplyConfiguration}))
+	}
+
+	return errs
+}
+
+func validatePolicyName(policyName string) field.ErrorList {
+	if policyName == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec", "policyName"), "")}
+	}
+
+	return nil
+}
+
+// matchCondition and variable are the fields shared by the v1 and v1beta1
+// types, which convert to them directly.
+type (
+	matchCondition struct{ Name, Expression string }
+	variable       struct{ Name, Expression string }
+)
+
+func validateMatchConditions(conditions []matchCondition, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if len(conditions) > maxMatchConditions {
+		errs = append(errs, field.TooMany(fldPath, len(conditions), maxMatchConditions))
+	}
+
+	names := sets.New[string]()
+
+	for i, condition := range conditions {
+		conditionPath := fldPath.Index(i)
+
+		switch {
+		case condition.Name == "":
+			errs = append(errs, field.Required(conditionPath.Child("name"), ""))
+		case names.Has(condition.Name):
+			errs = append(errs, field.Duplicate(conditionPath.Child("name"), condition.Name))
+		default:
+			for _, msg := range content.IsLabelKey(condition.Name) {
+				errs = append(errs, field.Invalid(conditionPath.Child("name"), condition.Name, msg))
+			}
+		}
+
+		names.Insert(condition.Name)
+
+		if strings.TrimSpace(condition.Expression) == "" {
+			errs = append(errs, field.Required(conditionPath.Child("expression"), ""))
+		}
+	}
+
+	return errs
+}
+
+func validateVariables(variables []variable, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	names := sets.New[string]()
+
+	for i, v := range variables {
+		variablePath := fldPath.Index(i)
+
+		switch {
+		case v.Name == "":
+			errs = append(errs, field.Required(variablePath.Child("name"), ""))
+		case names.Has(v.Name):
+			errs = append(errs, field.Duplicate(variablePath.Child("name"), v.Name))
+		default:
+			for _, msg := range content.IsCIdentifier(v.Name) {
+				errs = append(errs, field.Invalid(variablePath.Child("name"), v.Name, msg))
+			}
+		}
+
+		names.Insert(v.Name)
+
+		if strings.TrimSpace(v.Expression) == "" {
+			errs = append(errs, field.Required(variablePath.Child("expression"), ""))
+		}
+	}
+
+	return errs
+}
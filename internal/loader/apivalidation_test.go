@@ -0,0 +1,302 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateValidatingPolicy(t *testing.T) {
+	t.Parallel()
+
+	reason := func(r metav1.StatusReason) *metav1.StatusReason { return &r }
+
+	manyConditions := make([]admissionregv1.MatchCondition, 65)
+	for i := range manyConditions {
+		manyConditions[i] = admissionregv1.MatchCondition{Name: fmt.Sprintf("c%d", i), Expression: "true"}
+	}
+
+	valid := []admissionregv1.Validation{{Expression: "true"}}
+
+	tests := []struct {
+		name string
+		spec admissionregv1.ValidatingAdmissionPolicySpec
+		want []string
+	}{
+		{
+			name: "valid",
+			spec: admissionregv1.ValidatingAdmissionPolicySpec{
+				Validations:      []admissionregv1.Validation{{Expression: "true", Message: "ok", Reason: reason(metav1.StatusReasonForbidden)}},
+				AuditAnnotations: []admissionregv1.AuditAnnotation{{Key: "privileged", ValueExpression: "'true'"}},
+				MatchConditions:  []admissionregv1.MatchCondition{{Name: "example.com/is-pod", Expression: "true"}},
+				Variables:        []admissionregv1.Variable{{Name: "replicas", Expression: "object.spec.replicas"}},
+			},
+		},
+		{
+			name: "audit annotations only",
+			spec: admissionregv1.ValidatingAdmissionPolicySpec{
+				AuditAnnotations: []admissionregv1.AuditAnnotation{{Key: "seen", ValueExpression: "'true'"}},
+			},
+		},
+		{
+			name: "no validations",
+			want: []string{"spec.validations: Required value: validations or auditAnnotations must contain at least one item"},
+		},
+		{
+			name: "too many match conditions",
+			spec: admissionregv1.ValidatingAdmissionPolicySpec{Validations: valid, MatchConditions: manyConditions},
+			want: []string{"spec.matchConditions: Too many: 65: must have at most 64 items"},
+		},
+		{
+			name: "invalid match condition names",
+			spec: admissionregv1.ValidatingAdmissionPolicySpec{
+				Validations: valid,
+				MatchConditions: []admissionregv1.MatchCondition{
+					{Name: "", Expression: "true"},
+					{Name: "has space", Expression: "true"},
+					{Name: "dup", Expression: "true"},
+					{Name: "dup", Expression: " "},
+				},
+			},
+			want: []string{
+				"spec.matchConditions[0].name: Required value",
+				`spec.matchConditions[1].name: Invalid value: "has space"`,
+				`spec.matchConditions[3].name: Duplicate value: "dup"`,
+				"spec.matchConditions[3].expression: Required value",
+			},
+		},
+		{
+			name: "validation fields",
+			spec: admissionregv1.ValidatingAdmissionPolicySpec{
+				Validations: []admissionregv1.Validation{
+					{Expression: ""},
+					{Expression: "true", Message: "  "},
+					{Expression: "true", Message: "line\nbreak"},
+					{Expression: "true", Reason: reason("Warn")},
+					{Expression: "true", MessageExpression: " "},
+				},
+			},
+			want: []string{
+				"spec.validations[0].expression: Required value",
+				`spec.validations[1].message: Invalid value: "  ": message must be non-empty if specified`,
+				`spec.validations[2].message: Invalid value: "line\nbreak": message must not contain line breaks`,
+				`spec.validations[3].reason: Unsupported value: "Warn": supported values: "Unauthorized", "Forbidden", "Invalid", "RequestEntityTooLarge"`,
+				`spec.validations[4].messageExpression: Invalid value: " ": must be non-empty if specified`,
+			},
+		},
+		{
+			name: "audit annotations",
+			spec: admissionregv1.ValidatingAdmissionPolicySpec{
+				AuditAnnotations: []admissionregv1.AuditAnnotation{
+					{Key: "", ValueExpression: "'x'"},
+					{Key: "bad key!", ValueExpression: "'x'"},
+					{Key: strings.Repeat("k", 64), ValueExpression: "'x'"},
+					{Key: "dup", ValueExpression: "'x'"},
+					{Key: "dup", ValueExpression: ""},
+					{Key: "long", ValueExpression: "'" + strings.Repeat("x", 5*1024) + "'"},
+				},
+			},
+			want: []string{
+				"spec.auditAnnotations[0].key: Required value",
+				`spec.auditAnnotations[1].key: Invalid value: "bad key!"`,
+				`spec.auditAnnotations[2].key: Invalid value: "` + strings.Repeat("k", 64) + `"`,
+				"spec.auditAnnotations[4].valueExpression: Required value",
+				`spec.auditAnnotations[4].key: Duplicate value: "dup"`,
+				"spec.auditAnnotations[5].valueExpression: Too long: may not be more than 5120 bytes",
+			},
+		},
+		{
+			name: "variables",
+			spec: admissionregv1.ValidatingAdmissionPolicySpec{
+				Validations: valid,
+				Variables: []admissionregv1.Variable{
+					{Name: "", Expression: "1"},
+					{Name: "not-an-identifier", Expression: "1"},
+					{Name: "x", Expression: "1"},
+					{Name: "x", Expression: ""},
+				},
+			},
+			want: []string{
+				"spec.variables[0].name: Required value",
+				`spec.variables[1].name: Invalid value: "not-an-identifier"`,
+				`spec.variables[3].name: Duplicate value: "x"`,
+				"spec.variables[3].expression: Required value",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := &admissionregv1.ValidatingAdmissionPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy"}, Spec: tt.spec}
+
+			assertFieldErrors(t, validateValidatingPolicy(policy).ToAggregate(), tt.want)
+		})
+	}
+}
+
+func TestValidateValidatingBinding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		policyName string
+		actions    []admissionregv1.ValidationAction
+		want       []string
+	}{
+		{name: "valid", policyName: "p", actions: []admissionregv1.ValidationAction{admissionregv1.Deny, admissionregv1.Audit}},
+		{name: "warn and audit", policyName: "p", actions: []admissionregv1.ValidationAction{admissionregv1.Warn, admissionregv1.Audit}},
+		{
+			name:    "missing policy name and actions",
+			actions: nil,
+			want: []string{
+				"spec.policyName: Required value",
+				"spec.validationActions: Required value: at least one validation action is required",
+			},
+		},
+		{
+			name:       "unsupported and duplicate actions",
+			policyName: "p",
+			actions:    []admissionregv1.ValidationAction{"Block", admissionregv1.Audit, admissionregv1.Audit},
+			want: []string{
+				`spec.validationActions[0]: Unsupported value: "Block": supported values: "Deny", "Warn", "Audit"`,
+				`spec.validationActions[2]: Duplicate value: "Audit"`,
+			},
+		},
+		{
+			name:       "deny and warn",
+			policyName: "p",
+			actions:    []admissionregv1.ValidationAction{admissionregv1.Deny, admissionregv1.Warn},
+			want:       []string{"spec.validationActions: Invalid value: [\"Deny\",\"Warn\"]: must not contain both Deny and Warn"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			binding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+				Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{PolicyName: tt.policyName, ValidationActions: tt.actions},
+			}
+
+			assertFieldErrors(t, validateValidatingBinding(binding).ToAggregate(), tt.want)
+		})
+	}
+}
+
+func TestValidateMutatingPolicy(t *testing.T) {
+	t.Parallel()
+
+	jsonPatch := &admissionv1beta1.JSONPatch{Expression: "[]"}
+	applyConfiguration := &admissionv1beta1.ApplyConfiguration{Expression: "Object{}"}
+
+	manyConditions := make([]admissionv1beta1.MatchCondition, 65)
+	for i := range manyConditions {
+		manyConditions[i] = admissionv1beta1.MatchCondition{Name: fmt.Sprintf("c%d", i), Expression: "true"}
+	}
+
+	tests := []struct {
+		name      string
+		mutations []admissionv1beta1.Mutation
+		spec      admissionv1beta1.MutatingAdmissionPolicySpec
+		want      []string
+	}{
+		{
+			name: "valid",
+			mutations: []admissionv1beta1.Mutation{
+				{PatchType: admissionv1beta1.PatchTypeJSONPatch, JSONPatch: jsonPatch},
+				{PatchType: admissionv1beta1.PatchTypeApplyConfiguration, ApplyConfiguration: applyConfiguration},
+			},
+		},
+		{
+			name: "no mutations",
+			want: []string{"spec.mutations: Required value: mutations must contain at least one item"},
+		},
+		{
+			name:      "too many match conditions",
+			mutations: []admissionv1beta1.Mutation{{PatchType: admissionv1beta1.PatchTypeJSONPatch, JSONPatch: jsonPatch}},
+			spec:      admissionv1beta1.MutatingAdmissionPolicySpec{MatchConditions: manyConditions},
+			want:      []string{"spec.matchConditions: Too many: 65: must have at most 64 items"},
+		},
+		{
+			name: "mutation fields",
+			mutations: []admissionv1beta1.Mutation{
+				{JSONPatch: jsonPatch},
+				{PatchType: "Merge"},
+				{PatchType: admissionv1beta1.PatchTypeJSONPatch, ApplyConfiguration: applyConfiguration},
+				{PatchType: admissionv1beta1.PatchTypeApplyConfiguration, ApplyConfiguration: &admissionv1beta1.ApplyConfiguration{}},
+			},
+			want: []string{
+				"spec.mutations[0].patchType: Required value",
+				`spec.mutations[1].patchType: Unsupported value: "Merge": supported values: "JSONPatch", "ApplyConfiguration"`,
+				"spec.mutations[2].jsonPatch.expression: Required value: required when patchType is JSONPatch",
+				"spec.mutations[2].applyConfiguration: Forbidden: must not be set when patchType is JSONPatch",
+				"spec.mutations[3].applyConfiguration.expression: Required value: required when patchType is ApplyConfiguration",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := tt.spec
+			spec.Mutations = tt.mutations
+			policy := &admissionv1beta1.MutatingAdmissionPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy"}, Spec: spec}
+
+			assertFieldErrors(t, validateMutatingPolicy(policy).ToAggregate(), tt.want)
+		})
+	}
+}
+
+func TestValidatePolicySet(t *testing.T) {
+	t.Parallel()
+
+	ps := &PolicySet{
+		ValidatingPolicies: []*admissionregv1.ValidatingAdmissionPolicy{{ObjectMeta: metav1.ObjectMeta{Name: "empty"}}},
+		MutatingBindings:   []*admissionv1beta1.MutatingAdmissionPolicyBinding{{ObjectMeta: metav1.ObjectMeta{Name: "unbound"}}},
+	}
+
+	var got []string
+	for _, err := range validatePolicySet(ps) {
+		got = append(got, err.Error())
+	}
+
+	want := []string{
+		`ValidatingAdmissionPolicy "empty" would be rejected by the API server: spec.validations: Required value: validations or auditAnnotations must contain at least one item`,
+		`MutatingAdmissionPolicyBinding "unbound" would be rejected by the API server: spec.policyName: Required value`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("validatePolicySet() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// assertFieldErrors checks that each error of agg starts with the matching
+// prefix of want, in order.
+func assertFieldErrors(t *testing.T, agg interface{ Errors() []error }, want []string) {
+	t.Helper()
+
+	var got []string
+
+	if agg != nil {
+		for _, err := range agg.Errors() {
+			got = append(got, err.Error())
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%s", len(got), len(want), strings.Join(got, "\n"))
+	}
+
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("error[%d] = %q, want prefix %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package loader
+
+import "fmt"
+
+// danglingBindingWarnings reports the bindings whose spec.policyName names no
+// policy of their kind in ps. Such a binding never applies, so its tests
+// silently run without it.
+func danglingBindingWarnings(suiteName string, ps *PolicySet) []string {
+	var warnings []string
+
+	mutating := make(map[string]bool, len(ps.MutatingPolicies))
+	for _, p := range ps.MutatingPolicies {
+		mutating[p.Name] = true
+	}
+
+	for _, b := range ps.MutatingBindings {
+		if !mutating[b.Spec.PolicyName] {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: MutatingAdmissionPolicyBinding %q: spec.policyName %q matches no MutatingAdmissionPolicy",
+				suiteName, b.Name, b.Spec.PolicyName))
+		}
+	}
+
+	validating := make(map[string]bool, len(ps.ValidatingPolicies))
+	for _, p := range ps.ValidatingPolicies {
+		validating[p.Name] = true
+	}
+
+	for _, b := range ps.ValidatingBindings {
+		if !validating[b.Spec.PolicyName] {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: ValidatingAdmissionPolicyBinding %q: spec.policyName %q matches no ValidatingAdmissionPolicy",
+				suiteName, b.Name, b.Spec.PolicyName))
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,51 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionregv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDanglingBindingWarnings(t *testing.T) {
+	t.Parallel()
+
+	ps := &PolicySet{
+		MutatingPolicies: []*admissionregv1beta1.MutatingAdmissionPolicy{
+			{ObjectMeta: metav1.ObjectMeta{Name: "add-labels"}},
+		},
+		MutatingBindings: []*admissionregv1beta1.MutatingAdmissionPolicyBinding{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "add-labels"},
+				Spec:       admissionregv1beta1.MutatingAdmissionPolicyBindingSpec{PolicyName: "add-labels"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "wrong-kind"},
+				Spec:       admissionregv1beta1.MutatingAdmissionPolicyBindingSpec{PolicyName: "require-team"},
+			},
+		},
+		ValidatingPolicies: []*admissionregv1.ValidatingAdmissionPolicy{
+			{ObjectMeta: metav1.ObjectMeta{Name: "require-team"}},
+		},
+		ValidatingBindings: []*admissionregv1.ValidatingAdmissionPolicyBinding{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "require-team"},
+				Spec:       admissionregv1.ValidatingAdmissionPolicyBindingSpec{PolicyName: "require-team"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "typo"},
+				Spec:       admissionregv1.ValidatingAdmissionPolicyBindingSpec{PolicyName: "require-teem"},
+			},
+		},
+	}
+
+	want := []string{
+		`suite: MutatingAdmissionPolicyBinding "wrong-kind": spec.policyName "require-team" matches no MutatingAdmissionPolicy`,
+		`suite: ValidatingAdmissionPolicyBinding "typo": spec.policyName "require-teem" matches no ValidatingAdmissionPolicy`,
+	}
+	if diff := cmp.Diff(want, danglingBindingWarnings("suite", ps)); diff != "" {
+		t.Errorf("danglingBindingWarnings() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,41 @@
+package loader
+
+import "fmt"
+
+// UntestedPolicy is a policy of a suite that no test evaluates.
+type UntestedPolicy struct {
+	Kind string
+	Name string
+	// File is the file defining the policy.
+	File string
+}
+
+func (p UntestedPolicy) String() string {
+	return fmt.Sprintf("%s %q in %s", p.Kind, p.Name, p.File)
+}
+
+// untestedPolicies returns the policies of ps that are neither the policy nor
+// the chained validating policy of any test.
+func untestedPolicies(ps *PolicySet, tests []*TestCase) []UntestedPolicy {
+	tested := make(map[string]bool, len(tests))
+	for _, test := range tests {
+		tested[test.PolicyName] = true
+		tested[test.ChainPolicyName] = true
+	}
+
+	var untested []UntestedPolicy
+
+	for _, p := range ps.MutatingPolicies {
+		if !tested[p.Name] {
+			untested = append(untested, UntestedPolicy{Kind: "MutatingAdmissionPolicy", Name: p.Name, File: ps.PolicyFiles[p.Name]})
+		}
+	}
+
+	for _, p := range ps.ValidatingPolicies {
+		if !tested[p.Name] {
+			untested = append(untested, UntestedPolicy{Kind: "ValidatingAdmissionPolicy", Name: p.Name, File: ps.PolicyFiles[p.Name]})
+		}
+	}
+
+	return untested
+}
@@ -0,0 +1,52 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUntestedPolicies(t *testing.T) {
+	t.Parallel()
+
+	validating := func(name string) *admissionregv1.ValidatingAdmissionPolicy {
+		return &admissionregv1.ValidatingAdmissionPolicy{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	mutating := func(name string) *admissionv1beta1.MutatingAdmissionPolicy {
+		return &admissionv1beta1.MutatingAdmissionPolicy{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	ps := &PolicySet{
+		MutatingPolicies:   []*admissionv1beta1.MutatingAdmissionPolicy{mutating("add-labels"), mutating("add-sidecar")},
+		ValidatingPolicies: []*admissionregv1.ValidatingAdmissionPolicy{validating("require-labels"), validating("require-owner"), validating("replica-limit")},
+		PolicyFiles: map[string]string{
+			"add-labels":     "suite/policy.yaml",
+			"add-sidecar":    "suite/sidecar.policy.yaml",
+			"require-labels": "suite/policy.yaml",
+			"require-owner":  "suite/owner.policy.yaml",
+			"replica-limit":  "suite/policy.yaml",
+		},
+	}
+
+	tests := []*TestCase{
+		{Name: "add-labels.then.require-labels.yaml", PolicyName: "add-labels", ChainPolicyName: "require-labels"},
+		{Name: "replica-limit.within.allow.yaml", PolicyName: "replica-limit"},
+		{Name: "unmatched.yaml"},
+	}
+
+	want := []UntestedPolicy{
+		{Kind: "MutatingAdmissionPolicy", Name: "add-sidecar", File: "suite/sidecar.policy.yaml"},
+		{Kind: "ValidatingAdmissionPolicy", Name: "require-owner", File: "suite/owner.policy.yaml"},
+	}
+	if diff := cmp.Diff(want, untestedPolicies(ps, tests)); diff != "" {
+		t.Errorf("untestedPolicies() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got, want := want[1].String(), `ValidatingAdmissionPolicy "require-owner" in suite/owner.policy.yaml`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
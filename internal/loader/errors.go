@@ -12,4 +12,8 @@ var (
 	ErrUnknownFileType           = errors.New("unknown file type")
 	ErrUnsupportedV1Beta1Policy  = errors.New("ValidatingAdmissionPolicy v1beta1 not supported, use v1")
 	ErrUnsupportedV1Beta1Binding = errors.New("ValidatingAdmissionPolicyBinding v1beta1 not supported, use v1")
+	ErrNotCRD                    = errors.New("CRD file must only contain CustomResourceDefinitions")
+	ErrInvalidResource           = errors.New("would be rejected by the API server")
+	ErrNegativeDuration          = errors.New("duration must not be negative")
+	ErrInvalidFieldPath          = errors.New("invalid field path")
 )
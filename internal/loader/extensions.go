@@ -0,0 +1,49 @@
+package loader
+
+import (
+	"os"
+	"strings"
+)
+
+// yamlExtensions are the extensions of YAML test files, in the order files
+// next to a test are looked up.
+//
+//nolint:gochecknoglobals // Static lookup table
+var yamlExtensions = []string{".yaml", ".yml"}
+
+// hasYAMLSuffix reports whether name ends with suffix followed by a YAML
+// extension, e.g. ".object.yaml" or ".object.yml" for ".object".
+func hasYAMLSuffix(name, suffix string) bool {
+	for _, ext := range yamlExtensions {
+		if strings.HasSuffix(name, suffix+ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trimYAMLSuffix returns name without suffix and the YAML extension following
+// it, or name unchanged if it does not end with them.
+func trimYAMLSuffix(name, suffix string) string {
+	for _, ext := range yamlExtensions {
+		if trimmed, ok := strings.CutSuffix(name, suffix+ext); ok {
+			return trimmed
+		}
+	}
+
+	return name
+}
+
+// yamlFilePath returns prefix+suffix followed by the first YAML extension
+// naming an existing file, or by ".yaml" when there is none.
+func yamlFilePath(prefix, suffix string) string {
+	for _, ext := range yamlExtensions {
+		path := prefix + suffix + ext
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return prefix + suffix + yamlExtensions[0]
+}
@@ -0,0 +1,56 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestYAMLSuffixes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		suffix  string
+		has     bool
+		trimmed string
+	}{
+		{name: "p.a.object.yaml", suffix: ".object", has: true, trimmed: "p.a"},
+		{name: "p.a.object.yml", suffix: ".object", has: true, trimmed: "p.a"},
+		{name: "p.a.object.json", suffix: ".object", trimmed: "p.a.object.json"},
+		{name: "p.a.oldObject.yml", suffix: ".object", trimmed: "p.a.oldObject.yml"},
+	}
+
+	for _, tt := range tests {
+		if got := hasYAMLSuffix(tt.name, tt.suffix); got != tt.has {
+			t.Errorf("hasYAMLSuffix(%q, %q) = %t, want %t", tt.name, tt.suffix, got, tt.has)
+		}
+
+		if got := trimYAMLSuffix(tt.name, tt.suffix); got != tt.trimmed {
+			t.Errorf("trimYAMLSuffix(%q, %q) = %q, want %q", tt.name, tt.suffix, got, tt.trimmed)
+		}
+	}
+}
+
+func TestYAMLFilePath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"yml.gold.yml", "both.gold.yaml", "both.gold.yml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	tests := map[string]string{
+		"yml":     "yml.gold.yml",
+		"both":    "both.gold.yaml",
+		"missing": "missing.gold.yaml",
+	}
+
+	for prefix, want := range tests {
+		if got := yamlFilePath(filepath.Join(dir, prefix), ".gold"); got != filepath.Join(dir, want) {
+			t.Errorf("yamlFilePath(%q) = %q, want %q", prefix, got, filepath.Join(dir, want))
+		}
+	}
+}
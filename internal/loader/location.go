@@ -0,0 +1,148 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fieldLine returns the line of the field at path below node, a field path
+// like "spec.validations[0].reason". When the field is absent, it returns the
+// line of its closest parent that is present.
+func fieldLine(node *yaml.Node, path string) int {
+	line, _ := lookupField(node, fieldPathSteps(path))
+
+	return line
+}
+
+// lookupField follows steps from node and returns the line of the deepest
+// field found and whether all steps were found.
+func lookupField(node *yaml.Node, steps []string) (int, bool) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line := node.Line
+
+	for _, step := range steps {
+		child, childLine := childNode(node, step)
+		if child == nil {
+			return line, false
+		}
+
+		node, line = child, childLine
+	}
+
+	return line, true
+}
+
+// fieldPathSteps splits a field path into mapping keys and sequence indexes:
+// "spec.items[0].name" becomes "spec", "items", "[0]", "name".
+func fieldPathSteps(path string) []string {
+	var steps []string
+
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			open := strings.Index(part, "[")
+			if open == -1 {
+				steps = append(steps, part)
+
+				break
+			}
+
+			if open > 0 {
+				steps = append(steps, part[:open])
+			}
+
+			end := strings.Index(part[open:], "]")
+			if end == -1 {
+				steps = append(steps, part[open:])
+
+				break
+			}
+
+			steps = append(steps, part[open:open+end+1])
+			part = part[open+end+1:]
+		}
+	}
+
+	return steps
+}
+
+// childNode returns the value of key step of a mapping node or the item at
+// index step ("[0]") of a sequence node, with the line the key or item is
+// written on, or nil.
+func childNode(node *yaml.Node, step string) (*yaml.Node, int) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == step {
+				return node.Content[i+1], node.Content[i].Line
+			}
+		}
+	case yaml.SequenceNode:
+		index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(step, "["), "]"))
+		if err == nil && index >= 0 && index < len(node.Content) {
+			return node.Content[index], node.Content[index].Line
+		}
+	case yaml.DocumentNode, yaml.ScalarNode, yaml.AliasNode:
+	}
+
+	return nil, 0
+}
+
+// testFileLocation returns filePath followed by the line of the first unknown
+// or duplicate field reported by err, a strict decoding error, when that
+// field is written in the file. Objects embedded in request files are looked
+// up below their top-level key.
+func testFileLocation(filePath string, err error) string {
+	var strictErr interface{ Errors() []error }
+	if !errors.As(err, &strictErr) || len(strictErr.Errors()) == 0 {
+		return filePath
+	}
+
+	msg := strictErr.Errors()[0].Error()
+
+	quote := strings.Index(msg, `"`)
+	if quote == -1 {
+		return filePath
+	}
+
+	path, unquoteErr := strconv.Unquote(msg[quote:])
+	if unquoteErr != nil {
+		return filePath
+	}
+
+	data, readErr := os.ReadFile(filePath)
+	if readErr != nil {
+		return filePath
+	}
+
+	var doc yaml.Node
+	if yaml.Unmarshal(data, &doc) != nil || len(doc.Content) == 0 {
+		return filePath
+	}
+
+	root := doc.Content[0]
+	candidates := []*yaml.Node{root}
+
+	if root.Kind == yaml.MappingNode {
+		for i := 1; i < len(root.Content); i += 2 {
+			candidates = append(candidates, root.Content[i])
+		}
+	}
+
+	steps := fieldPathSteps(path)
+
+	for _, candidate := range candidates {
+		if line, found := lookupField(candidate, steps); found {
+			return fmt.Sprintf("%s:%d", filePath, line)
+		}
+	}
+
+	return filePath
+}
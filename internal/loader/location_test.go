@@ -0,0 +1,88 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestFieldLine(t *testing.T) {
+	t.Parallel()
+
+	data := "apiVersion: v1\nspec:\n  validations:\n  - expression: 'true'\n  - expression: 'false'\n    reason: Warn\n"
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "", want: 1},
+		{path: "spec", want: 2},
+		{path: "spec.validations[1].reason", want: 6},
+		{path: "spec.validations[1]", want: 5},
+		{path: "spec.validations[7].reason", want: 3},
+		{path: "spec.missing", want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := fieldLine(&doc, tt.path); got != tt.want {
+			t.Errorf("fieldLine(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTestFileLocation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	objectFile := filepath.Join(dir, "p.test.object.yaml")
+	requestFile := filepath.Join(dir, "p.test.request.yaml")
+
+	files := map[string]string{
+		objectFile:  "kind: Pod\nspec:\n  container: []\n",
+		requestFile: "operation: CREATE\nobject:\n  kind: Pod\n  spec:\n    container: []\n",
+	}
+
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	strictErr := runtime.NewStrictDecodingError([]error{errors.New(`unknown field "spec.container"`)})
+
+	tests := []struct {
+		name     string
+		filePath string
+		err      error
+		want     string
+	}{
+		{name: "object file", filePath: objectFile, err: strictErr, want: objectFile + ":3"},
+		{name: "embedded object", filePath: requestFile, err: strictErr, want: requestFile + ":5"},
+		{name: "not strict", filePath: objectFile, err: errUnknownField, want: objectFile},
+		{
+			name:     "field not in file",
+			filePath: objectFile,
+			err:      runtime.NewStrictDecodingError([]error{errors.New(`unknown field "spec.volumes"`)}),
+			want:     objectFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testFileLocation(tt.filePath, tt.err); got != tt.want {
+				t.Errorf("testFileLocation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package loader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+var errNoManifests = errors.New("no manifests found")
+
+// Manifest is a Kubernetes object read from a manifest file.
+type Manifest struct {
+	File     string // Path of the file the object was read from
+	Document int    // 1-based position of the object's document in the file
+	Object   *unstructured.Unstructured
+}
+
+// LoadManifests reads every object from path, a manifest file or a directory
+// walked recursively for *.yaml, *.yml and *.json files. Multi-document YAML
+// files yield one manifest per non-empty document, and List kinds are expanded
+// into their items. Directories starting with '.' are skipped.
+func LoadManifests(path string) ([]*Manifest, error) {
+	var manifests []*Manifest
+
+	err := filepath.WalkDir(path, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walk %s: %w", filePath, err)
+		}
+
+		if d.IsDir() {
+			if filePath != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filePath != path && !isManifestFile(d.Name()) {
+			return nil
+		}
+
+		fileManifests, err := loadManifestFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		manifests = append(manifests, fileManifests...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load manifests from %s: %w", path, err)
+	}
+
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("%w in %s", errNoManifests, path)
+	}
+
+	return manifests, nil
+}
+
+func isManifestFile(name string) bool {
+	ext := filepath.Ext(name)
+
+	return ext == ".yaml" || ext == ".yml" || ext == ".json"
+}
+
+func loadManifestFile(filePath string) ([]*Manifest, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", filePath, err)
+	}
+
+	var manifests []*Manifest
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	for docNum := 1; ; docNum++ {
+		var node yaml.Node
+
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: decode document %d: %w", filePath, docNum, err)
+		}
+
+		jsonBytes, err := yamlNodeToJSON(&node)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", filePath, docNum, err)
+		}
+
+		if string(jsonBytes) == "null" {
+			continue
+		}
+
+		var obj map[string]any
+		if err := sigsyaml.Unmarshal(jsonBytes, &obj); err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", filePath, docNum, err)
+		}
+
+		if err := validateWithScheme(obj, fmt.Sprintf("%s: document %d", filePath, docNum), nil); err != nil {
+			return nil, err
+		}
+
+		objects, err := expandList(&unstructured.Unstructured{Object: obj})
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", filePath, docNum, err)
+		}
+
+		for _, object := range objects {
+			manifests = append(manifests, &Manifest{File: filePath, Document: docNum, Object: object})
+		}
+	}
+
+	return manifests, nil
+}
+
+// expandList returns the items of List kinds and the object itself otherwise.
+func expandList(obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	if !obj.IsList() {
+		return []*unstructured.Unstructured{obj}, nil
+	}
+
+	list, err := obj.ToList()
+	if err != nil {
+		return nil, fmt.Errorf("read list items: %w", err)
+	}
+
+	items := make([]*unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		items = append(items, &list.Items[i])
+	}
+
+	return items, nil
+}
+
+// CreateRequest builds the CREATE admission request the API server would send
+// for obj.
+func CreateRequest(obj *unstructured.Unstructured) *admissionv1.AdmissionRequest {
+	return buildCreateRequestFromObject(obj.GetName(), obj)
+}
@@ -0,0 +1,96 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadManifests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		path    string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "multi-document file",
+			files: map[string]string{
+				"app.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata: {name: a}\n---\n---\napiVersion: v1\nkind: ConfigMap\nmetadata: {name: b}\n",
+			},
+			want: []string{"app.yaml#1 ConfigMap/a", "app.yaml#3 ConfigMap/b"},
+		},
+		{
+			name: "list is expanded",
+			files: map[string]string{
+				"list.yaml": "apiVersion: v1\nkind: List\nitems:\n- {apiVersion: v1, kind: ConfigMap, metadata: {name: a}}\n- {apiVersion: v1, kind: Secret, metadata: {name: b}}\n",
+			},
+			want: []string{"list.yaml#1 ConfigMap/a", "list.yaml#1 Secret/b"},
+		},
+		{
+			name: "directory walk skips other files",
+			files: map[string]string{
+				"b/deploy.json":  `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "b"}}`,
+				"a.yml":          "apiVersion: v1\nkind: ConfigMap\nmetadata: {name: a}\n",
+				"README.md":      "# not a manifest",
+				".git/head.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata: {name: hidden}\n",
+			},
+			want: []string{"a.yml#1 ConfigMap/a", "b/deploy.json#1 ConfigMap/b"},
+		},
+		{
+			name:    "missing kind",
+			files:   map[string]string{"bad.yaml": "apiVersion: v1\nmetadata: {name: a}\n"},
+			wantErr: errKindRequired,
+		},
+		{
+			name:    "no manifests",
+			files:   map[string]string{"README.md": "# nothing"},
+			wantErr: errNoManifests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			manifests, err := LoadManifests(dir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadManifests() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var got []string
+
+			for _, m := range manifests {
+				rel, err := filepath.Rel(dir, m.File)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got = append(got, fmt.Sprintf("%s#%d %s/%s", filepath.ToSlash(rel), m.Document, m.Object.GetKind(), m.Object.GetName()))
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("LoadManifests() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	errMatrixEmpty       = errors.New("matrix has no paramSets")
+	errMatrixSetName     = errors.New("matrix paramSet needs a unique, non-empty name")
+	errMatrixParams      = errors.New("matrix paramSet has no params")
+	errMatrixExpectation = errors.New("matrix paramSet expect must be one of allow, deny, warn, audit")
+)
+
+// matrixFile is a "<test>.matrix.yaml" file. Each named param set is
+// evaluated against the test's object as a sub-test "<test>[<name>]".
+type matrixFile struct {
+	ParamSets []matrixParamSet `json:"paramSets"`
+}
+
+// matrixParamSet is one row of a test matrix with its expected outcome.
+type matrixParamSet struct {
+	Name     string                 `json:"name"`
+	Params   map[string]interface{} `json:"params"`
+	Expect   string                 `json:"expect"`
+	Message  string                 `json:"message,omitempty"`
+	Warnings []string               `json:"warnings,omitempty"`
+}
+
+// parseMatrixYAML parses a test matrix of param sets.
+func parseMatrixYAML(testReq *testRequest, data []byte) error {
+	var matrix matrixFile
+	if err := yaml.UnmarshalStrict(data, &matrix); err != nil {
+		return fmt.Errorf("unmarshal matrix: %w", err)
+	}
+
+	if len(matrix.ParamSets) == 0 {
+		return errMatrixEmpty
+	}
+
+	seen := make(map[string]bool, len(matrix.ParamSets))
+
+	for i, set := range matrix.ParamSets {
+		if set.Name == "" || seen[set.Name] {
+			return fmt.Errorf("paramSets[%d]: %w", i, errMatrixSetName)
+		}
+
+		seen[set.Name] = true
+
+		if set.Params == nil {
+			return fmt.Errorf("paramSets[%d] %q: %w", i, set.Name, errMatrixParams)
+		}
+
+		switch set.Expect {
+		case "allow", "deny", "warn", "audit":
+		default:
+			return fmt.Errorf("paramSets[%d] %q: %w, got %q", i, set.Name, errMatrixExpectation, set.Expect)
+		}
+	}
+
+	testReq.Matrix = matrix.ParamSets
+
+	return nil
+}
+
+// expandMatrix turns a test with a matrix into one sub-test per param set.
+// The expectations of each param set replace those derived from file names.
+func expandMatrix(req *testRequest) []*testRequest {
+	if req.Matrix == nil || req.Error != nil {
+		return []*testRequest{req}
+	}
+
+	baseName := strings.TrimSuffix(req.Name, ".yaml")
+	expanded := make([]*testRequest, 0, len(req.Matrix))
+
+	for _, set := range req.Matrix {
+		sub := *req
+		sub.Name = fmt.Sprintf("%s[%s].yaml", baseName, set.Name)
+		sub.Matrix = nil
+		sub.Params = &unstructured.Unstructured{Object: set.Params}
+		sub.ExpectAllowed = set.Expect != "deny"
+		sub.ExpectAnyAllowed = false
+		sub.ExpectMessage = set.Message
+		sub.ExpectWarnings = set.Warnings
+
+		expanded = append(expanded, &sub)
+	}
+
+	return expanded
+}
@@ -0,0 +1,123 @@
+package loader
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseMatrixYAML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+		want    []string
+	}{
+		{
+			name: "valid",
+			data: `paramSets:
+- name: small
+  expect: deny
+  message: too many
+  params: {kind: ConfigMap}
+- name: large
+  expect: allow
+  params: {kind: ConfigMap}
+`,
+			want: []string{"small", "large"},
+		},
+		{
+			name:    "empty",
+			data:    "paramSets: []\n",
+			wantErr: errMatrixEmpty,
+		},
+		{
+			name:    "missing name",
+			data:    "paramSets:\n- expect: allow\n  params: {}\n",
+			wantErr: errMatrixSetName,
+		},
+		{
+			name:    "duplicate name",
+			data:    "paramSets:\n- {name: a, expect: allow, params: {}}\n- {name: a, expect: deny, params: {}}\n",
+			wantErr: errMatrixSetName,
+		},
+		{
+			name:    "missing params",
+			data:    "paramSets:\n- {name: a, expect: allow}\n",
+			wantErr: errMatrixParams,
+		},
+		{
+			name:    "unknown expectation",
+			data:    "paramSets:\n- {name: a, expect: reject, params: {}}\n",
+			wantErr: errMatrixExpectation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testReq := &testRequest{}
+
+			err := parseMatrixYAML(testReq, []byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseMatrixYAML() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var got []string
+			for _, set := range testReq.Matrix {
+				got = append(got, set.Name)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Param sets mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExpandMatrix(t *testing.T) {
+	t.Parallel()
+
+	req := &testRequest{
+		Name:          "policy.limits.yaml",
+		ExpectAllowed: true,
+		Matrix: []matrixParamSet{
+			{Name: "strict", Expect: "deny", Message: "too many", Params: map[string]interface{}{"kind": "ConfigMap"}},
+			{Name: "lenient", Expect: "warn", Warnings: []string{"close to limit"}, Params: map[string]interface{}{"kind": "ConfigMap"}},
+		},
+	}
+
+	type outcome struct {
+		Name     string
+		Allowed  bool
+		Message  string
+		Warnings []string
+		Params   bool
+	}
+
+	var got []outcome
+	for _, sub := range expandMatrix(req) {
+		got = append(got, outcome{sub.Name, sub.ExpectAllowed, sub.ExpectMessage, sub.ExpectWarnings, sub.Params != nil})
+
+		if sub.Matrix != nil {
+			t.Errorf("Sub-test %s still has a matrix", sub.Name)
+		}
+	}
+
+	want := []outcome{
+		{Name: "policy.limits[strict].yaml", Allowed: false, Message: "too many", Params: true},
+		{Name: "policy.limits[lenient].yaml", Allowed: true, Warnings: []string{"close to limit"}, Params: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("expandMatrix() mismatch (-want +got):\n%s", diff)
+	}
+
+	plain := &testRequest{Name: "policy.plain.yaml"}
+	if diff := cmp.Diff([]*testRequest{plain}, expandMatrix(plain)); diff != "" {
+		t.Errorf("expandMatrix() changed a test without matrix (-want +got):\n%s", diff)
+	}
+}
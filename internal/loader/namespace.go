@@ -0,0 +1,99 @@
+package loader
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// clusterScopedKinds are the built-in kinds without a namespace.
+// TestClusterScopedKinds fails when the scheme gains a kind missing from both
+// this list and its list of namespaced kinds.
+//
+//nolint:gochecknoglobals // Static lookup table
+var clusterScopedKinds = map[schema.GroupKind]bool{
+	{Kind: "Namespace"}:        true,
+	{Kind: "Node"}:             true,
+	{Kind: "PersistentVolume"}: true,
+	{Kind: "ComponentStatus"}:  true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingAdmissionPolicy"}:          true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingAdmissionPolicyBinding"}:   true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: true,
+	{Group: "authentication.k8s.io", Kind: "TokenReview"}:                             true,
+	{Group: "authentication.k8s.io", Kind: "SelfSubjectReview"}:                       true,
+	{Group: "authorization.k8s.io", Kind: "SubjectAccessReview"}:                      true,
+	{Group: "authorization.k8s.io", Kind: "SelfSubjectAccessReview"}:                  true,
+	{Group: "authorization.k8s.io", Kind: "SelfSubjectRulesReview"}:                   true,
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}:                 true,
+	{Group: "certificates.k8s.io", Kind: "ClusterTrustBundle"}:                        true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"}:                       true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"}:       true,
+	{Group: "internal.apiserver.k8s.io", Kind: "StorageVersion"}:                      true,
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                                true,
+	{Group: "networking.k8s.io", Kind: "IPAddress"}:                                   true,
+	{Group: "networking.k8s.io", Kind: "ServiceCIDR"}:                                 true,
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                      true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                         true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                  true,
+	{Group: "resource.k8s.io", Kind: "DeviceClass"}:                                   true,
+	{Group: "resource.k8s.io", Kind: "DeviceTaintRule"}:                               true,
+	{Group: "resource.k8s.io", Kind: "ResourceSlice"}:                                 true,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                               true,
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                      true,
+	{Group: "storage.k8s.io", Kind: "CSINode"}:                                        true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                   true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                               true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttributesClass"}:                          true,
+	{Group: "storagemigration.k8s.io", Kind: "StorageVersionMigration"}:               true,
+}
+
+// defaultNamespaces sets the namespace of tests of namespaced built-in kinds
+// that omit it, as clients and the API server do: an empty request namespace
+// becomes namespace, and objects without a namespace get the one of the
+// request. Custom resources are left alone, as their scope is unknown. An
+// empty namespace disables the defaulting.
+func defaultNamespaces(suites []*TestSuite, namespace string) {
+	if namespace == "" {
+		return
+	}
+
+	for _, suite := range suites {
+		for _, test := range suite.Tests {
+			defaultTestNamespace(test, namespace)
+		}
+	}
+}
+
+func defaultTestNamespace(test *TestCase, namespace string) {
+	obj := test.Object
+	if obj == nil {
+		obj = test.OldObject
+	}
+
+	if obj == nil || !isNamespacedBuiltinKind(obj.GroupVersionKind()) {
+		return
+	}
+
+	if test.Request != nil {
+		if test.Request.Namespace == "" {
+			test.Request.Namespace = namespace
+		}
+
+		namespace = test.Request.Namespace
+	}
+
+	for _, o := range []*unstructured.Unstructured{test.Object, test.OldObject, test.ExpectedObject} {
+		if o != nil && o.GetNamespace() == "" {
+			o.SetNamespace(namespace)
+		}
+	}
+}
+
+// isNamespacedBuiltinKind reports whether gvk is a built-in kind that lives
+// in a namespace.
+func isNamespacedBuiltinKind(gvk schema.GroupVersionKind) bool {
+	return scheme.Scheme.Recognizes(gvk) && !clusterScopedKinds[gvk.GroupKind()]
+}
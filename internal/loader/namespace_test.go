@@ -0,0 +1,188 @@
+package loader
+
+import (
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestDefaultNamespaces(t *testing.T) {
+	t.Parallel()
+
+	object := func(apiVersion, kind, namespace string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName("test")
+		obj.SetNamespace(namespace)
+
+		return obj
+	}
+
+	tests := []struct {
+		name          string
+		test          *TestCase
+		namespace     string
+		wantRequest   string
+		wantNamespace string
+	}{
+		{
+			name: "namespaceless pod is in default",
+			test: &TestCase{
+				Request: &admissionv1.AdmissionRequest{},
+				Object:  object("v1", "Pod", ""),
+			},
+			namespace:     "default",
+			wantRequest:   "default",
+			wantNamespace: "default",
+		},
+		{
+			name: "object takes the request namespace",
+			test: &TestCase{
+				Request: &admissionv1.AdmissionRequest{Namespace: "prod"},
+				Object:  object("apps/v1", "Deployment", ""),
+			},
+			namespace:     "default",
+			wantRequest:   "prod",
+			wantNamespace: "prod",
+		},
+		{
+			name: "explicit namespace kept",
+			test: &TestCase{
+				Request: &admissionv1.AdmissionRequest{Namespace: "prod"},
+				Object:  object("v1", "Pod", "prod"),
+			},
+			namespace:     "default",
+			wantRequest:   "prod",
+			wantNamespace: "prod",
+		},
+		{
+			name: "cluster-scoped kind exempt",
+			test: &TestCase{
+				Request: &admissionv1.AdmissionRequest{},
+				Object:  object("rbac.authorization.k8s.io/v1", "ClusterRole", ""),
+			},
+			namespace: "default",
+		},
+		{
+			name: "custom resource untouched",
+			test: &TestCase{
+				Request: &admissionv1.AdmissionRequest{},
+				Object:  object("example.com/v1", "Widget", ""),
+			},
+			namespace: "default",
+		},
+		{
+			name: "disabled",
+			test: &TestCase{
+				Request: &admissionv1.AdmissionRequest{},
+				Object:  object("v1", "Pod", ""),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			defaultNamespaces([]*TestSuite{{Tests: []*TestCase{tt.test}}}, tt.namespace)
+
+			if got := tt.test.Request.Namespace; got != tt.wantRequest {
+				t.Errorf("request namespace = %q, want %q", got, tt.wantRequest)
+			}
+
+			if got := tt.test.Object.GetNamespace(); got != tt.wantNamespace {
+				t.Errorf("object namespace = %q, want %q", got, tt.wantNamespace)
+			}
+		})
+	}
+}
+
+// TestClusterScopedKinds guards clusterScopedKinds against going stale when
+// client-go gains kinds: every kind of the scheme must be classified as
+// either cluster-scoped or namespaced.
+func TestClusterScopedKinds(t *testing.T) {
+	t.Parallel()
+
+	// namespacedKinds are the kinds of the scheme that are namespaced, or are
+	// not resources of their own (subresources, options, status).
+	namespacedKinds := map[schema.GroupKind]bool{
+		{Kind: "APIGroup"}:                                     true,
+		{Kind: "APIVersions"}:                                  true,
+		{Kind: "Binding"}:                                      true,
+		{Kind: "ConfigMap"}:                                    true,
+		{Kind: "Endpoints"}:                                    true,
+		{Kind: "Event"}:                                        true,
+		{Kind: "LimitRange"}:                                   true,
+		{Kind: "PersistentVolumeClaim"}:                        true,
+		{Kind: "Pod"}:                                          true,
+		{Kind: "PodStatusResult"}:                              true,
+		{Kind: "PodTemplate"}:                                  true,
+		{Kind: "RangeAllocation"}:                              true,
+		{Kind: "ReplicationController"}:                        true,
+		{Kind: "ResourceQuota"}:                                true,
+		{Kind: "Secret"}:                                       true,
+		{Kind: "SerializedReference"}:                          true,
+		{Kind: "Service"}:                                      true,
+		{Kind: "ServiceAccount"}:                               true,
+		{Kind: "Status"}:                                       true,
+		{Group: "apps", Kind: "ControllerRevision"}:            true,
+		{Group: "apps", Kind: "DaemonSet"}:                     true,
+		{Group: "apps", Kind: "Deployment"}:                    true,
+		{Group: "apps", Kind: "DeploymentRollback"}:            true,
+		{Group: "apps", Kind: "ReplicaSet"}:                    true,
+		{Group: "apps", Kind: "Scale"}:                         true,
+		{Group: "apps", Kind: "StatefulSet"}:                   true,
+		{Group: "authentication.k8s.io", Kind: "TokenRequest"}: true,
+		{Group: "authorization.k8s.io", Kind: "LocalSubjectAccessReview"}: true,
+		{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}:           true,
+		{Group: "autoscaling", Kind: "Scale"}:                             true,
+		{Group: "batch", Kind: "CronJob"}:                                 true,
+		{Group: "batch", Kind: "Job"}:                                     true,
+		{Group: "certificates.k8s.io", Kind: "PodCertificateRequest"}:     true,
+		{Group: "coordination.k8s.io", Kind: "Lease"}:                     true,
+		{Group: "coordination.k8s.io", Kind: "LeaseCandidate"}:            true,
+		{Group: "discovery.k8s.io", Kind: "EndpointSlice"}:                true,
+		{Group: "events.k8s.io", Kind: "Event"}:                           true,
+		{Group: "extensions", Kind: "DaemonSet"}:                          true,
+		{Group: "extensions", Kind: "Deployment"}:                         true,
+		{Group: "extensions", Kind: "DeploymentRollback"}:                 true,
+		{Group: "extensions", Kind: "Ingress"}:                            true,
+		{Group: "extensions", Kind: "NetworkPolicy"}:                      true,
+		{Group: "extensions", Kind: "ReplicaSet"}:                         true,
+		{Group: "extensions", Kind: "Scale"}:                              true,
+		{Group: "networking.k8s.io", Kind: "Ingress"}:                     true,
+		{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:               true,
+		{Group: "policy", Kind: "Eviction"}:                               true,
+		{Group: "policy", Kind: "PodDisruptionBudget"}:                    true,
+		{Group: "rbac.authorization.k8s.io", Kind: "Role"}:                true,
+		{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:         true,
+		{Group: "resource.k8s.io", Kind: "ResourceClaim"}:                 true,
+		{Group: "resource.k8s.io", Kind: "ResourceClaimTemplate"}:         true,
+		{Group: "scheduling.k8s.io", Kind: "Workload"}:                    true,
+		{Group: "storage.k8s.io", Kind: "CSIStorageCapacity"}:             true,
+	}
+
+	for gvk := range scheme.Scheme.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || gvk.Kind == "WatchEvent" ||
+			strings.HasSuffix(gvk.Kind, "List") || strings.HasSuffix(gvk.Kind, "Options") {
+			continue
+		}
+
+		gk := gvk.GroupKind()
+		if clusterScopedKinds[gk] == namespacedKinds[gk] {
+			t.Errorf("%s must be in exactly one of clusterScopedKinds and namespacedKinds", gk)
+		}
+	}
+
+	for gk := range clusterScopedKinds {
+		if len(scheme.Scheme.VersionsForGroupKind(gk)) == 0 {
+			t.Errorf("clusterScopedKinds holds %s, which the scheme does not know", gk)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	errOperationsEmpty       = errors.New("operations must list at least one operation")
+	errOperationsDuplicate   = errors.New("operation listed twice")
+	errOperationsNeedObject  = errors.New("operations requires an .object.yaml")
+	errOverrideNotListed     = errors.New("override for an operation not listed in operations")
+	errOverrideExpectation   = errors.New("override expect must be one of allow, deny, warn, audit")
+	errOperationsUnsupported = errors.New("operations supports CREATE, UPDATE and DELETE")
+	errExpectAllowed         = errors.New(`allowed must be "any"`)
+	errAnyAllowedToken       = errors.New("allowed: any conflicts with the outcome in the file name")
+)
+
+// anyAllowed is the allowed value of an expect file that skips the check of
+// whether the request is allowed.
+const anyAllowed = "any"
+
+// expectFile is a "<test>.expect.yaml" file. Operations runs the test once per
+// operation as a sub-test "<test>[<OPERATION>]". The expectations derived from
+// the test's file names apply to every operation unless overridden. Allowed
+// "any" makes the test assert only its other expectations, such as audit
+// annotations or the mutated object, whether the request is allowed or not.
+type expectFile struct {
+	Allowed    string                          `json:"allowed,omitempty"`
+	Operations []string                        `json:"operations,omitempty"`
+	Overrides  map[string]operationExpectation `json:"overrides,omitempty"`
+}
+
+// operationExpectation replaces the expected outcome of one operation.
+type operationExpectation struct {
+	Expect   string   `json:"expect"`
+	Message  string   `json:"message,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// loadExpectFile loads the operations and allowed expectation of a test from
+// path, if it exists.
+func loadExpectFile(testReq *testRequest, baseName, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("read expect file: %w", err)
+	}
+
+	var expect expectFile
+	if err := yaml.UnmarshalStrict(data, &expect); err != nil {
+		return fmt.Errorf("unmarshal expect file %s: %w", path, err)
+	}
+
+	switch {
+	case expect.Allowed == "":
+	case expect.Allowed != anyAllowed:
+		return fmt.Errorf("%s: %w, got %q", path, errExpectAllowed, expect.Allowed)
+	case hasOutcomeToken(baseName):
+		return fmt.Errorf("%s: %w", path, errAnyAllowedToken)
+	default:
+		testReq.ExpectAnyAllowed = true
+	}
+
+	// An expect file only asserting allowed: any runs the test once.
+	if expect.Operations == nil && expect.Overrides == nil && testReq.ExpectAnyAllowed {
+		return nil
+	}
+
+	if err := validateOperations(expect.Operations); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	for operation, override := range expect.Overrides {
+		if !containsOperation(expect.Operations, operation) {
+			return fmt.Errorf("%s: %w: %s", path, errOverrideNotListed, operation)
+		}
+
+		switch override.Expect {
+		case "allow", "deny", "warn", "audit":
+		default:
+			return fmt.Errorf("%s: overrides %s: %w, got %q", path, operation, errOverrideExpectation, override.Expect)
+		}
+	}
+
+	testReq.Operations = expect.Operations
+	testReq.OperationOverrides = expect.Overrides
+
+	return nil
+}
+
+// validateOperations checks the operations of an expect file or a suite's
+// kat.yaml.
+func validateOperations(operations []string) error {
+	if len(operations) == 0 {
+		return errOperationsEmpty
+	}
+
+	for i, operation := range operations {
+		switch admissionv1.Operation(operation) {
+		case admissionv1.Create, admissionv1.Update, admissionv1.Delete:
+		default:
+			return fmt.Errorf("%w, got %q", errOperationsUnsupported, operation)
+		}
+
+		if containsOperation(operations[:i], operation) {
+			return fmt.Errorf("%w: %s", errOperationsDuplicate, operation)
+		}
+	}
+
+	return nil
+}
+
+func containsOperation(operations []string, operation string) bool {
+	for _, o := range operations {
+		if o == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expandOperations turns a test with operations into one sub-test per
+// operation. UPDATE and DELETE use the test's oldObject, or a copy of its
+// object when it has none; CREATE drops the oldObject.
+func expandOperations(req *testRequest) []*testRequest {
+	if req.Operations == nil || req.Error != nil {
+		return []*testRequest{req}
+	}
+
+	if req.Object == nil || req.Request == nil {
+		req.Error = errOperationsNeedObject
+
+		return []*testRequest{req}
+	}
+
+	baseName := strings.TrimSuffix(req.Name, ".yaml")
+	expanded := make([]*testRequest, 0, len(req.Operations))
+
+	for _, operation := range req.Operations {
+		sub := *req
+		sub.Name = fmt.Sprintf("%s[%s].yaml", baseName, operation)
+		sub.Operations = nil
+		sub.OperationOverrides = nil
+		sub.Request = req.Request.DeepCopy()
+		sub.Request.Operation = admissionv1.Operation(operation)
+
+		oldObject := req.OldObject
+		if oldObject == nil {
+			oldObject = req.Object.DeepCopy()
+		}
+
+		switch admissionv1.Operation(operation) {
+		case admissionv1.Create:
+			sub.OldObject = nil
+		case admissionv1.Update:
+			sub.OldObject = oldObject
+		case admissionv1.Delete:
+			sub.Object = nil
+			sub.OldObject = oldObject
+		}
+
+		if override, ok := req.OperationOverrides[operation]; ok {
+			sub.ExpectAllowed = override.Expect != "deny"
+			sub.ExpectAnyAllowed = false
+			sub.ExpectMessage = override.Message
+			sub.ExpectWarnings = override.Warnings
+
+			if sub.ExpectAllowed {
+				sub.ExpectStatus = nil
+			}
+		}
+
+		expanded = append(expanded, &sub)
+	}
+
+	return expanded
+}
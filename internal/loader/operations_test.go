@@ -0,0 +1,163 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLoadExpectFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		baseName       string
+		content        string
+		wantOperations []string
+		wantAnyAllowed bool
+		wantErr        error
+	}{
+		{
+			name:           "operations with override",
+			content:        "operations: [CREATE, UPDATE]\noverrides:\n  CREATE:\n    expect: allow\n",
+			wantOperations: []string{"CREATE", "UPDATE"},
+		},
+		{name: "empty operations", content: "operations: []\n", wantErr: errOperationsEmpty},
+		{name: "duplicate operation", content: "operations: [CREATE, CREATE]\n", wantErr: errOperationsDuplicate},
+		{name: "unsupported operation", content: "operations: [CONNECT]\n", wantErr: errOperationsUnsupported},
+		{
+			name:    "override not listed",
+			content: "operations: [CREATE]\noverrides:\n  UPDATE:\n    expect: allow\n",
+			wantErr: errOverrideNotListed,
+		},
+		{
+			name:    "override without expect",
+			content: "operations: [CREATE]\noverrides:\n  CREATE:\n    message: denied\n",
+			wantErr: errOverrideExpectation,
+		},
+		{name: "allowed any", content: "allowed: any\n", wantAnyAllowed: true},
+		{
+			name:           "allowed any with operations",
+			content:        "allowed: any\noperations: [CREATE, UPDATE]\n",
+			wantOperations: []string{"CREATE", "UPDATE"},
+			wantAnyAllowed: true,
+		},
+		{name: "allowed not any", content: "allowed: true\n", wantErr: errExpectAllowed},
+		{name: "allowed any with outcome token", baseName: "policy.test.deny", content: "allowed: any\n", wantErr: errAnyAllowedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "policy.test.expect.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			req := &testRequest{}
+
+			baseName := tt.baseName
+			if baseName == "" {
+				baseName = "policy.test"
+			}
+
+			err := loadExpectFile(req, baseName, path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadExpectFile() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.wantOperations, req.Operations); diff != "" {
+				t.Errorf("Operations mismatch (-want +got):\n%s", diff)
+			}
+
+			if req.ExpectAnyAllowed != tt.wantAnyAllowed {
+				t.Errorf("ExpectAnyAllowed = %t, want %t", req.ExpectAnyAllowed, tt.wantAnyAllowed)
+			}
+		})
+	}
+}
+
+func TestExpandOperations(t *testing.T) {
+	t.Parallel()
+
+	object := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": "ConfigMap"}}
+	oldObject := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": "ConfigMap", "data": map[string]any{"a": "b"}}}
+
+	type outcome struct {
+		Name      string
+		Operation admissionv1.Operation
+		Object    bool
+		OldObject *unstructured.Unstructured
+		Allowed   bool
+		Message   string
+	}
+
+	tests := []struct {
+		name string
+		req  *testRequest
+		want []outcome
+	}{
+		{
+			name: "old object copied from object",
+			req: &testRequest{
+				Name:       "policy.test.deny.yaml",
+				Request:    &admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+				Object:     object,
+				Operations: []string{"CREATE", "UPDATE", "DELETE"},
+			},
+			want: []outcome{
+				{Name: "policy.test.deny[CREATE].yaml", Operation: admissionv1.Create, Object: true},
+				{Name: "policy.test.deny[UPDATE].yaml", Operation: admissionv1.Update, Object: true, OldObject: object},
+				{Name: "policy.test.deny[DELETE].yaml", Operation: admissionv1.Delete, OldObject: object},
+			},
+		},
+		{
+			name: "given old object and override",
+			req: &testRequest{
+				Name:          "policy.test.deny.yaml",
+				Request:       &admissionv1.AdmissionRequest{Operation: admissionv1.Update},
+				Object:        object,
+				OldObject:     oldObject,
+				ExpectMessage: "immutable",
+				Operations:    []string{"CREATE", "UPDATE"},
+				OperationOverrides: map[string]operationExpectation{
+					"CREATE": {Expect: "allow"},
+				},
+			},
+			want: []outcome{
+				{Name: "policy.test.deny[CREATE].yaml", Operation: admissionv1.Create, Object: true, Allowed: true},
+				{Name: "policy.test.deny[UPDATE].yaml", Operation: admissionv1.Update, Object: true, OldObject: oldObject, Message: "immutable"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []outcome
+			for _, sub := range expandOperations(tt.req) {
+				got = append(got, outcome{sub.Name, sub.Request.Operation, sub.Object != nil, sub.OldObject, sub.ExpectAllowed, sub.ExpectMessage})
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("expandOperations() mismatch (-want +got):\n%s", diff)
+			}
+
+			if tt.req.Request.Operation == admissionv1.Delete {
+				t.Error("expandOperations() changed the request of the original test")
+			}
+		})
+	}
+
+	noObject := &testRequest{Name: "policy.test.yaml", Operations: []string{"CREATE"}}
+	if subs := expandOperations(noObject); len(subs) != 1 || !errors.Is(subs[0].Error, errOperationsNeedObject) {
+		t.Errorf("expandOperations() without object error = %v, want %v", subs[0].Error, errOperationsNeedObject)
+	}
+}
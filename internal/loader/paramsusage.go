@@ -0,0 +1,39 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zemanlx/kat/internal/lint"
+)
+
+// paramsUsageWarnings reports policies of ps whose expressions read params
+// without a paramKind, so that params is always null on a cluster even when a
+// test supplies a .params.yaml, and policies declaring a paramKind that no
+// expression reads.
+func paramsUsageWarnings(suiteName string, ps *PolicySet) []string {
+	var warnings []string
+
+	check := func(kind, name string, hasParamKind bool, readers []string) {
+		switch {
+		case len(readers) > 0 && !hasParamKind:
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: %s %q reads params in %s but declares no paramKind; params is always null on a cluster",
+				suiteName, kind, name, strings.Join(readers, ", ")))
+		case len(readers) == 0 && hasParamKind:
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: %s %q declares a paramKind but no expression reads params",
+				suiteName, kind, name))
+		}
+	}
+
+	for _, policy := range ps.ValidatingPolicies {
+		check("ValidatingAdmissionPolicy", policy.Name, policy.Spec.ParamKind != nil, lint.ValidatingParamsReaders(policy))
+	}
+
+	for _, policy := range ps.MutatingPolicies {
+		check("MutatingAdmissionPolicy", policy.Name, policy.Spec.ParamKind != nil, lint.MutatingParamsReaders(policy))
+	}
+
+	return warnings
+}
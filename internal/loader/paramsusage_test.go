@@ -0,0 +1,50 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParamsUsageWarnings(t *testing.T) {
+	t.Parallel()
+
+	configMap := &admissionregv1.ParamKind{APIVersion: "v1", Kind: "ConfigMap"}
+	validating := func(name string, paramKind *admissionregv1.ParamKind, expression string) *admissionregv1.ValidatingAdmissionPolicy {
+		return &admissionregv1.ValidatingAdmissionPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+				ParamKind:   paramKind,
+				Validations: []admissionregv1.Validation{{Expression: expression}},
+			},
+		}
+	}
+
+	ps := &PolicySet{
+		ValidatingPolicies: []*admissionregv1.ValidatingAdmissionPolicy{
+			validating("consistent", configMap, "object.spec.replicas <= int(params.data.max)"),
+			validating("no-params", nil, "has(object.spec)"),
+			validating("missing-param-kind", nil, "object.spec.replicas <= int(params.data.max)"),
+			validating("unused-param-kind", configMap, "has(object.spec)"),
+			validating("comprehension-variable", nil, "object.spec.containers.all(c, c.name != '')"),
+		},
+		MutatingPolicies: []*admissionv1beta1.MutatingAdmissionPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+			Spec: admissionv1beta1.MutatingAdmissionPolicySpec{
+				MatchConditions: []admissionv1beta1.MatchCondition{{Name: "enabled", Expression: "params.data.enabled == 'true'"}},
+			},
+		}},
+	}
+
+	want := []string{
+		`suite: ValidatingAdmissionPolicy "missing-param-kind" reads params in spec.validations[0].expression but declares no paramKind; params is always null on a cluster`,
+		`suite: ValidatingAdmissionPolicy "unused-param-kind" declares a paramKind but no expression reads params`,
+		`suite: MutatingAdmissionPolicy "defaults" reads params in spec.matchConditions[0].expression but declares no paramKind; params is always null on a cluster`,
+	}
+	if diff := cmp.Diff(want, paramsUsageWarnings("suite", ps)); diff != "" {
+		t.Errorf("paramsUsageWarnings() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,123 @@
+package loader
+
+import "slices"
+
+// splitSuitesByPolicy replaces each suite by one suite per policy, named
+// "<suite>/<policy>", holding the policy, its bindings and its tests. A chain
+// test is part of the suites of both its policies, which then also hold the
+// other policy. Tests matching no policy stay in a suite with the original
+// name. Load warnings and policy problems are kept by the first suite, so they
+// are reported once.
+func splitSuitesByPolicy(suites []*TestSuite) []*TestSuite {
+	split := make([]*TestSuite, 0, len(suites))
+
+	for _, suite := range suites {
+		split = append(split, splitSuiteByPolicy(suite)...)
+	}
+
+	return split
+}
+
+func splitSuiteByPolicy(suite *TestSuite) []*TestSuite {
+	var names []string
+	for _, policy := range suite.MutatingPolicies {
+		names = append(names, policy.Name)
+	}
+
+	for _, policy := range suite.ValidatingPolicies {
+		names = append(names, policy.Name)
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	if len(names) == 0 {
+		return []*TestSuite{suite}
+	}
+
+	suites := make([]*TestSuite, 0, len(names)+1)
+
+	for _, name := range names {
+		policySuite := suite.subset(suite.Name+"/"+name, func(test *TestCase) bool {
+			return test.PolicyName == name || test.ChainPolicyName == name
+		})
+
+		// Chain tests need the other policy of the chain.
+		needed := []string{name}
+		for _, test := range policySuite.Tests {
+			needed = append(needed, test.PolicyName, test.ChainPolicyName)
+		}
+
+		policySuite.keepPolicies(suite, needed)
+
+		for _, untested := range suite.UntestedPolicies {
+			if untested.Name == name {
+				policySuite.UntestedPolicies = append(policySuite.UntestedPolicies, untested)
+			}
+		}
+
+		suites = append(suites, policySuite)
+	}
+
+	unmatched := suite.subset(suite.Name, func(test *TestCase) bool {
+		return !slices.Contains(names, test.PolicyName)
+	})
+	if len(unmatched.Tests) > 0 {
+		unmatched.keepPolicies(suite, names)
+		suites = append(suites, unmatched)
+	}
+
+	suites[0].Warnings = suite.Warnings
+	suites[0].PolicyProblems = suite.PolicyProblems
+	suites[0].TypeCheckWarnings = suite.TypeCheckWarnings
+
+	return suites
+}
+
+// subset returns a suite named name with the settings of s and the tests of s
+// for which keep returns true, but without policies.
+func (s *TestSuite) subset(name string, keep func(test *TestCase) bool) *TestSuite {
+	subset := &TestSuite{
+		Name:            name,
+		Path:            s.Path,
+		TestsDirFound:   s.TestsDirFound,
+		MaxTestDuration: s.MaxTestDuration,
+		Operations:      s.Operations,
+	}
+
+	for _, test := range s.Tests {
+		if keep(test) {
+			subset.Tests = append(subset.Tests, test)
+		}
+	}
+
+	return subset
+}
+
+// keepPolicies sets the policies of s to those of from named in names, with
+// their bindings.
+func (s *TestSuite) keepPolicies(from *TestSuite, names []string) {
+	for _, policy := range from.MutatingPolicies {
+		if slices.Contains(names, policy.Name) {
+			s.MutatingPolicies = append(s.MutatingPolicies, policy)
+		}
+	}
+
+	for _, binding := range from.MutatingBindings {
+		if slices.Contains(names, binding.Spec.PolicyName) {
+			s.MutatingBindings = append(s.MutatingBindings, binding)
+		}
+	}
+
+	for _, policy := range from.ValidatingPolicies {
+		if slices.Contains(names, policy.Name) {
+			s.ValidatingPolicies = append(s.ValidatingPolicies, policy)
+		}
+	}
+
+	for _, binding := range from.ValidatingBindings {
+		if slices.Contains(names, binding.Spec.PolicyName) {
+			s.ValidatingBindings = append(s.ValidatingBindings, binding)
+		}
+	}
+}
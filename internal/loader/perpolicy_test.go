@@ -0,0 +1,143 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplitSuitesByPolicy(t *testing.T) {
+	t.Parallel()
+
+	validating := func(name string) *admissionregv1.ValidatingAdmissionPolicy {
+		return &admissionregv1.ValidatingAdmissionPolicy{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	validatingBinding := func(policyName string) *admissionregv1.ValidatingAdmissionPolicyBinding {
+		return &admissionregv1.ValidatingAdmissionPolicyBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: policyName + "-binding"},
+			Spec:       admissionregv1.ValidatingAdmissionPolicyBindingSpec{PolicyName: policyName},
+		}
+	}
+
+	suite := &TestSuite{
+		Name: "policies",
+		Path: "dir/policies",
+		MutatingPolicies: []*admissionv1beta1.MutatingAdmissionPolicy{
+			{ObjectMeta: metav1.ObjectMeta{Name: "add-team"}},
+		},
+		ValidatingPolicies: []*admissionregv1.ValidatingAdmissionPolicy{validating("require-team"), validating("untested")},
+		ValidatingBindings: []*admissionregv1.ValidatingAdmissionPolicyBinding{validatingBinding("require-team"), validatingBinding("untested")},
+		Tests: []*TestCase{
+			{Name: "require-team.missing.deny.yaml", PolicyName: "require-team"},
+			{Name: "add-team+require-team.chain.allow.yaml", PolicyName: "add-team", ChainPolicyName: "require-team"},
+			{Name: "typo.allow.yaml"},
+		},
+		Warnings:         []string{"typo.allow.yaml matches no policy"},
+		UntestedPolicies: []UntestedPolicy{{Kind: "ValidatingAdmissionPolicy", Name: "untested"}},
+	}
+
+	type summary struct {
+		Name     string
+		Path     string
+		Policies []string
+		Bindings []string
+		Tests    []string
+		Warnings []string
+		Untested []string
+	}
+
+	var got []summary
+
+	for _, s := range splitSuitesByPolicy([]*TestSuite{suite}) {
+		sum := summary{Name: s.Name, Path: s.Path, Warnings: s.Warnings}
+		for _, p := range s.MutatingPolicies {
+			sum.Policies = append(sum.Policies, p.Name)
+		}
+
+		for _, p := range s.ValidatingPolicies {
+			sum.Policies = append(sum.Policies, p.Name)
+		}
+
+		for _, b := range s.ValidatingBindings {
+			sum.Bindings = append(sum.Bindings, b.Name)
+		}
+
+		for _, test := range s.Tests {
+			sum.Tests = append(sum.Tests, test.Name)
+		}
+
+		for _, u := range s.UntestedPolicies {
+			sum.Untested = append(sum.Untested, u.Name)
+		}
+
+		got = append(got, sum)
+	}
+
+	want := []summary{
+		{
+			Name:     "policies/add-team",
+			Path:     "dir/policies",
+			Policies: []string{"add-team", "require-team"},
+			Bindings: []string{"require-team-binding"},
+			Tests:    []string{"add-team+require-team.chain.allow.yaml"},
+			Warnings: []string{"typo.allow.yaml matches no policy"},
+		},
+		{
+			Name:     "policies/require-team",
+			Path:     "dir/policies",
+			Policies: []string{"add-team", "require-team"},
+			Bindings: []string{"require-team-binding"},
+			Tests:    []string{"require-team.missing.deny.yaml", "add-team+require-team.chain.allow.yaml"},
+		},
+		{
+			Name:     "policies/untested",
+			Path:     "dir/policies",
+			Policies: []string{"untested"},
+			Bindings: []string{"untested-binding"},
+			Untested: []string{"untested"},
+		},
+		{
+			Name:     "policies",
+			Path:     "dir/policies",
+			Policies: []string{"add-team", "require-team", "untested"},
+			Bindings: []string{"require-team-binding", "untested-binding"},
+			Tests:    []string{"typo.allow.yaml"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("splitSuitesByPolicy() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSplitRunPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern   string
+		suiteName string
+		wantSuite string
+		wantTest  string
+	}{
+		{pattern: "deny", suiteName: "dir", wantTest: "deny"},
+		{pattern: "dir/deny", suiteName: "dir", wantSuite: "dir", wantTest: "deny"},
+		{pattern: "dir/policy/deny", suiteName: "dir/policy", wantSuite: "dir/policy", wantTest: "deny"},
+		{pattern: "dir/policy", suiteName: "dir/policy", wantSuite: "dir/policy"},
+		{pattern: "dir/policy$", suiteName: "dir/policy", wantSuite: "dir/policy$"},
+		{pattern: "deny", suiteName: "dir/policy", wantTest: "deny"},
+		{pattern: "dir/a/b", suiteName: "dir", wantSuite: "dir", wantTest: "a/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.suiteName, func(t *testing.T) {
+			t.Parallel()
+
+			gotSuite, gotTest := splitRunPattern(tt.pattern, tt.suiteName)
+			if gotSuite != tt.wantSuite || gotTest != tt.wantTest {
+				t.Errorf("splitRunPattern() = %q, %q, want %q, %q", gotSuite, gotTest, tt.wantSuite, tt.wantTest)
+			}
+		})
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"gopkg.in/yaml.v3"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/kubernetes/scheme"
 )
@@ -29,13 +31,58 @@ type PolicySet struct {
 	MutatingBindings   []*admissionv1beta1.MutatingAdmissionPolicyBinding
 	ValidatingPolicies []*admissionv1.ValidatingAdmissionPolicy
 	ValidatingBindings []*admissionv1.ValidatingAdmissionPolicyBinding
+	// CRDs are CustomResourceDefinitions used to validate params of custom kinds.
+	CRDs []*unstructured.Unstructured
+	// PolicyFiles maps the name of each policy to the file defining it.
+	PolicyFiles map[string]string
+	// sources maps "<kind>/<name>" of each policy and binding to the YAML
+	// document defining it, to report problems with file and line.
+	sources map[string]resourceSource
+}
+
+// resourceSource is the YAML document a policy or binding was loaded from.
+type resourceSource struct {
+	file string
+	node *yaml.Node
+}
+
+// location returns "<file>:<line>" of the field at fieldPath of the resource
+// kind/name, or "" when its source is unknown.
+func (ps *PolicySet) location(kind, name, fieldPath string) string {
+	source, ok := ps.sources[kind+"/"+name]
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d", source.file, fieldLine(source.node, fieldPath))
 }
 
 // Skips directories: tests, testdata, .git, and any starting with '.'.
-//
-//nolint:cyclop // Directory walk needs several conditional exits
 func LoadPolicySet(dir string) (*PolicySet, error) {
-	ps := &PolicySet{Dir: dir}
+	return loadPolicySet(dir, nil)
+}
+
+// LoadPolicyFile loads the policies and bindings of a single YAML file,
+// whatever its name.
+func LoadPolicyFile(path string) (*PolicySet, error) {
+	ps := &PolicySet{Dir: filepath.Dir(path), PolicyFiles: map[string]string{}, sources: map[string]resourceSource{}}
+
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	if err := ps.loadDocuments(fileBytes, path); err != nil {
+		return nil, fmt.Errorf("load documents from %s: %w", path, err)
+	}
+
+	return ps, nil
+}
+
+// loadPolicySet implements LoadPolicySet. When problems is non-nil, files that
+// fail to load are recorded there and loading continues with the next file.
+func loadPolicySet(dir string, problems *[]error) (*PolicySet, error) {
+	ps := &PolicySet{Dir: dir, PolicyFiles: map[string]string{}, sources: map[string]resourceSource{}}
 
 	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -52,21 +99,18 @@ func LoadPolicySet(dir string) (*PolicySet, error) {
 			return nil
 		}
 
-		// Check if file matches policy or binding naming convention
+		// Check if file matches policy, binding, or CRD naming convention
 		name := d.Name()
-		if !isPolicyFile(name) && !isBindingFile(name) {
+		if !isPolicyFile(name) && !isBindingFile(name) && !isCRDFile(name) {
 			return nil
 		}
 
-		// Read and load the file
-		fileBytes, err := os.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("read %s: %w", path, err)
-		}
+		if err := ps.loadFile(path, name); err != nil {
+			if problems == nil {
+				return err
+			}
 
-		// Process all documents in the YAML file
-		if err := ps.loadDocuments(fileBytes, path); err != nil {
-			return fmt.Errorf("load documents from %s: %w", path, err)
+			*problems = append(*problems, err)
 		}
 
 		return nil
@@ -78,6 +122,29 @@ func LoadPolicySet(dir string) (*PolicySet, error) {
 	return ps, nil
 }
 
+// loadFile loads the policies, bindings or CRDs of the file at path.
+func (ps *PolicySet) loadFile(path, name string) error {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	if isCRDFile(name) {
+		if err := ps.loadCRDs(fileBytes); err != nil {
+			return fmt.Errorf("load CRDs from %s: %w", path, err)
+		}
+
+		return nil
+	}
+
+	// Process all documents in the YAML file
+	if err := ps.loadDocuments(fileBytes, path); err != nil {
+		return fmt.Errorf("load documents from %s: %w", path, err)
+	}
+
+	return nil
+}
+
 // Matches: policy.yaml, policies.yaml, *.policy.yaml, *.policies.yaml.
 func isPolicyFile(name string) bool {
 	return name == "policy.yaml" || name == "policy.yml" ||
@@ -94,6 +161,49 @@ func isBindingFile(name string) bool {
 		strings.HasSuffix(name, ".bindings.yaml") || strings.HasSuffix(name, ".bindings.yml")
 }
 
+// Matches: crd.yaml, crds.yaml, *.crd.yaml, *.crds.yaml.
+func isCRDFile(name string) bool {
+	return name == "crd.yaml" || name == "crd.yml" ||
+		name == "crds.yaml" || name == "crds.yml" ||
+		strings.HasSuffix(name, ".crd.yaml") || strings.HasSuffix(name, ".crd.yml") ||
+		strings.HasSuffix(name, ".crds.yaml") || strings.HasSuffix(name, ".crds.yml")
+}
+
+// loadCRDs loads CustomResourceDefinitions from a multi-document YAML file.
+// CRDs are kept unstructured since only their schemas are needed.
+func (ps *PolicySet) loadCRDs(yamlBytes []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(yamlBytes))
+
+	for docNum := 1; ; docNum++ {
+		var node yaml.Node
+
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("decode document %d: %w", docNum, err)
+		}
+
+		jsonBytes, err := yamlNodeToJSON(&node)
+		if err != nil {
+			return fmt.Errorf("convert document %d to JSON: %w", docNum, err)
+		}
+
+		crd := &unstructured.Unstructured{}
+		if err := crd.UnmarshalJSON(jsonBytes); err != nil {
+			return fmt.Errorf("decode document %d: %w", docNum, err)
+		}
+
+		if crd.GetKind() != "CustomResourceDefinition" {
+			return fmt.Errorf("%w: document %d has kind %q", ErrNotCRD, docNum, crd.GetKind())
+		}
+
+		ps.CRDs = append(ps.CRDs, crd)
+	}
+}
+
 // loadDocuments splits a YAML file into documents and loads each one.
 //
 //nolint:cyclop // Multiple document decoding and type dispatch
@@ -115,31 +225,39 @@ func (ps *PolicySet) loadDocuments(yamlBytes []byte, filePath string) error {
 			return fmt.Errorf("decode document %d: %w", docNum, err)
 		}
 
+		line := fieldLine(&node, "")
+
 		// Convert YAML node directly to JSON
 		jsonBytes, err := yamlNodeToJSON(&node)
 		if err != nil {
-			return fmt.Errorf("convert document %d to JSON: %w", docNum, err)
+			return fmt.Errorf("convert document %d at line %d to JSON: %w", docNum, line, err)
 		}
 
 		// Decode once and append based on concrete type
-		obj, _, err := universalDeserializer.Decode(jsonBytes, nil, nil)
+		obj, gvk, err := universalDeserializer.Decode(jsonBytes, nil, nil)
 		if err != nil {
-			return fmt.Errorf("decode document %d: %w", docNum, err)
+			return fmt.Errorf("decode document %d at line %d: %w", docNum, line, err)
 		}
 
 		switch o := obj.(type) {
 		case *admissionv1beta1.MutatingAdmissionPolicy:
 			ps.MutatingPolicies = append(ps.MutatingPolicies, o)
+			ps.PolicyFiles[o.Name] = filePath
 		case *admissionv1beta1.MutatingAdmissionPolicyBinding:
 			ps.MutatingBindings = append(ps.MutatingBindings, o)
 		case *admissionv1.ValidatingAdmissionPolicy:
 			ps.ValidatingPolicies = append(ps.ValidatingPolicies, o)
+			ps.PolicyFiles[o.Name] = filePath
 		case *admissionv1.ValidatingAdmissionPolicyBinding:
 			ps.ValidatingBindings = append(ps.ValidatingBindings, o)
 		case *admissionv1beta1.ValidatingAdmissionPolicy:
-			return fmt.Errorf("%w: document %d in %s", ErrUnsupportedV1Beta1Policy, docNum, filePath)
+			return fmt.Errorf("%w: document %d in %s:%d", ErrUnsupportedV1Beta1Policy, docNum, filePath, line)
 		case *admissionv1beta1.ValidatingAdmissionPolicyBinding:
-			return fmt.Errorf("%w: document %d in %s", ErrUnsupportedV1Beta1Binding, docNum, filePath)
+			return fmt.Errorf("%w: document %d in %s:%d", ErrUnsupportedV1Beta1Binding, docNum, filePath, line)
+		}
+
+		if accessor, err := meta.Accessor(obj); err == nil {
+			ps.sources[gvk.Kind+"/"+accessor.GetName()] = resourceSource{file: filePath, node: &node}
 		}
 
 		docNum++
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,12 +27,20 @@ var (
 	errAPIVersionRequired = errors.New("apiVersion is required")
 	errKindRequired       = errors.New("kind is required")
 	errKindMismatch       = errors.New("kind mismatch")
+	errInvalidTemplateRef = errors.New("invalid template reference")
+	errPatchValueRequired = errors.New("operation requires a value")
+	errExpectConflict     = errors.New("conflicting expectation")
 )
 
+// templateKey is the top-level key a test object uses to extend a suite-level template.
+const templateKey = "from"
+
 // parseTestRequestFile parses a test request file and populates the TestRequest.
 // Handles *.request.yaml (simplified AdmissionRequest format), *.object.yaml (raw Kubernetes object),
 // *.oldObject.yaml (object for DELETE operations), *.params.yaml (policy parameters),
-// *.annotations.yaml (expected audit annotations), and *.warnings.txt (expected warnings).
+// *.annotations.yaml (expected audit annotations), *.warnings.txt (expected warnings),
+// *.matrix.yaml (param sets with expected outcomes), and *.admissionreview.json/.yaml
+// (captured AdmissionReview payloads).
 func parseTestRequestFile(testReq *testRequest) error {
 	data, err := os.ReadFile(testReq.FilePath)
 	if err != nil {
@@ -38,25 +48,39 @@ func parseTestRequestFile(testReq *testRequest) error {
 	}
 
 	switch {
-	case strings.HasSuffix(testReq.FilePath, ".request.yaml"):
+	case hasYAMLSuffix(testReq.FilePath, ".request"):
 		return parseRequestYAML(testReq, data)
-	case strings.HasSuffix(testReq.FilePath, ".object.yaml"):
+	case hasYAMLSuffix(testReq.FilePath, ".object"):
 		return parseObjectYAML(testReq, data)
-	case strings.HasSuffix(testReq.FilePath, ".oldObject.yaml"):
+	case hasYAMLSuffix(testReq.FilePath, ".oldObject"):
 		return parseOldObjectYAML(testReq, data)
-	case strings.HasSuffix(testReq.FilePath, ".params.yaml"):
+	case hasYAMLSuffix(testReq.FilePath, ".params"):
 		return parseParamsYAML(testReq, data)
-	case strings.HasSuffix(testReq.FilePath, ".annotations.yaml"):
+	case hasYAMLSuffix(testReq.FilePath, ".annotations"):
 		return parseAnnotationsYAML(testReq, data)
 	case strings.HasSuffix(testReq.FilePath, ".warnings.txt"):
 		return parseWarningsFile(testReq, data)
-	case strings.HasSuffix(testReq.FilePath, ".authorizer.yaml"):
+	case hasYAMLSuffix(testReq.FilePath, ".authorizer"):
 		return parseAuthorizerYAML(testReq, data)
+	case hasYAMLSuffix(testReq.FilePath, ".matrix"):
+		return parseMatrixYAML(testReq, data)
+	case isAdmissionReviewFile(testReq.FilePath):
+		return parseAdmissionReview(testReq, data)
 	default:
 		return fmt.Errorf("%w: %s", ErrUnknownFileType, testReq.FilePath)
 	}
 }
 
+// connectOptionsKinds maps the pod subresources accepting CONNECT to the kind
+// of their options, which the API server passes to policies as object.
+//
+//nolint:gochecknoglobals // Static lookup table
+var connectOptionsKinds = map[string]string{
+	"attach":      "PodAttachOptions",
+	"exec":        "PodExecOptions",
+	"portforward": "PodPortForwardOptions",
+}
+
 // simplifiedRequest represents the simplified requestYAML format.
 type simplifiedRequest struct {
 	Operation       string                     `json:"operation"`
@@ -68,6 +92,21 @@ type simplifiedRequest struct {
 	Object          map[string]interface{}     `json:"object,omitempty"`
 	OldObject       map[string]interface{}     `json:"oldObject,omitempty"`
 	Options         map[string]interface{}     `json:"options,omitempty"`
+	Expect          *inlineExpectation         `json:"expect,omitempty"`
+}
+
+// inlineExpectation is the expect block of the simplified request format.
+// Its fields take precedence over the outcome implied by the file name;
+// setting one that a sibling file also sets is a load error.
+type inlineExpectation struct {
+	Allowed          *bool                  `json:"allowed,omitempty"`
+	Message          string                 `json:"message,omitempty"`
+	Warnings         []string               `json:"warnings,omitempty"`
+	AuditAnnotations map[string]string      `json:"auditAnnotations,omitempty"`
+	MutatedObject    map[string]interface{} `json:"mutatedObject,omitempty"`
+
+	// file is the request file declaring the block, for errors.
+	file string
 }
 
 // parseRequestYAML parses a simplified request format.
@@ -81,9 +120,21 @@ func parseRequestYAML(testReq *testRequest, data []byte) error {
 		return err
 	}
 
+	connectOptions, err := connectOptionsObject(&req)
+	if err != nil {
+		return err
+	}
+
 	testReq.Request = buildAdmissionRequestFromSimplified(&req, testReq)
 	testReq.NamespaceName = req.Namespace
 
+	if connectOptions != nil {
+		gvk := connectOptions.GroupVersionKind()
+		testReq.Object = connectOptions
+		testReq.Request.Kind = metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}
+		testReq.Request.Resource = metav1.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	}
+
 	// Parse additional objects
 	if req.OldObject != nil {
 		testReq.OldObject = &unstructured.Unstructured{Object: req.OldObject}
@@ -93,6 +144,70 @@ func parseRequestYAML(testReq *testRequest, data []byte) error {
 		testReq.NamespaceObj = &unstructured.Unstructured{Object: req.NamespaceObject}
 	}
 
+	if req.Expect != nil {
+		req.Expect.file = testReq.FilePath
+		testReq.InlineExpect = req.Expect
+	}
+
+	return nil
+}
+
+// applyInlineExpectation sets the expectations of the expect block of the
+// test's request file, once the test's other files are merged. An explicit
+// allowed overrides the default outcome of a file name without a token, but
+// must agree with an .allow, .deny, .warn or .audit token; the other fields
+// must not be set by sibling files too.
+func applyInlineExpectation(testReq *testRequest, baseName string) error {
+	expect := testReq.InlineExpect
+	if expect == nil {
+		return nil
+	}
+
+	if expect.Allowed != nil {
+		if hasOutcomeToken(baseName) && *expect.Allowed != testReq.ExpectAllowed {
+			return fmt.Errorf("%s: expect.allowed: %w: file name expects allowed=%t",
+				expect.file, errExpectConflict, testReq.ExpectAllowed)
+		}
+
+		testReq.ExpectAllowed = *expect.Allowed
+	}
+
+	conflicts := []struct {
+		field   string
+		inline  bool
+		sibling bool
+		source  string
+	}{
+		{"message", expect.Message != "", testReq.ExpectMessage != "", ".message.txt"},
+		{"warnings", len(expect.Warnings) > 0, len(testReq.ExpectWarnings) > 0, ".warnings.txt"},
+		{"auditAnnotations", expect.AuditAnnotations != nil, testReq.ExpectAuditAnnotations != nil, ".annotations.yaml"},
+		{"mutatedObject", expect.MutatedObject != nil, testReq.ExpectedObject != nil, ".gold.yaml"},
+	}
+
+	for _, c := range conflicts {
+		if c.inline && c.sibling {
+			return fmt.Errorf("%s: expect.%s: %w: also set by the test's %s file",
+				expect.file, c.field, errExpectConflict, c.source)
+		}
+	}
+
+	if expect.Message != "" {
+		testReq.ExpectMessage = expect.Message
+	}
+
+	if len(expect.Warnings) > 0 {
+		testReq.ExpectWarnings = expect.Warnings
+	}
+
+	if expect.AuditAnnotations != nil {
+		testReq.ExpectAuditAnnotations = expect.AuditAnnotations
+	}
+
+	if expect.MutatedObject != nil {
+		testReq.ExpectedObject = &unstructured.Unstructured{Object: expect.MutatedObject}
+		testReq.ExpectMutated = true
+	}
+
 	return nil
 }
 
@@ -120,6 +235,35 @@ func validateSimplifiedRequest(req *simplifiedRequest) error {
 	return nil
 }
 
+// connectOptionsObject returns the options of a CONNECT request to a pod
+// subresource as the typed object the API server binds to object, e.g. a
+// PodExecOptions for exec, or nil for other requests and for requests giving
+// an object. The options are validated strictly against the scheme; their
+// apiVersion and kind may be omitted.
+func connectOptionsObject(req *simplifiedRequest) (*unstructured.Unstructured, error) {
+	kind, ok := connectOptionsKinds[req.SubResource]
+	if !ok || req.Operation != string(admissionv1.Connect) || req.Object != nil || req.Options == nil {
+		return nil, nil //nolint:nilnil // Not a CONNECT request with typed options
+	}
+
+	gvk := schema.GroupVersionKind{Group: "", Version: "v1", Kind: kind}
+
+	options := &unstructured.Unstructured{Object: runtime.DeepCopyJSON(req.Options)}
+	if options.GetAPIVersion() == "" {
+		options.SetAPIVersion(gvk.GroupVersion().String())
+	}
+
+	if options.GetKind() == "" {
+		options.SetKind(kind)
+	}
+
+	if err := validateWithScheme(options.Object, "options", &gvk); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
 func validateWithScheme(obj map[string]interface{}, field string, expectedGVK *schema.GroupVersionKind) error {
 	if obj == nil {
 		return nil
@@ -202,11 +346,7 @@ func buildAdmissionRequestFromSimplified(req *simplifiedRequest, testReq *testRe
 		testReq.Object = obj
 
 		gvk := obj.GroupVersionKind()
-		admReq.Resource = metav1.GroupVersionResource{
-			Group:    gvk.Group,
-			Version:  gvk.Version,
-			Resource: strings.ToLower(gvk.Kind) + "s",
-		}
+		admReq.Resource = resourceForKind(gvk)
 		admReq.Kind = metav1.GroupVersionKind{
 			Group:   gvk.Group,
 			Version: gvk.Version,
@@ -229,6 +369,11 @@ func parseObjectYAML(testReq *testRequest, data []byte) error {
 		return fmt.Errorf("failed to unmarshal object: %w", err)
 	}
 
+	obj, err := resolveTemplate(obj, filepath.Dir(testReq.FilePath))
+	if err != nil {
+		return err
+	}
+
 	if err := validateWithScheme(obj, "object", nil); err != nil {
 		return err
 	}
@@ -245,6 +390,72 @@ func parseObjectYAML(testReq *testRequest, data []byte) error {
 	return nil
 }
 
+// resolveTemplate merges the template referenced by the object's top-level "from" key
+// underneath the object. Templates are "_<name>.object.yaml" files in the tests directory;
+// maps are merged recursively and any other value in the object replaces the template value.
+// Objects without a "from" key are returned unchanged.
+func resolveTemplate(obj map[string]interface{}, dir string) (map[string]interface{}, error) {
+	ref, ok := obj[templateKey]
+	if !ok {
+		return obj, nil
+	}
+
+	name, ok := ref.(string)
+	if !ok || !strings.HasPrefix(name, "_") || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("%w: %s must name a template starting with '_', got %v", errInvalidTemplateRef, templateKey, ref)
+	}
+
+	templatePath := yamlFilePath(filepath.Join(dir, name), ".object")
+
+	templateData, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("read template %s: %w", templatePath, err)
+	}
+
+	var base map[string]interface{}
+	if err := yaml.Unmarshal(templateData, &base); err != nil {
+		return nil, fmt.Errorf("unmarshal template %s: %w", templatePath, err)
+	}
+
+	if _, nested := base[templateKey]; nested {
+		return nil, fmt.Errorf("%w: template %s cannot itself use %q", errInvalidTemplateRef, templatePath, templateKey)
+	}
+
+	delete(obj, templateKey)
+	mergeMaps(base, obj)
+
+	return base, nil
+}
+
+// mergeMaps recursively merges src into dst. Nested maps are merged,
+// everything else (lists, scalars, type mismatches) in src replaces dst.
+func mergeMaps(dst, src map[string]interface{}) {
+	for key, srcVal := range src {
+		if dstMap, ok := dst[key].(map[string]interface{}); ok {
+			if srcMap, ok := srcVal.(map[string]interface{}); ok {
+				mergeMaps(dstMap, srcMap)
+
+				continue
+			}
+		}
+
+		dst[key] = srcVal
+	}
+}
+
+// resourceForKind returns the resource of a kind as the API server names it,
+// e.g. "ingresses" for Ingress and "endpoints" for Endpoints, so that rules
+// matching resources apply to the request.
+func resourceForKind(gvk schema.GroupVersionKind) metav1.GroupVersionResource {
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	return metav1.GroupVersionResource{
+		Group:    plural.Group,
+		Version:  plural.Version,
+		Resource: plural.Resource,
+	}
+}
+
 func buildCreateRequestFromObject(testName string, obj *unstructured.Unstructured) *admissionv1.AdmissionRequest {
 	gvk := obj.GroupVersionKind()
 
@@ -256,11 +467,7 @@ func buildCreateRequestFromObject(testName string, obj *unstructured.Unstructure
 			Version: gvk.Version,
 			Kind:    gvk.Kind,
 		},
-		Resource: metav1.GroupVersionResource{
-			Group:    gvk.Group,
-			Version:  gvk.Version,
-			Resource: strings.ToLower(gvk.Kind) + "s",
-		},
+		Resource:  resourceForKind(gvk),
 		Name:      obj.GetName(),
 		Namespace: obj.GetNamespace(),
 	}
@@ -291,7 +498,10 @@ func loadAuxiliaryFiles(testReq *testRequest) error {
 }
 
 func loadGoldFile(testReq *testRequest) error {
-	goldPath := strings.Replace(testReq.FilePath, ".object.yaml", ".gold.yaml", 1)
+	return loadGoldFileAt(testReq, yamlFilePath(trimYAMLSuffix(testReq.FilePath, ".object"), ".gold"))
+}
+
+func loadGoldFileAt(testReq *testRequest, goldPath string) error {
 	if _, err := os.Stat(goldPath); err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -317,8 +527,7 @@ func loadGoldFile(testReq *testRequest) error {
 }
 
 func loadParamsFile(testReq *testRequest) error {
-	paramsPath := strings.Replace(testReq.FilePath, ".object.yaml", ".params.yaml", 1)
-	paramsPath = strings.Replace(paramsPath, ".request.yaml", ".params.yaml", 1)
+	paramsPath := yamlFilePath(trimYAMLSuffix(testReq.FilePath, ".object"), ".params")
 
 	if _, err := os.Stat(paramsPath); err != nil {
 		if os.IsNotExist(err) {
@@ -344,9 +553,10 @@ func loadParamsFile(testReq *testRequest) error {
 }
 
 func loadMessageFile(testReq *testRequest) error {
-	messagePath := strings.Replace(testReq.FilePath, ".object.yaml", ".message.txt", 1)
-	messagePath = strings.Replace(messagePath, ".request.yaml", ".message.txt", 1)
+	return loadMessageFileAt(testReq, trimYAMLSuffix(testReq.FilePath, ".object")+".message.txt")
+}
 
+func loadMessageFileAt(testReq *testRequest, messagePath string) error {
 	if _, err := os.Stat(messagePath); err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -366,8 +576,7 @@ func loadMessageFile(testReq *testRequest) error {
 }
 
 func loadAuthorizerFile(testReq *testRequest) error {
-	authPath := strings.Replace(testReq.FilePath, ".object.yaml", ".authorizer.yaml", 1)
-	authPath = strings.Replace(authPath, ".request.yaml", ".authorizer.yaml", 1)
+	authPath := yamlFilePath(trimYAMLSuffix(testReq.FilePath, ".object"), ".authorizer")
 
 	if _, err := os.Stat(authPath); err != nil {
 		if os.IsNotExist(err) {
@@ -385,6 +594,90 @@ func loadAuthorizerFile(testReq *testRequest) error {
 	return parseAuthorizerYAML(testReq, authData)
 }
 
+// loadPatchFile loads the expected JSON Patch operations of a mutating test.
+func loadPatchFile(testReq *testRequest, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("read patch file: %w", err)
+	}
+
+	patch := []evaluator.PatchOperation{}
+	if err := yaml.UnmarshalStrict(data, &patch); err != nil {
+		return fmt.Errorf("unmarshal patch file %s: %w", path, err)
+	}
+
+	// A missing value decodes like an explicit null, so require it where
+	// RFC 6902 does.
+	var rawOps []map[string]any
+	if err := yaml.Unmarshal(data, &rawOps); err != nil {
+		return fmt.Errorf("unmarshal patch file %s: %w", path, err)
+	}
+
+	for i, op := range rawOps {
+		if _, ok := op["value"]; !ok && (patch[i].Op == "add" || patch[i].Op == "replace" || patch[i].Op == "test") {
+			return fmt.Errorf("patch file %s: %s %s: %w", path, patch[i].Op, patch[i].Path, errPatchValueRequired)
+		}
+	}
+
+	testReq.ExpectedPatch = patch
+
+	return nil
+}
+
+// loadStatusFile loads the expected status of a denial, as the API server
+// returns it.
+func loadStatusFile(testReq *testRequest, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("read status file: %w", err)
+	}
+
+	status := &metav1.Status{}
+	if err := yaml.UnmarshalStrict(data, status); err != nil {
+		return fmt.Errorf("unmarshal status file %s: %w", path, err)
+	}
+
+	testReq.ExpectStatus = status
+
+	return nil
+}
+
+// testResponseFilePath returns the path of the recorded cluster response of
+// the test named name, e.g. "p.allow[CREATE].yaml" for an expanded variant,
+// whose files live next to filePath.
+func testResponseFilePath(filePath, name string) string {
+	return yamlFilePath(filepath.Join(filepath.Dir(filePath), strings.TrimSuffix(name, ".yaml")), ".response")
+}
+
+// loadResponseFile loads a cluster admission response recorded with -record.
+func loadResponseFile(testReq *testRequest, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("read response file: %w", err)
+	}
+
+	var response evaluator.RecordedResponse
+	if err := yaml.UnmarshalStrict(data, &response); err != nil {
+		return fmt.Errorf("unmarshal response file %s: %w", path, err)
+	}
+
+	testReq.RecordedResponse = &response
+
+	return nil
+}
+
 // parseOldObjectYAML parses a raw Kubernetes object and creates an AdmissionRequest for DELETE operation.
 // This is used for testing deletion policies where only oldObject is relevant.
 func parseOldObjectYAML(testReq *testRequest, data []byte) error {
@@ -393,6 +686,11 @@ func parseOldObjectYAML(testReq *testRequest, data []byte) error {
 		return fmt.Errorf("failed to unmarshal oldObject: %w", err)
 	}
 
+	obj, err := resolveTemplate(obj, filepath.Dir(testReq.FilePath))
+	if err != nil {
+		return err
+	}
+
 	if err := validateWithScheme(obj, "oldObject", nil); err != nil {
 		return err
 	}
@@ -410,11 +708,7 @@ func parseOldObjectYAML(testReq *testRequest, data []byte) error {
 			Version: gvk.Version,
 			Kind:    gvk.Kind,
 		},
-		Resource: metav1.GroupVersionResource{
-			Group:    gvk.Group,
-			Version:  gvk.Version,
-			Resource: strings.ToLower(gvk.Kind) + "s",
-		},
+		Resource:  resourceForKind(gvk),
 		Name:      unstruct.GetName(),
 		Namespace: unstruct.GetNamespace(),
 	}
@@ -423,7 +717,7 @@ func parseOldObjectYAML(testReq *testRequest, data []byte) error {
 	testReq.NamespaceName = unstruct.GetNamespace()
 
 	// Look for corresponding .params.yaml file
-	paramsPath := strings.Replace(testReq.FilePath, ".oldObject.yaml", ".params.yaml", 1)
+	paramsPath := yamlFilePath(trimYAMLSuffix(testReq.FilePath, ".oldObject"), ".params")
 	if _, err := os.Stat(paramsPath); err == nil {
 		paramsData, err := os.ReadFile(paramsPath)
 		if err != nil {
@@ -439,7 +733,7 @@ func parseOldObjectYAML(testReq *testRequest, data []byte) error {
 	}
 
 	// Look for corresponding .message.txt file (expected error message)
-	messagePath := strings.Replace(testReq.FilePath, ".oldObject.yaml", ".message.txt", 1)
+	messagePath := trimYAMLSuffix(testReq.FilePath, ".oldObject") + ".message.txt"
 	if _, err := os.Stat(messagePath); err == nil {
 		messageData, err := os.ReadFile(messagePath)
 		if err != nil {
@@ -1,9 +1,16 @@
 package loader
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/zemanlx/kat/internal/evaluator"
 )
 
 //nolint:funlen // Table-driven test with many cases
@@ -58,6 +65,29 @@ func TestValidateWithScheme(t *testing.T) {
 			field:   "object",
 			wantErr: true, // Should fail with strict validation
 		},
+		{
+			name: "invalid pod structure - typo in nested container field (strict)",
+			obj: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"name": "test-pod",
+				},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "nginx",
+							"image": "nginx",
+							"securityContex": map[string]interface{}{ // Typo 'securityContex' instead of 'securityContext'
+								"privileged": true,
+							},
+						},
+					},
+				},
+			},
+			field:   "object",
+			wantErr: true,
+		},
 		{
 			name: "invalid pod structure - wrong type for field",
 			obj: map[string]interface{}{
@@ -227,3 +257,336 @@ func TestInferOperation(t *testing.T) {
 		})
 	}
 }
+
+//nolint:funlen // Table-driven test with many cases
+func TestResolveTemplate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	base := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: base
+  labels:
+    app: web
+    team: platform
+spec:
+  replicas: 1
+`
+
+	if err := os.WriteFile(filepath.Join(dir, "_base.object.yaml"), []byte(base), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "_nested.object.yaml"), []byte("from: _base\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		obj     map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "no template",
+			obj:  map[string]interface{}{"kind": "Pod"},
+			want: map[string]interface{}{"kind": "Pod"},
+		},
+		{
+			name: "override merges under test fields",
+			obj: map[string]interface{}{
+				"from": "_base",
+				"metadata": map[string]interface{}{
+					"name":   "override",
+					"labels": map[string]interface{}{"team": "payments"},
+				},
+				"spec": map[string]interface{}{"replicas": float64(3)},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":   "override",
+					"labels": map[string]interface{}{"app": "web", "team": "payments"},
+				},
+				"spec": map[string]interface{}{"replicas": float64(3)},
+			},
+		},
+		{
+			name:    "template name must start with underscore",
+			obj:     map[string]interface{}{"from": "base"},
+			wantErr: true,
+		},
+		{
+			name:    "template must not be a path",
+			obj:     map[string]interface{}{"from": "_../base"},
+			wantErr: true,
+		},
+		{
+			name:    "missing template",
+			obj:     map[string]interface{}{"from": "_missing"},
+			wantErr: true,
+		},
+		{
+			name:    "nested templates are rejected",
+			obj:     map[string]interface{}{"from": "_nested"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := resolveTemplate(tt.obj, dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("resolveTemplate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadPatchFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []evaluator.PatchOperation
+		wantErr error
+	}{
+		{
+			name:    "explicit null value",
+			content: "- op: add\n  path: /spec/x\n  value: null\n",
+			want:    []evaluator.PatchOperation{{Op: "add", Path: "/spec/x"}},
+		},
+		{
+			name:    "remove without value",
+			content: "- op: remove\n  path: /spec/x\n",
+			want:    []evaluator.PatchOperation{{Op: "remove", Path: "/spec/x"}},
+		},
+		{
+			name:    "add without value",
+			content: "- op: add\n  path: /spec/x\n",
+			wantErr: errPatchValueRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "test.patch.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+
+			testReq := &testRequest{}
+
+			err := loadPatchFile(testReq, path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("loadPatchFile() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.want, testReq.ExpectedPatch); diff != "" {
+				t.Errorf("loadPatchFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseRequestYAML_Expect(t *testing.T) {
+	t.Parallel()
+
+	allowed := false
+
+	tests := []struct {
+		name    string
+		content string
+		want    *inlineExpectation
+	}{
+		{
+			name:    "no expect block",
+			content: "operation: CREATE\n",
+		},
+		{
+			name: "all fields",
+			content: `operation: CREATE
+expect:
+  allowed: false
+  message: replicas must be at most 3
+  warnings: [deprecated field]
+  auditAnnotations:
+    policy/reason: too-many
+  mutatedObject:
+    apiVersion: v1
+    kind: Pod
+`,
+			want: &inlineExpectation{
+				Allowed:          &allowed,
+				Message:          "replicas must be at most 3",
+				Warnings:         []string{"deprecated field"},
+				AuditAnnotations: map[string]string{"policy/reason": "too-many"},
+				MutatedObject:    map[string]interface{}{"apiVersion": "v1", "kind": "Pod"},
+				file:             "test.request.yaml",
+			},
+		},
+		{
+			name:    "allowed only",
+			content: "operation: CREATE\nexpect:\n  allowed: false\n",
+			want:    &inlineExpectation{Allowed: &allowed, file: "test.request.yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testReq := &testRequest{FilePath: "test.request.yaml"}
+			if err := parseRequestYAML(testReq, []byte(tt.content)); err != nil {
+				t.Fatalf("parseRequestYAML() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, testReq.InlineExpect, cmp.AllowUnexported(inlineExpectation{})); diff != "" {
+				t.Errorf("InlineExpect mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseRequestYAML_ConnectOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		wantKind string
+		wantErr  string
+	}{
+		{
+			name:     "exec",
+			content:  "operation: CONNECT\nsubResource: exec\noptions:\n  container: app\n  command: [ls]\n",
+			wantKind: "PodExecOptions",
+		},
+		{
+			name:     "portforward",
+			content:  "operation: CONNECT\nsubResource: portforward\noptions:\n  ports: [8080]\n",
+			wantKind: "PodPortForwardOptions",
+		},
+		{
+			name:    "unknown subresource",
+			content: "operation: CONNECT\nsubResource: proxy\noptions:\n  path: /healthz\n",
+		},
+		{
+			name:    "not CONNECT",
+			content: "operation: CREATE\nsubResource: exec\noptions:\n  command: [ls]\n",
+		},
+		{
+			name:    "unknown field",
+			content: "operation: CONNECT\nsubResource: exec\noptions:\n  comand: [ls]\n",
+			wantErr: `options: invalid kubernetes object: strict decoding error: unknown field "comand"`,
+		},
+		{
+			name:    "kind mismatch",
+			content: "operation: CONNECT\nsubResource: exec\noptions:\n  apiVersion: v1\n  kind: PodAttachOptions\n",
+			wantErr: "options: kind mismatch: expected PodExecOptions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testReq := &testRequest{FilePath: "test.request.yaml"}
+
+			err := parseRequestYAML(testReq, []byte(tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseRequestYAML() error = %v, want to contain %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseRequestYAML() error = %v", err)
+			}
+
+			if tt.wantKind == "" {
+				if testReq.Object != nil {
+					t.Errorf("Object = %v, want nil", testReq.Object.Object)
+				}
+
+				return
+			}
+
+			if testReq.Object == nil || testReq.Object.GetKind() != tt.wantKind || testReq.Object.GetAPIVersion() != "v1" {
+				t.Fatalf("Object = %v, want a v1 %s", testReq.Object, tt.wantKind)
+			}
+
+			if testReq.Request.Kind.Kind != tt.wantKind || testReq.Request.Resource.Resource != "pods" {
+				t.Errorf("Request kind = %v, resource = %v, want %s on pods", testReq.Request.Kind, testReq.Request.Resource, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestParseAuthorizerYAML(t *testing.T) {
+	t.Parallel()
+
+	content := `- resource: pods
+  verb: create
+  error: webhook timeout
+- resource: secrets
+  verb: get
+  decision: allow
+  reason: 'RBAC: allowed by ClusterRoleBinding "admins"'
+`
+
+	testReq := &testRequest{}
+	if err := parseAuthorizerYAML(testReq, []byte(content)); err != nil {
+		t.Fatalf("parseAuthorizerYAML() error = %v", err)
+	}
+
+	want := []evaluator.AuthorizationMockConfig{
+		{Resource: "pods", Verb: "create", Error: "webhook timeout"},
+		{Resource: "secrets", Verb: "get", Decision: "allow", Reason: `RBAC: allowed by ClusterRoleBinding "admins"`},
+	}
+	if diff := cmp.Diff(want, testReq.Authorizer); diff != "" {
+		t.Errorf("Authorizer mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResourceForKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		gvk  schema.GroupVersionKind
+		want string
+	}{
+		{gvk: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, want: "pods"},
+		{gvk: schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, want: "ingresses"},
+		{gvk: schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}, want: "networkpolicies"},
+		{gvk: schema.GroupVersionKind{Version: "v1", Kind: "Endpoints"}, want: "endpoints"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.gvk.Kind, func(t *testing.T) {
+			t.Parallel()
+
+			got := resourceForKind(tt.gvk)
+			if got.Resource != tt.want || got.Group != tt.gvk.Group || got.Version != tt.gvk.Version {
+				t.Errorf("resourceForKind(%v) = %v, want resource %q", tt.gvk, got, tt.want)
+			}
+		})
+	}
+}
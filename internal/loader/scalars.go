@@ -0,0 +1,118 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// ambiguousScalarWarnings reports unquoted values in the YAML files of
+// testsDir that test objects do not load as written: numbers whose text
+// changes, like the version 1.10 loading as 1.1 or 0755 as the octal 493, and
+// YAML 1.1 booleans like "on". Quoting such a value keeps it a string.
+func ambiguousScalarWarnings(testsDir string) []string {
+	var paths []string
+
+	for _, ext := range yamlExtensions {
+		matches, err := filepath.Glob(filepath.Join(testsDir, "*"+ext))
+		if err != nil {
+			return nil
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	sort.Strings(paths)
+
+	var warnings []string
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// Unreadable files fail when their test is loaded.
+			continue
+		}
+
+		for _, warning := range ambiguousScalars(data) {
+			warnings = append(warnings, path+":"+warning)
+		}
+	}
+
+	return warnings
+}
+
+// ambiguousScalars returns "<line>: <problem>" for each ambiguous value of
+// the YAML documents in data.
+func ambiguousScalars(data []byte) []string {
+	var problems []string
+
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			// Invalid YAML fails when its test is loaded.
+			return problems
+		}
+
+		walkScalars(&doc, "", func(node *yaml.Node, key string) {
+			if problem := ambiguousScalar(node.Value); problem != "" {
+				problems = append(problems, fmt.Sprintf("%d: value %s of %q %s; quote it to keep it a string", node.Line, node.Value, key, problem))
+			}
+		})
+	}
+}
+
+// walkScalars calls fn for each plain (unquoted) mapping value and sequence
+// item below node, with the closest mapping key.
+func walkScalars(node *yaml.Node, key string, fn func(node *yaml.Node, key string)) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			walkScalars(child, key, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walkScalars(node.Content[i+1], node.Content[i].Value, fn)
+		}
+	case yaml.ScalarNode:
+		if node.Style == 0 {
+			fn(node, key)
+		}
+	case yaml.AliasNode:
+		// The anchored value is checked where it is defined.
+	}
+}
+
+// ambiguousScalar describes how the loader reads a plain value differently
+// from its text, or returns "" when it reads it as written.
+func ambiguousScalar(value string) string {
+	var parsed any
+	if err := sigsyaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return ""
+	}
+
+	switch parsed.(type) {
+	case float64, int64, int:
+		canonical, err := json.Marshal(parsed)
+		if err != nil || string(canonical) == value {
+			return ""
+		}
+
+		return "is parsed as the number " + string(canonical)
+	case bool:
+		if value == "true" || value == "false" {
+			return ""
+		}
+
+		return fmt.Sprintf("is parsed as the boolean %t", parsed)
+	default:
+		return ""
+	}
+}
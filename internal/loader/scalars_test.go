@@ -0,0 +1,140 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAmbiguousScalars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{
+			name: "numbers and strings as written",
+			yaml: "port: 80\nversion: \"1.10\"\nratio: 1.5\nname: web\nenabled: true\n",
+		},
+		{
+			name: "version losing a trailing zero",
+			yaml: "metadata:\n  labels:\n    version: 1.10\n",
+			want: []string{`3: value 1.10 of "version" is parsed as the number 1.1; quote it to keep it a string`},
+		},
+		{
+			name: "octal",
+			yaml: "mode: 0755\n",
+			want: []string{`1: value 0755 of "mode" is parsed as the number 493; quote it to keep it a string`},
+		},
+		{
+			name: "YAML 1.1 boolean in a list",
+			yaml: "args:\n- on\n- off\n",
+			want: []string{
+				`2: value on of "args" is parsed as the boolean true; quote it to keep it a string`,
+				`3: value off of "args" is parsed as the boolean false; quote it to keep it a string`,
+			},
+		},
+		{
+			name: "keys are not values",
+			yaml: "1.10: x\n",
+		},
+		{
+			name: "multiple documents",
+			yaml: "a: 1.0\n---\nb: 2.0\n",
+			want: []string{
+				`1: value 1.0 of "a" is parsed as the number 1; quote it to keep it a string`,
+				`3: value 2.0 of "b" is parsed as the number 2; quote it to keep it a string`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, ambiguousScalars([]byte(tt.yaml))); diff != "" {
+				t.Errorf("ambiguousScalars() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestLoadTestSuite_PortTypes pins down how quoted and unquoted container
+// ports load: unquoted numbers load as JSON numbers, built-in kinds reject a
+// quoted port, and other kinds keep the YAML type, so policies comparing with
+// a number see a string.
+func TestLoadTestSuite_PortTypes(t *testing.T) {
+	t.Parallel()
+
+	policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: ports\nspec:\n  validations:\n  - expression: 'true'\n"
+
+	tests := []struct {
+		name     string
+		object   string
+		wantPort any
+		wantErr  string
+	}{
+		{
+			name:     "pod with int port",
+			object:   "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n  - name: web\n    ports:\n    - containerPort: 80\n",
+			wantPort: float64(80),
+		},
+		{
+			name:    "pod with quoted port",
+			object:  "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n  - name: web\n    ports:\n    - containerPort: \"80\"\n",
+			wantErr: "invalid kubernetes object",
+		},
+		{
+			name:     "custom resource with quoted port",
+			object:   "apiVersion: example.com/v1\nkind: App\nmetadata:\n  name: web\nspec:\n  containers:\n  - name: web\n    ports:\n    - containerPort: \"80\"\n",
+			wantPort: "80",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			mustMkdir(t, filepath.Join(dir, "tests"))
+
+			files := map[string]string{
+				filepath.Join(dir, "policy.yaml"):                          policy,
+				filepath.Join(dir, "tests", "ports.web.allow.object.yaml"): tt.object,
+			}
+			for path, content := range files {
+				if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+					t.Fatalf("WriteFile failed: %v", err)
+				}
+			}
+
+			suite, err := LoadTestSuite(dir, "ports")
+			if err != nil {
+				t.Fatalf("LoadTestSuite() error = %v", err)
+			}
+
+			test := suite.Tests[0]
+			if tt.wantErr != "" {
+				if test.Error == nil || !strings.Contains(test.Error.Error(), tt.wantErr) {
+					t.Fatalf("test error = %v, want it to contain %q", test.Error, tt.wantErr)
+				}
+
+				return
+			}
+
+			containers, _, _ := unstructured.NestedSlice(test.Object.Object, "spec", "containers")
+			ports, _ := containers[0].(map[string]any)["ports"].([]any)
+			got := ports[0].(map[string]any)["containerPort"]
+
+			if diff := cmp.Diff(tt.wantPort, got); diff != "" {
+				t.Errorf("containerPort mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	errUnknownField     = errors.New("unknown field")
+	errInvalidType      = errors.New("invalid type")
+	errMissingRequired  = errors.New("required field missing")
+	errUnsupportedValue = errors.New("unsupported value")
+)
+
+// validateSuiteParams strictly validates each test's params against its policy's paramKind.
+// Params of built-in kinds are checked against the Kubernetes scheme, params of custom
+// kinds against the schema of a matching CRD in the suite. Unknown paramKinds are not checked.
+func validateSuiteParams(suite *TestSuite, crds []*unstructured.Unstructured) {
+	for _, test := range suite.Tests {
+		if test.Error != nil || test.Params == nil {
+			continue
+		}
+
+		paramKind := suiteParamKind(suite, test.PolicyName)
+		if paramKind == nil {
+			continue
+		}
+
+		if err := validateParams(test.Params.Object, paramKind, crds); err != nil {
+			test.Error = fmt.Errorf("params: %w", err)
+		}
+	}
+}
+
+// suiteParamKind returns the paramKind of the named policy, or nil.
+func suiteParamKind(suite *TestSuite, policyName string) *admissionregv1.ParamKind {
+	for _, p := range suite.ValidatingPolicies {
+		if p.Name == policyName {
+			return p.Spec.ParamKind
+		}
+	}
+
+	for _, p := range suite.MutatingPolicies {
+		if p.Name == policyName && p.Spec.ParamKind != nil {
+			return &admissionregv1.ParamKind{APIVersion: p.Spec.ParamKind.APIVersion, Kind: p.Spec.ParamKind.Kind}
+		}
+	}
+
+	return nil
+}
+
+func validateParams(params map[string]any, paramKind *admissionregv1.ParamKind, crds []*unstructured.Unstructured) error {
+	gv, err := schema.ParseGroupVersion(paramKind.APIVersion)
+	if err != nil {
+		// An invalid paramKind is reported by the API server, not here.
+		return nil //nolint:nilerr // Lenient for unparsable paramKinds
+	}
+
+	if openAPISchema := findCRDSchema(crds, gv.WithKind(paramKind.Kind)); openAPISchema != nil {
+		return validateSchema(params, openAPISchema, "", true)
+	}
+
+	// Strict for registered kinds (e.g. ConfigMap), lenient for unknown ones.
+	return validateStructureStrict(params, "params", nil, paramKind.Kind)
+}
+
+// findCRDSchema returns the openAPIV3Schema of the CRD version serving gvk, or nil.
+func findCRDSchema(crds []*unstructured.Unstructured, gvk schema.GroupVersionKind) map[string]any {
+	for _, crd := range crds {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+
+		if group != gvk.Group || kind != gvk.Kind {
+			continue
+		}
+
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, v := range versions {
+			version, ok := v.(map[string]any)
+			if !ok || version["name"] != gvk.Version {
+				continue
+			}
+
+			openAPISchema, _, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+
+			return openAPISchema
+		}
+	}
+
+	return nil
+}
+
+// validateSchema checks value against a structural OpenAPI v3 schema: types, unknown
+// fields, required fields, and enums. All violations are returned joined.
+func validateSchema(value any, openAPISchema map[string]any, path string, root bool) error {
+	if value == nil {
+		return nil
+	}
+
+	if err := checkSchemaType(value, openAPISchema, path); err != nil {
+		return err
+	}
+
+	if enum, ok := openAPISchema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return fmt.Errorf("%s: %w: %v", fieldPath(path), errUnsupportedValue, value)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateSchemaObject(v, openAPISchema, path, root)
+	case []any:
+		items, ok := openAPISchema["items"].(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		var errs []error
+		for i, item := range v {
+			errs = append(errs, validateSchema(item, items, fmt.Sprintf("%s[%d]", path, i), false))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+func validateSchemaObject(obj map[string]any, openAPISchema map[string]any, path string, root bool) error {
+	properties, _ := openAPISchema["properties"].(map[string]any)
+	preserveUnknown, _ := openAPISchema["x-kubernetes-preserve-unknown-fields"].(bool)
+
+	var errs []error
+
+	required, _ := openAPISchema["required"].([]any)
+	for _, r := range required {
+		name, ok := r.(string)
+		if ok {
+			if _, found := obj[name]; !found {
+				errs = append(errs, fmt.Errorf("%s: %w", fieldPath(join(path, name)), errMissingRequired))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if propSchema, ok := properties[key].(map[string]any); ok {
+			errs = append(errs, validateSchema(obj[key], propSchema, join(path, key), false))
+
+			continue
+		}
+
+		switch additional := openAPISchema["additionalProperties"].(type) {
+		case map[string]any:
+			errs = append(errs, validateSchema(obj[key], additional, join(path, key), false))
+
+			continue
+		case bool:
+			if additional {
+				continue
+			}
+		}
+
+		// Type metadata is accepted on every resource even if the schema omits it.
+		if preserveUnknown || (properties == nil && openAPISchema["additionalProperties"] == nil && !root) ||
+			(root && (key == "apiVersion" || key == "kind" || key == "metadata")) {
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", fieldPath(join(path, key)), errUnknownField))
+	}
+
+	return errors.Join(errs...)
+}
+
+func checkSchemaType(value any, openAPISchema map[string]any, path string) error {
+	if intOrString, _ := openAPISchema["x-kubernetes-int-or-string"].(bool); intOrString {
+		if _, ok := value.(string); ok || isInteger(value) {
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w: expected integer or string, got %T", fieldPath(path), errInvalidType, value)
+	}
+
+	typ, _ := openAPISchema["type"].(string)
+
+	var ok bool
+
+	switch typ {
+	case "object":
+		_, ok = value.(map[string]any)
+	case "array":
+		_, ok = value.([]any)
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "integer":
+		ok = isInteger(value)
+	case "number":
+		switch value.(type) {
+		case int, int64, float64:
+			ok = true
+		}
+	default:
+		ok = true
+	}
+
+	if !ok {
+		return fmt.Errorf("%s: %w: expected %s, got %T", fieldPath(path), errInvalidType, typ, value)
+	}
+
+	return nil
+}
+
+func isInteger(value any) bool {
+	switch v := value.(type) {
+	case int, int64:
+		return true
+	case float64:
+		return v == math.Trunc(v)
+	}
+
+	return false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+
+	return path
+}
@@ -0,0 +1,179 @@
+package loader
+
+import (
+	"errors"
+	"testing"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidateSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type":     "object",
+		"required": []any{"spec"},
+		"properties": map[string]any{
+			"spec": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"maxReplicas": map[string]any{"type": "integer"},
+					"mode":        map[string]any{"type": "string", "enum": []any{"audit", "enforce"}},
+					"port":        map[string]any{"x-kubernetes-int-or-string": true},
+					"labels": map[string]any{
+						"type":                 "object",
+						"additionalProperties": map[string]any{"type": "string"},
+					},
+					"teams": map[string]any{
+						"type":  "array",
+						"items": map[string]any{"type": "string"},
+					},
+					"extra": map[string]any{
+						"type":                                 "object",
+						"x-kubernetes-preserve-unknown-fields": true,
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		value   map[string]any
+		wantErr error
+	}{
+		{
+			name: "valid",
+			value: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "ReplicaLimit",
+				"metadata":   map[string]any{"name": "limit"},
+				"spec": map[string]any{
+					"maxReplicas": float64(3),
+					"mode":        "enforce",
+					"port":        "http",
+					"labels":      map[string]any{"team": "platform"},
+					"teams":       []any{"a", "b"},
+					"extra":       map[string]any{"anything": true},
+				},
+			},
+		},
+		{
+			name:    "unknown field",
+			value:   map[string]any{"spec": map[string]any{"maxReplica": float64(3)}},
+			wantErr: errUnknownField,
+		},
+		{
+			name:    "wrong type",
+			value:   map[string]any{"spec": map[string]any{"maxReplicas": "3"}},
+			wantErr: errInvalidType,
+		},
+		{
+			name:    "fractional integer",
+			value:   map[string]any{"spec": map[string]any{"maxReplicas": 2.5}},
+			wantErr: errInvalidType,
+		},
+		{
+			name:    "missing required",
+			value:   map[string]any{},
+			wantErr: errMissingRequired,
+		},
+		{
+			name:    "unsupported enum value",
+			value:   map[string]any{"spec": map[string]any{"mode": "dryrun"}},
+			wantErr: errUnsupportedValue,
+		},
+		{
+			name:    "wrong array item type",
+			value:   map[string]any{"spec": map[string]any{"teams": []any{"a", true}}},
+			wantErr: errInvalidType,
+		},
+		{
+			name:    "wrong additional property type",
+			value:   map[string]any{"spec": map[string]any{"labels": map[string]any{"replicas": float64(1)}}},
+			wantErr: errInvalidType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateSchema(tt.value, schema, "", true)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateSchema() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	t.Parallel()
+
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]any{
+			"group": "example.com",
+			"names": map[string]any{"kind": "ReplicaLimit"},
+			"versions": []any{
+				map[string]any{
+					"name": "v1",
+					"schema": map[string]any{"openAPIV3Schema": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"maxReplicas": map[string]any{"type": "integer"},
+						},
+					}},
+				},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		paramKind *admissionregv1.ParamKind
+		params    map[string]any
+		wantErr   bool
+	}{
+		{
+			name:      "CRD schema accepts known fields",
+			paramKind: &admissionregv1.ParamKind{APIVersion: "example.com/v1", Kind: "ReplicaLimit"},
+			params:    map[string]any{"apiVersion": "example.com/v1", "kind": "ReplicaLimit", "maxReplicas": float64(3)},
+		},
+		{
+			name:      "CRD schema rejects typo",
+			paramKind: &admissionregv1.ParamKind{APIVersion: "example.com/v1", Kind: "ReplicaLimit"},
+			params:    map[string]any{"apiVersion": "example.com/v1", "kind": "ReplicaLimit", "maxReplica": float64(3)},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown CRD version is lenient",
+			paramKind: &admissionregv1.ParamKind{APIVersion: "example.com/v2", Kind: "ReplicaLimit"},
+			params:    map[string]any{"apiVersion": "example.com/v2", "kind": "ReplicaLimit", "maxReplica": float64(3)},
+		},
+		{
+			name:      "unknown kind is lenient",
+			paramKind: &admissionregv1.ParamKind{APIVersion: "other.io/v1", Kind: "Limits"},
+			params:    map[string]any{"apiVersion": "other.io/v1", "kind": "Limits", "anything": true},
+		},
+		{
+			name:      "built-in kind rejects typo",
+			paramKind: &admissionregv1.ParamKind{APIVersion: "v1", Kind: "ConfigMap"},
+			params:    map[string]any{"apiVersion": "v1", "kind": "ConfigMap", "dat": map[string]any{"a": "b"}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateParams(tt.params, tt.paramKind, []*unstructured.Unstructured{crd})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
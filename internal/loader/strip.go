@@ -0,0 +1,103 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ServerFields are the fields the API server populates on every object. They
+// make objects exported with "kubectl get -o yaml" differ from hand-written
+// fixtures while policies rarely read them.
+var ServerFields = []string{
+	"metadata.managedFields",
+	"metadata.resourceVersion",
+	"metadata.uid",
+	"metadata.creationTimestamp",
+	"metadata.generation",
+	"metadata.selfLink",
+	"metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]",
+}
+
+// ParseFieldPath splits a field path such as "metadata.managedFields" into its
+// keys. A key containing dots is written in brackets, e.g.
+// "metadata.annotations[example.com/owner]".
+func ParseFieldPath(path string) ([]string, error) {
+	var keys []string
+
+	rest := path
+	for rest != "" {
+		var key string
+
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("%w %q: unterminated [", ErrInvalidFieldPath, path)
+			}
+
+			key, rest = rest[1:end], rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			key, rest = rest[:end], rest[end:]
+		}
+
+		if key == "" {
+			return nil, fmt.Errorf("%w %q: empty key", ErrInvalidFieldPath, path)
+		}
+
+		keys = append(keys, key)
+
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, fmt.Errorf("%w %q: empty key", ErrInvalidFieldPath, path)
+			}
+		} else if rest != "" && !strings.HasPrefix(rest, "[") {
+			return nil, fmt.Errorf("%w %q: expected . or [ after ]", ErrInvalidFieldPath, path)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w %q: empty path", ErrInvalidFieldPath, path)
+	}
+
+	return keys, nil
+}
+
+// stripFields removes fields from the objects, old objects, namespaces and
+// expected objects of the tests of suites, so that gold files compare equal
+// to objects exported from a cluster.
+func stripFields(suites []*TestSuite, fields [][]string) {
+	for _, suite := range suites {
+		for _, test := range suite.Tests {
+			for _, obj := range []*unstructured.Unstructured{test.Object, test.OldObject, test.NamespaceObj, test.ExpectedObject} {
+				if obj != nil {
+					stripObjectFields(obj.Object, fields)
+				}
+			}
+		}
+	}
+}
+
+// stripObjectFields removes fields from obj. Maps left empty by the removal,
+// such as annotations holding only the last applied configuration, are
+// removed too.
+func stripObjectFields(obj map[string]any, fields [][]string) {
+	for _, field := range fields {
+		unstructured.RemoveNestedField(obj, field...)
+
+		for parent := field[:len(field)-1]; len(parent) > 0; parent = parent[:len(parent)-1] {
+			m, found, err := unstructured.NestedMap(obj, parent...)
+			if err != nil || !found || len(m) > 0 {
+				break
+			}
+
+			unstructured.RemoveNestedField(obj, parent...)
+		}
+	}
+}
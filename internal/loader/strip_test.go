@@ -0,0 +1,127 @@
+package loader
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{path: "metadata.uid", want: []string{"metadata", "uid"}},
+		{path: "status", want: []string{"status"}},
+		{
+			path: "metadata.annotations[kubectl.kubernetes.io/last-applied-configuration]",
+			want: []string{"metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration"},
+		},
+		{path: "metadata.labels[a.b].c", want: []string{"metadata", "labels", "a.b", "c"}},
+		{path: "[a.b]", want: []string{"a.b"}},
+		{path: "", wantErr: true},
+		{path: "metadata..uid", wantErr: true},
+		{path: "metadata.", wantErr: true},
+		{path: "metadata.labels[a", wantErr: true},
+		{path: "metadata.labels[]", wantErr: true},
+		{path: "metadata.labels[a]b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseFieldPath(tt.path)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFieldPath) {
+					t.Fatalf("ParseFieldPath() error = %v, want %v", err, ErrInvalidFieldPath)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseFieldPath() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseFieldPath() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStripObjectFields(t *testing.T) {
+	t.Parallel()
+
+	fields := make([][]string, 0, len(ServerFields))
+
+	for _, field := range ServerFields {
+		keys, err := ParseFieldPath(field)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fields = append(fields, keys)
+	}
+
+	tests := []struct {
+		name string
+		obj  map[string]any
+		want map[string]any
+	}{
+		{
+			name: "server fields removed",
+			obj: map[string]any{
+				"metadata": map[string]any{
+					"name":              "web",
+					"uid":               "6f1c2b5e",
+					"resourceVersion":   "12345",
+					"creationTimestamp": "2025-01-15T10:00:00Z",
+					"managedFields":     []any{map[string]any{"manager": "kubectl"}},
+					"annotations": map[string]any{
+						"kubectl.kubernetes.io/last-applied-configuration": "{}",
+						"team": "web",
+					},
+				},
+			},
+			want: map[string]any{
+				"metadata": map[string]any{
+					"name":        "web",
+					"annotations": map[string]any{"team": "web"},
+				},
+			},
+		},
+		{
+			name: "emptied annotations removed",
+			obj: map[string]any{
+				"metadata": map[string]any{
+					"name":        "web",
+					"annotations": map[string]any{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+				},
+			},
+			want: map[string]any{"metadata": map[string]any{"name": "web"}},
+		},
+		{
+			name: "nothing to strip",
+			obj:  map[string]any{"metadata": map[string]any{"name": "web"}},
+			want: map[string]any{"metadata": map[string]any{"name": "web"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stripObjectFields(tt.obj, fields)
+
+			if diff := cmp.Diff(tt.want, tt.obj); diff != "" {
+				t.Errorf("stripObjectFields() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -5,13 +5,17 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
 	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apiserver/pkg/authentication/user"
 
@@ -27,6 +31,31 @@ type TestSuite struct {
 	ValidatingPolicies []*admissionregv1.ValidatingAdmissionPolicy
 	ValidatingBindings []*admissionregv1.ValidatingAdmissionPolicyBinding
 	Tests              []*TestCase
+	// TestsDirFound reports whether the suite's tests directory exists. A
+	// suite without tests either has no tests directory or one holding no
+	// test files.
+	TestsDirFound bool
+
+	// Warnings describe problems found while loading that do not stop the
+	// suite from running, such as test files that match no policy.
+	Warnings []string
+	// PolicyProblems describe policies and bindings the API server would
+	// reject, such as policies with more than 64 matchConditions.
+	PolicyProblems []error
+	// TypeCheckWarnings are the issues the API server's type checking finds
+	// in the expressions of validating policies matching kinds of the
+	// suite's CRDs, which it records in the policy's status but does not
+	// reject.
+	TypeCheckWarnings []string
+	// MaxTestDuration is the maxTestDuration of the suite's kat.yaml; zero
+	// when unset.
+	MaxTestDuration time.Duration
+	// Operations are the operations of the suite's kat.yaml, run by every
+	// test with an object but no explicit request.
+	Operations []string
+	// UntestedPolicies are the policies no test of the suite evaluates,
+	// determined before tests are filtered.
+	UntestedPolicies []UntestedPolicy
 }
 
 // TestCase represents a single test case with all inputs and expected outcomes.
@@ -35,6 +64,10 @@ type TestCase struct {
 	FilePath   string
 	PolicyName string
 
+	// ChainPolicyName names the validating policy that must accept the object
+	// produced by the mutating policy PolicyName (file prefix "mutating+validating.").
+	ChainPolicyName string
+
 	// Inputs for evaluation
 	Request      *admissionv1.AdmissionRequest
 	Object       *unstructured.Unstructured
@@ -46,14 +79,23 @@ type TestCase struct {
 
 	// Expected outcomes
 	ExpectAllowed          bool
+	ExpectAnyAllowed       bool
 	ExpectMessage          string
 	ExpectWarnings         []string
 	ExpectAuditAnnotations map[string]string
 	ExpectMutated          bool
 	ExpectedObject         *unstructured.Unstructured
+	RecordedResponse       *evaluator.RecordedResponse
+	ExpectedPatch          []evaluator.PatchOperation
+	ExpectStatus           *metav1.Status
 	Error                  error
 }
 
+// ResponseFilePath returns the path of the test's recorded cluster response.
+func (tc *TestCase) ResponseFilePath() string {
+	return testResponseFilePath(tc.FilePath, tc.Name)
+}
+
 // Getter methods for TestCase to satisfy evaluator.TestCase interface.
 func (tc *TestCase) GetRequest() *admissionv1.AdmissionRequest          { return tc.Request }
 func (tc *TestCase) GetObject() *unstructured.Unstructured              { return tc.Object }
@@ -63,17 +105,22 @@ func (tc *TestCase) GetNamespaceObj() *unstructured.Unstructured        { return
 func (tc *TestCase) GetUserInfo() user.Info                             { return tc.UserInfo }
 func (tc *TestCase) GetAuthorizer() []evaluator.AuthorizationMockConfig { return tc.Authorizer }
 func (tc *TestCase) GetExpectAllowed() bool                             { return tc.ExpectAllowed }
+func (tc *TestCase) GetExpectAnyAllowed() bool                          { return tc.ExpectAnyAllowed }
 func (tc *TestCase) GetExpectMessage() string                           { return tc.ExpectMessage }
 func (tc *TestCase) GetExpectWarnings() []string                        { return tc.ExpectWarnings }
 func (tc *TestCase) GetExpectAuditAnnotations() map[string]string       { return tc.ExpectAuditAnnotations }
 func (tc *TestCase) GetExpectedObject() *unstructured.Unstructured      { return tc.ExpectedObject }
 func (tc *TestCase) GetError() error                                    { return tc.Error }
+func (tc *TestCase) GetRecordedResponse() *evaluator.RecordedResponse   { return tc.RecordedResponse }
+func (tc *TestCase) GetExpectedPatch() []evaluator.PatchOperation       { return tc.ExpectedPatch }
+func (tc *TestCase) GetExpectStatus() *metav1.Status                    { return tc.ExpectStatus }
 
 // testRequest represents a test admission request with expected outcome (internal use only).
 type testRequest struct {
-	Name       string
-	FilePath   string
-	PolicyName string
+	Name            string
+	FilePath        string
+	PolicyName      string
+	ChainPolicyName string
 
 	// Input
 	Request       *admissionv1.AdmissionRequest
@@ -86,18 +133,74 @@ type testRequest struct {
 
 	// Expected outcomes
 	ExpectAllowed          bool
+	ExpectAnyAllowed       bool
 	ExpectMessage          string
 	ExpectWarnings         []string
 	ExpectAuditAnnotations map[string]string
 	ExpectMutated          bool
 	ExpectedObject         *unstructured.Unstructured
+	RecordedResponse       *evaluator.RecordedResponse
+	ExpectedPatch          []evaluator.PatchOperation
+	ExpectStatus           *metav1.Status
 	Error                  error
 	Authorizer             []evaluator.AuthorizationMockConfig
+
+	// Matrix holds param sets that expand this request into sub-tests.
+	Matrix []matrixParamSet
+
+	// Operations expand this request into one sub-test per operation, with
+	// the expectations of OperationOverrides replacing the derived ones.
+	Operations         []string
+	OperationOverrides map[string]operationExpectation
+
+	// InlineExpect is the expect block of the test's request file, applied
+	// after the test's other files are merged.
+	InlineExpect *inlineExpectation
 }
 
-// Load discovers and loads all test suites from the given path.
-// Pattern is optional and filters tests by name (like -run flag in go test).
-func Load(path string, pattern string) ([]*TestSuite, error) {
+// Options controls which tests Load returns.
+type Options struct {
+	// Pattern filters tests like the -run flag of go test. A pattern without "/"
+	// is matched against test names; "suite/test" matches the suite name and the
+	// test name separately. Each part is an unanchored regular expression and an
+	// empty part matches everything.
+	Pattern string
+	// ExactMatch treats each pattern part as a literal name that must match in full.
+	// Test names match with or without their ".yaml" suffix.
+	ExactMatch bool
+	// TestsDir, when set, is a tree of tests kept apart from the policies: the
+	// tests of the suite in <path>/<dir> are loaded from <TestsDir>/<dir>
+	// instead of <path>/<dir>/tests, unless only the latter exists. When path
+	// is a suite itself, its tests are loaded from TestsDir.
+	TestsDir string
+	// Dirs limits the suites to those whose directory, relative to path, is
+	// one of these directories or below one of them. Empty keeps all suites.
+	Dirs []string
+	// SuitePerPolicy splits each suite into one suite per policy, named
+	// "<suite>/<policy>", so that results and -run patterns operate per policy.
+	SuitePerPolicy bool
+	// DefaultNamespace is the namespace of test objects of namespaced
+	// built-in kinds without one, and of their requests; empty keeps them
+	// without a namespace.
+	DefaultNamespace string
+	// StripFields are field paths, as returned by [ParseFieldPath], removed
+	// from the objects and expected objects of every test before evaluation.
+	StripFields [][]string
+}
+
+// Load discovers and loads all test suites from the given path, filtered by opts.
+// A path naming a test file, e.g. "suite/tests/policy.deny.object.yaml", loads
+// the enclosing suite with only that test; opts.TestsDir does not apply to it.
+func Load(path string, opts Options) ([]*TestSuite, error) {
+	if isRegularFile(path) {
+		suite, err := loadTestFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return filterAndStrip([]*TestSuite{suite}, opts)
+	}
+
 	// Check if path is a single test suite (has policy files directly)
 	hasPolicies, err := hasPolicyFiles(path)
 	if err != nil {
@@ -110,7 +213,7 @@ func Load(path string, pattern string) ([]*TestSuite, error) {
 		// Load single test suite
 		suiteName := filepath.Base(path)
 
-		suite, err := LoadTestSuite(path, suiteName)
+		suite, err := LoadTestSuiteWithTests(path, suiteTestsDir(path, opts.TestsDir), suiteName)
 		if err != nil {
 			return nil, fmt.Errorf("load test suite: %w", err)
 		}
@@ -120,17 +223,63 @@ func Load(path string, pattern string) ([]*TestSuite, error) {
 		}
 	} else {
 		// Discover multiple test suites
-		suites, err = DiscoverTestSuites(path)
+		suites, err = discoverTestSuites(path, opts.TestsDir, nil)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if len(opts.Dirs) > 0 {
+		suites = filterSuitesByDir(path, suites, opts.Dirs)
+	}
+
+	if opts.SuitePerPolicy {
+		suites = splitSuitesByPolicy(suites)
+	}
+
+	return filterAndStrip(suites, opts)
+}
+
+// filterSuitesByDir keeps the suites whose directory relative to root is one
+// of dirs or below one of them.
+func filterSuitesByDir(root string, suites []*TestSuite, dirs []string) []*TestSuite {
+	filtered := make([]*TestSuite, 0, len(suites))
+
+	for _, suite := range suites {
+		rel, err := filepath.Rel(root, suite.Path)
+		if err != nil {
+			continue
+		}
+
+		for _, dir := range dirs {
+			dir = filepath.Clean(dir)
+			if dir == "." || rel == dir || strings.HasPrefix(rel, dir+string(filepath.Separator)) {
+				filtered = append(filtered, suite)
+
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// filterAndStrip filters the tests of suites by opts.Pattern and strips
+// opts.StripFields from them.
+func filterAndStrip(suites []*TestSuite, opts Options) ([]*TestSuite, error) {
 	// Filter by pattern if provided
-	if pattern != "" {
-		suites = filterTestsByPattern(suites, pattern)
+	if opts.Pattern != "" {
+		var err error
+
+		suites, err = filterTestsByPattern(suites, opts.Pattern, opts.ExactMatch)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	stripFields(suites, opts.StripFields)
+	defaultNamespaces(suites, opts.DefaultNamespace)
+
 	return suites, nil
 }
 
@@ -142,6 +291,7 @@ func convertToTestCases(requests []*testRequest) []*TestCase {
 			Name:                   req.Name,
 			FilePath:               req.FilePath,
 			PolicyName:             req.PolicyName,
+			ChainPolicyName:        req.ChainPolicyName,
 			Request:                req.Request,
 			Object:                 req.Object,
 			OldObject:              req.OldObject,
@@ -149,11 +299,15 @@ func convertToTestCases(requests []*testRequest) []*TestCase {
 			NamespaceObj:           req.NamespaceObj,
 			UserInfo:               convertUserInfo(req.UserInfo),
 			ExpectAllowed:          req.ExpectAllowed,
+			ExpectAnyAllowed:       req.ExpectAnyAllowed,
 			ExpectMessage:          req.ExpectMessage,
 			ExpectWarnings:         req.ExpectWarnings,
 			ExpectAuditAnnotations: req.ExpectAuditAnnotations,
 			ExpectMutated:          req.ExpectMutated,
 			ExpectedObject:         req.ExpectedObject,
+			RecordedResponse:       req.RecordedResponse,
+			ExpectedPatch:          req.ExpectedPatch,
+			ExpectStatus:           req.ExpectStatus,
 			Error:                  req.Error,
 			Authorizer:             req.Authorizer,
 		}
@@ -162,16 +316,31 @@ func convertToTestCases(requests []*testRequest) []*TestCase {
 	return tests
 }
 
-// filterTestsByPattern filters test suites and their tests by a glob pattern.
-func filterTestsByPattern(suites []*TestSuite, pattern string) []*TestSuite {
+// filterTestsByPattern filters test suites and their tests by a -run style pattern.
+func filterTestsByPattern(suites []*TestSuite, pattern string, exact bool) ([]*TestSuite, error) {
 	filtered := make([]*TestSuite, 0, len(suites))
 
 	for _, suite := range suites {
+		suitePattern, testPattern := splitRunPattern(pattern, suite.Name)
+
+		matchSuite, err := newNameMatcher(suitePattern, exact)
+		if err != nil {
+			return nil, err
+		}
+
+		matchTest, err := newNameMatcher(testPattern, exact)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matchSuite(suite.Name) {
+			continue
+		}
+
 		filteredTests := make([]*TestCase, 0, len(suite.Tests))
 
 		for _, test := range suite.Tests {
-			matched, _ := regexp.MatchString(pattern, test.Name)
-			if matched {
+			if matchTest(test.Name) || matchTest(strings.TrimSuffix(test.Name, ".yaml")) {
 				filteredTests = append(filteredTests, test)
 			}
 		}
@@ -182,7 +351,50 @@ func filterTestsByPattern(suites []*TestSuite, pattern string) []*TestSuite {
 		}
 	}
 
-	return filtered
+	return filtered, nil
+}
+
+// splitRunPattern splits a -run pattern into its suite and test parts for the
+// suite named suiteName. The suite part has as many "/"-separated parts as the
+// suite name, e.g. "dir/policy" of -suite-per-policy: a pattern with more
+// parts ends with the test part, and a pattern with exactly as many parts
+// selects whole suites. Otherwise the suite part ends at the first "/". A
+// pattern without "/" is a test pattern.
+func splitRunPattern(pattern, suiteName string) (string, string) {
+	suiteParts := strings.Count(suiteName, "/") + 1
+
+	parts := strings.SplitN(pattern, "/", suiteParts+1)
+	if len(parts) == suiteParts+1 {
+		return strings.Join(parts[:suiteParts], "/"), parts[suiteParts]
+	}
+
+	if suiteParts > 1 && len(parts) == suiteParts {
+		return pattern, ""
+	}
+
+	if before, after, ok := strings.Cut(pattern, "/"); ok {
+		return before, after
+	}
+
+	return "", pattern
+}
+
+// newNameMatcher returns a function matching names against one pattern part.
+// An empty part matches every name.
+func newNameMatcher(pattern string, exact bool) (func(string) bool, error) {
+	switch {
+	case pattern == "":
+		return func(string) bool { return true }, nil
+	case exact:
+		return func(name string) bool { return name == pattern }, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run pattern %q: %w", pattern, err)
+	}
+
+	return re.MatchString, nil
 }
 
 // convertUserInfo converts authenticationv1.UserInfo to user.Info interface.
@@ -217,75 +429,181 @@ func convertExtra(extra map[string]authenticationv1.ExtraValue) map[string][]str
 // Each subdirectory with policy files is considered a test suite.
 // Test requests are loaded from the tests/ subdirectory if present.
 func DiscoverTestSuites(rootDir string) ([]*TestSuite, error) {
+	return discoverTestSuites(rootDir, "", nil)
+}
+
+// discoverTestSuites implements DiscoverTestSuites. testsRoot is the directory
+// of rootDir in a separate tests tree, or empty when tests are colocated. When
+// problems is non-nil, suites that fail to load are recorded there and
+// discovery continues.
+func discoverTestSuites(rootDir, testsRoot string, problems *[]error) ([]*TestSuite, error) {
+	dirs, err := findSuiteDirs(rootDir, testsRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadSuiteDirs(dirs, problems)
+}
+
+// suiteDir is a directory holding policy files, found by findSuiteDirs.
+type suiteDir struct {
+	dir      string
+	testsDir string
+	name     string
+}
+
+// findSuiteDirs returns the suite directories below rootDir, depth first in
+// directory order. A directory with policy files is a suite and is not
+// searched further.
+func findSuiteDirs(rootDir, testsRoot string) ([]suiteDir, error) {
 	entries, err := os.ReadDir(rootDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory %s: %w", rootDir, err)
 	}
 
-	suites := make([]*TestSuite, 0, len(entries))
+	var dirs []suiteDir
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() || shouldSkipDir(entry.Name()) {
 			continue
 		}
 
-		if err := collectSuitesFromEntry(&suites, rootDir, entry); err != nil {
+		dirName := entry.Name()
+		dir := filepath.Join(rootDir, dirName)
+
+		var entryTestsRoot string
+		if testsRoot != "" {
+			entryTestsRoot = filepath.Join(testsRoot, dirName)
+		}
+
+		hasPolicies, err := hasPolicyFiles(dir)
+		if err != nil {
 			return nil, err
 		}
+
+		if !hasPolicies {
+			subDirs, err := findSuiteDirs(dir, entryTestsRoot)
+			if err != nil {
+				return nil, err
+			}
+
+			dirs = append(dirs, subDirs...)
+
+			continue
+		}
+
+		dirs = append(dirs, suiteDir{dir: dir, testsDir: suiteTestsDir(dir, entryTestsRoot), name: dirName})
 	}
 
-	return suites, nil
+	return dirs, nil
 }
 
-func collectSuitesFromEntry(suites *[]*TestSuite, rootDir string, entry os.DirEntry) error {
-	dirName := entry.Name()
-	if shouldSkipDir(dirName) {
-		return nil
-	}
+// loadedSuite is the outcome of loading a suite directory.
+type loadedSuite struct {
+	suite    *TestSuite
+	problems []error
+	err      error
+}
 
-	suiteDir := filepath.Join(rootDir, dirName)
+// loadSuiteDirs loads the suites of dirs concurrently, at most GOMAXPROCS at
+// a time, as suites are independent of each other. Suites, problems and the
+// first error are reported in the order of dirs, so the result does not
+// depend on scheduling.
+func loadSuiteDirs(dirs []suiteDir, problems *[]error) ([]*TestSuite, error) {
+	loaded := make([]loadedSuite, len(dirs))
+	slots := make(chan struct{}, runtime.GOMAXPROCS(0))
 
-	hasPolicies, err := hasPolicyFiles(suiteDir)
-	if err != nil {
-		return err
+	var wg sync.WaitGroup
+
+	for i, dir := range dirs {
+		wg.Go(func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			var dirProblems *[]error
+			if problems != nil {
+				dirProblems = &loaded[i].problems
+			}
+
+			loaded[i].suite, loaded[i].err = loadTestSuite(dir.dir, dir.testsDir, dir.name, dirProblems)
+		})
 	}
 
-	if !hasPolicies {
-		subSuites, err := DiscoverTestSuites(suiteDir)
-		if err != nil {
-			return err
+	wg.Wait()
+
+	suites := make([]*TestSuite, 0, len(dirs))
+
+	for i, result := range loaded {
+		if problems != nil {
+			*problems = append(*problems, result.problems...)
 		}
 
-		*suites = append(*suites, subSuites...)
+		if result.err != nil {
+			err := fmt.Errorf("failed to load test suite %s: %w", dirs[i].name, result.err)
+			if problems == nil {
+				return nil, err
+			}
 
-		return nil
-	}
+			*problems = append(*problems, err)
 
-	suite, err := LoadTestSuite(suiteDir, dirName)
-	if err != nil {
-		return fmt.Errorf("failed to load test suite %s: %w", dirName, err)
-	}
+			continue
+		}
 
-	if suite != nil {
-		*suites = append(*suites, suite)
+		if result.suite != nil {
+			suites = append(suites, result.suite)
+		}
 	}
 
-	return nil
+	return suites, nil
 }
 
 func shouldSkipDir(dirName string) bool {
 	return strings.HasPrefix(dirName, ".") || dirName == "tests" || dirName == "testdata"
 }
 
+// suiteTestsDir returns the directory holding the tests of the suite in dir:
+// testsDir when tests are kept in a separate tree, else dir/tests. Suites
+// without a directory in the tests tree keep using their own tests
+// directory, so both layouts can coexist.
+func suiteTestsDir(dir, testsDir string) string {
+	colocated := filepath.Join(dir, "tests")
+	if testsDir == "" || (!isDir(testsDir) && isDir(colocated)) {
+		return colocated
+	}
+
+	return testsDir
+}
+
+// isDir reports whether path is a directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
 // LoadTestSuite loads policies, bindings, and test requests from a directory.
 func LoadTestSuite(dir string, name string) (*TestSuite, error) {
+	return LoadTestSuiteWithTests(dir, filepath.Join(dir, "tests"), name)
+}
+
+// LoadTestSuiteWithTests loads policies and bindings from dir and test requests
+// from testsDir. Test files are matched to policies by name, as in LoadTestSuite.
+// A missing testsDir leaves the suite without tests.
+func LoadTestSuiteWithTests(dir, testsDir, name string) (*TestSuite, error) {
+	return loadTestSuite(dir, testsDir, name, nil)
+}
+
+// loadTestSuite implements LoadTestSuiteWithTests. When problems is non-nil,
+// policy, binding and suite config files that fail to load are recorded there
+// and the suite is loaded from the remaining files.
+func loadTestSuite(dir, testsDir, name string, problems *[]error) (*TestSuite, error) {
 	suite := &TestSuite{
 		Name: name,
 		Path: dir,
 	}
 
 	// Load policies and bindings from the directory
-	policySet, err := LoadPolicySet(dir)
+	policySet, err := loadPolicySet(dir, problems)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load policies: %w", err)
 	}
@@ -294,10 +612,20 @@ func LoadTestSuite(dir string, name string) (*TestSuite, error) {
 	suite.MutatingBindings = policySet.MutatingBindings
 	suite.ValidatingPolicies = policySet.ValidatingPolicies
 	suite.ValidatingBindings = policySet.ValidatingBindings
+	suite.PolicyProblems = validatePolicySet(policySet)
+
+	if err := loadSuiteConfig(suite, dir); err != nil {
+		if problems == nil {
+			return nil, err
+		}
+
+		*problems = append(*problems, err)
+	}
 
-	// Check if there's a tests/ subdirectory
-	testsDir := filepath.Join(dir, "tests")
+	// Check if there's a tests directory
 	if info, err := os.Stat(testsDir); err == nil && info.IsDir() {
+		suite.TestsDirFound = true
+
 		// Collect policy names for matching test files
 		policyNames := make([]string, 0)
 		for _, p := range suite.MutatingPolicies {
@@ -308,15 +636,24 @@ func LoadTestSuite(dir string, name string) (*TestSuite, error) {
 			policyNames = append(policyNames, p.Name)
 		}
 
-		// Load test requests from tests/ directory
-		testRequests, err := loadTestRequests(testsDir, policyNames)
+		// Load test requests from the tests directory
+		testRequests, err := loadTestRequests(testsDir, policyNames, suite.Operations)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load test requests: %w", err)
 		}
 
 		suite.Tests = convertToTestCases(testRequests)
+		suite.Warnings = unmatchedTestWarnings(suite.Tests, policyNames)
+		suite.Warnings = append(suite.Warnings, ambiguousScalarWarnings(testsDir)...)
+		validateSuiteParams(suite, policySet.CRDs)
 	}
 
+	suite.Warnings = append(suite.Warnings, danglingBindingWarnings(suite.Name, policySet)...)
+	suite.Warnings = append(suite.Warnings, paramsUsageWarnings(suite.Name, policySet)...)
+	suite.Warnings = append(suite.Warnings, longWarningMessageWarnings(suite.Name, policySet)...)
+	suite.TypeCheckWarnings = typeCheckWarnings(suite.Name, policySet)
+	suite.UntestedPolicies = untestedPolicies(policySet, suite.Tests)
+
 	return suite, nil
 }
 
@@ -327,7 +664,10 @@ func LoadTestSuite(dir string, name string) (*TestSuite, error) {
 // Expected outcomes can be specified in corresponding *.gold.yaml files.
 // Test file names should be prefixed with the policy name (e.g., "policy-name.test-case.request.yaml").
 // Files with the same base name (e.g., "test.allow.object.yaml" and "test.allow.request.yaml") are merged.
-func loadTestRequests(dir string, policyNames []string) ([]*testRequest, error) {
+//
+// Tests without an explicit request run once per operation of operations, if
+// any, unless their own .expect.yaml lists operations.
+func loadTestRequests(dir string, policyNames, operations []string) ([]*testRequest, error) {
 	testFiles, err := collectTestFiles(dir)
 	if err != nil {
 		return nil, err
@@ -345,8 +685,18 @@ func loadTestRequests(dir string, policyNames []string) ([]*testRequest, error)
 
 	for _, baseName := range baseNames {
 		filePaths := testFiles[baseName]
-		req := buildTestRequest(baseName, filePaths, policyNames)
-		requests = append(requests, req)
+		req := buildTestRequest(baseName, filePaths, policyNames, operations)
+		for _, sub := range expandOperations(req) {
+			for _, variant := range expandMatrix(sub) {
+				// Variants differ in params or operation, so each has its
+				// own recorded response.
+				if variant.Error == nil {
+					variant.Error = loadResponseFile(variant, testResponseFilePath(variant.FilePath, variant.Name))
+				}
+
+				requests = append(requests, variant)
+			}
+		}
 	}
 
 	return requests, nil
@@ -366,7 +716,7 @@ func collectTestFiles(dir string) (map[string][]string, error) {
 		}
 
 		name := entry.Name()
-		if !isTestFile(name) {
+		if !isTestFile(name) || isTemplateFile(name) {
 			continue
 		}
 
@@ -377,50 +727,79 @@ func collectTestFiles(dir string) (map[string][]string, error) {
 	return testFiles, nil
 }
 
+// testFileSuffixes are the suffixes of the YAML files of a test, each
+// followed by one of yamlExtensions.
+//
+//nolint:gochecknoglobals // Static lookup table
+var testFileSuffixes = []string{".request", ".object", ".oldObject", ".params", ".annotations", ".authorizer", ".matrix"}
+
 func isTestFile(name string) bool {
-	return strings.HasSuffix(name, ".request.yaml") ||
-		strings.HasSuffix(name, ".object.yaml") ||
-		strings.HasSuffix(name, ".oldObject.yaml") ||
-		strings.HasSuffix(name, ".params.yaml") ||
-		strings.HasSuffix(name, ".annotations.yaml") ||
-		strings.HasSuffix(name, ".warnings.txt") ||
-		strings.HasSuffix(name, ".authorizer.yaml")
+	for _, suffix := range testFileSuffixes {
+		if hasYAMLSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return strings.HasSuffix(name, ".warnings.txt") || isAdmissionReviewFile(name)
+}
+
+// isTemplateFile reports whether a file is a suite-level object template (e.g. "_base.object.yaml").
+// Templates are only used through the "from" key of test objects and are never run as tests.
+// Other files starting with '_' are loaded as usual, so they are not silently ignored.
+func isTemplateFile(name string) bool {
+	return strings.HasPrefix(name, "_") && hasYAMLSuffix(name, ".object")
 }
 
 func testBaseName(name string) string {
-	baseName := strings.TrimSuffix(name, ".request.yaml")
-	baseName = strings.TrimSuffix(baseName, ".object.yaml")
-	baseName = strings.TrimSuffix(baseName, ".oldObject.yaml")
-	baseName = strings.TrimSuffix(baseName, ".params.yaml")
-	baseName = strings.TrimSuffix(baseName, ".annotations.yaml")
+	baseName := name
+	for _, suffix := range testFileSuffixes {
+		baseName = trimYAMLSuffix(baseName, suffix)
+	}
+
 	baseName = strings.TrimSuffix(baseName, ".warnings.txt")
-	baseName = strings.TrimSuffix(baseName, ".authorizer.yaml")
+
+	for _, suffix := range admissionReviewSuffixes {
+		baseName = strings.TrimSuffix(baseName, suffix)
+	}
 
 	return baseName
 }
 
-func buildTestRequest(baseName string, filePaths []string, policyNames []string) *testRequest {
+func buildTestRequest(baseName string, filePaths, policyNames, operations []string) *testRequest {
 	matchedPolicyName := matchPolicyName(baseName, policyNames)
+	chainPolicyName := ""
+
+	if first, second, ok := matchChainPolicyNames(baseName, policyNames); ok {
+		matchedPolicyName, chainPolicyName = first, second
+	}
+
 	expectAllowed := expectedAllowed(baseName)
 
 	testReq := &testRequest{
-		Name:          baseName + ".yaml",
-		FilePath:      filePaths[0],
-		PolicyName:    matchedPolicyName,
-		ExpectAllowed: expectAllowed,
+		Name:            baseName + ".yaml",
+		FilePath:        filePaths[0],
+		PolicyName:      matchedPolicyName,
+		ChainPolicyName: chainPolicyName,
+		ExpectAllowed:   expectAllowed,
+	}
+
+	if err := checkAdmissionReviewFiles(filePaths); err != nil {
+		testReq.Error = err
+
+		return testReq
 	}
 
 	var hasExplicitRequest bool
 
 	for _, filePath := range filePaths {
-		if strings.HasSuffix(filePath, ".request.yaml") {
+		if hasYAMLSuffix(filePath, ".request") || isAdmissionReviewFile(filePath) {
 			hasExplicitRequest = true
 		}
 
 		tempReq := newTempTestRequest(filePath, matchedPolicyName, expectAllowed)
 
 		if err := parseTestRequestFile(tempReq); err != nil {
-			testReq.Error = fmt.Errorf("failed to parse test file %s: %w", filePath, err)
+			testReq.Error = fmt.Errorf("failed to parse test file %s: %w", testFileLocation(filePath, err), err)
 
 			return testReq
 		}
@@ -428,11 +807,39 @@ func buildTestRequest(baseName string, filePaths []string, policyNames []string)
 		mergeTestRequests(testReq, tempReq)
 	}
 
+	if err := applyInlineExpectation(testReq, baseName); err != nil {
+		testReq.Error = err
+
+		return testReq
+	}
+
+	if err := loadPatchFile(testReq, yamlFilePath(filepath.Join(filepath.Dir(testReq.FilePath), baseName), ".patch")); err != nil {
+		testReq.Error = err
+
+		return testReq
+	}
+
+	if err := loadStatusFile(testReq, yamlFilePath(filepath.Join(filepath.Dir(testReq.FilePath), baseName), ".status")); err != nil {
+		testReq.Error = err
+
+		return testReq
+	}
+
+	if err := loadExpectFile(testReq, baseName, yamlFilePath(filepath.Join(filepath.Dir(testReq.FilePath), baseName), expectFileSuffix)); err != nil {
+		testReq.Error = err
+
+		return testReq
+	}
+
 	if !hasExplicitRequest && testReq.Request != nil {
 		op, err := InferOperation(testReq.Object != nil, testReq.OldObject != nil, "")
 		if err == nil && op != "" {
 			testReq.Request.Operation = admissionv1.Operation(op)
 		}
+
+		if testReq.Operations == nil && testReq.Object != nil {
+			testReq.Operations = operations
+		}
 	}
 
 	return testReq
@@ -452,6 +859,50 @@ func matchPolicyName(baseName string, policyNames []string) string {
 	return ""
 }
 
+// unmatchedTestWarnings reports tests whose file name matches no policy of a
+// suite with several policies; such tests cannot be evaluated.
+func unmatchedTestWarnings(tests []*TestCase, policyNames []string) []string {
+	var warnings []string
+
+	available := "none"
+	if len(policyNames) > 0 {
+		sorted := append([]string(nil), policyNames...)
+		sort.Strings(sorted)
+		available = strings.Join(sorted, ", ")
+	}
+
+	for _, test := range tests {
+		if test.PolicyName != "" {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf(
+			"%s: test %q matches no policy; prefix the file name with one of: %s",
+			test.FilePath, test.Name, available))
+	}
+
+	return warnings
+}
+
+// matchChainPolicyNames detects composite tests named "<first>+<second>.<test>",
+// where the object is mutated by the first policy and then validated by the second.
+func matchChainPolicyNames(baseName string, policyNames []string) (string, string, bool) {
+	for _, first := range policyNames {
+		rest, ok := strings.CutPrefix(baseName, first+"+")
+		if !ok {
+			continue
+		}
+
+		for _, second := range policyNames {
+			if strings.HasPrefix(rest, second+".") {
+				return first, second, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
 func expectedAllowed(baseName string) bool {
 	if strings.Contains(baseName, ".deny.") || strings.HasSuffix(baseName, ".deny") {
 		return false
@@ -465,6 +916,18 @@ func expectedAllowed(baseName string) bool {
 	return true
 }
 
+// hasOutcomeToken reports whether baseName states the expected outcome with
+// an .allow, .deny, .warn or .audit token rather than defaulting to allow.
+func hasOutcomeToken(baseName string) bool {
+	for _, token := range []string{".allow", ".deny", ".warn", ".audit"} {
+		if strings.Contains(baseName, token+".") || strings.HasSuffix(baseName, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func newTempTestRequest(filePath, policyName string, expectAllowed bool) *testRequest {
 	return &testRequest{
 		Name:          filepath.Base(filePath),
@@ -524,9 +987,17 @@ func mergeTestRequests(testReq, tempReq *testRequest) {
 		testReq.ExpectMutated = tempReq.ExpectMutated
 	}
 
+	if tempReq.Matrix != nil {
+		testReq.Matrix = tempReq.Matrix
+	}
+
 	if len(tempReq.Authorizer) > 0 {
 		testReq.Authorizer = tempReq.Authorizer
 	}
+
+	if tempReq.InlineExpect != nil {
+		testReq.InlineExpect = tempReq.InlineExpect
+	}
 }
 
 // mergeRequest merges fields from tempReq into testReq (tempReq takes precedence).
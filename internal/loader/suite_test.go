@@ -2,8 +2,11 @@ package loader
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -244,38 +247,99 @@ func TestFilterTestsByPattern(t *testing.T) {
 	tests := []struct {
 		name          string
 		pattern       string
+		exact         bool
 		expectedCount int
+		expectedTests int
+		expectErr     bool
 	}{
 		{
 			name:          "match all",
 			pattern:       ".*",
 			expectedCount: 2, // 2 suites (with matching tests)
+			expectedTests: 4,
 		},
 		{
 			name:          "match specific test",
 			pattern:       "test1",
 			expectedCount: 1, // suite1 only
+			expectedTests: 1,
 		},
 		{
 			name:          "match test prefix",
 			pattern:       "test",
 			expectedCount: 2, // both suites have "test..." cases
+			expectedTests: 3,
 		},
 		{
 			name:          "no match",
 			pattern:       "nomatch",
 			expectedCount: 0,
 		},
+		{
+			name:          "suite and test",
+			pattern:       "suite2/test",
+			expectedCount: 1,
+			expectedTests: 1,
+		},
+		{
+			name:          "suite only",
+			pattern:       "suite1/",
+			expectedCount: 1,
+			expectedTests: 2,
+		},
+		{
+			name:          "anchored test",
+			pattern:       "/^test$",
+			expectedCount: 0,
+		},
+		{
+			name:          "exact test",
+			pattern:       "test1",
+			exact:         true,
+			expectedCount: 1,
+			expectedTests: 1,
+		},
+		{
+			name:          "exact does not match prefix",
+			pattern:       "test",
+			exact:         true,
+			expectedCount: 0,
+		},
+		{
+			name:          "exact suite and test",
+			pattern:       "suite2/other",
+			exact:         true,
+			expectedCount: 1,
+			expectedTests: 1,
+		},
+		{
+			name:      "invalid regexp",
+			pattern:   "test[",
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			filtered := filterTestsByPattern(copySuites(suites), tt.pattern)
+			filtered, err := filterTestsByPattern(copySuites(suites), tt.pattern, tt.exact)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("filterTestsByPattern() error = %v, expectErr %v", err, tt.expectErr)
+			}
+
 			if len(filtered) != tt.expectedCount {
 				t.Errorf("Expected %d suites, got %d", tt.expectedCount, len(filtered))
 			}
+
+			total := 0
+			for _, suite := range filtered {
+				total += len(suite.Tests)
+			}
+
+			if total != tt.expectedTests {
+				t.Errorf("Expected %d tests, got %d", tt.expectedTests, total)
+			}
 		})
 	}
 }
@@ -330,7 +394,7 @@ object:
 	}
 
 	// Test 1: Load specific pattern
-	suites, err := Load(tmpDir, "p1")
+	suites, err := Load(tmpDir, Options{Pattern: "p1"})
 	if err != nil {
 		t.Fatalf("Load error: %v", err)
 	}
@@ -342,7 +406,7 @@ object:
 	}
 
 	// Test 2: Load non-matching pattern
-	suites, err = Load(tmpDir, "nopattern")
+	suites, err = Load(tmpDir, Options{Pattern: "nopattern"})
 	if err != nil {
 		t.Fatalf("Load error: %v", err)
 	}
@@ -352,7 +416,7 @@ object:
 	}
 
 	// Test 3: Load directly from suite dir
-	suites, err = Load(suite1Dir, "")
+	suites, err = Load(suite1Dir, Options{})
 	if err != nil {
 		t.Fatalf("Load direct error: %v", err)
 	}
@@ -456,6 +520,50 @@ func TestMatchPolicyName(t *testing.T) {
 	}
 }
 
+func TestMatchChainPolicyNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		baseName    string
+		policyNames []string
+		wantFirst   string
+		wantSecond  string
+		wantOK      bool
+	}{
+		{
+			name:        "chain",
+			baseName:    "mutate+validate.case.allow",
+			policyNames: []string{"mutate", "validate"},
+			wantFirst:   "mutate",
+			wantSecond:  "validate",
+			wantOK:      true,
+		},
+		{
+			name:        "single policy prefix",
+			baseName:    "mutate.case.allow",
+			policyNames: []string{"mutate", "validate"},
+		},
+		{
+			name:        "unknown second policy",
+			baseName:    "mutate+other.case.allow",
+			policyNames: []string{"mutate", "validate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			first, second, ok := matchChainPolicyNames(tt.baseName, tt.policyNames)
+			if first != tt.wantFirst || second != tt.wantSecond || ok != tt.wantOK {
+				t.Errorf("matchChainPolicyNames(%q, %v) = (%q, %q, %v); want (%q, %q, %v)",
+					tt.baseName, tt.policyNames, first, second, ok, tt.wantFirst, tt.wantSecond, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestExpectedAllowed(t *testing.T) {
 	t.Parallel()
 
@@ -557,7 +665,7 @@ func TestLoad_Errors(t *testing.T) {
 		t.Fatalf("WriteFile failed: %v", err)
 	}
 
-	_, err := Load(suiteDir, "")
+	_, err := Load(suiteDir, Options{})
 	if err == nil {
 		t.Error("Expected error loading invalid policy")
 	}
@@ -576,7 +684,7 @@ func TestDiscover_Recursive(t *testing.T) {
 		t.Fatalf("WriteFile failed: %v", err)
 	}
 
-	suites, err := Load(tmpDir, "")
+	suites, err := Load(tmpDir, Options{})
 	if err != nil {
 		t.Fatalf("Load recursive error: %v", err)
 	}
@@ -588,6 +696,59 @@ func TestDiscover_Recursive(t *testing.T) {
 	}
 }
 
+// TestDiscover_Order checks that suites loaded concurrently come back in
+// directory order, and that the first broken suite in that order is reported.
+func TestDiscover_Order(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: p1\nspec:\n  validations:\n  - expression: 'true'"
+
+	var want []string
+
+	for group := range 4 {
+		for suite := range 16 {
+			name := fmt.Sprintf("suite-%d-%02d", group, suite)
+			dir := filepath.Join(tmpDir, fmt.Sprintf("group-%d", group), name)
+			mustMkdir(t, dir)
+
+			if err := os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(policy), 0o600); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+
+			want = append(want, name)
+		}
+	}
+
+	suites, err := Load(tmpDir, Options{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got := make([]string, 0, len(suites))
+	for _, suite := range suites {
+		got = append(got, suite.Name)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("suite order mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, name := range []string{"suite-3-07", "suite-1-04", "suite-2-00"} {
+		group := name[len("suite-") : len("suite-")+1]
+		path := filepath.Join(tmpDir, "group-"+group, name, "policy.yaml")
+
+		if err := os.WriteFile(path, []byte("kind: [invalid"), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	_, err = Load(tmpDir, Options{})
+	if err == nil || !strings.Contains(err.Error(), "failed to load test suite suite-1-04:") {
+		t.Errorf("Load() error = %v, want the error of suite-1-04", err)
+	}
+}
+
 func TestMergeRequest_AllFields(t *testing.T) {
 	t.Parallel()
 
@@ -655,3 +816,716 @@ func testGroupVersionResource(version, resource string) metav1.GroupVersionResou
 func testGroupVersionKind(version, kind string) metav1.GroupVersionKind {
 	return metav1.GroupVersionKind{Version: version, Kind: kind}
 }
+
+func TestLoadTestSuite_ObjectTemplate(t *testing.T) {
+	t.Parallel()
+
+	suiteDir := t.TempDir()
+	testsDir := filepath.Join(suiteDir, "tests")
+	mustMkdir(t, testsDir)
+
+	files := map[string]string{
+		filepath.Join(suiteDir, "policy.yaml"):                   "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: p1\nspec:\n  validations:\n  - expression: 'true'",
+		filepath.Join(testsDir, "_base.object.yaml"):             "apiVersion: v1\nkind: Pod\nmetadata:\n  name: base\n  labels:\n    app: web\n",
+		filepath.Join(testsDir, "p1.labelled.allow.object.yaml"): "from: _base\nmetadata:\n  labels:\n    team: platform\n",
+	}
+
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	suite, err := LoadTestSuite(suiteDir, "suite")
+	if err != nil {
+		t.Fatalf("LoadTestSuite() error = %v", err)
+	}
+
+	if len(suite.Tests) != 1 {
+		t.Fatalf("Expected template to be excluded and 1 test loaded, got %d", len(suite.Tests))
+	}
+
+	test := suite.Tests[0]
+	if test.Error != nil {
+		t.Fatalf("Unexpected test error: %v", test.Error)
+	}
+
+	want := map[string]string{"app": "web", "team": "platform"}
+	if diff := cmp.Diff(want, test.Object.GetLabels()); diff != "" {
+		t.Errorf("Labels mismatch (-want +got):\n%s", diff)
+	}
+
+	if test.Request.Name != "base" {
+		t.Errorf("Expected request name from template, got %q", test.Request.Name)
+	}
+}
+
+func TestLoadTestSuite_NestedFieldTypo(t *testing.T) {
+	t.Parallel()
+
+	suiteDir := t.TempDir()
+	testsDir := filepath.Join(suiteDir, "tests")
+	mustMkdir(t, testsDir)
+
+	object := `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  initContainers:
+  - name: init
+    image: busybox
+    securityContext:
+      runAsNonRoot: true
+  containers:
+  - name: app
+    image: nginx
+    securityContext:
+      privileged: false
+  - name: sidecar
+    image: envoy
+    securityContex:
+      privileged: true
+`
+	fixed := strings.Replace(object, "securityContex:", "securityContext:", 1)
+
+	files := map[string]string{
+		filepath.Join(suiteDir, "policy.yaml"):                      "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: p1\nspec:\n  validations:\n  - expression: 'true'",
+		filepath.Join(testsDir, "p1.typo.deny.object.yaml"):         object,
+		filepath.Join(testsDir, "p1.typo.deny.oldObject.yaml"):      fixed,
+		filepath.Join(testsDir, "p1.fixed.allow.object.yaml"):       fixed,
+		filepath.Join(testsDir, "p1.old-typo.allow.object.yaml"):    fixed,
+		filepath.Join(testsDir, "p1.old-typo.allow.oldObject.yaml"): object,
+	}
+
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	suite, err := LoadTestSuite(suiteDir, "suite")
+	if err != nil {
+		t.Fatalf("LoadTestSuite() error = %v", err)
+	}
+
+	errs := make(map[string]error)
+	for _, test := range suite.Tests {
+		errs[test.Name] = test.Error
+	}
+
+	if err := errs["p1.fixed.allow.yaml"]; err != nil {
+		t.Errorf("Unexpected error for valid object: %v", err)
+	}
+
+	for name, field := range map[string]string{
+		"p1.typo.deny.yaml":      "object",
+		"p1.old-typo.allow.yaml": "oldObject",
+	} {
+		err := errs[name]
+		if err == nil {
+			t.Errorf("%s: expected error for misspelled securityContext", name)
+
+			continue
+		}
+
+		for _, want := range []string{field + ":", `unknown field "spec.containers[1].securityContex"`} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: error %q does not contain %q", name, err, want)
+			}
+		}
+	}
+}
+
+func TestLoadTestSuite_CRDParams(t *testing.T) {
+	t.Parallel()
+
+	suiteDir := t.TempDir()
+	testsDir := filepath.Join(suiteDir, "tests")
+	mustMkdir(t, testsDir)
+
+	crd := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: replicalimits.example.com
+spec:
+  group: example.com
+  names:
+    kind: ReplicaLimit
+    plural: replicalimits
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          maxReplicas:
+            type: integer
+`
+	policy := `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: p1
+spec:
+  paramKind:
+    apiVersion: example.com/v1
+    kind: ReplicaLimit
+  validations:
+  - expression: 'true'
+`
+	object := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n"
+	params := "apiVersion: example.com/v1\nkind: ReplicaLimit\nmetadata:\n  name: limit\n%s: 3\n"
+
+	files := map[string]string{
+		filepath.Join(suiteDir, "crd.yaml"):                  crd,
+		filepath.Join(suiteDir, "policy.yaml"):               policy,
+		filepath.Join(testsDir, "p1.good.allow.object.yaml"): object,
+		filepath.Join(testsDir, "p1.good.allow.params.yaml"): fmt.Sprintf(params, "maxReplicas"),
+		filepath.Join(testsDir, "p1.typo.allow.object.yaml"): object,
+		filepath.Join(testsDir, "p1.typo.allow.params.yaml"): fmt.Sprintf(params, "maxReplica"),
+	}
+
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	suite, err := LoadTestSuite(suiteDir, "suite")
+	if err != nil {
+		t.Fatalf("LoadTestSuite() error = %v", err)
+	}
+
+	errs := make(map[string]error)
+	for _, test := range suite.Tests {
+		errs[test.Name] = test.Error
+	}
+
+	if err := errs["p1.good.allow.yaml"]; err != nil {
+		t.Errorf("Unexpected error for valid params: %v", err)
+	}
+
+	if err := errs["p1.typo.allow.yaml"]; !errors.Is(err, errUnknownField) {
+		t.Errorf("Expected errUnknownField for misspelled params field, got %v", err)
+	}
+}
+
+func TestLoadTestSuite_UnmatchedTestWarnings(t *testing.T) {
+	t.Parallel()
+
+	suiteDir := t.TempDir()
+	testsDir := filepath.Join(suiteDir, "tests")
+	mustMkdir(t, testsDir)
+
+	policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: %s\nspec:\n  validations:\n  - expression: 'true'\n"
+	object := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n"
+
+	files := map[string]string{
+		filepath.Join(suiteDir, "zeta.policy.yaml"):             fmt.Sprintf(policy, "zeta"),
+		filepath.Join(suiteDir, "alpha.policy.yaml"):            fmt.Sprintf(policy, "alpha"),
+		filepath.Join(testsDir, "alpha.ok.allow.object.yaml"):   object,
+		filepath.Join(testsDir, "alpah.typo.allow.object.yaml"): object,
+	}
+
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	suite, err := LoadTestSuite(suiteDir, "suite")
+	if err != nil {
+		t.Fatalf("LoadTestSuite() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(testsDir, "alpah.typo.allow.object.yaml") +
+			`: test "alpah.typo.allow.yaml" matches no policy; prefix the file name with one of: alpha, zeta`,
+	}
+	if diff := cmp.Diff(want, suite.Warnings); diff != "" {
+		t.Errorf("Warnings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoad_TestsDir(t *testing.T) {
+	t.Parallel()
+
+	policiesDir := t.TempDir()
+	testsDir := t.TempDir()
+
+	policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: %s\nspec:\n  validations:\n  - expression: 'true'\n"
+	object := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n"
+
+	files := map[string]string{
+		filepath.Join(policiesDir, "team", "alpha", "policy.yaml"):                          fmt.Sprintf(policy, "alpha"),
+		filepath.Join(policiesDir, "beta", "policy.yaml"):                                   fmt.Sprintf(policy, "beta"),
+		filepath.Join(policiesDir, "beta", "tests", "beta.colocated.allow.object.yaml"):     object,
+		filepath.Join(policiesDir, "team", "alpha", "tests", "alpha.old.allow.object.yaml"): object,
+		filepath.Join(testsDir, "team", "alpha", "alpha.ok.allow.object.yaml"):              object,
+		filepath.Join(testsDir, "team", "alpha", "alpha.no.deny.object.yaml"):               object,
+		filepath.Join(testsDir, "orphan", "gamma.ok.allow.object.yaml"):                     object,
+	}
+
+	for path, content := range files {
+		mustMkdir(t, filepath.Dir(path))
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		testsDir string
+		want     map[string][]string
+	}{
+		{
+			name:     "discovered suites",
+			path:     policiesDir,
+			testsDir: testsDir,
+			want: map[string][]string{
+				"alpha": {"alpha.no.deny.yaml", "alpha.ok.allow.yaml"},
+				// Colocated tests are used by suites without a directory in
+				// the tests tree, and ignored by the others.
+				"beta": {"beta.colocated.allow.yaml"},
+			},
+		},
+		{
+			name:     "single suite",
+			path:     filepath.Join(policiesDir, "team", "alpha"),
+			testsDir: filepath.Join(testsDir, "team", "alpha"),
+			want:     map[string][]string{"alpha": {"alpha.no.deny.yaml", "alpha.ok.allow.yaml"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			suites, err := Load(tt.path, Options{TestsDir: tt.testsDir})
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			got := map[string][]string{}
+
+			for _, suite := range suites {
+				var names []string
+				for _, test := range suite.Tests {
+					names = append(names, test.Name)
+				}
+
+				got[suite.Name] = names
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Load() tests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoad_Dirs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: %s\nspec:\n  validations:\n  - expression: 'true'\n"
+
+	for _, dir := range []string{"payments/refunds", "payments/charges", "payments-legacy/fees", "identity/users"} {
+		name := filepath.Base(dir)
+		path := filepath.Join(root, dir, "policy.yaml")
+		mustMkdir(t, filepath.Dir(path))
+
+		if err := os.WriteFile(path, []byte(fmt.Sprintf(policy, name)), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		dirs []string
+		want []string
+	}{
+		{name: "no filter", want: []string{"charges", "fees", "refunds", "users"}},
+		{name: "prefix", dirs: []string{"payments"}, want: []string{"charges", "refunds"}},
+		{name: "suite directory", dirs: []string{"payments/refunds/"}, want: []string{"refunds"}},
+		{name: "repeated", dirs: []string{"identity", "payments-legacy"}, want: []string{"fees", "users"}},
+		{name: "no match", dirs: []string{"billing"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			suites, err := Load(root, Options{Dirs: tt.dirs})
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			var got []string
+			for _, suite := range suites {
+				got = append(got, suite.Name)
+			}
+
+			slices.Sort(got)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Load() suites mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCollectTestFiles_OnlyObjectTemplatesSkipped(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"_base.object.yaml", "_x.params.yaml", "_foo.deny.object.yaml", "p1.ok.object.yaml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	got, err := collectTestFiles(dir)
+	if err != nil {
+		t.Fatalf("collectTestFiles() error = %v", err)
+	}
+
+	want := map[string][]string{
+		"_x":    {filepath.Join(dir, "_x.params.yaml")},
+		"p1.ok": {filepath.Join(dir, "p1.ok.object.yaml")},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("collectTestFiles() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadTestSuite_VariantResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		files    map[string]string
+		recorded string
+		other    string
+	}{
+		{
+			name: "matrix",
+			files: map[string]string{
+				"p1.sized.object.yaml": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n",
+				"p1.sized.matrix.yaml": "paramSets:\n- name: small\n  expect: allow\n  params: {kind: ConfigMap}\n- name: large\n  expect: deny\n  params: {kind: ConfigMap}\n",
+			},
+			recorded: "p1.sized[large].yaml",
+			other:    "p1.sized[small].yaml",
+		},
+		{
+			name: "operations",
+			files: map[string]string{
+				"p1.immutable.deny.object.yaml": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n",
+				"p1.immutable.deny.expect.yaml": "operations: [CREATE, UPDATE]\n",
+			},
+			recorded: "p1.immutable.deny[UPDATE].yaml",
+			other:    "p1.immutable.deny[CREATE].yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			suiteDir := t.TempDir()
+			testsDir := filepath.Join(suiteDir, "tests")
+			mustMkdir(t, testsDir)
+
+			policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: p1\nspec:\n  validations:\n  - expression: 'true'"
+			if err := os.WriteFile(filepath.Join(suiteDir, "policy.yaml"), []byte(policy), 0o600); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(testsDir, name), []byte(content), 0o600); err != nil {
+					t.Fatalf("WriteFile failed: %v", err)
+				}
+			}
+
+			suite, err := LoadTestSuite(suiteDir, "suite")
+			if err != nil {
+				t.Fatalf("LoadTestSuite() error = %v", err)
+			}
+
+			// Record a response for one variant only, the way -record writes it.
+			var recorded *TestCase
+
+			for _, test := range suite.Tests {
+				if test.Error != nil {
+					t.Fatalf("Unexpected test error: %v", test.Error)
+				}
+
+				if test.Name == tt.recorded {
+					recorded = test
+				}
+			}
+
+			if recorded == nil {
+				t.Fatalf("Expected a %s variant, got %d tests", tt.recorded, len(suite.Tests))
+			}
+
+			if err := os.WriteFile(recorded.ResponseFilePath(), []byte("allowed: false\nmessage: denied\n"), 0o600); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+
+			suite, err = LoadTestSuite(suiteDir, "suite")
+			if err != nil {
+				t.Fatalf("LoadTestSuite() error = %v", err)
+			}
+
+			got := map[string]*evaluator.RecordedResponse{}
+			for _, test := range suite.Tests {
+				got[test.Name] = test.RecordedResponse
+			}
+
+			want := map[string]*evaluator.RecordedResponse{
+				tt.other:    nil,
+				tt.recorded: {Allowed: false, Message: "denied"},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Recorded responses mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadTestSuite_YMLExtensions(t *testing.T) {
+	t.Parallel()
+
+	suiteDir := t.TempDir()
+	testsDir := filepath.Join(suiteDir, "tests")
+	mustMkdir(t, testsDir)
+
+	pod := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n"
+	files := map[string]string{
+		filepath.Join(suiteDir, "policy.yml"):                   "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: p1\nspec:\n  validations:\n  - expression: 'true'",
+		filepath.Join(testsDir, "_base.object.yml"):             pod,
+		filepath.Join(testsDir, "p1.full.deny.object.yml"):      "from: _base\nmetadata:\n  labels:\n    team: a\n",
+		filepath.Join(testsDir, "p1.full.deny.params.yml"):      "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: params\n",
+		filepath.Join(testsDir, "p1.full.deny.gold.yml"):        pod,
+		filepath.Join(testsDir, "p1.full.deny.annotations.yml"): "key: value\n",
+		filepath.Join(testsDir, "p1.full.deny.authorizer.yml"):  "- resource: pods\n  verb: create\n  decision: deny\n",
+		filepath.Join(testsDir, "p1.full.deny.message.txt"):     "denied\n",
+		filepath.Join(testsDir, "p1.req.allow.request.yml"):     "operation: CREATE\nobject:\n  apiVersion: v1\n  kind: Pod\n  metadata:\n    name: web\n",
+		filepath.Join(testsDir, "p1.ops.allow.object.yml"):      pod,
+		filepath.Join(testsDir, "p1.ops.allow.expect.yml"):      "operations: [CREATE, UPDATE]\n",
+	}
+
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	suite, err := LoadTestSuite(suiteDir, "suite")
+	if err != nil {
+		t.Fatalf("LoadTestSuite() error = %v", err)
+	}
+
+	if len(suite.ValidatingPolicies) != 1 {
+		t.Fatalf("Expected 1 policy from policy.yml, got %d", len(suite.ValidatingPolicies))
+	}
+
+	tests := map[string]*TestCase{}
+	for _, test := range suite.Tests {
+		if test.Error != nil {
+			t.Fatalf("Unexpected error in %s: %v", test.Name, test.Error)
+		}
+
+		tests[test.Name] = test
+	}
+
+	var names []string
+	for name := range tests {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	wantNames := []string{"p1.full.deny.yaml", "p1.ops.allow[CREATE].yaml", "p1.ops.allow[UPDATE].yaml", "p1.req.allow.yaml"}
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Fatalf("Test names mismatch (-want +got):\n%s", diff)
+	}
+
+	full := tests["p1.full.deny.yaml"]
+	if full.Object.GetLabels()["team"] != "a" || full.Object.GetName() != "web" {
+		t.Errorf("Object not merged with _base.object.yml template: %v", full.Object.Object)
+	}
+
+	if full.Params == nil || full.ExpectedObject == nil || len(full.Authorizer) != 1 {
+		t.Errorf("Expected params, gold and authorizer from .yml files, got %v, %v, %v", full.Params, full.ExpectedObject, full.Authorizer)
+	}
+
+	if full.ExpectMessage != "denied" {
+		t.Errorf("ExpectMessage = %q, want %q", full.ExpectMessage, "denied")
+	}
+
+	if diff := cmp.Diff(map[string]string{"key": "value"}, full.ExpectAuditAnnotations); diff != "" {
+		t.Errorf("Audit annotations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+//nolint:funlen // Table-driven test with many cases
+func TestLoadTestSuite_InlineExpectations(t *testing.T) {
+	t.Parallel()
+
+	pod := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n"
+	request := "operation: CREATE\nobject:\n  apiVersion: v1\n  kind: Pod\n  metadata:\n    name: web\nexpect:\n"
+
+	tests := []struct {
+		name         string
+		files        map[string]string
+		wantAllowed  bool
+		wantMessage  string
+		wantWarnings []string
+		wantMutated  bool
+		wantErr      string
+	}{
+		{
+			name: "overrides default outcome",
+			files: map[string]string{
+				"p1.replicas.request.yaml": request + "  allowed: false\n  message: too many\n",
+			},
+			wantAllowed: false,
+			wantMessage: "too many",
+		},
+		{
+			name: "agrees with file name token",
+			files: map[string]string{
+				"p1.replicas.deny.request.yaml": request + "  allowed: false\n",
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "conflicts with file name token",
+			files: map[string]string{
+				"p1.replicas.allow.request.yaml": request + "  allowed: false\n",
+			},
+			wantErr: "expect.allowed: conflicting expectation: file name expects allowed=true",
+		},
+		{
+			name: "warnings and mutated object",
+			files: map[string]string{
+				"p1.replicas.warn.request.yaml": request + "  warnings: [deprecated]\n  mutatedObject:\n    apiVersion: v1\n    kind: Pod\n",
+			},
+			wantAllowed:  true,
+			wantWarnings: []string{"deprecated"},
+			wantMutated:  true,
+		},
+		{
+			name: "sibling fields are kept",
+			files: map[string]string{
+				"p1.replicas.deny.request.yaml": request + "  message: too many\n",
+				"p1.replicas.deny.warnings.txt": "deprecated\n",
+			},
+			wantAllowed:  false,
+			wantMessage:  "too many",
+			wantWarnings: []string{"deprecated"},
+		},
+		{
+			name: "conflicts with warnings file",
+			files: map[string]string{
+				"p1.replicas.request.yaml": request + "  warnings: [deprecated]\n",
+				"p1.replicas.warnings.txt": "deprecated\n",
+			},
+			wantErr: "expect.warnings: conflicting expectation: also set by the test's .warnings.txt file",
+		},
+		{
+			name: "conflicts with annotations file",
+			files: map[string]string{
+				"p1.replicas.request.yaml":     request + "  auditAnnotations:\n    key: value\n",
+				"p1.replicas.annotations.yaml": "key: value\n",
+			},
+			wantErr: "expect.auditAnnotations: conflicting expectation",
+		},
+		{
+			name: "conflicts with message file",
+			files: map[string]string{
+				"p1.replicas.deny.request.yaml": "operation: UPDATE\nexpect:\n  message: too many\n",
+				"p1.replicas.deny.object.yaml":  pod,
+				"p1.replicas.deny.message.txt":  "too many\n",
+			},
+			wantErr: "expect.message: conflicting expectation",
+		},
+		{
+			name: "conflicts with gold file",
+			files: map[string]string{
+				"p1.replicas.request.yaml": "operation: UPDATE\nexpect:\n  mutatedObject:\n    kind: Pod\n",
+				"p1.replicas.object.yaml":  pod,
+				"p1.replicas.gold.yaml":    pod,
+			},
+			wantErr: "expect.mutatedObject: conflicting expectation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			suiteDir := t.TempDir()
+			testsDir := filepath.Join(suiteDir, "tests")
+			mustMkdir(t, testsDir)
+
+			policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: p1\nspec:\n  validations:\n  - expression: 'true'"
+			if err := os.WriteFile(filepath.Join(suiteDir, "policy.yaml"), []byte(policy), 0o600); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(testsDir, name), []byte(content), 0o600); err != nil {
+					t.Fatalf("WriteFile failed: %v", err)
+				}
+			}
+
+			suite, err := LoadTestSuite(suiteDir, "suite")
+			if err != nil {
+				t.Fatalf("LoadTestSuite() error = %v", err)
+			}
+
+			if len(suite.Tests) != 1 {
+				t.Fatalf("Expected 1 test, got %d", len(suite.Tests))
+			}
+
+			test := suite.Tests[0]
+
+			if tt.wantErr != "" {
+				if test.Error == nil || !strings.Contains(test.Error.Error(), tt.wantErr) {
+					t.Fatalf("Error = %v, want to contain %q", test.Error, tt.wantErr)
+				}
+
+				return
+			}
+
+			if test.Error != nil {
+				t.Fatalf("Unexpected test error: %v", test.Error)
+			}
+
+			if test.ExpectAllowed != tt.wantAllowed {
+				t.Errorf("ExpectAllowed = %t, want %t", test.ExpectAllowed, tt.wantAllowed)
+			}
+
+			if test.ExpectMessage != tt.wantMessage {
+				t.Errorf("ExpectMessage = %q, want %q", test.ExpectMessage, tt.wantMessage)
+			}
+
+			if diff := cmp.Diff(tt.wantWarnings, test.ExpectWarnings); diff != "" {
+				t.Errorf("ExpectWarnings mismatch (-want +got):\n%s", diff)
+			}
+
+			if got := test.ExpectedObject != nil; got != tt.wantMutated || test.ExpectMutated != tt.wantMutated {
+				t.Errorf("ExpectedObject set = %t, ExpectMutated = %t, want %t", got, test.ExpectMutated, tt.wantMutated)
+			}
+		})
+	}
+}
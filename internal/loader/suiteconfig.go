@@ -0,0 +1,67 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// suiteConfigFile holds settings of the suite in its directory.
+const suiteConfigFile = "kat.yaml"
+
+// suiteConfig is the content of a suite's kat.yaml.
+type suiteConfig struct {
+	// MaxTestDuration overrides -max-test-duration for the tests of the suite.
+	MaxTestDuration metav1.Duration `json:"maxTestDuration"`
+	// Operations runs every test with an object but no explicit request once
+	// per operation, like the operations of a test's .expect.yaml.
+	Operations []string `json:"operations,omitempty"`
+}
+
+// loadSuiteConfig reads the kat.yaml in dir, if any, into suite.
+func loadSuiteConfig(suite *TestSuite, dir string) error {
+	path := filepath.Join(dir, suiteConfigFile)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("read suite config: %w", err)
+	}
+
+	var config suiteConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return fmt.Errorf("unmarshal suite config %s: %w", path, err)
+	}
+
+	if config.MaxTestDuration.Duration < 0 {
+		return fmt.Errorf("%s: %w: %s", path, ErrNegativeDuration, config.MaxTestDuration.Duration)
+	}
+
+	if config.Operations != nil {
+		if err := validateOperations(config.Operations); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	suite.MaxTestDuration = config.MaxTestDuration.Duration
+	suite.Operations = config.Operations
+
+	return nil
+}
+
+// DurationLimit returns the maximum evaluation time of the suite's tests: the
+// maxTestDuration of its kat.yaml when set, else limit.
+func (s *TestSuite) DurationLimit(limit time.Duration) time.Duration {
+	if s.MaxTestDuration > 0 {
+		return s.MaxTestDuration
+	}
+
+	return limit
+}
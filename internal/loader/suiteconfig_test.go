@@ -0,0 +1,72 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSuiteConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string // empty: no kat.yaml
+		want    time.Duration
+		wantErr bool
+		errIs   error
+	}{
+		{name: "no config"},
+		{name: "max test duration", content: "maxTestDuration: 5ms\n", want: 5 * time.Millisecond},
+		{name: "operations", content: "operations: [CREATE, UPDATE]\n"},
+		{name: "invalid operations", content: "operations: [CONNECT]\n", wantErr: true, errIs: errOperationsUnsupported},
+		{name: "negative", content: "maxTestDuration: -1s\n", wantErr: true, errIs: ErrNegativeDuration},
+		{name: "unknown field", content: "maxDuration: 5ms\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if tt.content != "" {
+				if err := os.WriteFile(filepath.Join(dir, suiteConfigFile), []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			suite := &TestSuite{}
+
+			err := loadSuiteConfig(suite, dir)
+			if tt.wantErr {
+				if err == nil || tt.errIs != nil && !errors.Is(err, tt.errIs) {
+					t.Fatalf("loadSuiteConfig() error = %v, want %v", err, tt.errIs)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("loadSuiteConfig() error = %v", err)
+			}
+
+			if suite.MaxTestDuration != tt.want {
+				t.Errorf("MaxTestDuration = %s, want %s", suite.MaxTestDuration, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationLimit(t *testing.T) {
+	t.Parallel()
+
+	if got := (&TestSuite{}).DurationLimit(time.Second); got != time.Second {
+		t.Errorf("DurationLimit() without kat.yaml = %s, want 1s", got)
+	}
+
+	if got := (&TestSuite{MaxTestDuration: time.Millisecond}).DurationLimit(time.Second); got != time.Millisecond {
+		t.Errorf("DurationLimit() with kat.yaml = %s, want 1ms", got)
+	}
+}
@@ -0,0 +1,97 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	errNotTestFile      = errors.New("not a test file")
+	errNoEnclosingSuite = errors.New("no policy files in any parent directory")
+	errTestNotLoaded    = errors.New("test not found in suite")
+)
+
+// expectFileSuffix is the suffix of the operations file of a test, followed
+// by a YAML extension.
+const expectFileSuffix = ".expect"
+
+// loadTestFile loads the suite enclosing the test file path, the nearest
+// parent directory with policy files, with only the test of that file. Its
+// operation and matrix variants are kept.
+func loadTestFile(path string) (*TestSuite, error) {
+	name := filepath.Base(path)
+	if !isTestFile(name) && !hasYAMLSuffix(name, expectFileSuffix) {
+		return nil, fmt.Errorf("%s: %w", path, errNotTestFile)
+	}
+
+	testsDir := filepath.Dir(path)
+
+	suiteDir, err := findSuiteDir(testsDir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	suiteName := filepath.Base(suiteDir)
+	if abs, err := filepath.Abs(suiteDir); err == nil {
+		suiteName = filepath.Base(abs)
+	}
+
+	suite, err := LoadTestSuiteWithTests(suiteDir, testsDir, suiteName)
+	if err != nil {
+		return nil, fmt.Errorf("load test suite: %w", err)
+	}
+
+	baseName := testBaseName(trimYAMLSuffix(name, expectFileSuffix))
+	tests := make([]*TestCase, 0, 1)
+
+	for _, test := range suite.Tests {
+		if test.Name == baseName+".yaml" || strings.HasPrefix(test.Name, baseName+"[") {
+			tests = append(tests, test)
+		}
+	}
+
+	if len(tests) == 0 {
+		return nil, fmt.Errorf("%s: %w", path, errTestNotLoaded)
+	}
+
+	suite.Tests = tests
+
+	return suite, nil
+}
+
+// findSuiteDir returns dir or its nearest parent directory with policy files.
+func findSuiteDir(dir string) (string, error) {
+	for {
+		hasPolicies, err := hasPolicyFiles(dir)
+		if err != nil {
+			return "", err
+		}
+
+		if hasPolicies {
+			return dir, nil
+		}
+
+		parent := filepath.Join(dir, "..")
+
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("resolve %s: %w", dir, err)
+		}
+
+		if filepath.Dir(abs) == abs {
+			return "", errNoEnclosingSuite
+		}
+
+		dir = parent
+	}
+}
+
+// isRegularFile reports whether path exists and is not a directory.
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,122 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoad_TestFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	suiteDir := filepath.Join(dir, "require-owner")
+
+	policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: require-owner\nspec:\n  validations:\n  - expression: 'true'\n"
+	object := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n"
+
+	files := map[string]string{
+		filepath.Join(suiteDir, "policy.yaml"):                                             policy,
+		filepath.Join(suiteDir, "tests", "require-owner.ok.allow.object.yaml"):             object,
+		filepath.Join(suiteDir, "tests", "require-owner.ok.allow.warnings.txt"):            "",
+		filepath.Join(suiteDir, "tests", "require-owner.no.deny.object.yaml"):              object,
+		filepath.Join(suiteDir, "tests", "require-owner.ops.allow.object.yaml"):            object,
+		filepath.Join(suiteDir, "tests", "require-owner.ops.allow.expect.yaml"):            "operations: [CREATE, UPDATE]\n",
+		filepath.Join(suiteDir, "tests", "nested", "require-owner.deep.allow.object.yaml"): object,
+		filepath.Join(suiteDir, "tests", "require-owner.yml.allow.object.yml"):             object,
+		filepath.Join(suiteDir, "tests", "require-owner.yml.allow.expect.yml"):             "operations: [DELETE]\n",
+		filepath.Join(suiteDir, "tests", "require-owner.ok.allow.gold.yaml"):               object,
+		filepath.Join(dir, "orphan.ok.allow.object.yaml"):                                  object,
+	}
+
+	for path, content := range files {
+		mustMkdir(t, filepath.Dir(path))
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "object file",
+			path: filepath.Join(suiteDir, "tests", "require-owner.no.deny.object.yaml"),
+			want: []string{"require-owner.no.deny.yaml"},
+		},
+		{
+			name: "other file of the test",
+			path: filepath.Join(suiteDir, "tests", "require-owner.ok.allow.warnings.txt"),
+			want: []string{"require-owner.ok.allow.yaml"},
+		},
+		{
+			name: "operation variants",
+			path: filepath.Join(suiteDir, "tests", "require-owner.ops.allow.expect.yaml"),
+			want: []string{"require-owner.ops.allow[CREATE].yaml", "require-owner.ops.allow[UPDATE].yaml"},
+		},
+		{
+			name: "yml expect file",
+			path: filepath.Join(suiteDir, "tests", "require-owner.yml.allow.expect.yml"),
+			want: []string{"require-owner.yml.allow[DELETE].yaml"},
+		},
+		{
+			name: "nested tests directory",
+			path: filepath.Join(suiteDir, "tests", "nested", "require-owner.deep.allow.object.yaml"),
+			want: []string{"require-owner.deep.allow.yaml"},
+		},
+		{
+			name:    "not a test file",
+			path:    filepath.Join(suiteDir, "tests", "require-owner.ok.allow.gold.yaml"),
+			wantErr: errNotTestFile,
+		},
+		{
+			name:    "policy file",
+			path:    filepath.Join(suiteDir, "policy.yaml"),
+			wantErr: errNotTestFile,
+		},
+		{
+			name:    "no enclosing suite",
+			path:    filepath.Join(dir, "orphan.ok.allow.object.yaml"),
+			wantErr: errNoEnclosingSuite,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			suites, err := Load(tt.path, Options{})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if len(suites) != 1 || suites[0].Name != "require-owner" {
+				t.Fatalf("Load() returned %d suites, want suite require-owner", len(suites))
+			}
+
+			var got []string
+			for _, test := range suites[0].Tests {
+				got = append(got, test.Name)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Load() tests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
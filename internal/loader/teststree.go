@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// OrphanTestDirs returns the directories of the tests tree testsDir that hold
+// test files but have no suite at the same relative path below policiesDir,
+// e.g. after a policy directory was renamed, so their tests would silently
+// stop running.
+func OrphanTestDirs(policiesDir, testsDir string) ([]string, error) {
+	var orphans []string
+
+	err := filepath.WalkDir(testsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != testsDir && shouldSkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		hasTests, err := hasTestFiles(path)
+		if err != nil || !hasTests {
+			return err
+		}
+
+		rel, err := filepath.Rel(testsDir, path)
+		if err != nil {
+			return fmt.Errorf("relative path of %s: %w", path, err)
+		}
+
+		hasPolicies, err := hasPolicyFiles(filepath.Join(policiesDir, rel))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		if !hasPolicies {
+			orphans = append(orphans, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk tests dir: %w", err)
+	}
+
+	return orphans, nil
+}
+
+// hasTestFiles reports whether dir directly holds test files.
+func hasTestFiles(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("read dir %s: %w", dir, err)
+	}
+
+	return slices.ContainsFunc(entries, func(entry os.DirEntry) bool {
+		return !entry.IsDir() && isTestFile(entry.Name())
+	}), nil
+}
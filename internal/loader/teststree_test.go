@@ -0,0 +1,50 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOrphanTestDirs(t *testing.T) {
+	t.Parallel()
+
+	policiesDir := t.TempDir()
+	testsDir := t.TempDir()
+
+	policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: alpha\nspec:\n  validations:\n  - expression: 'true'\n"
+	object := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n"
+
+	files := map[string]string{
+		filepath.Join(policiesDir, "team", "alpha", "policy.yaml"):              policy,
+		filepath.Join(policiesDir, "team", "renamed", "README.md"):              "not a suite",
+		filepath.Join(testsDir, "team", "alpha", "alpha.ok.allow.object.yaml"):  object,
+		filepath.Join(testsDir, "team", "renamed", "beta.ok.allow.object.yaml"): object,
+		filepath.Join(testsDir, "gone", "gamma.ok.allow.object.yaml"):           object,
+		filepath.Join(testsDir, "docs", "notes.md"):                             "no tests",
+		filepath.Join(testsDir, ".git", "x.allow.object.yaml"):                  object,
+	}
+
+	for path, content := range files {
+		mustMkdir(t, filepath.Dir(path))
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	got, err := OrphanTestDirs(policiesDir, testsDir)
+	if err != nil {
+		t.Fatalf("OrphanTestDirs() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(testsDir, "gone"),
+		filepath.Join(testsDir, "team", "renamed"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("OrphanTestDirs() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,142 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission/plugin/policy/validating"
+	"k8s.io/apiserver/pkg/cel/openapi/resolver"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// typeCheckWarnings type checks the expressions of the validating policies
+// of ps as the API server does before recording the result in the policy's
+// status.typeChecking.expressionWarnings: against the schema of each kind
+// the policy matches and of its paramKind. Only kinds defined by a CRD of the
+// suite have a schema; expressions are not checked against other kinds.
+// Failures to check, as opposed to issues found, are not reported, as on the
+// API server.
+func typeCheckWarnings(suiteName string, ps *PolicySet) []string {
+	if len(ps.CRDs) == 0 {
+		return nil
+	}
+
+	checker := &validating.TypeChecker{
+		SchemaResolver: crdSchemaResolver(ps.CRDs),
+		RestMapper:     crdRESTMapper(ps.CRDs),
+	}
+
+	var warnings []string
+
+	for _, policy := range ps.ValidatingPolicies {
+		for _, warning := range checker.Check(policy) {
+			location := ps.location("ValidatingAdmissionPolicy", policy.Name, warning.FieldRef)
+			if location == "" {
+				location = suiteName
+			}
+
+			warnings = append(warnings, fmt.Sprintf("%s: ValidatingAdmissionPolicy %q %s: %s",
+				location, policy.Name, warning.FieldRef, strings.TrimSpace(warning.Warning)))
+		}
+	}
+
+	return warnings
+}
+
+// crdSchemaResolver resolves the schemas of the kinds served by crds.
+type crdSchemaResolver []*unstructured.Unstructured
+
+// ResolveSchema returns the openAPIV3Schema of the CRD version serving gvk,
+// with the object metadata the API server adds to it.
+func (r crdSchemaResolver) ResolveSchema(gvk schema.GroupVersionKind) (*spec.Schema, error) {
+	openAPISchema := findCRDSchema(r, gvk)
+	if openAPISchema == nil {
+		return nil, fmt.Errorf("%w: %s", resolver.ErrSchemaNotFound, gvk)
+	}
+
+	data, err := json.Marshal(openAPISchema)
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema of %s: %w", gvk, err)
+	}
+
+	s := &spec.Schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("decode schema of %s: %w", gvk, err)
+	}
+
+	if s.Properties == nil {
+		s.Properties = map[string]spec.Schema{}
+	}
+
+	s.Properties["apiVersion"] = *spec.StringProperty()
+	s.Properties["kind"] = *spec.StringProperty()
+	s.Properties["metadata"] = objectMetaSchema()
+
+	return s, nil
+}
+
+// objectMetaSchema returns the schema of the metadata fields expressions
+// commonly read.
+func objectMetaSchema() spec.Schema {
+	stringMap := *spec.MapProperty(spec.StringProperty())
+	stringList := *spec.ArrayProperty(spec.StringProperty())
+
+	return spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"name":              *spec.StringProperty(),
+				"generateName":      *spec.StringProperty(),
+				"namespace":         *spec.StringProperty(),
+				"uid":               *spec.StringProperty(),
+				"resourceVersion":   *spec.StringProperty(),
+				"generation":        *spec.Int64Property(),
+				"creationTimestamp": *spec.DateTimeProperty(),
+				"deletionTimestamp": *spec.DateTimeProperty(),
+				"labels":            stringMap,
+				"annotations":       stringMap,
+				"finalizers":        stringList,
+			},
+		},
+	}
+}
+
+// crdRESTMapper maps the resources of crds to their kinds.
+func crdRESTMapper(crds []*unstructured.Unstructured) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+
+	for _, crd := range crds {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		singular, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "singular")
+		scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+
+		if singular == "" {
+			singular = strings.ToLower(kind)
+		}
+
+		restScope := meta.RESTScopeNamespace
+		if scope == "Cluster" {
+			restScope = meta.RESTScopeRoot
+		}
+
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, v := range versions {
+			version, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			name, _ := version["name"].(string)
+			gv := schema.GroupVersion{Group: group, Version: name}
+			mapper.AddSpecific(gv.WithKind(kind), gv.WithResource(plural), gv.WithResource(singular), restScope)
+		}
+	}
+
+	return mapper
+}
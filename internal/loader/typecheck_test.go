@@ -0,0 +1,132 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTestSuite_TypeCheckWarnings(t *testing.T) {
+	t.Parallel()
+
+	crd := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              replicas:
+                type: integer
+`
+	policy := `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: widgets
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups: [%s]
+      apiVersions: [v1]
+      operations: [CREATE]
+      resources: [%s]
+  validations:
+  - expression: %s
+`
+
+	tests := []struct {
+		name       string
+		group      string
+		resource   string
+		expression string
+		want       []string
+	}{
+		{
+			name:       "valid expression",
+			group:      "example.com",
+			resource:   "widgets",
+			expression: "object.spec.replicas <= 3 && object.metadata.name != ''",
+		},
+		{
+			name:       "unknown field",
+			group:      "example.com",
+			resource:   "widgets",
+			expression: "object.spec.replica <= 3",
+			want: []string{
+				`policy.yaml:13: ValidatingAdmissionPolicy "widgets" spec.validations[0].expression: example.com/v1, Kind=Widget:`,
+				"undefined field 'replica'",
+			},
+		},
+		{
+			name:       "mismatched type",
+			group:      "example.com",
+			resource:   "widgets",
+			expression: "object.spec.replicas == 'three'",
+			want:       []string{"found no matching overload for '_==_'"},
+		},
+		{
+			name:       "kind without CRD",
+			group:      `""`,
+			resource:   "pods",
+			expression: "object.spec.replica <= 3",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			suiteDir := t.TempDir()
+
+			files := map[string]string{
+				filepath.Join(suiteDir, "crd.yaml"):    crd,
+				filepath.Join(suiteDir, "policy.yaml"): fmt.Sprintf(policy, tc.group, tc.resource, tc.expression),
+			}
+
+			for path, content := range files {
+				if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+					t.Fatalf("WriteFile failed: %v", err)
+				}
+			}
+
+			suite, err := LoadTestSuite(suiteDir, "suite")
+			if err != nil {
+				t.Fatalf("LoadTestSuite() error = %v", err)
+			}
+
+			if len(tc.want) == 0 {
+				if len(suite.TypeCheckWarnings) != 0 {
+					t.Errorf("TypeCheckWarnings = %q, want none", suite.TypeCheckWarnings)
+				}
+
+				return
+			}
+
+			if len(suite.TypeCheckWarnings) != 1 {
+				t.Fatalf("TypeCheckWarnings = %q, want one", suite.TypeCheckWarnings)
+			}
+
+			for _, want := range tc.want {
+				if !strings.Contains(suite.TypeCheckWarnings[0], want) {
+					t.Errorf("TypeCheckWarnings[0] = %q, want to contain %q", suite.TypeCheckWarnings[0], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Validate loads all test suites from path like Load, but instead of stopping
+// at the first problem it collects every suite that fails to load, every
+// policy, binding or suite config file that cannot be parsed, every test file
+// that cannot be parsed or validated and every policy or binding the API
+// server would reject. Problems name the file, and the line where known. It returns the suites that loaded and the problems
+// found. testsDir is a separate tests tree as in Options.TestsDir, or empty
+// when tests are colocated with their policies.
+func Validate(path, testsDir string) ([]*TestSuite, []error) {
+	hasPolicies, err := hasPolicyFiles(path)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var (
+		suites   []*TestSuite
+		problems []error
+	)
+
+	if hasPolicies {
+		suite, err := loadTestSuite(path, suiteTestsDir(path, testsDir), filepath.Base(path), &problems)
+		if err != nil {
+			return nil, append(problems, fmt.Errorf("load test suite: %w", err))
+		}
+
+		suites = []*TestSuite{suite}
+	} else {
+		suites, err = discoverTestSuites(path, testsDir, &problems)
+		if err != nil {
+			return nil, append(problems, err)
+		}
+	}
+
+	for _, suite := range suites {
+		// Policy problems are reported here rather than left on the suite.
+		for _, problem := range suite.PolicyProblems {
+			problems = append(problems, fmt.Errorf("%s: %w", suite.Name, problem))
+		}
+
+		suite.PolicyProblems = nil
+
+		for _, test := range suite.Tests {
+			if test.Error != nil {
+				problems = append(problems, fmt.Errorf("%s/%s: %w", suite.Name, test.Name, test.Error))
+			}
+		}
+	}
+
+	return suites, problems
+}
@@ -0,0 +1,65 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	policy := "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: p1\nspec:\n  validations:\n  - expression: 'true'\n"
+	object := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n"
+
+	files := map[string]string{
+		// A suite with an unparsable policy file and an unparsable binding
+		// file; both are reported.
+		filepath.Join(root, "broken-files", "policy.yaml"):  "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata: [\n",
+		filepath.Join(root, "broken-files", "binding.yaml"): "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicyBinding\nspec: 3\n",
+		// A suite whose policy the API server would reject.
+		filepath.Join(root, "invalid-policy", "policy.yaml"): "apiVersion: admissionregistration.k8s.io/v1\nkind: ValidatingAdmissionPolicy\nmetadata:\n  name: p1\nspec:\n  validations:\n  - expression: 'true'\n    reason: Warn\n",
+		// A suite with one valid and one invalid test file.
+		filepath.Join(root, "good", "policy.yaml"):                       policy,
+		filepath.Join(root, "good", "tests", "p1.ok.allow.object.yaml"):  object,
+		filepath.Join(root, "good", "tests", "p1.bad.allow.object.yaml"): "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  container: []\n",
+	}
+
+	for path, content := range files {
+		mustMkdir(t, filepath.Dir(path))
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	suites, problems := Validate(root, "")
+
+	var names []string
+	for _, suite := range suites {
+		names = append(names, suite.Name)
+	}
+
+	if strings.Join(names, ",") != "broken-files,good,invalid-policy" {
+		t.Fatalf("Validate() suites = %v, want broken-files, good and invalid-policy", names)
+	}
+
+	want := []string{
+		filepath.Join("broken-files", "binding.yaml") + ": decode document 1 at line 1",
+		filepath.Join("broken-files", "policy.yaml") + ": decode document 1: yaml: line 3",
+		"good/p1.bad.allow.yaml: failed to parse test file " + filepath.Join(root, "good", "tests", "p1.bad.allow.object.yaml") + ":6:",
+		"invalid-policy: " + filepath.Join(root, "invalid-policy", "policy.yaml") + ":8: ValidatingAdmissionPolicy \"p1\" would be rejected",
+	}
+
+	if len(problems) != len(want) {
+		t.Fatalf("Validate() problems = %v, want %d", problems, len(want))
+	}
+
+	for i, want := range want {
+		if !strings.Contains(problems[i].Error(), want) {
+			t.Errorf("problem %d = %q, want it to mention %q", i, problems[i], want)
+		}
+	}
+}
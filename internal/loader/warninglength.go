@@ -0,0 +1,44 @@
+package loader
+
+import (
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+)
+
+// longWarningMessageWarnings reports the static messages of validations bound
+// with the Warn action that are longer than the API server keeps a warning
+// once a response's warnings exceed evaluator.MaxWarningsRunes.
+func longWarningMessageWarnings(suiteName string, ps *PolicySet) []string {
+	var warnings []string
+
+	for _, policy := range ps.ValidatingPolicies {
+		if !boundWithWarn(policy.Name, ps.ValidatingBindings) {
+			continue
+		}
+
+		for i, validation := range policy.Spec.Validations {
+			length := utf8.RuneCountInString(validation.Message)
+			if length <= evaluator.MaxWarningRunes {
+				continue
+			}
+
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: ValidatingAdmissionPolicy %q: spec.validations[%d].message is %d characters; the API server cuts warnings to %d once a response's warnings exceed %d",
+				suiteName, policy.Name, i, length, evaluator.MaxWarningRunes, evaluator.MaxWarningsRunes))
+		}
+	}
+
+	return warnings
+}
+
+// boundWithWarn reports whether a binding of bindings binds the policy with
+// the Warn action.
+func boundWithWarn(policyName string, bindings []*admissionregv1.ValidatingAdmissionPolicyBinding) bool {
+	return slices.ContainsFunc(bindings, func(b *admissionregv1.ValidatingAdmissionPolicyBinding) bool {
+		return b.Spec.PolicyName == policyName && slices.Contains(b.Spec.ValidationActions, admissionregv1.Warn)
+	})
+}
@@ -0,0 +1,51 @@
+package loader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLongWarningMessageWarnings(t *testing.T) {
+	t.Parallel()
+
+	policy := func(name, message string) *admissionregv1.ValidatingAdmissionPolicy {
+		return &admissionregv1.ValidatingAdmissionPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+				Validations: []admissionregv1.Validation{{Expression: "false", Message: message}},
+			},
+		}
+	}
+	binding := func(policyName string, action admissionregv1.ValidationAction) *admissionregv1.ValidatingAdmissionPolicyBinding {
+		return &admissionregv1.ValidatingAdmissionPolicyBinding{
+			Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+				PolicyName:        policyName,
+				ValidationActions: []admissionregv1.ValidationAction{action},
+			},
+		}
+	}
+
+	ps := &PolicySet{
+		ValidatingPolicies: []*admissionregv1.ValidatingAdmissionPolicy{
+			policy("at-limit", strings.Repeat("a", 256)),
+			policy("over-limit", strings.Repeat("a", 257)),
+			policy("denied", strings.Repeat("a", 300)),
+		},
+		ValidatingBindings: []*admissionregv1.ValidatingAdmissionPolicyBinding{
+			binding("at-limit", admissionregv1.Warn),
+			binding("over-limit", admissionregv1.Warn),
+			binding("denied", admissionregv1.Deny),
+		},
+	}
+
+	want := []string{
+		`suite: ValidatingAdmissionPolicy "over-limit": spec.validations[0].message is 257 characters; the API server cuts warnings to 256 once a response's warnings exceed 4096`,
+	}
+	if diff := cmp.Diff(want, longWarningMessageWarnings("suite", ps)); diff != "" {
+		t.Errorf("longWarningMessageWarnings() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,68 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader names the columns of the CSV output, in order.
+//
+//nolint:gochecknoglobals // Static list
+var csvHeader = []string{
+	"suite", "test", "policy", "operation", "status",
+	"expected_allowed", "actual_allowed", "message", "duration_seconds", "timestamp",
+}
+
+// csvRecord returns the CSV record of r, in the order of csvHeader.
+func csvRecord(r testRow) []string {
+	timestamp := ""
+	if !r.start.IsZero() {
+		timestamp = r.start.UTC().Format(time.RFC3339)
+	}
+
+	return []string{
+		r.suite, r.test, r.policy, r.operation, r.status,
+		r.expectedAllowed, r.actualAllowed, r.message,
+		strconv.FormatFloat(r.duration, 'f', 3, 64), timestamp,
+	}
+}
+
+// CSVRenderer writes a CSV row per test, after a header row naming the
+// columns: suite, test, policy, operation, status (pass, fail or skip),
+// expected_allowed, actual_allowed, message, duration_seconds and timestamp
+// (the RFC 3339 start of the test in UTC). The allowed columns are empty for
+// tests that were not evaluated.
+type CSVRenderer struct {
+	w io.Writer
+}
+
+// NewCSVRenderer returns a CSVRenderer writing to w.
+func NewCSVRenderer(w io.Writer) *CSVRenderer {
+	return &CSVRenderer{w: w}
+}
+
+// Render writes the test results as CSV.
+func (c *CSVRenderer) Render(results *Collector) error {
+	w := csv.NewWriter(c.w)
+
+	if err := w.Write(csvHeader); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+
+	for _, row := range results.tests {
+		if err := w.Write(csvRecord(row)); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+
+	return nil
+}
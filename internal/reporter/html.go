@@ -0,0 +1,160 @@
+package reporter
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+//go:embed report.html.tmpl
+var reportTemplateText string
+
+//nolint:gochecknoglobals // Parsed once
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"diff": diffLines,
+}).Parse(reportTemplateText))
+
+// htmlReport is the data rendered as the HTML report.
+type htmlReport struct {
+	// Status is "PASS" or "FAIL".
+	Status string
+	// Generated is the time of the report, empty without timestamps.
+	Generated string
+	Elapsed   float64
+	// Total is the number of tests run.
+	Total int
+	// Counts and Policies are those of the final JSON event.
+	Counts   Counts
+	Policies map[string]Counts
+	Suites   []htmlSuite
+}
+
+type htmlSuite struct {
+	Name string
+	// Status is "pass" or "fail".
+	Status  string
+	Counts  Counts
+	Elapsed float64
+	NoTests string
+	Tests   []htmlTest
+}
+
+type htmlTest struct {
+	Name      string
+	Policy    string
+	Operation string
+	// Status is "pass", "fail" or "skip".
+	Status   string
+	Message  string
+	File     string
+	Outcome  *Outcome
+	Duration float64
+}
+
+// diffLine is a line of a failure message with the class highlighting it.
+type diffLine struct {
+	Class string
+	Text  string
+}
+
+// diffLines splits message into lines, classifying the lines of unified diffs
+// as headers, hunks, additions and removals.
+func diffLines(message string) []diffLine {
+	lines := strings.Split(message, "\n")
+	result := make([]diffLine, 0, len(lines))
+
+	for _, line := range lines {
+		var class string
+
+		switch {
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+			class = "hdr"
+		case strings.HasPrefix(line, "@@"):
+			class = "hunk"
+		case strings.HasPrefix(line, "+"):
+			class = "add"
+		case strings.HasPrefix(line, "-"):
+			class = "del"
+		}
+
+		result = append(result, diffLine{Class: class, Text: line})
+	}
+
+	return result
+}
+
+// HTMLRenderer writes a self-contained HTML report: summary cards with the
+// counts of the final JSON event, and a filterable table of the tests with
+// their failure messages.
+type HTMLRenderer struct {
+	w io.Writer
+}
+
+// NewHTMLRenderer returns an HTMLRenderer writing to w.
+func NewHTMLRenderer(w io.Writer) *HTMLRenderer {
+	return &HTMLRenderer{w: w}
+}
+
+// Render writes the results as an HTML report.
+func (h *HTMLRenderer) Render(results *Collector) error {
+	if err := reportTemplate.Execute(h.w, newHTMLReport(results)); err != nil {
+		return fmt.Errorf("write html: %w", err)
+	}
+
+	return nil
+}
+
+// newHTMLReport returns the collected results as the data of the HTML report.
+func newHTMLReport(results *Collector) htmlReport {
+	report := htmlReport{
+		Status:   "PASS",
+		Elapsed:  results.elapsed,
+		Total:    results.total,
+		Counts:   results.Counts(),
+		Policies: results.policies(),
+	}
+
+	if results.Failed() {
+		report.Status = "FAIL"
+	}
+
+	if !results.end.IsZero() {
+		report.Generated = results.end.UTC().Format(time.RFC3339)
+	}
+
+	tests := make(map[string][]htmlTest)
+
+	for _, row := range results.tests {
+		tests[row.suite] = append(tests[row.suite], htmlTest{
+			Name:      row.test,
+			Policy:    row.policy,
+			Operation: row.operation,
+			Status:    row.status,
+			Message:   row.message,
+			File:      row.file,
+			Outcome:   row.outcome,
+			Duration:  row.duration,
+		})
+	}
+
+	for _, suite := range results.suites {
+		status := "pass"
+		if suite.failed {
+			status = "fail"
+		}
+
+		report.Suites = append(report.Suites, htmlSuite{
+			Name:    suite.name,
+			Status:  status,
+			Counts:  suite.counts,
+			Elapsed: suite.elapsed,
+			NoTests: suite.noTests,
+			Tests:   tests[suite.name],
+		})
+	}
+
+	return report
+}
@@ -0,0 +1,147 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// junitTestSuites is the root element of a JUnit XML report, in the form CI
+// systems such as Jenkins, GitLab and GitHub Actions reporters read.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+	// SystemErr explains why a suite without tests failed.
+	SystemErr string `xml:"system-err,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+// junitMessage holds the first line of a failure or skip message as its
+// message attribute and the whole message as its text.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// JUnitRenderer writes a JUnit XML report: a testsuite per suite and a
+// testcase per test, named after the suite, with the failure or skip message
+// of the tests that did not pass. A suite failing for having no tests counts
+// as an error.
+type JUnitRenderer struct {
+	w io.Writer
+}
+
+// NewJUnitRenderer returns a JUnitRenderer writing to w.
+func NewJUnitRenderer(w io.Writer) *JUnitRenderer {
+	return &JUnitRenderer{w: w}
+}
+
+// newJUnitReport returns the collected results as a JUnit report.
+func newJUnitReport(results *Collector) junitTestSuites {
+	report := junitTestSuites{
+		Name:     "kat",
+		Tests:    results.total,
+		Failures: results.failed,
+		Skipped:  results.skipped,
+		Time:     junitTime(results.elapsed),
+	}
+
+	cases := make(map[string][]junitTestCase)
+
+	for _, row := range results.tests {
+		testCase := junitTestCase{
+			Name:      row.test,
+			ClassName: row.suite,
+			File:      row.file,
+			Time:      junitTime(row.duration),
+		}
+
+		switch row.status {
+		case "fail":
+			testCase.Failure = newJUnitMessage(row.message)
+		case "skip":
+			testCase.Skipped = newJUnitMessage(row.message)
+		}
+
+		cases[row.suite] = append(cases[row.suite], testCase)
+	}
+
+	for _, suite := range results.suites {
+		junitSuite := junitTestSuite{
+			Name:     suite.name,
+			Tests:    suite.counts.Passed + suite.counts.Failed + suite.counts.Skipped,
+			Failures: suite.counts.Failed,
+			Skipped:  suite.counts.Skipped,
+			Time:     junitTime(suite.elapsed),
+			Cases:    cases[suite.name],
+		}
+
+		if suite.failed && suite.noTests != "" {
+			junitSuite.Errors = 1
+			junitSuite.SystemErr = suite.noTests
+			report.Errors++
+		}
+
+		report.Suites = append(report.Suites, junitSuite)
+	}
+
+	return report
+}
+
+// newJUnitMessage returns message as a JUnit failure or skip message.
+func newJUnitMessage(message string) *junitMessage {
+	first, _, _ := strings.Cut(message, "\n")
+
+	return &junitMessage{Message: first, Text: message}
+}
+
+// junitTime formats seconds as JUnit time attributes do.
+func junitTime(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
+
+// Render writes the results as a JUnit XML report.
+func (j *JUnitRenderer) Render(results *Collector) error {
+	if _, err := io.WriteString(j.w, xml.Header); err != nil {
+		return fmt.Errorf("write junit: %w", err)
+	}
+
+	enc := xml.NewEncoder(j.w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(newJUnitReport(results)); err != nil {
+		return fmt.Errorf("write junit: %w", err)
+	}
+
+	if _, err := io.WriteString(j.w, "\n"); err != nil {
+		return fmt.Errorf("write junit: %w", err)
+	}
+
+	return nil
+}
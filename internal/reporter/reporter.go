@@ -1,17 +1,43 @@
 package reporter
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
+	"text/tabwriter"
 	"time"
 	"unicode"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/zemanlx/kat/internal/evaluator"
 )
 
+const (
+	// mutationDiffContextLines is the number of unchanged lines shown around each change.
+	mutationDiffContextLines = 3
+	// maxMutationDiffLines caps the diff printed for a passing mutating test.
+	maxMutationDiffLines = 50
+	// minGroupedFailureLines is the number of lines from which identical
+	// failure messages are grouped; shorter ones are cheaper to repeat than
+	// to refer to.
+	minGroupedFailureLines = 3
+	// maxOutcomeEntries caps the warnings and audit annotations of a JSON
+	// outcome.
+	maxOutcomeEntries = 100
+	// maxOutcomeValueBytes caps each warning and audit annotation value of a
+	// JSON outcome.
+	maxOutcomeValueBytes = 1024
+)
+
 // OutputFormat specifies the output format for test results.
 type OutputFormat int
 
@@ -28,23 +54,62 @@ const (
 type Reporter struct {
 	out io.Writer
 
+	// mainOut is the output given to New; out also writes to the current
+	// suite's output while a suite runs.
+	mainOut io.Writer
+	// openSuiteOutput opens the output of a suite, if set.
+	openSuiteOutput func(suite string) (io.WriteCloser, error)
+	// suiteOut is the output of the current suite.
+	suiteOut io.WriteCloser
+	// outputErrs collects errors opening or closing suite outputs.
+	outputErrs []error
+
 	format OutputFormat
 
-	// Global stats
-	totalTests  int
-	passedTests int
-	failedTests int
+	// noTimestamps omits event times and reports zero durations.
+	noTimestamps bool
+
+	// results collects the results of the run for the renderers and decides
+	// whether it failed.
+	results *Collector
+
+	// summaryOut receives a plain-text summary at the end of the run, if set.
+	summaryOut io.Writer
+	// failedNames lists failed tests as "suite/test (policy)" for the plain
+	// summary.
+	failedNames []string
+
+	// byPolicy prints the per-policy counts at the end of text output.
+	byPolicy bool
+
+	// groupByPolicy prints text output in sections per policy at the end.
+	groupByPolicy bool
+	// sections holds the buffered output of each policy, by name.
+	sections map[string]*policySection
+
+	// groupFailures prints identical failure messages once.
+	groupFailures bool
+	// failureGroups maps normalized failure messages to the tests failing
+	// with them, in failureGroupOrder.
+	failureGroups     map[string]*failureGroup
+	failureGroupOrder []string
 
 	startTime time.Time
 }
 
-var errTestsFailed = errors.New("tests failed")
+// failureGroup lists the "suite/test" identifiers of the tests that failed
+// with the same message, the first of which printed it.
+type failureGroup struct {
+	tests []string
+}
 
 // New creates a new Reporter that writes to the given output.
 func New(out io.Writer) *Reporter {
 	return &Reporter{
 		out:       out,
+		mainOut:   out,
 		format:    FormatDefault,
+		results:   newCollector(),
 		startTime: time.Now(),
 	}
 }
@@ -54,41 +119,346 @@ func (r *Reporter) SetFormat(format OutputFormat) {
 	r.format = format
 }
 
+// SetNoTimestamps omits the time of JSON events and reports every duration as
+// zero, in all formats. It exists purely to make output reproducible, e.g. for
+// golden tests.
+func (r *Reporter) SetNoTimestamps(noTimestamps bool) {
+	r.noTimestamps = noTimestamps
+}
+
+// SetSummaryOutput writes a plain-text summary of the run to w at the end, in
+// addition to the regular output. Combined with FormatJSON it gives humans a
+// quick pass/fail on a separate stream while the JSON stream stays clean.
+func (r *Reporter) SetSummaryOutput(w io.Writer) {
+	r.summaryOut = w
+}
+
+// AddRenderer additionally writes the results of the run with renderer, at
+// the end of the run or, for an EventRenderer, also as they happen. Renderers
+// do not change the text output or whether the run fails.
+func (r *Reporter) AddRenderer(renderer Renderer) {
+	r.results.renderers = append(r.results.renderers, renderer)
+}
+
+// Results returns the results collected so far.
+func (r *Reporter) Results() *Collector {
+	return r.results
+}
+
+// SetSuiteOutput additionally writes the output of each suite to the writer
+// open returns for it, which is closed when the suite ends. Run-level output,
+// such as the final summary, only goes to the main output.
+func (r *Reporter) SetSuiteOutput(open func(suite string) (io.WriteCloser, error)) {
+	r.openSuiteOutput = open
+}
+
+// startSuiteOutput tees the output to the suite's output, if any.
+func (r *Reporter) startSuiteOutput(suiteName string) {
+	if r.openSuiteOutput == nil {
+		return
+	}
+
+	w, err := r.openSuiteOutput(suiteName)
+	if err != nil {
+		r.outputErrs = append(r.outputErrs, err)
+
+		return
+	}
+
+	r.suiteOut = w
+	r.out = io.MultiWriter(r.mainOut, w)
+}
+
+// endSuiteOutput closes the current suite's output, if any.
+func (r *Reporter) endSuiteOutput() {
+	if r.suiteOut == nil {
+		return
+	}
+
+	if err := r.suiteOut.Close(); err != nil {
+		r.outputErrs = append(r.outputErrs, err)
+	}
+
+	r.suiteOut = nil
+	r.out = r.mainOut
+}
+
+// ReportStart reports the kat build and the flags limiting which tests run,
+// e.g. "-dir=a", so that verbose and JSON logs show why only a subset ran and
+// JSON logs which kat produced them. Verbose output shows only the filters;
+// the default format shows neither.
+func (r *Reporter) ReportStart(build *BuildInfo, filters []string) {
+	if build == nil && len(filters) == 0 {
+		return
+	}
+
+	r.emitJSON(TestEvent{
+		Action:  "start",
+		Filters: filters,
+		Build:   build,
+	})
+
+	if len(filters) == 0 {
+		return
+	}
+
+	switch r.format {
+	case FormatVerbose:
+		fmt.Fprintf(r.out, "=== FILTER %s\n", strings.Join(filters, " "))
+	case FormatJSON:
+		// Reported by emitJSON
+		break
+	case FormatDefault:
+		// Default format only reports results
+		break
+	}
+}
+
+// SetByPolicy prints the number of tests run, passed, failed and skipped of
+// each policy at the end of text output. JSON output always carries them.
+func (r *Reporter) SetByPolicy(byPolicy bool) {
+	r.byPolicy = byPolicy
+}
+
+// SetGroupFailures prints identical failure messages of at least
+// minGroupedFailureLines lines, after replacing the suite and test names,
+// only for the first test failing with them; the other
+// tests refer to it and the summary lists each group. JSON output is not
+// affected.
+func (r *Reporter) SetGroupFailures(group bool) {
+	r.groupFailures = group
+	if group && r.failureGroups == nil {
+		r.failureGroups = make(map[string]*failureGroup)
+	}
+}
+
+// SetUntestedPolicies reports n policies without tests in the summary.
+func (r *Reporter) SetUntestedPolicies(n int) {
+	r.results.untestedPolicies = n
+}
+
+// AddNotRun records n tests that were not run because the run stopped early,
+// e.g. after too many failures. The summary reports them.
+func (r *Reporter) AddNotRun(n int) {
+	r.results.notRun += n
+}
+
+// SetFailOnEmptySuites makes suites without tests fail the run.
+func (r *Reporter) SetFailOnEmptySuites(fail bool) {
+	r.results.failOnEmpty = fail
+}
+
+// SetMaxFailures makes Collector.MaxFailuresReached report when n tests
+// failed. Zero never stops the run.
+func (r *Reporter) SetMaxFailures(n int) {
+	r.results.maxFailures = n
+}
+
+// since returns the seconds elapsed since start, or zero without timestamps.
+func (r *Reporter) since(start time.Time) float64 {
+	if r.noTimestamps {
+		return 0
+	}
+
+	return time.Since(start).Seconds()
+}
+
+// seconds returns d in seconds, or zero without timestamps.
+func (r *Reporter) seconds(d time.Duration) float64 {
+	if r.noTimestamps {
+		return 0
+	}
+
+	return d.Seconds()
+}
+
 // TestEvent represents a JSON test event (similar to go test -json).
 type TestEvent struct {
-	Time    time.Time `json:"time"`
+	Time    time.Time `json:"time,omitzero"`
 	Action  string    `json:"action"`
 	Package string    `json:"package,omitempty"`
 	Test    string    `json:"test,omitempty"`
 	Elapsed float64   `json:"elapsed,omitempty"`
-	Output  string    `json:"output,omitempty"`
+	// EvalElapsed is the policy evaluation time of a test in seconds, set on
+	// test "pass" and "fail" events.
+	EvalElapsed float64 `json:"evalElapsed,omitempty"`
+	Output      string  `json:"output,omitempty"`
+	// File is the test fixture path, set on test "fail" events.
+	File string `json:"file,omitempty"`
+	// Counts is set on "summary" events at the end of each suite and on the
+	// final run-level pass/fail event.
+	Counts *Counts `json:"counts,omitempty"`
+	// NoTests is set on the pass/fail event of a suite without tests.
+	NoTests bool `json:"noTests,omitempty"`
+	// Policies holds the counts of each policy, set on the final run-level
+	// event.
+	Policies map[string]Counts `json:"policies,omitempty"`
+	// Filters lists the flags limiting which tests run, set on the "start"
+	// event at the beginning of a filtered run.
+	Filters []string `json:"filters,omitempty"`
+	// Build describes the kat build, set on the "start" event.
+	Build *BuildInfo `json:"build,omitempty"`
+	// Outcome is set on test "pass" and "fail" events of tests whose
+	// evaluation produced warnings or audit annotations.
+	Outcome *Outcome `json:"outcome,omitempty"`
+	// Policy is set on test "pass" and "fail" events of tests whose policy
+	// is known.
+	Policy *PolicyRef `json:"policy,omitempty"`
+}
+
+// BuildInfo describes a kat build: its version, the VCS revision and time
+// it was built from, and the versions of the Kubernetes libraries deciding
+// which policies it evaluates as the API server does.
+type BuildInfo struct {
+	Version  string `json:"version"`
+	Revision string `json:"revision,omitempty"`
+	// Modified is set when the working tree had uncommitted changes.
+	Modified bool   `json:"modified,omitempty"`
+	Date     string `json:"date,omitempty"`
+	// Kubernetes maps the module paths of the Kubernetes libraries to their
+	// versions.
+	Kubernetes map[string]string `json:"kubernetes,omitempty"`
+}
+
+// PolicyRef identifies the policy a test exercised and its binding. Kind and
+// Binding are empty when the test failed before it was evaluated.
+type PolicyRef struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind,omitempty"`
+	Binding string `json:"binding,omitempty"`
+}
+
+// String returns e.g. "ValidatingAdmissionPolicy require-owner, binding
+// require-owner-binding".
+func (p *PolicyRef) String() string {
+	ref := p.Name
+	if p.Kind != "" {
+		ref = p.Kind + " " + ref
+	}
+
+	if p.Binding != "" {
+		ref += ", binding " + p.Binding
+	}
+
+	return ref
+}
+
+// Outcome holds the warnings and audit annotations a test actually produced,
+// whether or not it expected them.
+type Outcome struct {
+	Warnings         []string          `json:"warnings,omitempty"`
+	AuditAnnotations map[string]string `json:"auditAnnotations,omitempty"`
+	// Truncated is set when entries or values were dropped to limit the
+	// event size.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Counts holds the number of tests by outcome.
+type Counts struct {
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+	// EmptySuites is the number of suites without tests, set on the final
+	// run-level event.
+	EmptySuites int `json:"emptySuites,omitempty"`
+	// UntestedPolicies is the number of policies without tests, set on the
+	// final run-level event.
+	UntestedPolicies int `json:"untestedPolicies,omitempty"`
+	// NotRun is the number of tests not run because the run stopped early,
+	// set on the final run-level event.
+	NotRun int `json:"notRun,omitempty"`
 }
 
-// emitJSON writes a JSON test event.
+// emitJSON writes a JSON test event to the output in FormatJSON and passes it
+// to the renderers streaming events.
 func (r *Reporter) emitJSON(event TestEvent) {
-	event.Time = time.Now()
+	if r.format != FormatJSON && !r.results.streamsEvents() {
+		return
+	}
+
+	if !r.noTimestamps {
+		event.Time = time.Now()
+	}
+
+	if r.format == FormatJSON {
+		writeJSON(r.out, event)
+	}
+
+	r.results.event(event)
+}
+
+// writeJSON writes event to w as a line of JSON.
+func writeJSON(w io.Writer, event TestEvent) {
 	// Use json.Encoder to safely encode (and defaults to HTML escaping,
 	// though not strictly required for CLI logs, it's safer).
 	// It automatically adds a newline.
-	if err := json.NewEncoder(r.out).Encode(event); err != nil {
-		fmt.Fprintf(r.out, "{\"Action\":\"error\",\"Test\":\"%s\",\"Package\":\"%s\",\"Output\":\"json error: %v\"}\n", event.Test, event.Package, err)
+	if err := json.NewEncoder(w).Encode(event); err != nil {
+		fmt.Fprintf(w, "{\"Action\":\"error\",\"Test\":\"%s\",\"Package\":\"%s\",\"Output\":\"json error: %v\"}\n", event.Test, event.Package, err)
 	}
 }
 
+// JSONRenderer writes the JSON test events as they happen, in any format, so
+// that a run shown in verbose format also leaves a machine-readable log.
+type JSONRenderer struct {
+	w io.Writer
+}
+
+// NewJSONRenderer returns a JSONRenderer writing to w.
+func NewJSONRenderer(w io.Writer) *JSONRenderer {
+	return &JSONRenderer{w: w}
+}
+
+// Event writes event as a line of JSON.
+func (j *JSONRenderer) Event(event TestEvent) {
+	writeJSON(j.w, event)
+}
+
+// Render does nothing, as every event was written as it happened.
+func (j *JSONRenderer) Render(*Collector) error {
+	return nil
+}
+
 // SuiteReporter handles reporting for a specific test suite.
 type SuiteReporter struct {
 	rep  *Reporter
 	name string
 
-	startTime   time.Time
-	passedTests int
-	failedTests int
+	startTime    time.Time
+	passedTests  int
+	failedTests  int
+	skippedTests int
 
-	// testStart tracks the start time of the current test.
-	// Only valid during a test execution.
+	// testStart and testFile track the start time and fixture path of the
+	// current test. Only valid during a test execution.
 	testStart time.Time
+	testFile  string
+	// testEval is the policy evaluation time of the current test, set by
+	// ReportResult.
+	testEval time.Duration
+	// testOutcome is the JSON outcome of the current test, set by
+	// ReportResult.
+	testOutcome *Outcome
+	// testPolicy is the policy of the current test, set by SetTestPolicy.
+	testPolicy string
+	// testOperation is the operation of the current test, set by
+	// SetTestOperation.
+	testOperation string
+	// testResult is the evaluation result of the current test, set by
+	// ReportResult.
+	testResult *evaluator.TestResult
+	// testStatus and testElapsed are the status ("pass", "fail" or "skip")
+	// and duration of the current test once reported.
+	testStatus  string
+	testElapsed float64
+	// testOut buffers the text output of the current test with
+	// SetGroupByPolicy, in place of prevOut.
+	testOut *bytes.Buffer
+	prevOut io.Writer
 
 	firstFailure bool // Track if this is first failure in non-verbose mode
+
+	// noTests explains why the suite has no tests, set by ReportEmpty.
+	noTests string
 }
 
 // StartSuite reports the start of a test suite.
@@ -100,14 +470,21 @@ func (r *Reporter) StartSuite(suiteName string) *SuiteReporter {
 		firstFailure: true,
 	}
 
+	r.startSuiteOutput(suiteName)
+
+	r.emitJSON(TestEvent{
+		Action:  "run",
+		Package: suiteName,
+	})
+
 	switch r.format {
 	case FormatVerbose:
-		fmt.Fprintf(r.out, "\n=== RUN   %s\n", suiteName)
+		if !r.groupByPolicy {
+			fmt.Fprintf(r.out, "\n=== RUN   %s\n", suiteName)
+		}
 	case FormatJSON:
-		r.emitJSON(TestEvent{
-			Action:  "run",
-			Package: suiteName,
-		})
+		// Reported by emitJSON
+		break
 	case FormatDefault:
 		// Default format doesn't output suite start
 		break
@@ -116,83 +493,307 @@ func (r *Reporter) StartSuite(suiteName string) *SuiteReporter {
 	return sr
 }
 
-// StartTest reports the start of an individual test.
-func (s *SuiteReporter) StartTest(testName string) {
-	s.rep.totalTests++
+// StartTest reports the start of an individual test. file is the test's
+// fixture path, shown when the test fails.
+func (s *SuiteReporter) StartTest(testName, file string) {
+	s.startTestOutput()
+	s.rep.results.total++
 	s.testStart = time.Now()
+	s.testFile = file
+	s.testEval = 0
+	s.testOutcome = nil
+	s.testPolicy = ""
+	s.testOperation = ""
+	s.testResult = nil
+	s.testStatus = ""
+	s.testElapsed = 0
+
+	s.rep.emitJSON(TestEvent{
+		Action:  "run",
+		Package: s.name,
+		Test:    testName,
+	})
 
 	switch s.rep.format {
 	case FormatVerbose:
 		fmt.Fprintf(s.rep.out, "=== RUN   %s/%s\n", s.name, testName)
 	case FormatJSON:
-		s.rep.emitJSON(TestEvent{
-			Action:  "run",
-			Package: s.name,
-			Test:    testName,
-		})
+		// Reported by emitJSON
+		break
 	case FormatDefault:
 		// Default format doesn't output test start
 		break
 	}
 }
 
+// noPolicy is the policy of tests that name no policy.
+const noPolicy = "(no policy)"
+
+// SetTestPolicy sets the policy the current test evaluates, for the
+// per-policy counts. Tests without one count as "(no policy)".
+func (s *SuiteReporter) SetTestPolicy(policy string) {
+	s.testPolicy = policy
+}
+
+// SetTestOperation sets the operation of the current test, e.g. "CREATE", for
+// the CSV output.
+func (s *SuiteReporter) SetTestOperation(operation string) {
+	s.testOperation = operation
+}
+
+// policyRef returns the policy of the current test, or nil when unknown.
+func (s *SuiteReporter) policyRef() *PolicyRef {
+	if s.testResult != nil && s.testResult.PolicyName != "" {
+		return &PolicyRef{Name: s.testResult.PolicyName, Kind: s.testResult.PolicyKind, Binding: s.testResult.BindingName}
+	}
+
+	if s.testPolicy != "" {
+		return &PolicyRef{Name: s.testPolicy}
+	}
+
+	return nil
+}
+
+// policyCounts returns the counts of the current test's policy.
+func (s *SuiteReporter) policyCounts() *Counts {
+	policy := s.testPolicy
+	if policy == "" {
+		policy = noPolicy
+	}
+
+	counts, ok := s.rep.results.policyCounts[policy]
+	if !ok {
+		counts = &Counts{}
+		s.rep.results.policyCounts[policy] = counts
+	}
+
+	return counts
+}
+
 // ReportPass reports a passing test.
 func (s *SuiteReporter) ReportPass(testName string) {
-	s.rep.passedTests++
+	s.rep.results.passed++
 	s.passedTests++
-	elapsed := time.Since(s.testStart).Seconds()
+	s.policyCounts().Passed++
+	elapsed := s.rep.since(s.testStart)
+
+	var message string
+	if s.testResult != nil {
+		message = s.testResult.Actual.Message
+	}
+
+	s.recordTest("pass", testName, message, elapsed)
+
+	s.rep.emitJSON(TestEvent{
+		Action:      "pass",
+		Package:     s.name,
+		Test:        testName,
+		Elapsed:     elapsed,
+		EvalElapsed: s.rep.seconds(s.testEval),
+		Outcome:     s.testOutcome,
+		Policy:      s.policyRef(),
+	})
 
 	switch s.rep.format {
 	case FormatVerbose:
 		fmt.Fprintf(s.rep.out, "--- PASS: %s/%s (%.2fs)\n", s.name, testName, elapsed)
 	case FormatJSON:
+		// Reported by emitJSON
+		break
+	case FormatDefault:
+		// Default format doesn't output individual test passes
+		break
+	}
+}
+
+// ReportSkip reports a test that was not run, with the reason why.
+// Skipped tests do not count as failures.
+func (s *SuiteReporter) ReportSkip(testName, reason string) {
+	s.rep.results.skipped++
+	s.skippedTests++
+	s.policyCounts().Skipped++
+	elapsed := s.rep.since(s.testStart)
+
+	reason = strings.TrimRightFunc(reason, unicode.IsSpace)
+	s.recordTest("skip", testName, reason, elapsed)
+
+	if reason != "" {
 		s.rep.emitJSON(TestEvent{
-			Action:  "pass",
+			Action:  "output",
 			Package: s.name,
 			Test:    testName,
-			Elapsed: elapsed,
+			Output:  reason + "\n",
 		})
+	}
+
+	s.rep.emitJSON(TestEvent{
+		Action:  "skip",
+		Package: s.name,
+		Test:    testName,
+		Elapsed: elapsed,
+	})
+
+	switch s.rep.format {
+	case FormatVerbose:
+		fmt.Fprintf(s.rep.out, "--- SKIP: %s/%s (%.2fs)\n", s.name, testName, elapsed)
+
+		if reason != "" {
+			s.printIndented(reason)
+		}
+	case FormatJSON:
+		// Reported by emitJSON
+		break
 	case FormatDefault:
-		// Default format doesn't output individual test passes
+		// Skips are only counted in the summary
 		break
 	}
 }
 
 // ReportFail reports a failing test with a message.
 func (s *SuiteReporter) ReportFail(testName, message string) {
-	s.rep.failedTests++
+	s.rep.results.failed++
 	s.failedTests++
-	elapsed := time.Since(s.testStart).Seconds()
+	s.policyCounts().Failed++
+	policy := s.policyRef()
+	elapsed := s.rep.since(s.testStart)
+
+	failedName := s.name + "/" + testName
+	if policy != nil {
+		failedName += " (" + policy.String() + ")"
+	}
+
+	s.rep.failedNames = append(s.rep.failedNames, failedName)
 
 	// Trim trailing whitespace to prevent extra empty lines in output
 	message = strings.TrimRightFunc(message, unicode.IsSpace)
+	s.recordTest("fail", testName, message, elapsed)
+
+	s.rep.emitJSON(TestEvent{
+		Action:  "output",
+		Package: s.name,
+		Test:    testName,
+		Output:  message + "\n",
+	})
+	s.rep.emitJSON(TestEvent{
+		Action:      "fail",
+		Package:     s.name,
+		Test:        testName,
+		Elapsed:     elapsed,
+		EvalElapsed: s.rep.seconds(s.testEval),
+		File:        s.testFile,
+		Outcome:     s.testOutcome,
+		Policy:      policy,
+	})
 
 	switch s.rep.format {
 	case FormatVerbose:
 		fmt.Fprintf(s.rep.out, "--- FAIL: %s/%s (%.2fs)\n", s.name, testName, elapsed)
-		s.printIndented(message)
+		s.printFile()
+
+		// Policy sections name the policy themselves
+		if policy != nil && s.testOut == nil {
+			fmt.Fprintf(s.rep.out, "    policy: %s\n", policy)
+		}
+
+		s.printFailure(testName, message)
 	case FormatJSON:
-		s.rep.emitJSON(TestEvent{
-			Action:  "output",
-			Package: s.name,
-			Test:    testName,
-			Output:  message + "\n",
-		})
-		s.rep.emitJSON(TestEvent{
-			Action:  "fail",
-			Package: s.name,
-			Test:    testName,
-			Elapsed: elapsed,
-		})
+		// Reported by emitJSON
+		break
 	case FormatDefault:
 		// Only show failures in default mode
-		if s.firstFailure {
+		if s.firstFailure && s.testOut == nil {
 			s.firstFailure = false
 			fmt.Fprintf(s.rep.out, "\n")
 		}
 
 		fmt.Fprintf(s.rep.out, "--- FAIL: %s/%s (%.2fs)\n", s.name, testName, elapsed)
+		s.printFile()
+		s.printFailure(testName, message)
+	}
+}
+
+// printFailure prints the failure message of a test. With SetGroupFailures, a
+// multi-line message already printed for another test is replaced by a
+// reference to it.
+func (s *SuiteReporter) printFailure(testName, message string) {
+	if !s.rep.groupFailures || strings.Count(message, "\n")+1 < minGroupedFailureLines {
 		s.printIndented(message)
+
+		return
+	}
+
+	id := s.name + "/" + testName
+	key := normalizeFailure(message, s.name, testName)
+
+	group, ok := s.rep.failureGroups[key]
+	if !ok {
+		s.rep.failureGroups[key] = &failureGroup{tests: []string{id}}
+		s.rep.failureGroupOrder = append(s.rep.failureGroupOrder, key)
+		s.printIndented(message)
+
+		return
+	}
+
+	group.tests = append(group.tests, id)
+	fmt.Fprintf(s.rep.out, "    same failure as %s\n", group.tests[0])
+}
+
+// normalizeFailure replaces the suite and test names in a failure message, so
+// that messages only differing in them are grouped. Names are only replaced
+// as whole words or fields, so that a test named "pod" leaves "podSpec" and
+// "pods" alone.
+func normalizeFailure(message, suiteName, testName string) string {
+	message = replaceName(message, testName, "<test>")
+	message = replaceName(message, strings.TrimSuffix(testName, ".yaml"), "<test>")
+
+	return replaceName(message, suiteName, "<suite>")
+}
+
+// replaceName replaces the occurrences of name in message that are neither
+// preceded nor followed by a word character.
+func replaceName(message, name, replacement string) string {
+	if name == "" {
+		return message
+	}
+
+	var b strings.Builder
+
+	for {
+		i := strings.Index(message, name)
+		if i < 0 {
+			break
+		}
+
+		end := i + len(name)
+
+		before, _ := utf8.DecodeLastRuneInString(message[:i])
+		after, _ := utf8.DecodeRuneInString(message[end:])
+
+		b.WriteString(message[:i])
+
+		if isWordRune(before) || isWordRune(after) {
+			b.WriteString(name)
+		} else {
+			b.WriteString(replacement)
+		}
+
+		message = message[end:]
+	}
+
+	b.WriteString(message)
+
+	return b.String()
+}
+
+// isWordRune reports whether r is a word character, as for \b in regular
+// expressions.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func (s *SuiteReporter) printFile() {
+	if s.testFile != "" {
+		fmt.Fprintf(s.rep.out, "    file: %s\n", s.testFile)
 	}
 }
 
@@ -207,85 +808,439 @@ func (s *SuiteReporter) printIndented(message string) {
 	}
 }
 
-// ReportResult reports a test result from the evaluator.
-func (s *SuiteReporter) ReportResult(testName string, result *evaluator.TestResult) {
+// ReportResult reports a test result from the evaluator. original is the
+// object the test submitted; in verbose mode, passing tests that mutated it
+// show a diff between original and the patched object.
+func (s *SuiteReporter) ReportResult(testName string, result *evaluator.TestResult, original *unstructured.Unstructured) {
+	s.testEval = result.Duration
+	s.testOutcome = newOutcome(result.Actual)
+	s.testResult = result
+
 	if result.Passed {
 		s.ReportPass(testName)
+		s.reportMutationDiff(original, result.PatchedObject)
 	} else {
 		s.ReportFail(testName, result.Message)
 	}
+
+	s.reportNotes(result.Notes)
+}
+
+// newOutcome returns the JSON outcome of actual, or nil when it produced no
+// warnings or audit annotations. Entries beyond maxOutcomeEntries are dropped
+// and values are cut to maxOutcomeValueBytes.
+func newOutcome(actual evaluator.TestOutcome) *Outcome {
+	if len(actual.Warnings) == 0 && len(actual.AuditAnnotations) == 0 {
+		return nil
+	}
+
+	outcome := &Outcome{}
+
+	for _, warning := range actual.Warnings {
+		if len(outcome.Warnings) == maxOutcomeEntries {
+			outcome.Truncated = true
+
+			break
+		}
+
+		outcome.Warnings = append(outcome.Warnings, outcome.limit(warning))
+	}
+
+	keys := slices.Sorted(maps.Keys(actual.AuditAnnotations))
+	if len(keys) > maxOutcomeEntries {
+		keys = keys[:maxOutcomeEntries]
+		outcome.Truncated = true
+	}
+
+	if len(keys) > 0 {
+		outcome.AuditAnnotations = make(map[string]string, len(keys))
+		for _, key := range keys {
+			outcome.AuditAnnotations[key] = outcome.limit(actual.AuditAnnotations[key])
+		}
+	}
+
+	return outcome
+}
+
+// limit cuts value to maxOutcomeValueBytes, marking the outcome truncated.
+func (o *Outcome) limit(value string) string {
+	if len(value) <= maxOutcomeValueBytes {
+		return value
+	}
+
+	o.Truncated = true
+
+	return strings.ToValidUTF8(value[:maxOutcomeValueBytes], "")
+}
+
+// reportMutationDiff prints a unified diff of a mutation in verbose mode.
+func (s *SuiteReporter) reportMutationDiff(original, patched *unstructured.Unstructured) {
+	if s.rep.format != FormatVerbose || original == nil || patched == nil {
+		return
+	}
+
+	diff := mutationDiff(original, patched)
+	if diff == "" {
+		return
+	}
+
+	s.printIndented(diff)
+}
+
+// mutationDiff returns a unified diff between the YAML of the original and
+// patched objects, truncated to maxMutationDiffLines.
+func mutationDiff(original, patched *unstructured.Unstructured) string {
+	originalYAML, err := yaml.Marshal(original.Object)
+	if err != nil {
+		return ""
+	}
+
+	patchedYAML, err := yaml.Marshal(patched.Object)
+	if err != nil {
+		return ""
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(originalYAML)),
+		B:        difflib.SplitLines(string(patchedYAML)),
+		FromFile: "Original",
+		ToFile:   "Mutated",
+		Context:  mutationDiffContextLines,
+	})
+
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	if len(lines) <= maxMutationDiffLines {
+		return strings.TrimRight(diff, "\n")
+	}
+
+	truncated := len(lines) - maxMutationDiffLines
+
+	return strings.Join(lines[:maxMutationDiffLines], "\n") + fmt.Sprintf("\n... (%d more diff lines truncated)", truncated)
+}
+
+// reportNotes prints informational notes about a test in verbose mode.
+func (s *SuiteReporter) reportNotes(notes []string) {
+	if s.rep.format != FormatVerbose {
+		return
+	}
+
+	for _, note := range notes {
+		fmt.Fprintf(s.rep.out, "    NOTE: %s\n", note)
+	}
+}
+
+// ReportEmpty marks the suite as having no tests, e.g. "no tests" or "no
+// tests directory". The suite reports the reason instead of a duration and
+// fails with SetFailOnEmptySuites.
+func (s *SuiteReporter) ReportEmpty(reason string) {
+	s.rep.results.emptySuites++
+	s.noTests = reason
+
+	if s.rep.results.failOnEmpty {
+		s.rep.failedNames = append(s.rep.failedNames, fmt.Sprintf("%s (%s)", s.name, reason))
+	}
 }
 
 // End reports the end of a test suite.
 func (s *SuiteReporter) End() {
-	elapsed := time.Since(s.startTime).Seconds()
+	defer s.rep.endSuiteOutput()
+
+	s.endTestOutput()
+
+	if s.noTests != "" {
+		s.endEmpty()
+
+		return
+	}
+
+	elapsed := s.rep.since(s.startTime)
+
+	// Counts precede the package-level result so consumers need not tally test events.
+	s.rep.emitJSON(TestEvent{
+		Action:  "summary",
+		Package: s.name,
+		Counts:  &Counts{Passed: s.passedTests, Failed: s.failedTests, Skipped: s.skippedTests},
+	})
+
+	action := "pass"
+	if s.failedTests > 0 {
+		action = "fail"
+	}
+
+	s.recordSuite(s.failedTests > 0, elapsed)
+
+	s.rep.emitJSON(TestEvent{
+		Action:  action,
+		Package: s.name,
+		Elapsed: elapsed,
+	})
 
 	switch s.rep.format {
 	case FormatDefault:
-		// In non-verbose mode, print ok/FAIL line for each suite
-		if s.failedTests > 0 {
-			fmt.Fprintf(s.rep.out, "FAIL\t%s\t%.3fs\n", s.name, elapsed)
-		} else {
-			fmt.Fprintf(s.rep.out, "ok  \t%s\t%.3fs\n", s.name, elapsed)
+		if s.rep.groupByPolicy {
+			// Policy sections end with their own ok/FAIL lines
+			break
 		}
-	case FormatJSON:
-		// JSON mode emits package-level result
+
+		// In non-verbose mode, print ok/FAIL line for each suite with its
+		// passed and total test counts
+		total := s.passedTests + s.failedTests + s.skippedTests
 		if s.failedTests > 0 {
-			s.rep.emitJSON(TestEvent{
-				Action:  "fail",
-				Package: s.name,
-				Elapsed: elapsed,
-			})
+			fmt.Fprintf(s.rep.out, "FAIL\t%s\t(%d/%d)\t%.3fs\n", s.name, s.passedTests, total, elapsed)
 		} else {
-			s.rep.emitJSON(TestEvent{
-				Action:  "pass",
-				Package: s.name,
-				Elapsed: elapsed,
-			})
+			fmt.Fprintf(s.rep.out, "ok  \t%s\t(%d/%d)\t%.3fs\n", s.name, s.passedTests, total, elapsed)
 		}
+	case FormatJSON:
+		// Reported by emitJSON
+		break
 	case FormatVerbose:
 		// Verbose mode doesn't output suite-level lines
 		break
 	}
 }
 
-// Summary prints the final test summary and returns an error if tests failed.
+// endEmpty reports the end of a suite without tests.
+func (s *SuiteReporter) endEmpty() {
+	status, action := "ok  ", "pass"
+	if s.rep.results.failOnEmpty {
+		status, action = "FAIL", "fail"
+	}
+
+	s.recordSuite(s.rep.results.failOnEmpty, 0)
+
+	s.rep.emitJSON(TestEvent{
+		Action:  "summary",
+		Package: s.name,
+		Counts:  &Counts{},
+	})
+	s.rep.emitJSON(TestEvent{
+		Action:  action,
+		Package: s.name,
+		Output:  s.noTests + "\n",
+		NoTests: true,
+	})
+
+	if s.rep.format != FormatJSON {
+		fmt.Fprintf(s.rep.out, "%s\t%s\t(%s)\n", status, s.name, s.noTests)
+	}
+}
+
+// Summary prints the final test summary and writes the results with the
+// renderers. It returns an error if an output could not be written; whether
+// the run failed is up to Results.
 func (r *Reporter) Summary() error {
-	elapsed := time.Since(r.startTime).Seconds()
+	elapsed := r.since(r.startTime)
+
+	// Overall result
+	counts := r.results.Counts()
+	action := "pass"
+	if r.results.Failed() {
+		action = "fail"
+	}
+
+	r.emitJSON(TestEvent{
+		Action:   action,
+		Elapsed:  elapsed,
+		Counts:   &counts,
+		Policies: r.results.policies(),
+	})
 
 	switch r.format {
 	case FormatJSON:
-		// Overall result
-		if r.failedTests > 0 {
-			r.emitJSON(TestEvent{
-				Action:  "fail",
-				Elapsed: elapsed,
-			})
-		} else {
-			r.emitJSON(TestEvent{
-				Action:  "pass",
-				Elapsed: elapsed,
-			})
-		}
+		// Reported by emitJSON
+		break
 	case FormatVerbose:
+		r.printPolicySections()
+		r.printFailureGroups()
+		r.printPolicyCounts()
+		r.printSkipped()
+
 		// Summary only in default and verbose modes
-		if r.failedTests > 0 {
+		if r.results.Failed() {
 			fmt.Fprintf(r.out, "FAIL\n")
 		} else {
 			fmt.Fprintf(r.out, "PASS\n")
 		}
 	case FormatDefault:
-		break
+		r.printPolicySections()
+		r.printFailureGroups()
+		r.printPolicyCounts()
+		r.printSkipped()
+	}
+
+	if r.summaryOut != nil {
+		r.printPlainSummary()
 	}
 
-	if r.failedTests > 0 {
-		return fmt.Errorf("%w: %d", errTestsFailed, r.failedTests)
+	var end time.Time
+	if !r.noTimestamps {
+		end = time.Now()
+	}
+
+	if err := r.results.render(elapsed, end); err != nil {
+		return err
+	}
+
+	if len(r.outputErrs) > 0 {
+		return fmt.Errorf("write suite output: %w", errors.Join(r.outputErrs...))
 	}
 
 	return nil
 }
 
+// printPlainSummary writes the failed tests and the overall counts to the
+// summary output.
+func (r *Reporter) printPlainSummary() {
+	for _, name := range r.failedNames {
+		fmt.Fprintf(r.summaryOut, "--- FAIL: %s\n", name)
+	}
+
+	status := "PASS"
+	if r.results.Failed() {
+		status = "FAIL"
+	}
+
+	c := r.results.Counts()
+
+	var extra string
+	if c.EmptySuites > 0 {
+		extra += fmt.Sprintf(", %d empty suite(s)", c.EmptySuites)
+	}
+
+	if c.UntestedPolicies > 0 {
+		extra += fmt.Sprintf(", %d untested policy(ies)", c.UntestedPolicies)
+	}
+
+	if c.NotRun > 0 {
+		extra += fmt.Sprintf(", %d not run", c.NotRun)
+	}
+
+	fmt.Fprintf(r.summaryOut, "%s\t%d test(s): %d passed, %d failed, %d skipped%s\n",
+		status, r.results.total, c.Passed, c.Failed, c.Skipped, extra)
+}
+
+// printPolicyCounts prints a table of the counts of each policy, by name,
+// with SetByPolicy.
+func (r *Reporter) printPolicyCounts() {
+	if !r.byPolicy || len(r.results.policyCounts) == 0 {
+		return
+	}
+
+	tw := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "POLICY\tRUN\tPASSED\tFAILED\tSKIPPED")
+
+	for _, policy := range slices.Sorted(maps.Keys(r.results.policyCounts)) {
+		c := r.results.policyCounts[policy]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", policy, c.Passed+c.Failed+c.Skipped, c.Passed, c.Failed, c.Skipped)
+	}
+
+	_ = tw.Flush()
+}
+
+// printFailureGroups lists the tests of each failure message shared by more
+// than one test.
+func (r *Reporter) printFailureGroups() {
+	for _, key := range r.failureGroupOrder {
+		group := r.failureGroups[key]
+		if len(group.tests) > 1 {
+			fmt.Fprintf(r.out, "SAME FAILURE (%d tests): %s\n", len(group.tests), strings.Join(group.tests, ", "))
+		}
+	}
+}
+
+// printSkipped prints the number of skipped tests, of suites without tests
+// and of policies without tests, if any.
+func (r *Reporter) printSkipped() {
+	c := r.results.Counts()
+
+	if c.Skipped > 0 {
+		fmt.Fprintf(r.out, "SKIP: %d skipped\n", c.Skipped)
+	}
+
+	if c.EmptySuites > 0 {
+		fmt.Fprintf(r.out, "EMPTY: %d suite(s) without tests\n", c.EmptySuites)
+	}
+
+	if c.UntestedPolicies > 0 {
+		fmt.Fprintf(r.out, "UNTESTED: %d policy(ies) without tests (list them with -require-tests)\n", c.UntestedPolicies)
+	}
+
+	if c.NotRun > 0 {
+		fmt.Fprintf(r.out, "STOPPED: %d test(s) not run after %d failure(s)\n", c.NotRun, c.Failed)
+	}
+}
+
 // Stats returns the current test statistics.
-func (r *Reporter) Stats() (total, passed, failed int) {
-	return r.totalTests, r.passedTests, r.failedTests
+func (r *Reporter) Stats() (total, passed, failed, skipped int) {
+	return r.results.total, r.results.passed, r.results.failed, r.results.skipped
+}
+
+// Benchmark holds the aggregated evaluation time of a policy or expression.
+type Benchmark struct {
+	Name       string
+	Iterations int
+	Total      time.Duration
+}
+
+// NsPerOp returns the average duration of a single iteration in nanoseconds.
+func (b Benchmark) NsPerOp() int64 {
+	if b.Iterations == 0 {
+		return 0
+	}
+
+	return b.Total.Nanoseconds() / int64(b.Iterations)
+}
+
+// ReportBenchmarks prints per-policy and per-expression benchmark results.
+// Expressions slower than slowThreshold per evaluation are flagged as SLOW;
+// a zero threshold disables flagging.
+func (r *Reporter) ReportBenchmarks(policies, expressions []Benchmark, slowThreshold time.Duration) {
+	for _, b := range policies {
+		r.emitBenchmark("policy", b, false)
+	}
+
+	for _, b := range expressions {
+		r.emitBenchmark("expression", b, isSlow(b, slowThreshold))
+	}
+
+	if r.format == FormatJSON {
+		return
+	}
+
+	tw := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "BENCHMARK\tITERATIONS\tNS/OP\t")
+
+	for _, b := range policies {
+		fmt.Fprintf(tw, "policy/%s\t%d\t%d\t\n", b.Name, b.Iterations, b.NsPerOp())
+	}
+
+	for _, b := range expressions {
+		flag := ""
+		if isSlow(b, slowThreshold) {
+			flag = "SLOW"
+		}
+
+		fmt.Fprintf(tw, "expression/%s\t%d\t%d\t%s\n", b.Name, b.Iterations, b.NsPerOp(), flag)
+	}
+
+	_ = tw.Flush()
+}
+
+func (r *Reporter) emitBenchmark(kind string, b Benchmark, slow bool) {
+	output := fmt.Sprintf("%d\t%d ns/op", b.Iterations, b.NsPerOp())
+	if slow {
+		output += "\tSLOW"
+	}
+
+	r.emitJSON(TestEvent{
+		Action: "bench",
+		Test:   kind + "/" + b.Name,
+		Output: output + "\n",
+	})
+}
+
+func isSlow(b Benchmark, threshold time.Duration) bool {
+	return threshold > 0 && b.NsPerOp() > threshold.Nanoseconds()
 }
@@ -2,9 +2,16 @@ package reporter
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/zemanlx/kat/internal/evaluator"
 )
@@ -24,6 +31,68 @@ func TestReporter_StartSuite(t *testing.T) {
 	}
 }
 
+func TestReporter_ReportStart(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		build   *BuildInfo
+		filters []string
+		want    string
+	}{
+		{
+			name:    "verbose",
+			format:  FormatVerbose,
+			filters: []string{"-dir=payments", "-run=refund"},
+			want:    "=== FILTER -dir=payments -run=refund\n",
+		},
+		{
+			name:    "json",
+			format:  FormatJSON,
+			filters: []string{"-dir=payments"},
+			want:    `{"action":"start","filters":["-dir=payments"]}` + "\n",
+		},
+		{
+			name:    "default",
+			format:  FormatDefault,
+			filters: []string{"-dir=payments"},
+		},
+		{
+			name:   "no filters",
+			format: FormatJSON,
+		},
+		{
+			name:   "json build",
+			format: FormatJSON,
+			build:  &BuildInfo{Version: "v1.2.0", Revision: "abc123", Kubernetes: map[string]string{"k8s.io/api": "v0.35.0"}},
+			want:   `{"action":"start","build":{"version":"v1.2.0","revision":"abc123","kubernetes":{"k8s.io/api":"v0.35.0"}}}` + "\n",
+		},
+		{
+			name:   "verbose build without filters",
+			format: FormatVerbose,
+			build:  &BuildInfo{Version: "v1.2.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			rep := New(buf)
+			rep.SetFormat(tt.format)
+			rep.SetNoTimestamps(true)
+
+			rep.ReportStart(tt.build, tt.filters)
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("ReportStart() output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestReporter_StartTest(t *testing.T) {
 	t.Parallel()
 
@@ -32,14 +101,14 @@ func TestReporter_StartTest(t *testing.T) {
 	rep.SetFormat(FormatVerbose)
 
 	s := rep.StartSuite("suite")
-	s.StartTest("test")
+	s.StartTest("test", "")
 
 	output := buf.String()
 	if !strings.Contains(output, "=== RUN   suite/test") {
 		t.Errorf("Expected test start output, got: %s", output)
 	}
 
-	total, _, _ := rep.Stats()
+	total, _, _, _ := rep.Stats()
 	if total != 1 {
 		t.Errorf("Expected total tests to be 1, got %d", total)
 	}
@@ -53,7 +122,7 @@ func TestReporter_ReportPass(t *testing.T) {
 	rep.SetFormat(FormatVerbose)
 
 	s := rep.StartSuite("suite")
-	s.StartTest("test")
+	s.StartTest("test", "")
 	s.ReportPass("test")
 
 	output := buf.String()
@@ -61,7 +130,7 @@ func TestReporter_ReportPass(t *testing.T) {
 		t.Errorf("Expected pass output, got: %s", output)
 	}
 
-	total, passed, failed := rep.Stats()
+	total, passed, failed, _ := rep.Stats()
 	if total != 1 || passed != 1 || failed != 0 {
 		t.Errorf("Expected stats (1, 1, 0), got (%d, %d, %d)", total, passed, failed)
 	}
@@ -74,24 +143,398 @@ func TestReporter_ReportFail(t *testing.T) {
 	rep := New(buf)
 
 	s := rep.StartSuite("suite")
-	s.StartTest("test")
+	s.StartTest("test", "suite/tests/test.deny.object.yaml")
 	s.ReportFail("test", "something went wrong")
 
 	output := buf.String()
-	if !strings.Contains(output, "--- FAIL: suite/test") {
-		t.Errorf("Expected fail output, got: %s", output)
+	if !strings.Contains(output, "--- FAIL: suite/test (0.00s)\n    file: suite/tests/test.deny.object.yaml\n") {
+		t.Errorf("Expected fail output with fixture path, got: %s", output)
 	}
 
 	if !strings.Contains(output, "something went wrong") {
 		t.Errorf("Expected failure message in output, got: %s", output)
 	}
 
-	total, passed, failed := rep.Stats()
+	total, passed, failed, _ := rep.Stats()
 	if total != 1 || passed != 0 || failed != 1 {
 		t.Errorf("Expected stats (1, 0, 1), got (%d, %d, %d)", total, passed, failed)
 	}
 }
 
+func TestReporter_GroupFailures(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+	rep.SetFormat(FormatDefault)
+	rep.SetNoTimestamps(true)
+	rep.SetGroupFailures(true)
+
+	diff := "object does not match:\n-  name: %s\n+  name: %s-renamed"
+
+	for _, suite := range []string{"suite-a", "suite-b"} {
+		s := rep.StartSuite(suite)
+
+		for _, test := range []string{"test-1.yaml", "test-2.yaml"} {
+			name := strings.TrimSuffix(test, ".yaml")
+
+			s.StartTest(test, "")
+			s.ReportFail(test, fmt.Sprintf(diff, name, name))
+		}
+
+		s.StartTest("short.yaml", "")
+		s.ReportFail("short.yaml", "expected allowed=true, got allowed=false")
+
+		s.End()
+	}
+
+	_ = rep.Summary()
+
+	output := buf.String()
+
+	if got := strings.Count(output, "object does not match"); got != 1 {
+		t.Errorf("Expected the grouped message once, got %d times:\n%s", got, output)
+	}
+
+	if got := strings.Count(output, "    same failure as suite-a/test-1.yaml\n"); got != 3 {
+		t.Errorf("Expected 3 references to the first failure, got %d:\n%s", got, output)
+	}
+
+	if got := strings.Count(output, "expected allowed=true, got allowed=false"); got != 2 {
+		t.Errorf("Expected single-line messages to be repeated, got %d:\n%s", got, output)
+	}
+
+	want := "SAME FAILURE (4 tests): suite-a/test-1.yaml, suite-a/test-2.yaml, suite-b/test-1.yaml, suite-b/test-2.yaml\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("Expected summary %q, got:\n%s", want, output)
+	}
+
+	_, _, failed, _ := rep.Stats()
+	if failed != 6 {
+		t.Errorf("Expected 6 failed tests, got %d", failed)
+	}
+}
+
+func TestNormalizeFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		message string
+		suite   string
+		test    string
+		want    string
+	}{
+		{
+			name:    "names in paths",
+			message: "ns/team-a/tests/pod.deny.object.yaml: pod.deny rejected",
+			suite:   "team-a",
+			test:    "pod.deny.yaml",
+			want:    "ns/<suite>/tests/<test>.object.yaml: <test> rejected",
+		},
+		{
+			name:    "short test name",
+			message: "image of data.yaml in a.yaml: a_b is invalid, see tests/a.object.yaml",
+			suite:   "s",
+			test:    "a.yaml",
+			want:    "image of data.yaml in <test>: a_b is invalid, see tests/<test>.object.yaml",
+		},
+		{
+			name:    "part of longer words",
+			message: "pods and podSpec of apod, pod_name and pod-template",
+			suite:   "pods-suite",
+			test:    "pod.yaml",
+			want:    "pods and podSpec of apod, pod_name and <test>-template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := normalizeFailure(tt.message, tt.suite, tt.test); got != tt.want {
+				t.Errorf("normalizeFailure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReporter_ReportSkip(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+	rep.SetFormat(FormatVerbose)
+
+	s := rep.StartSuite("suite")
+	s.StartTest("test", "")
+	s.ReportSkip("test", "requires a cluster")
+
+	output := buf.String()
+	if !strings.Contains(output, "--- SKIP: suite/test") {
+		t.Errorf("Expected skip output, got: %s", output)
+	}
+
+	if !strings.Contains(output, "    requires a cluster") {
+		t.Errorf("Expected skip reason in output, got: %s", output)
+	}
+
+	total, passed, failed, skipped := rep.Stats()
+	if total != 1 || passed != 0 || failed != 0 || skipped != 1 {
+		t.Errorf("Expected stats (1, 0, 0, 1), got (%d, %d, %d, %d)", total, passed, failed, skipped)
+	}
+
+	if err := rep.Summary(); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	if err := rep.Results().Err(); err != nil {
+		t.Errorf("Results().Err() = %v, skipped tests must not fail the run", err)
+	}
+}
+
+func TestReporter_ReportFail_JSONFile(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+	rep.SetFormat(FormatJSON)
+
+	s := rep.StartSuite("suite")
+	s.StartTest("test", "suite/tests/test.deny.object.yaml")
+	s.ReportFail("test", "something went wrong")
+
+	var files []string
+
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var event TestEvent
+		if err := dec.Decode(&event); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if event.Action == "fail" {
+			files = append(files, event.File)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"suite/tests/test.deny.object.yaml"}, files); diff != "" {
+		t.Errorf("Fail event files mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReporter_ReportResult_JSONOutcome(t *testing.T) {
+	t.Parallel()
+
+	manyWarnings := make([]string, maxOutcomeEntries+1)
+	for i := range manyWarnings {
+		manyWarnings[i] = fmt.Sprintf("warning %d", i)
+	}
+
+	tests := []struct {
+		name   string
+		result *evaluator.TestResult
+		want   *Outcome
+	}{
+		{
+			name:   "no warnings or annotations",
+			result: &evaluator.TestResult{Passed: true},
+		},
+		{
+			name: "passing test",
+			result: &evaluator.TestResult{
+				Passed: true,
+				Actual: evaluator.TestOutcome{
+					Warnings:         []string{"deprecated field"},
+					AuditAnnotations: map[string]string{"owner": "platform"},
+				},
+			},
+			want: &Outcome{
+				Warnings:         []string{"deprecated field"},
+				AuditAnnotations: map[string]string{"owner": "platform"},
+			},
+		},
+		{
+			name: "failing test",
+			result: &evaluator.TestResult{
+				Message: "failed",
+				Actual:  evaluator.TestOutcome{Warnings: []string{"unexpected"}},
+			},
+			want: &Outcome{Warnings: []string{"unexpected"}},
+		},
+		{
+			name: "too many warnings",
+			result: &evaluator.TestResult{
+				Passed: true,
+				Actual: evaluator.TestOutcome{Warnings: manyWarnings},
+			},
+			want: &Outcome{Warnings: manyWarnings[:maxOutcomeEntries], Truncated: true},
+		},
+		{
+			name: "long annotation value",
+			result: &evaluator.TestResult{
+				Passed: true,
+				Actual: evaluator.TestOutcome{AuditAnnotations: map[string]string{
+					"owner": strings.Repeat("x", maxOutcomeValueBytes+1),
+				}},
+			},
+			want: &Outcome{
+				AuditAnnotations: map[string]string{"owner": strings.Repeat("x", maxOutcomeValueBytes)},
+				Truncated:        true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			rep := New(buf)
+			rep.SetFormat(FormatJSON)
+
+			s := rep.StartSuite("suite")
+			s.StartTest("test", "")
+			s.ReportResult("test", tt.result, nil)
+			s.End()
+
+			var got *Outcome
+
+			dec := json.NewDecoder(buf)
+			for dec.More() {
+				var event TestEvent
+				if err := dec.Decode(&event); err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+
+				if event.Test == "test" && (event.Action == "pass" || event.Action == "fail") {
+					got = event.Outcome
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Outcome mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReporter_NoTimestamps(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		want   string
+	}{
+		{
+			name:   "json",
+			format: FormatJSON,
+			want: `{"action":"run","package":"suite"}
+{"action":"run","package":"suite","test":"test"}
+{"action":"output","package":"suite","test":"test","output":"boom\n"}
+{"action":"fail","package":"suite","test":"test","file":"test.yaml"}
+{"action":"summary","package":"suite","counts":{"passed":0,"failed":1,"skipped":0}}
+{"action":"fail","package":"suite"}
+{"action":"fail","counts":{"passed":0,"failed":1,"skipped":0},"policies":{"(no policy)":{"passed":0,"failed":1,"skipped":0}}}
+`,
+		},
+		{
+			name:   "verbose",
+			format: FormatVerbose,
+			want: `
+=== RUN   suite
+=== RUN   suite/test
+--- FAIL: suite/test (0.00s)
+    file: test.yaml
+    boom
+FAIL
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			rep := New(buf)
+			rep.SetFormat(tt.format)
+			rep.SetNoTimestamps(true)
+
+			s := rep.StartSuite("suite")
+			s.StartTest("test", "test.yaml")
+			time.Sleep(10 * time.Millisecond)
+			s.ReportFail("test", "boom")
+			s.End()
+
+			_ = rep.Summary()
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("Output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReporter_ReportSkip_JSON(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+	rep.SetFormat(FormatJSON)
+
+	s := rep.StartSuite("suite")
+	s.StartTest("test", "")
+	s.ReportSkip("test", "requires a cluster")
+	s.End()
+
+	var actions []string
+
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var event TestEvent
+		if err := dec.Decode(&event); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		actions = append(actions, event.Action)
+
+		if event.Action == "summary" && event.Counts.Skipped != 1 {
+			t.Errorf("Expected 1 skipped test in summary counts, got %+v", event.Counts)
+		}
+	}
+
+	want := []string{"run", "run", "output", "skip", "summary", "pass"}
+	if diff := cmp.Diff(want, actions); diff != "" {
+		t.Errorf("Actions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReporter_ReportSkip_Default(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+
+	s := rep.StartSuite("suite")
+	s.StartTest("test", "")
+	s.ReportSkip("test", "requires a cluster")
+	s.End()
+
+	if strings.Contains(buf.String(), "requires a cluster") {
+		t.Errorf("Expected no skip details in default output, got: %s", buf.String())
+	}
+
+	if err := rep.Summary(); err != nil {
+		t.Errorf("Summary() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "SKIP: 1 skipped") {
+		t.Errorf("Expected skip count in summary, got: %s", buf.String())
+	}
+}
+
 func TestReporter_ReportResult_Pass(t *testing.T) {
 	t.Parallel()
 
@@ -100,19 +543,19 @@ func TestReporter_ReportResult_Pass(t *testing.T) {
 	rep.SetFormat(FormatVerbose)
 
 	s := rep.StartSuite("suite")
-	s.StartTest("test")
+	s.StartTest("test", "")
 
 	result := &evaluator.TestResult{
 		Passed: true,
 	}
-	s.ReportResult("test", result)
+	s.ReportResult("test", result, nil)
 
 	output := buf.String()
 	if !strings.Contains(output, "--- PASS: suite/test") {
 		t.Errorf("Expected pass output, got: %s", output)
 	}
 
-	_, passed, _ := rep.Stats()
+	_, passed, _, _ := rep.Stats()
 	if passed != 1 {
 		t.Errorf("Expected 1 passed test, got %d", passed)
 	}
@@ -125,13 +568,13 @@ func TestReporter_ReportResult_Fail(t *testing.T) {
 	rep := New(buf)
 
 	s := rep.StartSuite("suite")
-	s.StartTest("test")
+	s.StartTest("test", "")
 
 	result := &evaluator.TestResult{
 		Passed:  false,
 		Message: "validation failed",
 	}
-	s.ReportResult("test", result)
+	s.ReportResult("test", result, nil)
 
 	output := buf.String()
 	if !strings.Contains(output, "--- FAIL: suite/test") {
@@ -142,12 +585,382 @@ func TestReporter_ReportResult_Fail(t *testing.T) {
 		t.Errorf("Expected failure message in output, got: %s", output)
 	}
 
-	_, _, failed := rep.Stats()
+	_, _, failed, _ := rep.Stats()
 	if failed != 1 {
 		t.Errorf("Expected 1 failed test, got %d", failed)
 	}
 }
 
+func TestReporter_ReportResult_Policy(t *testing.T) {
+	t.Parallel()
+
+	result := &evaluator.TestResult{
+		Message:     "validation failed",
+		PolicyName:  "require-owner",
+		PolicyKind:  evaluator.PolicyKindValidating,
+		BindingName: "require-owner-binding",
+	}
+	ref := "ValidatingAdmissionPolicy require-owner, binding require-owner-binding"
+
+	t.Run("verbose", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		rep := New(buf)
+		rep.SetFormat(FormatVerbose)
+
+		s := rep.StartSuite("suite")
+		s.StartTest("test", "")
+		s.ReportResult("test", result, nil)
+
+		if !strings.Contains(buf.String(), "    policy: "+ref+"\n") {
+			t.Errorf("Expected policy line in output, got: %s", buf.String())
+		}
+	})
+
+	t.Run("json and summary", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		summary := &bytes.Buffer{}
+		rep := New(buf)
+		rep.SetFormat(FormatJSON)
+		rep.SetSummaryOutput(summary)
+
+		s := rep.StartSuite("suite")
+		s.StartTest("test", "")
+		s.SetTestPolicy("require-owner")
+		s.ReportResult("test", result, nil)
+		s.StartTest("broken", "")
+		s.SetTestPolicy("require-owner")
+		s.ReportFail("broken", "no binding")
+		s.End()
+		_ = rep.Summary()
+
+		var policies []*PolicyRef
+
+		dec := json.NewDecoder(buf)
+		for dec.More() {
+			var event TestEvent
+			if err := dec.Decode(&event); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if event.Action == "fail" && event.Test != "" {
+				policies = append(policies, event.Policy)
+			}
+		}
+
+		want := []*PolicyRef{
+			{Name: "require-owner", Kind: evaluator.PolicyKindValidating, Binding: "require-owner-binding"},
+			{Name: "require-owner"},
+		}
+		if diff := cmp.Diff(want, policies); diff != "" {
+			t.Errorf("Fail event policies mismatch (-want +got):\n%s", diff)
+		}
+
+		wantSummary := "--- FAIL: suite/test (" + ref + ")\n--- FAIL: suite/broken (require-owner)\n" +
+			"FAIL\t2 test(s): 0 passed, 2 failed, 0 skipped\n"
+		if diff := cmp.Diff(wantSummary, summary.String()); diff != "" {
+			t.Errorf("Summary mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestReporter_ReportResult_Notes(t *testing.T) {
+	t.Parallel()
+
+	result := &evaluator.TestResult{
+		Passed: true,
+		Notes:  []string{"created missing parent map /metadata/labels for JSONPatch add"},
+	}
+
+	verbose := &bytes.Buffer{}
+	rep := New(verbose)
+	rep.SetFormat(FormatVerbose)
+
+	s := rep.StartSuite("suite")
+	s.StartTest("test", "")
+	s.ReportResult("test", result, nil)
+
+	if !strings.Contains(verbose.String(), "    NOTE: created missing parent map /metadata/labels") {
+		t.Errorf("Expected note in verbose output, got: %s", verbose.String())
+	}
+
+	quiet := &bytes.Buffer{}
+	rep = New(quiet)
+
+	s = rep.StartSuite("suite")
+	s.StartTest("test", "")
+	s.ReportResult("test", result, nil)
+
+	if strings.Contains(quiet.String(), "NOTE") {
+		t.Errorf("Expected no notes in default output, got: %s", quiet.String())
+	}
+}
+
+func TestReporter_ReportResult_MutationDiff(t *testing.T) {
+	t.Parallel()
+
+	original := &unstructured.Unstructured{Object: map[string]any{
+		"kind":     "Pod",
+		"metadata": map[string]any{"name": "web"},
+	}}
+	patched := &unstructured.Unstructured{Object: map[string]any{
+		"kind":     "Pod",
+		"metadata": map[string]any{"name": "web", "labels": map[string]any{"team": "platform"}},
+	}}
+
+	many := map[string]any{}
+	for i := range maxMutationDiffLines {
+		many[fmt.Sprintf("key%03d", i)] = "value"
+	}
+
+	tests := []struct {
+		name     string
+		format   OutputFormat
+		result   *evaluator.TestResult
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "verbose pass shows diff",
+			format:   FormatVerbose,
+			result:   &evaluator.TestResult{Passed: true, PatchedObject: patched},
+			contains: []string{"    --- Original", "    +++ Mutated", "    +    labels:", "    +        team: platform"},
+		},
+		{
+			name:     "verbose pass without mutation",
+			format:   FormatVerbose,
+			result:   &evaluator.TestResult{Passed: true},
+			excludes: []string{"Original"},
+		},
+		{
+			name:     "default format omits diff",
+			format:   FormatDefault,
+			result:   &evaluator.TestResult{Passed: true, PatchedObject: patched},
+			excludes: []string{"Original"},
+		},
+		{
+			name:     "failure omits diff",
+			format:   FormatVerbose,
+			result:   &evaluator.TestResult{Passed: false, Message: "mismatch", PatchedObject: patched},
+			excludes: []string{"Original"},
+		},
+		{
+			name:     "large diff is truncated",
+			format:   FormatVerbose,
+			result:   &evaluator.TestResult{Passed: true, PatchedObject: &unstructured.Unstructured{Object: map[string]any{"kind": "Pod", "data": many}}},
+			contains: []string{"more diff lines truncated"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			rep := New(buf)
+			rep.SetFormat(tt.format)
+
+			s := rep.StartSuite("suite")
+			s.StartTest("test", "")
+			s.ReportResult("test", tt.result, original)
+
+			for _, want := range tt.contains {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("Expected output to contain %q, got: %s", want, buf.String())
+				}
+			}
+
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(buf.String(), unwanted) {
+					t.Errorf("Expected output not to contain %q, got: %s", unwanted, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestReporter_JSONCounts(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+	rep.SetFormat(FormatJSON)
+
+	outcomes := map[string][]bool{
+		"suite-a": {true, true, false},
+		"suite-b": {true},
+	}
+
+	for _, name := range []string{"suite-a", "suite-b"} {
+		s := rep.StartSuite(name)
+
+		for i, passed := range outcomes[name] {
+			testName := fmt.Sprintf("test-%d", i)
+			s.StartTest(testName, "")
+			s.ReportResult(testName, &evaluator.TestResult{Passed: passed, Message: "failed"}, nil)
+		}
+
+		s.End()
+	}
+
+	_ = rep.Summary()
+
+	suiteCounts := make(map[string]Counts)
+
+	var runCounts *Counts
+
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var event TestEvent
+		if err := dec.Decode(&event); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		switch {
+		case event.Action == "summary":
+			suiteCounts[event.Package] = *event.Counts
+		case event.Package == "" && event.Test == "":
+			runCounts = event.Counts
+		}
+	}
+
+	wantSuites := map[string]Counts{
+		"suite-a": {Passed: 2, Failed: 1},
+		"suite-b": {Passed: 1},
+	}
+	if diff := cmp.Diff(wantSuites, suiteCounts); diff != "" {
+		t.Errorf("Suite counts mismatch (-want +got):\n%s", diff)
+	}
+
+	if runCounts == nil {
+		t.Fatal("Expected counts on the final run-level event")
+	}
+
+	_, passed, failed, skipped := rep.Stats()
+	if diff := cmp.Diff(Counts{Passed: passed, Failed: failed, Skipped: skipped}, *runCounts); diff != "" {
+		t.Errorf("Run counts do not match Stats() (-want +got):\n%s", diff)
+	}
+}
+
+func TestReporter_GroupByPolicy(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+	rep.SetNoTimestamps(true)
+	rep.SetGroupByPolicy(true)
+
+	a := rep.StartSuite("suite-a")
+	a.StartTest("owned", "")
+	a.SetTestPolicy("require-owner")
+	a.ReportResult("owned", &evaluator.TestResult{Passed: true, PolicyName: "require-owner"}, nil)
+	a.StartTest("defaults", "")
+	a.SetTestPolicy("add-defaults")
+	a.ReportPass("defaults")
+	a.End()
+
+	b := rep.StartSuite("suite-b")
+	b.StartTest("unowned", "suite-b/tests/unowned.deny.object.yaml")
+	b.SetTestPolicy("require-owner")
+	b.ReportResult("unowned", &evaluator.TestResult{
+		Message:     "expected allowed=false, got allowed=true",
+		PolicyName:  "require-owner",
+		PolicyKind:  evaluator.PolicyKindValidating,
+		BindingName: "require-owner-binding",
+	}, nil)
+	b.End()
+
+	_ = rep.Summary()
+
+	want := "ok  \tadd-defaults\t(1/1)\t0.000s\n" +
+		"=== POLICY ValidatingAdmissionPolicy require-owner, binding require-owner-binding\n" +
+		"    --- FAIL: suite-b/unowned (0.00s)\n" +
+		"        file: suite-b/tests/unowned.deny.object.yaml\n" +
+		"        expected allowed=false, got allowed=true\n" +
+		"FAIL\trequire-owner\t(1/2)\t0.000s\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReporter_ByPolicy(t *testing.T) {
+	t.Parallel()
+
+	run := func(rep *Reporter) {
+		s := rep.StartSuite("suite")
+
+		for i, test := range []struct {
+			policy string
+			passed bool
+		}{
+			{policy: "require-owner", passed: true},
+			{policy: "require-owner"},
+			{policy: "add-labels", passed: true},
+			{},
+		} {
+			name := fmt.Sprintf("test-%d", i)
+			s.StartTest(name, "")
+			s.SetTestPolicy(test.policy)
+			s.ReportResult(name, &evaluator.TestResult{Passed: test.passed, Message: "failed"}, nil)
+		}
+
+		s.End()
+		_ = rep.Summary()
+	}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		rep := New(buf)
+		rep.SetByPolicy(true)
+		run(rep)
+
+		want := "POLICY         RUN  PASSED  FAILED  SKIPPED\n" +
+			"(no policy)    1    0       1       0\n" +
+			"add-labels     1    1       0       0\n" +
+			"require-owner  2    1       1       0\n"
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected policy table %q, got:\n%s", want, buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		rep := New(buf)
+		rep.SetFormat(FormatJSON)
+		run(rep)
+
+		var policies map[string]Counts
+
+		dec := json.NewDecoder(buf)
+		for dec.More() {
+			var event TestEvent
+			if err := dec.Decode(&event); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if event.Package == "" && event.Test == "" {
+				policies = event.Policies
+			}
+		}
+
+		want := map[string]Counts{
+			"(no policy)":   {Failed: 1},
+			"add-labels":    {Passed: 1},
+			"require-owner": {Passed: 1, Failed: 1},
+		}
+		if diff := cmp.Diff(want, policies); diff != "" {
+			t.Errorf("Policies mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
 func TestReporter_Summary_AllPass(t *testing.T) {
 	t.Parallel()
 
@@ -156,14 +969,17 @@ func TestReporter_Summary_AllPass(t *testing.T) {
 	rep.SetFormat(FormatVerbose)
 
 	s := rep.StartSuite("suite")
-	s.StartTest("test1")
+	s.StartTest("test1", "")
 	s.ReportPass("test1")
-	s.StartTest("test2")
+	s.StartTest("test2", "")
 	s.ReportPass("test2")
 	s.End()
 
-	err := rep.Summary()
-	if err != nil {
+	if err := rep.Summary(); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	if err := rep.Results().Err(); err != nil {
 		t.Errorf("Expected no error for all passing tests, got: %v", err)
 	}
 
@@ -172,7 +988,7 @@ func TestReporter_Summary_AllPass(t *testing.T) {
 		t.Errorf("Expected PASS in summary, got: %s", output)
 	}
 
-	total, passed, _ := rep.Stats()
+	total, passed, _, _ := rep.Stats()
 	if total != 2 || passed != 2 {
 		t.Errorf("Expected stats (2, 2, 0), got (%d, %d)", total, passed)
 	}
@@ -186,15 +1002,19 @@ func TestReporter_Summary_WithFailures(t *testing.T) {
 	rep.SetFormat(FormatVerbose)
 
 	s := rep.StartSuite("suite")
-	s.StartTest("test1")
+	s.StartTest("test1", "")
 	s.ReportPass("test1")
-	s.StartTest("test2")
+	s.StartTest("test2", "")
 	s.ReportFail("test2", "failed")
 	s.End()
 
-	err := rep.Summary()
+	if err := rep.Summary(); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	err := rep.Results().Err()
 	if err == nil {
-		t.Error("Expected error for failed tests")
+		t.Fatal("Expected error for failed tests")
 	}
 
 	if !errors.Is(err, errTestsFailed) {
@@ -210,8 +1030,514 @@ func TestReporter_Summary_WithFailures(t *testing.T) {
 		t.Errorf("Expected FAIL in summary, got: %s", output)
 	}
 
-	total, passed, failed := rep.Stats()
+	total, passed, failed, _ := rep.Stats()
 	if total != 2 || passed != 1 || failed != 1 {
 		t.Errorf("Expected stats (2, 1, 1), got (%d, %d, %d)", total, passed, failed)
 	}
 }
+
+func TestReporter_SummaryOutput(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	summary := &bytes.Buffer{}
+	rep := New(out)
+	rep.SetFormat(FormatJSON)
+	rep.SetSummaryOutput(summary)
+
+	s := rep.StartSuite("suite")
+	s.StartTest("test1", "")
+	s.ReportPass("test1")
+	s.StartTest("test2", "")
+	s.ReportFail("test2", "failed")
+	s.StartTest("test3", "")
+	s.ReportSkip("test3", "")
+	s.End()
+
+	_ = rep.Summary()
+
+	if err := rep.Results().Err(); err == nil {
+		t.Error("Expected error for failing tests")
+	}
+
+	want := "--- FAIL: suite/test2\nFAIL\t3 test(s): 1 passed, 1 failed, 1 skipped\n"
+	if diff := cmp.Diff(want, summary.String()); diff != "" {
+		t.Errorf("Summary mismatch (-want +got):\n%s", diff)
+	}
+
+	for line := range strings.Lines(out.String()) {
+		if !strings.HasPrefix(line, "{") {
+			t.Errorf("Expected only JSON events on the main output, got line %q", line)
+		}
+	}
+}
+
+func TestReporter_EmptySuites(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		failOnEmpty bool
+		wantOut     string
+		wantErr     bool
+	}{
+		{
+			name:    "reported",
+			wantOut: "ok  \tempty\t(no tests)\nok  \tsuite\t(1/1)\t0.000s\nEMPTY: 1 suite(s) without tests\n",
+		},
+		{
+			name:        "failing",
+			failOnEmpty: true,
+			wantOut:     "FAIL\tempty\t(no tests)\nok  \tsuite\t(1/1)\t0.000s\nEMPTY: 1 suite(s) without tests\n",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := &bytes.Buffer{}
+			rep := New(out)
+			rep.SetNoTimestamps(true)
+			rep.SetFailOnEmptySuites(tt.failOnEmpty)
+
+			empty := rep.StartSuite("empty")
+			empty.ReportEmpty("no tests")
+			empty.End()
+
+			s := rep.StartSuite("suite")
+			s.StartTest("test1", "")
+			s.ReportPass("test1")
+			s.End()
+
+			if err := rep.Summary(); err != nil {
+				t.Fatalf("Summary() error = %v", err)
+			}
+
+			if err := rep.Results().Err(); (err != nil) != tt.wantErr {
+				t.Errorf("Results().Err() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if diff := cmp.Diff(tt.wantOut, out.String()); diff != "" {
+				t.Errorf("Output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReporter_EmptySuites_JSON(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	rep := New(out)
+	rep.SetFormat(FormatJSON)
+	rep.SetNoTimestamps(true)
+
+	s := rep.StartSuite("empty")
+	s.ReportEmpty("no tests directory")
+	s.End()
+
+	if err := rep.Summary(); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	want := `{"action":"run","package":"empty"}
+{"action":"summary","package":"empty","counts":{"passed":0,"failed":0,"skipped":0}}
+{"action":"pass","package":"empty","output":"no tests directory\n","noTests":true}
+{"action":"pass","counts":{"passed":0,"failed":0,"skipped":0,"emptySuites":1}}
+`
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("JSON mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// suiteBuffer is a suite output recording whether it was closed.
+type suiteBuffer struct {
+	bytes.Buffer
+
+	closed bool
+}
+
+func (b *suiteBuffer) Close() error {
+	b.closed = true
+
+	return nil
+}
+
+func TestReporter_SuiteOutput(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	suites := map[string]*suiteBuffer{}
+	rep := New(out)
+	rep.SetNoTimestamps(true)
+	rep.SetSuiteOutput(func(suite string) (io.WriteCloser, error) {
+		if suite == "broken" {
+			return nil, errors.New("disk full")
+		}
+
+		suites[suite] = &suiteBuffer{}
+
+		return suites[suite], nil
+	})
+
+	for _, name := range []string{"a", "broken", "b"} {
+		s := rep.StartSuite(name)
+		s.StartTest("test1", "")
+		s.ReportFail("test1", "denied")
+		s.End()
+	}
+
+	err := rep.Summary()
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Summary() error = %v, want the suite output error", err)
+	}
+
+	wantA := "\n--- FAIL: a/test1 (0.00s)\n    denied\nFAIL\ta\t(0/1)\t0.000s\n"
+	if diff := cmp.Diff(wantA, suites["a"].String()); diff != "" {
+		t.Errorf("Suite output mismatch (-want +got):\n%s", diff)
+	}
+
+	if !suites["a"].closed || !suites["b"].closed {
+		t.Error("Suite outputs not closed")
+	}
+
+	for _, name := range []string{"a", "broken", "b"} {
+		if !strings.Contains(out.String(), "FAIL\t"+name) {
+			t.Errorf("Main output misses suite %s:\n%s", name, out.String())
+		}
+	}
+}
+
+func TestReporter_SuiteLineCounts(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	rep := New(out)
+	rep.SetNoTimestamps(true)
+
+	suite := rep.StartSuite("suite")
+	suite.StartTest("pass.yaml", "")
+	suite.ReportPass("pass.yaml")
+	suite.StartTest("fail.yaml", "")
+	suite.ReportFail("fail.yaml", "denied")
+	suite.StartTest("skip.yaml", "")
+	suite.ReportSkip("skip.yaml", "not selected")
+	suite.End()
+
+	if want := "FAIL\tsuite\t(1/3)\t0.000s\n"; !strings.HasSuffix(out.String(), want) {
+		t.Errorf("Output = %q, want suite line %q", out.String(), want)
+	}
+}
+
+func TestReporter_EventLog(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	events := &bytes.Buffer{}
+	rep := New(out)
+	rep.SetNoTimestamps(true)
+	rep.AddRenderer(NewJSONRenderer(events))
+
+	suite := rep.StartSuite("suite")
+	suite.StartTest("test.yaml", "")
+	suite.ReportFail("test.yaml", "denied")
+	suite.End()
+	_ = rep.Summary()
+
+	wantOut := "\n--- FAIL: suite/test.yaml (0.00s)\n    denied\nFAIL\tsuite\t(0/1)\t0.000s\n"
+	if diff := cmp.Diff(wantOut, out.String()); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	wantEvents := `{"action":"run","package":"suite"}
+{"action":"run","package":"suite","test":"test.yaml"}
+{"action":"output","package":"suite","test":"test.yaml","output":"denied\n"}
+{"action":"fail","package":"suite","test":"test.yaml"}
+{"action":"summary","package":"suite","counts":{"passed":0,"failed":1,"skipped":0}}
+{"action":"fail","package":"suite"}
+{"action":"fail","counts":{"passed":0,"failed":1,"skipped":0},"policies":{"(no policy)":{"passed":0,"failed":1,"skipped":0}}}
+`
+	if diff := cmp.Diff(wantEvents, events.String()); diff != "" {
+		t.Errorf("event log mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReporter_CSVOutput(t *testing.T) {
+	t.Parallel()
+
+	csvOut := &bytes.Buffer{}
+	rep := New(&bytes.Buffer{})
+	rep.SetNoTimestamps(true)
+	rep.AddRenderer(NewCSVRenderer(csvOut))
+
+	suite := rep.StartSuite("suite")
+
+	suite.StartTest("deny.yaml", "")
+	suite.SetTestPolicy("require-team")
+	suite.SetTestOperation("UPDATE")
+	suite.ReportResult("deny.yaml", &evaluator.TestResult{
+		Passed:   true,
+		Expected: evaluator.TestExpectation{Allowed: false},
+		Actual:   evaluator.TestOutcome{Allowed: false, Message: `missing "team", add it`},
+	}, nil)
+
+	suite.StartTest("broken.yaml", "")
+	suite.ReportFail("broken.yaml", "invalid object:\nline 2")
+
+	suite.StartTest("skipped.yaml", "")
+	suite.ReportSkip("skipped.yaml", "not applicable")
+	suite.End()
+	_ = rep.Summary()
+
+	want := `suite,test,policy,operation,status,expected_allowed,actual_allowed,message,duration_seconds,timestamp
+suite,deny.yaml,require-team,UPDATE,pass,false,false,"missing ""team"", add it",0.000,
+suite,broken.yaml,,,fail,,,"invalid object:
+line 2",0.000,
+suite,skipped.yaml,,,skip,,,not applicable,0.000,
+`
+	if diff := cmp.Diff(want, csvOut.String()); diff != "" {
+		t.Errorf("CSV mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReporter_JUnitOutput(t *testing.T) {
+	t.Parallel()
+
+	junitOut := &bytes.Buffer{}
+	rep := New(&bytes.Buffer{})
+	rep.SetNoTimestamps(true)
+	rep.SetFailOnEmptySuites(true)
+	rep.AddRenderer(NewJUnitRenderer(junitOut))
+
+	suite := rep.StartSuite("suite")
+	suite.StartTest("broken.yaml", "tests/broken.object.yaml")
+	suite.ReportFail("broken.yaml", "invalid object:\nline 2")
+	suite.StartTest("skipped.yaml", "")
+	suite.ReportSkip("skipped.yaml", "not applicable")
+	suite.End()
+
+	empty := rep.StartSuite("empty")
+	empty.ReportEmpty("no tests")
+	empty.End()
+
+	if err := rep.Summary(); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	if err := rep.Results().Err(); err == nil {
+		t.Error("Results().Err() = nil, want the failed test")
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="kat" tests="2" failures="1" errors="1" skipped="1" time="0.000">
+  <testsuite name="suite" tests="2" failures="1" errors="0" skipped="1" time="0.000">
+    <testcase name="broken.yaml" classname="suite" file="tests/broken.object.yaml" time="0.000">
+      <failure message="invalid object:"><![CDATA[invalid object:
+line 2]]></failure>
+    </testcase>
+    <testcase name="skipped.yaml" classname="suite" time="0.000">
+      <skipped message="not applicable"><![CDATA[not applicable]]></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="empty" tests="0" failures="0" errors="1" skipped="0" time="0.000">
+    <system-err>no tests</system-err>
+  </testsuite>
+</testsuites>
+`
+	if diff := cmp.Diff(want, junitOut.String()); diff != "" {
+		t.Errorf("JUnit mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReporter_AddNotRun(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	summary := &bytes.Buffer{}
+	rep := New(buf)
+	rep.SetNoTimestamps(true)
+	rep.SetSummaryOutput(summary)
+
+	s := rep.StartSuite("suite")
+	s.StartTest("test", "")
+	s.ReportFail("test", "denied")
+	rep.AddNotRun(2)
+	s.End()
+	rep.AddNotRun(3)
+
+	if err := rep.Summary(); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	if err := rep.Results().Err(); err == nil {
+		t.Error("Results().Err() = nil, want the failed test")
+	}
+
+	if want := "STOPPED: 5 test(s) not run after 1 failure(s)\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected %q in output, got:\n%s", want, buf.String())
+	}
+
+	if want := "FAIL\t1 test(s): 0 passed, 1 failed, 0 skipped, 5 not run\n"; !strings.HasSuffix(summary.String(), want) {
+		t.Errorf("Expected summary %q, got:\n%s", want, summary.String())
+	}
+}
+
+func TestReporter_HTMLOutput(t *testing.T) {
+	t.Parallel()
+
+	htmlOut := &bytes.Buffer{}
+	rep := New(&bytes.Buffer{})
+	rep.SetNoTimestamps(true)
+	rep.AddRenderer(NewHTMLRenderer(htmlOut))
+
+	suite := rep.StartSuite("suite")
+	suite.StartTest("broken.yaml", "tests/broken.object.yaml")
+	suite.SetTestPolicy("<require-team>")
+	suite.ReportFail("broken.yaml", "--- Expected\n+++ Actual\n@@ -1 +1 @@\n-a\n+b")
+	suite.End()
+
+	empty := rep.StartSuite("empty")
+	empty.ReportEmpty("no tests")
+	empty.End()
+
+	_ = rep.Summary()
+
+	got := htmlOut.String()
+	for _, want := range []string{
+		"<title>kat report: FAIL</title>",
+		`<div class="value fail">1</div><div class="label">failed</div>`,
+		`<td>empty</td><td class="pass">pass (no tests)</td>`,
+		`<tr data-status="fail" data-search="suite/broken.yaml &lt;require-team&gt;">`,
+		`<summary>failure (tests/broken.object.yaml)</summary>`,
+		`<span class="hunk">@@ -1 &#43;1 @@</span><span class="del">-a</span><span class="add">&#43;b</span>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML output does not contain %q", want)
+		}
+	}
+
+	if strings.Contains(got, "generated") {
+		t.Error("HTML output has a generation time without timestamps")
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestCollector_RendererIndependent(t *testing.T) {
+	t.Parallel()
+
+	run := func(rep *Reporter) *Collector {
+		rep.SetNoTimestamps(true)
+		rep.SetFailOnEmptySuites(true)
+		rep.SetMaxFailures(2)
+
+		s := rep.StartSuite("suite")
+		s.StartTest("pass.yaml", "")
+		s.ReportPass("pass.yaml")
+		s.StartTest("fail.yaml", "")
+		s.ReportFail("fail.yaml", "denied")
+		s.End()
+
+		empty := rep.StartSuite("empty")
+		empty.ReportEmpty("no tests")
+		empty.End()
+
+		return rep.Results()
+	}
+
+	plain := New(&bytes.Buffer{})
+	plainResults := run(plain)
+
+	if err := plain.Summary(); err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+
+	rendered := New(&bytes.Buffer{})
+	rendered.SetFormat(FormatJSON)
+
+	for _, r := range []Renderer{
+		NewJSONRenderer(failingWriter{}),
+		NewCSVRenderer(failingWriter{}),
+		NewHTMLRenderer(&bytes.Buffer{}),
+		NewJUnitRenderer(failingWriter{}),
+	} {
+		rendered.AddRenderer(r)
+	}
+
+	renderedResults := run(rendered)
+
+	if err := rendered.Summary(); err == nil || !strings.Contains(err.Error(), "write csv") || !strings.Contains(err.Error(), "write junit") {
+		t.Errorf("Summary() error = %v, want the csv and junit errors", err)
+	}
+
+	if diff := cmp.Diff(plainResults.Counts(), renderedResults.Counts()); diff != "" {
+		t.Errorf("Counts() mismatch (-plain +rendered):\n%s", diff)
+	}
+
+	for name, results := range map[string]*Collector{"plain": plainResults, "rendered": renderedResults} {
+		if err := results.Err(); !errors.Is(err, errTestsFailed) {
+			t.Errorf("%s: Err() = %v, want %v", name, err, errTestsFailed)
+		}
+
+		if results.MaxFailuresReached() {
+			t.Errorf("%s: MaxFailuresReached() = true after 1 of 2 failures", name)
+		}
+	}
+}
+
+func TestReporter_ReportBenchmarks(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+
+	policies := []Benchmark{{Name: "policy-a", Iterations: 10, Total: 10 * time.Microsecond}}
+	expressions := []Benchmark{
+		{Name: "policy-a/fast", Iterations: 10, Total: time.Microsecond},
+		{Name: "policy-a/slow", Iterations: 10, Total: 20 * time.Millisecond},
+	}
+	rep.ReportBenchmarks(policies, expressions, time.Millisecond)
+
+	output := buf.String()
+	for _, want := range []string{"policy/policy-a", "1000", "expression/policy-a/fast"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in benchmark output, got: %s", want, output)
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "policy-a/slow") && !strings.Contains(line, "SLOW") {
+			t.Errorf("Expected slow expression to be flagged, got: %s", line)
+		}
+
+		if strings.Contains(line, "policy-a/fast") && strings.Contains(line, "SLOW") {
+			t.Errorf("Expected fast expression not to be flagged, got: %s", line)
+		}
+	}
+}
+
+func TestReporter_ReportBenchmarks_JSON(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	rep := New(buf)
+	rep.SetFormat(FormatJSON)
+
+	rep.ReportBenchmarks([]Benchmark{{Name: "policy-a", Iterations: 2, Total: 4 * time.Microsecond}}, nil, 0)
+
+	output := buf.String()
+	if !strings.Contains(output, `"action":"bench"`) || !strings.Contains(output, `"test":"policy/policy-a"`) {
+		t.Errorf("Expected bench JSON event, got: %s", output)
+	}
+
+	if !strings.Contains(output, "2000 ns/op") {
+		t.Errorf("Expected ns/op in bench JSON event, got: %s", output)
+	}
+}
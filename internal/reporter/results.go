@@ -0,0 +1,223 @@
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	errTestsFailed = errors.New("tests failed")
+	errEmptySuites = errors.New("suites without tests")
+)
+
+// testRow is the result of a test, as collected for the renderers.
+type testRow struct {
+	suite     string
+	test      string
+	policy    string
+	operation string
+	// status is "pass", "fail" or "skip".
+	status string
+	// expectedAllowed and actualAllowed are empty when the test was not
+	// evaluated.
+	expectedAllowed string
+	actualAllowed   string
+	message         string
+	file            string
+	outcome         *Outcome
+	duration        float64
+	start           time.Time
+}
+
+// suiteRow is the result of a suite, as collected for the renderers.
+type suiteRow struct {
+	name    string
+	counts  Counts
+	failed  bool
+	elapsed float64
+	// noTests explains why the suite has no tests, if it has none.
+	noTests string
+}
+
+// Renderer writes the results of a run in one format, e.g. a JUnit report.
+type Renderer interface {
+	// Render writes the results once the run ended.
+	Render(results *Collector) error
+}
+
+// EventRenderer is a Renderer that also receives the JSON test events as they
+// happen.
+type EventRenderer interface {
+	Renderer
+	Event(event TestEvent)
+}
+
+// Collector collects the result of every suite and test of a run and feeds
+// them to the renderers. Whether the run failed depends on the collected
+// results only, not on the renderers or the text output.
+type Collector struct {
+	tests  []testRow
+	suites []suiteRow
+
+	total   int
+	passed  int
+	failed  int
+	skipped int
+
+	// emptySuites counts the suites without tests.
+	emptySuites int
+	// failOnEmpty makes suites without tests fail.
+	failOnEmpty bool
+	// untestedPolicies is the number of policies without tests.
+	untestedPolicies int
+	// notRun is the number of tests not run because the run stopped early.
+	notRun int
+	// maxFailures is the number of failed tests stopping the run, if not
+	// zero.
+	maxFailures int
+
+	// policyCounts counts the tests of each policy, by name.
+	policyCounts map[string]*Counts
+
+	// elapsed is the duration of the run in seconds, and end its end or zero
+	// without timestamps, set once it ended.
+	elapsed float64
+	end     time.Time
+
+	renderers []Renderer
+}
+
+func newCollector() *Collector {
+	return &Collector{policyCounts: make(map[string]*Counts)}
+}
+
+// Failed reports whether the run failed so far: a test failed, or a suite had
+// no tests with SetFailOnEmptySuites.
+func (c *Collector) Failed() bool {
+	return c.failed > 0 || c.failOnEmpty && c.emptySuites > 0
+}
+
+// Err returns an error when the run failed, naming why.
+func (c *Collector) Err() error {
+	if c.failed > 0 {
+		return fmt.Errorf("%w: %d", errTestsFailed, c.failed)
+	}
+
+	if c.Failed() {
+		return fmt.Errorf("%w: %d", errEmptySuites, c.emptySuites)
+	}
+
+	return nil
+}
+
+// MaxFailuresReached reports whether as many tests failed as
+// SetMaxFailures allows, so that the run should stop.
+func (c *Collector) MaxFailuresReached() bool {
+	return c.maxFailures > 0 && c.failed >= c.maxFailures
+}
+
+// Counts returns the counts of the whole run.
+func (c *Collector) Counts() Counts {
+	return Counts{
+		Passed:           c.passed,
+		Failed:           c.failed,
+		Skipped:          c.skipped,
+		EmptySuites:      c.emptySuites,
+		UntestedPolicies: c.untestedPolicies,
+		NotRun:           c.notRun,
+	}
+}
+
+// policies returns the counts of each policy, or nil without tests.
+func (c *Collector) policies() map[string]Counts {
+	if len(c.policyCounts) == 0 {
+		return nil
+	}
+
+	policies := make(map[string]Counts, len(c.policyCounts))
+	for policy, counts := range c.policyCounts {
+		policies[policy] = *counts
+	}
+
+	return policies
+}
+
+// event passes a JSON test event to the renderers streaming them.
+func (c *Collector) event(event TestEvent) {
+	for _, r := range c.renderers {
+		if er, ok := r.(EventRenderer); ok {
+			er.Event(event)
+		}
+	}
+}
+
+// streamsEvents reports whether a renderer receives the JSON test events.
+func (c *Collector) streamsEvents() bool {
+	for _, r := range c.renderers {
+		if _, ok := r.(EventRenderer); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// render ends the run and writes its results with every renderer.
+func (c *Collector) render(elapsed float64, end time.Time) error {
+	c.elapsed, c.end = elapsed, end
+
+	var errs []error
+
+	for _, r := range c.renderers {
+		if err := r.Render(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// recordTest notes the status of the current test and collects its result.
+func (s *SuiteReporter) recordTest(status, testName, message string, elapsed float64) {
+	s.testStatus, s.testElapsed = status, elapsed
+
+	row := testRow{
+		suite:     s.name,
+		test:      testName,
+		policy:    s.testPolicy,
+		operation: s.testOperation,
+		status:    status,
+		message:   message,
+		file:      s.testFile,
+		outcome:   s.testOutcome,
+		duration:  elapsed,
+	}
+
+	if !s.rep.noTimestamps {
+		row.start = s.testStart
+	}
+
+	if s.testResult != nil {
+		row.expectedAllowed = strconv.FormatBool(s.testResult.Expected.Allowed)
+		if s.testResult.Expected.AnyAllowed {
+			row.expectedAllowed = "any"
+		}
+
+		row.actualAllowed = strconv.FormatBool(s.testResult.Actual.Allowed)
+	}
+
+	s.rep.results.tests = append(s.rep.results.tests, row)
+}
+
+// recordSuite collects the result of the suite.
+func (s *SuiteReporter) recordSuite(failed bool, elapsed float64) {
+	s.rep.results.suites = append(s.rep.results.suites, suiteRow{
+		name:    s.name,
+		counts:  Counts{Passed: s.passedTests, Failed: s.failedTests, Skipped: s.skippedTests},
+		failed:  failed,
+		elapsed: elapsed,
+		noTests: s.noTests,
+	})
+}
@@ -0,0 +1,129 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// policySection is the buffered text output of the tests of a policy, with
+// SetGroupByPolicy.
+type policySection struct {
+	// title names the policy, its kind and binding once a test of the
+	// policy was evaluated.
+	title   string
+	kind    bool
+	out     bytes.Buffer
+	counts  Counts
+	elapsed float64
+}
+
+// SetGroupByPolicy prints the text output of the tests of each policy in a
+// section of its own at the end of the run, instead of suite by suite: the
+// output of the tests indented below a "=== POLICY" line, followed by an
+// ok/FAIL line with the policy's counts. Policies whose tests printed nothing
+// get the ok/FAIL line only. Tests go to the section of the policy they
+// exercised, the mutating policy of a chained test. JSON output is not
+// affected.
+func (r *Reporter) SetGroupByPolicy(group bool) {
+	r.groupByPolicy = group
+	if group && r.sections == nil {
+		r.sections = make(map[string]*policySection)
+	}
+}
+
+// groupsByPolicy reports whether text output is grouped by policy.
+func (r *Reporter) groupsByPolicy() bool {
+	return r.groupByPolicy && r.format != FormatJSON
+}
+
+// startTestOutput buffers the text output of the test starting, with
+// SetGroupByPolicy, after moving that of the previous test to its section.
+func (s *SuiteReporter) startTestOutput() {
+	s.endTestOutput()
+
+	if !s.rep.groupsByPolicy() {
+		return
+	}
+
+	s.testOut = &bytes.Buffer{}
+	s.prevOut = s.rep.out
+	s.rep.out = s.testOut
+}
+
+// endTestOutput moves the buffered output of the current test to the section
+// of its policy, and to the suite's output, if any.
+func (s *SuiteReporter) endTestOutput() {
+	if s.testOut == nil {
+		return
+	}
+
+	s.rep.out = s.prevOut
+	if s.rep.suiteOut != nil {
+		_, _ = s.rep.suiteOut.Write(s.testOut.Bytes())
+	}
+
+	name, title, kind := noPolicy, noPolicy, false
+	if ref := s.policyRef(); ref != nil {
+		name, title, kind = ref.Name, ref.String(), ref.Kind != ""
+	}
+
+	section, ok := s.rep.sections[name]
+	if !ok {
+		section = &policySection{title: title, kind: kind}
+		s.rep.sections[name] = section
+	}
+
+	// Prefer a title naming the kind and binding of an evaluated test.
+	if kind && !section.kind {
+		section.title, section.kind = title, true
+	}
+
+	switch s.testStatus {
+	case "pass":
+		section.counts.Passed++
+	case "fail":
+		section.counts.Failed++
+	case "skip":
+		section.counts.Skipped++
+	}
+
+	section.elapsed += s.testElapsed
+
+	for line := range strings.Lines(s.testOut.String()) {
+		if line != "\n" {
+			section.out.WriteString("    ")
+		}
+
+		section.out.WriteString(line)
+	}
+
+	s.testOut = nil
+}
+
+// printPolicySections prints the section of each policy, by name, with
+// SetGroupByPolicy.
+func (r *Reporter) printPolicySections() {
+	if !r.groupsByPolicy() {
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(r.sections)) {
+		section := r.sections[name]
+		if section.out.Len() > 0 {
+			fmt.Fprintf(r.out, "=== POLICY %s\n", section.title)
+			_, _ = r.out.Write(section.out.Bytes())
+		}
+
+		c := section.counts
+		status := "ok  "
+
+		if c.Failed > 0 {
+			status = "FAIL"
+		}
+
+		fmt.Fprintf(r.out, "%s\t%s\t(%d/%d)\t%.3fs\n", status, name, c.Passed, c.Passed+c.Failed+c.Skipped, section.elapsed)
+	}
+}
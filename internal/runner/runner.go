@@ -0,0 +1,108 @@
+// Package runner pairs loaded test cases with the policies they exercise and
+// evaluates them. It is the single code path shared by the kat CLI and the
+// public pkg/kat API, so both report the same results for the same suites.
+package runner
+
+import (
+	"errors"
+	"fmt"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+var (
+	errNoPolicyName      = errors.New("test file name matches no policy of the suite")
+	errPolicyNotFound    = errors.New("not found")
+	errNotMutatingPolicy = errors.New("is not a mutating policy")
+)
+
+// Policies are the policies and bindings a test case is evaluated against. A
+// chained test has both a mutating and a validating policy.
+type Policies struct {
+	MutatingPolicy    *admissionv1beta1.MutatingAdmissionPolicy
+	MutatingBinding   *admissionv1beta1.MutatingAdmissionPolicyBinding
+	ValidatingPolicy  *admissionregv1.ValidatingAdmissionPolicy
+	ValidatingBinding *admissionregv1.ValidatingAdmissionPolicyBinding
+}
+
+// Find returns the policy of the suite named policyName with its first binding.
+// A mutating policy takes precedence over a validating policy of the same name.
+func Find(suite *loader.TestSuite, policyName string) Policies {
+	var policies Policies
+
+	for _, policy := range suite.MutatingPolicies {
+		if policy.Name == policyName {
+			policies.MutatingPolicy = policy
+			// Find matching binding
+			for _, binding := range suite.MutatingBindings {
+				if binding.Spec.PolicyName == policy.Name {
+					policies.MutatingBinding = binding
+
+					break
+				}
+			}
+
+			break
+		}
+	}
+
+	if policies.MutatingPolicy == nil {
+		for _, policy := range suite.ValidatingPolicies {
+			if policy.Name == policyName {
+				policies.ValidatingPolicy = policy
+				// Find matching binding
+				for _, binding := range suite.ValidatingBindings {
+					if binding.Spec.PolicyName == policy.Name {
+						policies.ValidatingBinding = binding
+
+						break
+					}
+				}
+
+				break
+			}
+		}
+	}
+
+	return policies
+}
+
+// ForTest returns the policies test is evaluated against. The error explains
+// why a test cannot run and is reported as the test's failure.
+func ForTest(suite *loader.TestSuite, test *loader.TestCase) (Policies, error) {
+	if test.PolicyName == "" {
+		return Policies{}, errNoPolicyName
+	}
+
+	policies := Find(suite, test.PolicyName)
+	if policies.MutatingPolicy == nil && policies.ValidatingPolicy == nil {
+		return Policies{}, fmt.Errorf("policy %q %w", test.PolicyName, errPolicyNotFound)
+	}
+
+	if test.ChainPolicyName == "" {
+		return policies, nil
+	}
+
+	if policies.MutatingPolicy == nil {
+		return Policies{}, fmt.Errorf("policy %q %w", test.PolicyName, errNotMutatingPolicy)
+	}
+
+	chained := Find(suite, test.ChainPolicyName)
+	if chained.ValidatingPolicy == nil {
+		return Policies{}, fmt.Errorf("validating policy %q %w", test.ChainPolicyName, errPolicyNotFound)
+	}
+
+	policies.ValidatingPolicy = chained.ValidatingPolicy
+	policies.ValidatingBinding = chained.ValidatingBinding
+
+	return policies, nil
+}
+
+// Evaluate evaluates test against the policies.
+func (p Policies) Evaluate(eval *evaluator.Evaluator, test *loader.TestCase) *evaluator.TestResult {
+	return eval.EvaluateTest(p.MutatingPolicy, p.MutatingBinding, p.ValidatingPolicy, p.ValidatingBinding, test)
+}
@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+func TestForTest(t *testing.T) {
+	t.Parallel()
+
+	suite := &loader.TestSuite{
+		MutatingPolicies: []*admissionv1beta1.MutatingAdmissionPolicy{
+			{ObjectMeta: metav1.ObjectMeta{Name: "add-label"}},
+		},
+		ValidatingPolicies: []*admissionregv1.ValidatingAdmissionPolicy{
+			{ObjectMeta: metav1.ObjectMeta{Name: "require-label"}},
+		},
+		ValidatingBindings: []*admissionregv1.ValidatingAdmissionPolicyBinding{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "require-label-binding"},
+				Spec:       admissionregv1.ValidatingAdmissionPolicyBindingSpec{PolicyName: "require-label"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		test           *loader.TestCase
+		wantErr        error
+		wantMutating   string
+		wantValidating string
+		wantBinding    string
+	}{
+		{
+			name:         "mutating",
+			test:         &loader.TestCase{PolicyName: "add-label"},
+			wantMutating: "add-label",
+		},
+		{
+			name:           "validating with binding",
+			test:           &loader.TestCase{PolicyName: "require-label"},
+			wantValidating: "require-label",
+			wantBinding:    "require-label-binding",
+		},
+		{
+			name:           "chain",
+			test:           &loader.TestCase{PolicyName: "add-label", ChainPolicyName: "require-label"},
+			wantMutating:   "add-label",
+			wantValidating: "require-label",
+			wantBinding:    "require-label-binding",
+		},
+		{
+			name:    "no policy name",
+			test:    &loader.TestCase{},
+			wantErr: errNoPolicyName,
+		},
+		{
+			name:    "unknown policy",
+			test:    &loader.TestCase{PolicyName: "missing"},
+			wantErr: errPolicyNotFound,
+		},
+		{
+			name:    "chain from validating policy",
+			test:    &loader.TestCase{PolicyName: "require-label", ChainPolicyName: "require-label"},
+			wantErr: errNotMutatingPolicy,
+		},
+		{
+			name:    "chain to unknown policy",
+			test:    &loader.TestCase{PolicyName: "add-label", ChainPolicyName: "missing"},
+			wantErr: errPolicyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policies, err := ForTest(suite, tt.test)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ForTest() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := policyName(policies.MutatingPolicy); got != tt.wantMutating {
+				t.Errorf("MutatingPolicy = %q, want %q", got, tt.wantMutating)
+			}
+
+			if got := policyName(policies.ValidatingPolicy); got != tt.wantValidating {
+				t.Errorf("ValidatingPolicy = %q, want %q", got, tt.wantValidating)
+			}
+
+			if got := policyName(policies.ValidatingBinding); got != tt.wantBinding {
+				t.Errorf("ValidatingBinding = %q, want %q", got, tt.wantBinding)
+			}
+		})
+	}
+}
+
+func policyName[T interface{ GetName() string }](obj T) string {
+	var zero T
+	if any(obj) == any(zero) {
+		return ""
+	}
+
+	return obj.GetName()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+)
+
+// listLibraries prints the CEL libraries of the evaluator environment at
+// kubeVersion, one per line with the Kubernetes version introducing it and
+// the functions it adds.
+func listLibraries(kubeVersion *utilversion.Version, stdout io.Writer) error {
+	libraries, err := evaluator.Libraries(kubeVersion)
+	if err != nil {
+		return fmt.Errorf("list CEL libraries: %w", err)
+	}
+
+	for _, library := range libraries {
+		var since string
+
+		switch library.Introduced {
+		case "":
+			since = "not in the API server"
+		case "1.0":
+			since = "all Kubernetes versions"
+		default:
+			since = "Kubernetes " + library.Introduced + "+"
+		}
+
+		functions := "no new functions"
+		if len(library.Functions) > 0 {
+			functions = strings.Join(library.Functions, ", ")
+		}
+
+		fmt.Fprintf(stdout, "%s (%s): %s\n", library.Name, since, functions)
+	}
+
+	return nil
+}
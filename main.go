@@ -2,91 +2,592 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
 	"runtime/debug"
+	"runtime/pprof"
+	"time"
 
-	admissionregv1 "k8s.io/api/admissionregistration/v1"
-	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 
 	"github.com/zemanlx/kat/internal/evaluator"
 	"github.com/zemanlx/kat/internal/loader"
 	"github.com/zemanlx/kat/internal/reporter"
+	"github.com/zemanlx/kat/internal/runner"
 )
 
 const defaultVersion = "(devel)"
 
+// Exit codes follow go test: 1 when tests fail, 2 when kat cannot run them.
+const (
+	exitTestsFailed = 1
+	exitSetupFailed = 2
+)
+
+var (
+	// errUsage marks invalid flags or configuration.
+	errUsage = errors.New("usage error")
+	// errLoad marks failures to load policies or test suites.
+	errLoad = errors.New("load error")
+
+	errConflictingKindFilters  = errors.New("-only-mutating and -only-validating are mutually exclusive")
+	errNegativeNestingLimit    = errors.New("-max-comprehension-nesting must not be negative")
+	errNegativeMaxTestDuration = errors.New("-max-test-duration must not be negative")
+	errNegativeMaxFailures     = errors.New("-max-failures must not be negative")
+	errFailfastMaxFailures     = errors.New("-failfast stops after 1 failure and conflicts with -max-failures above 1")
+	errStrictWarnings          = errors.New("load warnings are errors with -strict")
+	errUntestedPolicies        = errors.New("policies without tests are errors with -require-tests")
+	errPoliciesDirWithPaths    = errors.New("-policies-dir cannot be combined with path arguments")
+	errTestsDirNeedsPolicies   = errors.New("-tests-dir requires -policies-dir")
+	errLintNeedsValidateOnly   = errors.New("-lint requires -validate-only")
+)
+
 var version = defaultVersion
 
 type config struct {
 	runPattern string
+	runExact   bool
 	verbose    bool
 	jsonOutput bool
-	version    bool
-	testPaths  []string
+	summary    bool
+	// groupFailures prints identical failure messages once.
+	groupFailures bool
+	byPolicy      bool
+	// groupByPolicy prints text output in sections per policy.
+	groupByPolicy bool
+	version       bool
+	libraries     bool
+	testPaths     []string
+	testsDir      string
+	// dirs limit discovered suites to these paths relative to testPaths.
+	dirs []string
+	// suitePerPolicy splits suites into one suite per policy.
+	suitePerPolicy bool
+	// defaultNamespace is set on namespaced objects without a namespace.
+	defaultNamespace string
+
+	bench     bool
+	benchtime benchtime
+	benchSlow time.Duration
+
+	maxTestDuration time.Duration
+	// maxFailures stops the run after this many failed tests (0 = unlimited).
+	maxFailures int
+
+	cpuProfile string
+	memProfile string
+
+	createParents bool
+	noWarnings    bool
+
+	checkReinvocation bool
+	checkDeterminism  bool
+	compareQuantities bool
+
+	onlyMutating   bool
+	onlyValidating bool
+
+	auditPolicyPrefix bool
+
+	record      bool
+	kubeconfig  string
+	kubeContext string
+
+	compareFile string
+
+	requireGold bool
+	// keepLongWarnings disables the API server's truncation of warnings.
+	keepLongWarnings bool
+	// deleteObjectFallback binds object to oldObject on DELETE.
+	deleteObjectFallback bool
+	// traceCEL prints the AST of each expression to stderr.
+	traceCEL bool
+
+	noTimestamps bool
+
+	strict      bool
+	failOnEmpty bool
+	// warnTypeChecking reports the type checking warnings of policies.
+	warnTypeChecking bool
+
+	requireTests bool
+
+	kubeVersion *utilversion.Version
+
+	validateOnly bool
+	lint         bool
+
+	maxComprehensionNesting int
+	costLimit               uint64
+
+	dumpFailures string
+	outputDir    string
+	eventLog     string
+	csvFile      string
+	htmlFile     string
+	junitFile    string
+
+	extraVars []evaluator.ExtraVariable
+
+	// stripFields are the field paths removed from test objects.
+	stripFields [][]string
 }
 
 func main() {
-	if err := run(context.Background(), os.Args, os.Getenv, os.Stdin, os.Stdout); err != nil {
+	if err := run(context.Background(), os.Args, os.Getenv, os.Stdin, os.Stdout, os.Stderr); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode maps an error returned by run to the process exit code.
+func exitCode(err error) int {
+	if errors.Is(err, errUsage) || errors.Is(err, errLoad) {
+		return exitSetupFailed
 	}
+
+	return exitTestsFailed
 }
 
-// run is testable: inject args/getenv/stdin/stdout.
-func run(_ context.Context, args []string, _ func(string) string, _ *os.File, stdout *os.File) error {
+// run is testable: inject args/getenv/stdin/stdout/stderr.
+func run(ctx context.Context, args []string, _ func(string) string, _ *os.File, stdout, stderr *os.File) (err error) {
+	if len(args) > 1 && args[1] == "docs" {
+		return runDocs(args, stdout)
+	}
+
+	if len(args) > 1 && args[1] == "gen" {
+		return runGen(args, stdout)
+	}
+
+	if len(args) > 1 && args[1] == "version" {
+		return runVersion(args, stdout)
+	}
+
+	if len(args) > 1 && args[1] == "check" {
+		return runCheck(ctx, args, stdout, stderr)
+	}
+
 	cfg, err := parseFlags(args, stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", errUsage, err)
 	}
 
 	if cfg.version {
-		fmt.Fprintln(stdout, getVersion())
+		return printVersion(stdout, buildInfo())
+	}
 
-		return nil
+	if cfg.libraries {
+		return listLibraries(cfg.kubeVersion, stdout)
+	}
+
+	stopProfiling, err := startProfiling(cfg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
 	}
 
-	suites, err := loadSuites(cfg.testPaths, cfg.runPattern)
+	// Profiles are written even when tests fail.
+	defer func() {
+		err = errors.Join(err, stopProfiling())
+	}()
+
+	if cfg.validateOnly {
+		return validateOnly(cfg, stdout, stderr)
+	}
+
+	suites, err := loadSuites(cfg.testPaths, loader.Options{
+		Pattern:          cfg.runPattern,
+		ExactMatch:       cfg.runExact,
+		TestsDir:         cfg.testsDir,
+		Dirs:             cfg.dirs,
+		SuitePerPolicy:   cfg.suitePerPolicy,
+		DefaultNamespace: cfg.defaultNamespace,
+		StripFields:      cfg.stripFields,
+	})
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", errLoad, err)
 	}
 
-	return executeTests(suites, cfg, stdout)
+	suites = filterSuitesByPolicyKind(suites, cfg)
+
+	if err := reportLoadWarnings(suites, cfg, stderr); err != nil {
+		return fmt.Errorf("%w: %w", errLoad, err)
+	}
+
+	untested, err := untestedPolicies(suites, cfg.requireTests, stderr)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errLoad, err)
+	}
+
+	if cfg.record {
+		return recordResponses(ctx, suites, cfg, stdout, stderr)
+	}
+
+	if cfg.compareFile != "" {
+		return comparePolicies(suites, cfg, stdout, stderr)
+	}
+
+	return executeTests(ctx, suites, untested, cfg, stdout, stderr)
+}
+
+// startProfiling starts CPU profiling when requested and returns a function
+// that stops it and writes the memory profile.
+func startProfiling(cfg *config) (func() error, error) {
+	var cpuFile *os.File
+
+	if cfg.cpuProfile != "" {
+		f, err := os.Create(cfg.cpuProfile)
+		if err != nil {
+			return nil, fmt.Errorf("create cpu profile: %w", err)
+		}
+
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+
+			return nil, fmt.Errorf("start cpu profile: %w", err)
+		}
+
+		cpuFile = f
+	}
+
+	return func() error {
+		var errs []error
+
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+
+			if err := cpuFile.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close cpu profile: %w", err))
+			}
+		}
+
+		if cfg.memProfile != "" {
+			if err := writeMemProfile(cfg.memProfile); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	}, nil
+}
+
+func writeMemProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create memory profile: %w", err)
+	}
+	defer f.Close()
+
+	// Get up-to-date statistics, as go test does.
+	runtime.GC()
+
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("write memory profile: %w", err)
+	}
+
+	return nil
+}
+
+// parseFlagSet parses args with fs. Only -h prints the usage, to stdout; other
+// errors are left to the caller, so that main prints them once, to stderr.
+func parseFlagSet(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+	fs.SetOutput(io.Discard)
+
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		fs.SetOutput(stdout)
+		fs.Usage()
+	}
+
+	return err //nolint:wrapcheck // callers wrap it
 }
 
 func parseFlags(args []string, stdout *os.File) (*config, error) {
-	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
-	fs.SetOutput(stdout)
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+
+	runPattern := fs.String("run", "", "run only tests matching pattern (test regexp or suite/test)")
+	runExact := fs.Bool("run-exact", false, "match -run parts as exact names instead of regular expressions")
+
+	var dirsFlag dirFlags
+	fs.Var(&dirsFlag, "dir", "run only suites below `prefix`, relative to the path arguments (repeatable)")
+	suitePerPolicy := fs.Bool("suite-per-policy", false, "run the tests of each policy as a suite of its own, named <dir>/<policy>")
+	defaultNamespace := fs.String("default-namespace", "default", "`namespace` of requests and objects of namespaced built-in kinds that omit it; empty keeps them without one")
 
-	runPattern := fs.String("run", "", "run only tests matching pattern")
 	verbose := fs.Bool("v", false, "verbose output")
 	jsonOutput := fs.Bool("json", false, "output test results in JSON format")
-	showVersion := fs.Bool("version", false, "print version and exit")
+	summary := fs.Bool("summary", false, "also print a plain-text pass/fail summary to stderr, e.g. alongside -json")
+	byPolicy := fs.Bool("by-policy", false, "print the number of tests run, passed and failed of each policy at the end")
+	groupByPolicy := fs.Bool("group-by-policy", false, "print the output of the tests of each policy in a section of its own instead of per suite")
+	groupFailures := fs.Bool("group-failures", false, "print identical failure messages once and list the tests sharing them (default true without -v and -json)")
+	showVersion := fs.Bool("version", false, "print version and build information and exit, like kat version")
+	listLibrariesFlag := fs.Bool("list-libraries", false, "print the CEL libraries and functions available to expressions (see -kube-version) and exit")
+	bench := fs.Bool("bench", false, "benchmark policy evaluation latency")
+	benchtimeFlag := fs.String("benchtime", "100x", "run each benchmark for duration d or N times (Nx)")
+	cpuProfile := fs.String("cpuprofile", "", "write a CPU profile to `file`")
+	memProfile := fs.String("memprofile", "", "write a memory profile to `file`")
+	createParents := fs.Bool("create-parents", false, "create missing parent maps for JSONPatch add operations (not done by the API server)")
+	noWarnings := fs.Bool("assert-no-unexpected-warnings", false, "fail tests that produce warnings without a .warnings.txt expectation")
+	compareQuantities := fs.Bool("compare-quantities", false, "compare resource quantities of expected objects by value, so 1024Mi matches 1Gi")
+	checkReinvocation := fs.Bool("check-reinvocation", false, "reapply mutating policies with reinvocationPolicy IfNeeded to their output and fail if the object changes again")
+	checkDeterminism := fs.Bool("check-determinism", false, "evaluate each test twice and fail it if the two outcomes differ")
+	benchSlow := fs.Duration("bench-slow", time.Millisecond, "flag expressions slower than this per evaluation (0 disables)")
+	maxTestDuration := fs.Duration("max-test-duration", 0, "fail tests whose policy evaluation takes longer than `d`, e.g. 5ms; a suite's kat.yaml maxTestDuration overrides it (0 disables)")
+	maxFailures := fs.Int("max-failures", 0, "stop running tests after `n` failures and report how many were not run (0 = unlimited)")
+	failfast := fs.Bool("failfast", false, "stop running tests after the first failure, like -max-failures 1")
+	onlyMutating := fs.Bool("only-mutating", false, "run only tests of mutating policies (including chained tests)")
+	onlyValidating := fs.Bool("only-validating", false, "run only tests of validating policies (including chained tests)")
+	auditPolicyPrefix := fs.Bool("audit-policy-prefix", false, "record audit annotation keys as <policy-name>/<key>, as in the audit log")
+	record := fs.Bool("record", false, "record cluster admission responses as .response.yaml files instead of running tests")
+	compareFile := fs.String("compare", "", "instead of running tests, evaluate each test against the policy of the same name in `file` too and fail tests whose decision differs")
+	noTimestamps := fs.Bool("no-timestamps", false, "omit event times and report zero durations, for reproducible output")
+	traceCEL := fs.Bool("trace-cel", false, "print the AST of each CEL expression to stderr when it is first compiled, to show how it parsed")
+	noWarningTruncation := fs.Bool("no-warning-truncation", false, "report warnings in full instead of truncating them as the API server does beyond 4096 characters per response")
+	deleteObjectFallback := fs.Bool("delete-object-fallback", false, "bind object to the deleted object on DELETE in validating policies, as kat did before, instead of null as the API server does")
+	requireGold := fs.Bool("require-gold", false, "fail mutating tests without a .gold.yaml expected object")
+	kubeVersionFlag := fs.String("kube-version", "latest", "limit CEL libraries to those of a Kubernetes `version` (e.g. 1.28)")
+	validateOnlyFlag := fs.Bool("validate-only", false, "load all policies and tests and report every problem without running tests")
+	lintFlag := fs.Bool("lint", false, "with -validate-only, also report field accesses on object, oldObject and params not guarded by has() or optional access")
+	maxNesting := fs.Int("max-comprehension-nesting", 0, "fail expressions nesting more than `n` comprehensions such as all() or map() (0 disables)")
+	costLimit := fs.Uint64("cost-limit", evaluator.DefaultCostLimit, "fail expression evaluations exceeding this runtime `cost`; the default is the API server's limit per expression (0 disables)")
+	var extraVars extraVarFlags
+	fs.Var(&extraVars, "extra-var", "declare an extra CEL variable as `name=<cel-expression-or-file>`, for API servers with custom variables (repeatable)")
+	stripServerFields := fs.Bool("strip-server-fields", false, "remove fields the API server populates, such as metadata.managedFields, from test objects and gold files")
+	var stripFieldsFlag stripFieldFlags
+	fs.Var(&stripFieldsFlag, "strip-field", "remove the field at `path` (e.g. metadata.labels[example.com/owner]) from test objects and gold files (repeatable)")
+	outputDir := fs.String("output-dir", "", "also write the output of each suite to `dir`/<suite>.txt, or <suite>.json with -json")
+	csvFile := fs.String("csv", "", "also write a CSV row per test to `file`, e.g. as compliance evidence")
+	htmlFile := fs.String("html", "", "also write a self-contained HTML report to `file`, e.g. as a CI artifact")
+	eventLog := fs.String("event-log", "", "also write the test events of -json to `file`, whatever the output format")
+
+	var outputsFlag outputFlags
+	fs.Var(&outputsFlag, "output", "write results as `format[=file]`: text or json to stdout, or json, junit, csv or html to a file (repeatable)")
+	dumpFailures := fs.String("dump-failures", "", "write expected and actual objects, patch and message of failing tests below `dir`")
+	policiesDir := fs.String("policies-dir", "", "load policies from `dir` instead of the path arguments")
+	testsDir := fs.String("tests-dir", "", "load the tests of each suite below -policies-dir from the same relative path below `dir`")
+	requireTests := fs.Bool("require-tests", false, "fail when a policy has no test, listing the untested policies")
+	failOnEmpty := fs.Bool("fail-on-empty-suites", false, "fail suites that have no tests, e.g. because of a misnamed tests directory")
+	strict := fs.Bool("strict", false, "fail when loading produces warnings, such as test files matching no policy")
+	warnDeprecations := fs.Bool("warn-deprecations", false, "warn about expressions the API server's type checking flags against the schemas of the suite's CRDs")
+	kubeconfig := fs.String("kubeconfig", "", "kubeconfig `file` for -record and cluster params (default $KUBECONFIG or ~/.kube/config)")
+	kubeContext := fs.String("context", "", "kubeconfig `context` for -record and cluster params (default current context)")
+	configFile := fs.String("config", "", "read default flag values from `file` (default .kat.yaml in the working directory, if any)")
+
+	if err := parseFlagSet(fs, args[1:], stdout); err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
 
-	if err := fs.Parse(args[1:]); err != nil {
+	if err := applyConfigFile(fs, *configFile); err != nil {
 		return nil, fmt.Errorf("parse flags: %w", err)
 	}
 
-	testPaths := []string{"."}
-	if fs.NArg() > 0 {
-		testPaths = fs.Args()
+	if *onlyMutating && *onlyValidating {
+		return nil, errConflictingKindFilters
+	}
+
+	bt, err := parseBenchtime(*benchtimeFlag)
+	if err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
+
+	if *lintFlag && !*validateOnlyFlag {
+		return nil, errLintNeedsValidateOnly
+	}
+
+	if *maxNesting < 0 {
+		return nil, errNegativeNestingLimit
+	}
+
+	if *maxTestDuration < 0 {
+		return nil, errNegativeMaxTestDuration
+	}
+
+	if *maxFailures < 0 {
+		return nil, errNegativeMaxFailures
+	}
+
+	if *failfast {
+		if *maxFailures > 1 {
+			return nil, errFailfastMaxFailures
+		}
+
+		*maxFailures = 1
+	}
+
+	kubeVersion, err := parseKubeVersion(*kubeVersionFlag)
+	if err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
+
+	extraVariables, err := parseExtraVars(extraVars)
+	if err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
+
+	stripFields, err := parseStripFields(*stripServerFields, stripFieldsFlag)
+	if err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
+
+	testPaths, err := suitePaths(fs.Args(), *policiesDir, *testsDir)
+	if err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
+
+	dirs, err := parseDirs(dirsFlag)
+	if err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
+
+	outs, err := parseOutputs(outputsFlag, outputs{json: *jsonOutput, eventLog: *eventLog, csvFile: *csvFile, htmlFile: *htmlFile})
+	if err != nil {
+		return nil, fmt.Errorf("parse flags: %w", err)
+	}
+
+	// Grouping defaults to on only for the default format, where failures
+	// are all that is printed.
+	if !isFlagSet(fs, "group-failures") {
+		*groupFailures = !*verbose && !outs.json
 	}
 
 	return &config{
 		runPattern: *runPattern,
+		runExact:   *runExact,
 		verbose:    *verbose,
-		jsonOutput: *jsonOutput,
-		version:    *showVersion,
-		testPaths:  testPaths,
+		jsonOutput: outs.json,
+		summary:    *summary,
+
+		groupFailures: *groupFailures,
+		byPolicy:      *byPolicy,
+		groupByPolicy: *groupByPolicy,
+		version:       *showVersion,
+		libraries:     *listLibrariesFlag,
+		testPaths:     testPaths,
+		testsDir:      *testsDir,
+		dirs:          dirs,
+
+		suitePerPolicy:   *suitePerPolicy,
+		defaultNamespace: *defaultNamespace,
+
+		bench:     *bench,
+		benchtime: bt,
+		benchSlow: *benchSlow,
+
+		maxTestDuration: *maxTestDuration,
+		maxFailures:     *maxFailures,
+		cpuProfile:      *cpuProfile,
+		memProfile:      *memProfile,
+
+		createParents: *createParents,
+		noWarnings:    *noWarnings,
+
+		checkReinvocation: *checkReinvocation,
+		checkDeterminism:  *checkDeterminism,
+		compareQuantities: *compareQuantities,
+
+		onlyMutating:   *onlyMutating,
+		onlyValidating: *onlyValidating,
+
+		auditPolicyPrefix: *auditPolicyPrefix,
+
+		record:      *record,
+		compareFile: *compareFile,
+		kubeconfig:  *kubeconfig,
+		kubeContext: *kubeContext,
+
+		requireGold: *requireGold,
+
+		keepLongWarnings:     *noWarningTruncation,
+		deleteObjectFallback: *deleteObjectFallback,
+		traceCEL:             *traceCEL,
+
+		noTimestamps: *noTimestamps,
+
+		strict:      *strict,
+		failOnEmpty: *failOnEmpty,
+
+		warnTypeChecking: *warnDeprecations,
+
+		requireTests: *requireTests,
+
+		kubeVersion: kubeVersion,
+
+		validateOnly: *validateOnlyFlag,
+		lint:         *lintFlag,
+
+		maxComprehensionNesting: *maxNesting,
+		costLimit:               *costLimit,
+
+		dumpFailures: *dumpFailures,
+		outputDir:    *outputDir,
+		eventLog:     outs.eventLog,
+		csvFile:      outs.csvFile,
+		htmlFile:     outs.htmlFile,
+		junitFile:    outs.junitFile,
+
+		extraVars: extraVariables,
+
+		stripFields: stripFields,
 	}, nil
 }
 
-func loadSuites(paths []string, pattern string) ([]*loader.TestSuite, error) {
+// isFlagSet reports whether the flag name was given on the command line.
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+// parseKubeVersion parses a -kube-version value. "latest" returns nil; other
+// values are reduced to their major.minor version.
+func parseKubeVersion(value string) (*utilversion.Version, error) {
+	if value == "latest" {
+		return nil, nil //nolint:nilnil // nil selects the latest environment
+	}
+
+	v, err := utilversion.ParseGeneric(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -kube-version %q: %w", value, err)
+	}
+
+	return utilversion.MajorMinor(v.Major(), v.Minor()), nil
+}
+
+// suitePaths returns the paths to load suites from: the path arguments or
+// policiesDir, defaulting to the current directory. A separate testsDir maps
+// suites by their path below a single policies root, so it needs policiesDir.
+func suitePaths(args []string, policiesDir, testsDir string) ([]string, error) {
+	switch {
+	case policiesDir != "" && len(args) > 0:
+		return nil, errPoliciesDirWithPaths
+	case testsDir != "" && policiesDir == "":
+		return nil, errTestsDirNeedsPolicies
+	case policiesDir != "":
+		return []string{policiesDir}, nil
+	case len(args) > 0:
+		return args, nil
+	default:
+		return []string{"."}, nil
+	}
+}
+
+func loadSuites(paths []string, opts loader.Options) ([]*loader.TestSuite, error) {
 	var suites []*loader.TestSuite
 
 	for _, path := range paths {
-		pathSuites, err := loader.Load(path, pattern)
+		pathSuites, err := loader.Load(path, opts)
 		if err != nil {
 			return nil, fmt.Errorf("load test suites from %s: %w", path, err)
 		}
@@ -97,29 +598,274 @@ func loadSuites(paths []string, pattern string) ([]*loader.TestSuite, error) {
 	return suites, nil
 }
 
-func executeTests(suites []*loader.TestSuite, cfg *config, stdout *os.File) error {
-	eval, err := evaluator.New()
+// reportLoadWarnings prints the load warnings and policy problems of all suites
+// to stderr, with -warn-deprecations their type checking warnings, and the
+// directories of -tests-dir without a suite. With -strict, any of them is an
+// error.
+func reportLoadWarnings(suites []*loader.TestSuite, cfg *config, stderr io.Writer) error {
+	count := 0
+
+	if cfg.testsDir != "" {
+		// -tests-dir requires -policies-dir, the only path then.
+		policiesDir := cfg.testPaths[0]
+
+		orphans, err := loader.OrphanTestDirs(policiesDir, cfg.testsDir)
+		if err != nil {
+			return err
+		}
+
+		for _, dir := range orphans {
+			fmt.Fprintf(stderr, "warning: %s: tests without a suite in %s\n", dir, policiesDir)
+
+			count++
+		}
+	}
+
+	for _, suite := range suites {
+		for _, warning := range suite.Warnings {
+			fmt.Fprintf(stderr, "warning: %s\n", warning)
+
+			count++
+		}
+
+		for _, problem := range suite.PolicyProblems {
+			fmt.Fprintf(stderr, "warning: %s: %v\n", suite.Name, problem)
+
+			count++
+		}
+
+		if cfg.warnTypeChecking {
+			for _, warning := range suite.TypeCheckWarnings {
+				fmt.Fprintf(stderr, "warning: %s\n", warning)
+
+				count++
+			}
+		}
+	}
+
+	if cfg.strict && count > 0 {
+		return fmt.Errorf("%w: %d warning(s)", errStrictWarnings, count)
+	}
+
+	return nil
+}
+
+// untestedPolicies counts the policies no test evaluates. With requireTests,
+// it lists them on stderr and returns an error if there are any.
+func untestedPolicies(suites []*loader.TestSuite, requireTests bool, stderr io.Writer) (int, error) {
+	count := 0
+
+	for _, suite := range suites {
+		for _, policy := range suite.UntestedPolicies {
+			if requireTests {
+				fmt.Fprintf(stderr, "untested: %s: %s\n", suite.Name, policy)
+			}
+
+			count++
+		}
+	}
+
+	if requireTests && count > 0 {
+		return count, fmt.Errorf("%w: %d untested policies", errUntestedPolicies, count)
+	}
+
+	return count, nil
+}
+
+// filterSuitesByPolicyKind keeps only tests whose policy is of the kind selected
+// by -only-mutating or -only-validating. Chained tests exercise both kinds and
+// are kept by either filter. Suites left without tests are dropped.
+func filterSuitesByPolicyKind(suites []*loader.TestSuite, cfg *config) []*loader.TestSuite {
+	if !cfg.onlyMutating && !cfg.onlyValidating {
+		return suites
+	}
+
+	filtered := make([]*loader.TestSuite, 0, len(suites))
+
+	for _, suite := range suites {
+		tests := make([]*loader.TestCase, 0, len(suite.Tests))
+
+		for _, test := range suite.Tests {
+			policies := runner.Find(suite, test.PolicyName)
+
+			keep := test.ChainPolicyName != "" ||
+				(cfg.onlyMutating && policies.MutatingPolicy != nil) ||
+				(cfg.onlyValidating && policies.ValidatingPolicy != nil)
+			if keep {
+				tests = append(tests, test)
+			}
+		}
+
+		if len(tests) > 0 {
+			suite.Tests = tests
+			filtered = append(filtered, suite)
+		}
+	}
+
+	return filtered
+}
+
+// evaluatorOptions returns the evaluator options selected by the flags.
+func evaluatorOptions(cfg *config, stderr io.Writer) []evaluator.Option {
+	var evalOpts []evaluator.Option
+
+	if cfg.createParents {
+		evalOpts = append(evalOpts, evaluator.WithCreateMissingParents())
+	}
+
+	if cfg.noWarnings {
+		evalOpts = append(evalOpts, evaluator.WithAssertNoUnexpectedWarnings())
+	}
+
+	if cfg.checkReinvocation {
+		evalOpts = append(evalOpts, evaluator.WithReinvocationCheck())
+	}
+
+	if cfg.checkDeterminism {
+		evalOpts = append(evalOpts, evaluator.WithDeterminismCheck())
+	}
+
+	if cfg.compareQuantities {
+		evalOpts = append(evalOpts, evaluator.WithQuantityComparison())
+	}
+
+	if cfg.auditPolicyPrefix {
+		evalOpts = append(evalOpts, evaluator.WithPolicyNameAuditAnnotationKeys())
+	}
+
+	if cfg.requireGold {
+		evalOpts = append(evalOpts, evaluator.WithRequireGold())
+	}
+
+	if cfg.keepLongWarnings {
+		evalOpts = append(evalOpts, evaluator.WithoutWarningTruncation())
+	}
+
+	if cfg.deleteObjectFallback {
+		evalOpts = append(evalOpts, evaluator.WithDeleteObjectFallback())
+	}
+
+	if cfg.traceCEL {
+		evalOpts = append(evalOpts, evaluator.WithCELTrace(stderr))
+	}
+
+	if cfg.kubeVersion != nil {
+		evalOpts = append(evalOpts, evaluator.WithKubeVersion(cfg.kubeVersion))
+	}
+
+	if cfg.maxComprehensionNesting > 0 {
+		evalOpts = append(evalOpts, evaluator.WithComprehensionNestingLimit(cfg.maxComprehensionNesting))
+	}
+
+	evalOpts = append(evalOpts, evaluator.WithCostLimit(cfg.costLimit))
+
+	if len(cfg.extraVars) > 0 {
+		evalOpts = append(evalOpts, evaluator.WithExtraVariables(cfg.extraVars...))
+	}
+
+	return evalOpts
+}
+
+// executeTests runs the tests of suites. untested is the number of policies
+// without tests, reported in the summary.
+func executeTests(ctx context.Context, suites []*loader.TestSuite, untested int, cfg *config, stdout, stderr *os.File) (err error) {
+	var bench *benchmarks
+
+	evalOpts := evaluatorOptions(cfg, stderr)
+
+	if cfg.bench {
+		evalOpts = append(evalOpts, evaluator.WithTimings())
+		bench = newBenchmarks()
+	}
+
+	eval, err := evaluator.New(evalOpts...)
 	if err != nil {
-		return fmt.Errorf("create evaluator: %w", err)
+		return fmt.Errorf("%w: create evaluator: %w", errUsage, err)
 	}
 
 	rep := reporter.New(stdout)
-	configureReporter(rep, cfg)
+	configureReporter(rep, cfg, stderr)
+	rep.SetUntestedPolicies(untested)
+
+	for _, output := range []struct {
+		file        string
+		name        string
+		newRenderer func(io.Writer) reporter.Renderer
+	}{
+		{cfg.eventLog, "event log", func(w io.Writer) reporter.Renderer { return reporter.NewJSONRenderer(w) }},
+		{cfg.csvFile, "csv file", func(w io.Writer) reporter.Renderer { return reporter.NewCSVRenderer(w) }},
+		{cfg.htmlFile, "html file", func(w io.Writer) reporter.Renderer { return reporter.NewHTMLRenderer(w) }},
+		{cfg.junitFile, "junit file", func(w io.Writer) reporter.Renderer { return reporter.NewJUnitRenderer(w) }},
+	} {
+		if output.file == "" {
+			continue
+		}
 
-	for _, suite := range suites {
-		if err := runSuite(eval, rep, suite); err != nil {
+		f, err := os.Create(output.file)
+		if err != nil {
+			return fmt.Errorf("%w: create %s: %w", errUsage, output.name, err)
+		}
+
+		defer func() {
+			err = errors.Join(err, f.Close())
+		}()
+
+		rep.AddRenderer(output.newRenderer(f))
+	}
+
+	rep.ReportStart(buildInfo(), cfg.filters())
+
+	if cfg.outputDir != "" {
+		outputs, err := newSuiteOutputs(cfg.outputDir, cfg.jsonOutput)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+
+		rep.SetSuiteOutput(outputs.open)
+	}
+
+	params := newClusterParams(cfg, stderr)
+
+	dumper, err := newFailureDumper(cfg.dumpFailures)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	for i, suite := range suites {
+		if rep.Results().MaxFailuresReached() {
+			rep.AddNotRun(countTests(suites[i:]))
+
+			break
+		}
+
+		if err := runSuite(ctx, eval, rep, suite, params, dumper, bench, cfg); err != nil {
 			return err
 		}
 	}
 
+	if bench != nil {
+		bench.report(rep, cfg.benchSlow)
+	}
+
 	if err := rep.Summary(); err != nil {
 		return fmt.Errorf("test summary: %w", err)
 	}
 
-	return nil
+	return rep.Results().Err()
 }
 
-func configureReporter(rep *reporter.Reporter, cfg *config) {
+func configureReporter(rep *reporter.Reporter, cfg *config, stderr io.Writer) {
+	rep.SetNoTimestamps(cfg.noTimestamps)
+	rep.SetFailOnEmptySuites(cfg.failOnEmpty)
+	rep.SetMaxFailures(cfg.maxFailures)
+	rep.SetGroupFailures(cfg.groupFailures)
+	rep.SetByPolicy(cfg.byPolicy)
+	rep.SetGroupByPolicy(cfg.groupByPolicy)
+
+	if cfg.summary {
+		rep.SetSummaryOutput(stderr)
+	}
+
 	switch {
 	case cfg.jsonOutput:
 		rep.SetFormat(reporter.FormatJSON)
@@ -130,73 +876,81 @@ func configureReporter(rep *reporter.Reporter, cfg *config) {
 	}
 }
 
-func runSuite(eval *evaluator.Evaluator, rep *reporter.Reporter, suite *loader.TestSuite) error {
+// runSuite evaluates every test in the suite. When params is non-nil, missing
+// params are resolved from the cluster. When dumper is non-nil, the artifacts
+// of failing tests are written to disk. When bench is non-nil, each test is
+// additionally evaluated repeatedly to measure its latency.
+func runSuite(ctx context.Context, eval *evaluator.Evaluator, rep *reporter.Reporter, suite *loader.TestSuite, params *clusterParams, dumper *failureDumper, bench *benchmarks, cfg *config) error {
 	suiteRep := rep.StartSuite(suite.Name)
 	defer suiteRep.End()
 
-	for _, test := range suite.Tests {
-		suiteRep.StartTest(test.Name)
+	switch {
+	case len(suite.Tests) > 0:
+	case suite.TestsDirFound:
+		suiteRep.ReportEmpty("no tests")
+	default:
+		suiteRep.ReportEmpty("no tests directory")
+	}
 
-		mutatingPolicy, mutatingBinding, validatingPolicy, validatingBinding := findPolicies(suite, test.PolicyName)
+	for i, test := range suite.Tests {
+		if rep.Results().MaxFailuresReached() {
+			rep.AddNotRun(len(suite.Tests) - i)
 
-		if mutatingPolicy == nil && validatingPolicy == nil {
-			suiteRep.ReportFail(test.Name, fmt.Sprintf("policy %q not found", test.PolicyName))
+			break
+		}
+
+		suiteRep.StartTest(test.Name, test.FilePath)
+		suiteRep.SetTestPolicy(test.PolicyName)
+
+		if test.Request != nil {
+			suiteRep.SetTestOperation(string(test.Request.Operation))
+		}
+
+		policies, err := runner.ForTest(suite, test)
+		if err != nil {
+			result := &evaluator.TestResult{Message: err.Error()}
+			dumpFailure(dumper, suite.Name, test.Name, result)
+			suiteRep.ReportFail(test.Name, result.Message)
 
 			continue
 		}
 
-		// Evaluate test
-		result := eval.EvaluateTest(mutatingPolicy, mutatingBinding, validatingPolicy, validatingBinding, test)
+		if err := params.resolve(ctx, test, policies); err != nil {
+			result := &evaluator.TestResult{Message: err.Error()}
+			dumpFailure(dumper, suite.Name, test.Name, result)
+			suiteRep.ReportFail(test.Name, result.Message)
 
-		suiteRep.ReportResult(test.Name, result)
-	}
+			continue
+		}
 
-	return nil
-}
+		// Evaluate test
+		result := policies.Evaluate(eval, test)
+		evaluator.CheckDuration(result, suite.DurationLimit(cfg.maxTestDuration))
 
-func findPolicies(suite *loader.TestSuite, policyName string) (*admissionv1beta1.MutatingAdmissionPolicy, *admissionv1beta1.MutatingAdmissionPolicyBinding, *admissionregv1.ValidatingAdmissionPolicy, *admissionregv1.ValidatingAdmissionPolicyBinding) {
-	var (
-		mutatingPolicy    *admissionv1beta1.MutatingAdmissionPolicy
-		mutatingBinding   *admissionv1beta1.MutatingAdmissionPolicyBinding
-		validatingPolicy  *admissionregv1.ValidatingAdmissionPolicy
-		validatingBinding *admissionregv1.ValidatingAdmissionPolicyBinding
-	)
-
-	for _, policy := range suite.MutatingPolicies {
-		if policy.Name == policyName {
-			mutatingPolicy = policy
-			// Find matching binding
-			for _, binding := range suite.MutatingBindings {
-				if binding.Spec.PolicyName == policy.Name {
-					mutatingBinding = binding
-
-					break
-				}
-			}
+		if !result.Passed {
+			dumpFailure(dumper, suite.Name, test.Name, result)
+		}
 
-			break
+		suiteRep.ReportResult(test.Name, result, test.Object)
+
+		if bench != nil {
+			bench.run(eval, cfg.benchtime, test, func() *evaluator.TestResult {
+				return policies.Evaluate(eval, test)
+			})
 		}
 	}
 
-	if mutatingPolicy == nil {
-		for _, policy := range suite.ValidatingPolicies {
-			if policy.Name == policyName {
-				validatingPolicy = policy
-				// Find matching binding
-				for _, binding := range suite.ValidatingBindings {
-					if binding.Spec.PolicyName == policy.Name {
-						validatingBinding = binding
-
-						break
-					}
-				}
+	return nil
+}
 
-				break
-			}
-		}
+// countTests returns the number of tests of suites.
+func countTests(suites []*loader.TestSuite) int {
+	n := 0
+	for _, suite := range suites {
+		n += len(suite.Tests)
 	}
 
-	return mutatingPolicy, mutatingBinding, validatingPolicy, validatingBinding
+	return n
 }
 
 func getVersion() string {
@@ -1,15 +1,23 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime/pprof"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/zemanlx/kat/internal/loader"
 )
 
 //nolint:gochecknoglobals // Test flag
@@ -60,12 +68,280 @@ func TestRun(t *testing.T) {
 			args:   []string{"kat", "-run", "limit.*params", "test-policies-pass"},
 			golden: "testdata/regex_limit_params.golden",
 		},
+		{
+			name:   "RunSuiteAndTest",
+			args:   []string{"kat", "-v", "-run", "^sidecar-injection$/skip", "test-policies-pass"},
+			golden: "testdata/run_suite_and_test.golden",
+		},
+		{
+			name:   "RunExact",
+			args:   []string{"kat", "-v", "-run-exact", "-run", "replica-limit/replica-limit.within-limit.allow", "test-policies-pass"},
+			golden: "testdata/run_exact.golden",
+		},
 		{
 			name:    "FailPolicies",
 			args:    []string{"kat", "test-policies-fail"},
 			golden:  "testdata/fail_policies.golden",
 			wantErr: true,
 		},
+		{
+			name:   "VerboseMutationDiff",
+			args:   []string{"kat", "-v", "test-policies-pass/mutating/add-default-labels"},
+			golden: "testdata/verbose_mutation_diff.golden",
+		},
+		{
+			name:    "RequireGold",
+			args:    []string{"kat", "-require-gold", "test-policies-pass/mutating"},
+			golden:  "testdata/require_gold.golden",
+			wantErr: true,
+		},
+		{
+			name:   "JSONNoTimestamps",
+			args:   []string{"kat", "-json", "-no-timestamps", "test-policies-pass/mutating/add-default-labels"},
+			golden: "testdata/json_no_timestamps.golden",
+		},
+		{
+			name:   "JSONOutcome",
+			args:   []string{"kat", "-json", "-no-timestamps", "test-policies-pass/validating/deprecated-api-warn", "test-policies-pass/validating/track-privileged-audit"},
+			golden: "testdata/json_outcome.golden",
+		},
+		{
+			name:   "SingleTestFile",
+			args:   []string{"kat", "-v", "test-policies-pass/validating/block-pod-exec/tests/block-pod-exec.prod-non-admin.deny.request.yaml"},
+			golden: "testdata/single_test_file.golden",
+		},
+		{
+			name:   "DirFilter",
+			args:   []string{"kat", "-v", "-dir", "validating/block-pod-exec", "-dir", "mutating/add-default-labels", "test-policies-pass"},
+			golden: "testdata/dir_filter.golden",
+		},
+		{
+			name:   "DirFilterJSON",
+			args:   []string{"kat", "-json", "-no-timestamps", "-dir", "validating/block-pod-exec", "-run", "prod-admin", "test-policies-pass"},
+			golden: "testdata/dir_filter_json.golden",
+		},
+		{
+			name:    "ByPolicy",
+			args:    []string{"kat", "-by-policy", "-group-failures=false", "test-policies-fail"},
+			golden:  "testdata/by_policy.golden",
+			wantErr: true,
+		},
+		{
+			name:    "GroupByPolicy",
+			args:    []string{"kat", "-group-by-policy", "-group-failures=false", "test-policies-fail"},
+			golden:  "testdata/group_by_policy.golden",
+			wantErr: true,
+		},
+		{
+			name:    "GroupByPolicyVerbose",
+			args:    []string{"kat", "-v", "-group-by-policy", "test-policies-fail/mutating-with-binding", "test-policies-fail/block-pod-exec"},
+			golden:  "testdata/group_by_policy_verbose.golden",
+			wantErr: true,
+		},
+		{
+			name:    "GroupFailures",
+			args:    []string{"kat", "testdata/group-failures"},
+			golden:  "testdata/group_failures.golden",
+			wantErr: true,
+		},
+		{
+			name:    "MaxFailures",
+			args:    []string{"kat", "-max-failures", "2", "testdata/group-failures", "test-policies-pass/validating/replica-limit"},
+			golden:  "testdata/max_failures.golden",
+			wantErr: true,
+		},
+		{
+			name:    "FailfastVerbose",
+			args:    []string{"kat", "-v", "-failfast", "testdata/group-failures", "test-policies-pass/validating/replica-limit"},
+			golden:  "testdata/failfast_verbose.golden",
+			wantErr: true,
+		},
+		{
+			name:    "GroupFailuresVerboseDefaultOff",
+			args:    []string{"kat", "-v", "testdata/group-failures"},
+			golden:  "testdata/group_failures_verbose.golden",
+			wantErr: true,
+		},
+		{
+			name:   "ClusterParamsUnavailable",
+			args:   []string{"kat", "-kubeconfig", "does-not-exist", "test-policies-pass/validating/replica-limit-with-params"},
+			golden: "testdata/cluster_params_unavailable.golden",
+		},
+		{
+			name:   "KubeVersionLatest",
+			args:   []string{"kat", "testdata/kube-version"},
+			golden: "testdata/kube_version_latest.golden",
+		},
+		{
+			name:    "KubeVersionTooOld",
+			args:    []string{"kat", "-kube-version", "1.29", "testdata/kube-version"},
+			golden:  "testdata/kube_version_too_old.golden",
+			wantErr: true,
+		},
+		{
+			name:   "Version",
+			args:   []string{"kat", "version"},
+			golden: "testdata/version.golden",
+		},
+		{
+			name:   "VersionFlag",
+			args:   []string{"kat", "-version"},
+			golden: "testdata/version.golden",
+		},
+		{
+			name:   "ListLibraries",
+			args:   []string{"kat", "-list-libraries", "-kube-version", "1.30"},
+			golden: "testdata/list_libraries.golden",
+		},
+		{
+			name:   "ValidateOnly",
+			args:   []string{"kat", "-validate-only", "test-policies-pass"},
+			golden: "testdata/validate_only.golden",
+		},
+		{
+			name:    "ValidateOnlyInvalidPolicy",
+			args:    []string{"kat", "-validate-only", "testdata/invalid-policy"},
+			golden:  "testdata/validate_only_invalid_policy.golden",
+			wantErr: true,
+		},
+		{
+			name:    "ValidateOnlyLint",
+			args:    []string{"kat", "-validate-only", "-lint", "testdata/lint"},
+			golden:  "testdata/validate_only_lint.golden",
+			wantErr: true,
+		},
+		{
+			name:    "ValidateOnlyProblems",
+			args:    []string{"kat", "-validate-only", "test-policies-fail"},
+			golden:  "testdata/validate_only_problems.golden",
+			wantErr: true,
+		},
+		{
+			name:   "ParamsUsageWarnings",
+			args:   []string{"kat", "testdata/params-usage"},
+			golden: "testdata/params_usage_warnings.golden",
+		},
+		{
+			name:   "ManagedFields",
+			args:   []string{"kat", "-v", "testdata/managed-fields"},
+			golden: "testdata/managed_fields.golden",
+		},
+		{
+			name:   "EmptySuites",
+			args:   []string{"kat", "testdata/empty-suites"},
+			golden: "testdata/empty_suites.golden",
+		},
+		{
+			name:    "FailOnEmptySuitesJSON",
+			args:    []string{"kat", "-json", "-fail-on-empty-suites", "testdata/empty-suites"},
+			golden:  "testdata/fail_on_empty_suites_json.golden",
+			wantErr: true,
+		},
+		{
+			name:    "RequireTests",
+			args:    []string{"kat", "-require-tests", "testdata/empty-suites"},
+			golden:  "testdata/require_tests.golden",
+			wantErr: true,
+		},
+		{
+			name:   "OperationVariants",
+			args:   []string{"kat", "-v", "testdata/operations"},
+			golden: "testdata/operation_variants.golden",
+		},
+		{
+			name:   "StripServerFields",
+			args:   []string{"kat", "-v", "-strip-server-fields", "testdata/server-fields"},
+			golden: "testdata/strip_server_fields.golden",
+		},
+		{
+			name:   "IrregularPlurals",
+			args:   []string{"kat", "-v", "testdata/irregular-plurals"},
+			golden: "testdata/irregular_plurals.golden",
+		},
+		{
+			name:    "UnmatchedTestWarning",
+			args:    []string{"kat", "testdata/unmatched-policy"},
+			golden:  "testdata/unmatched_test_warning.golden",
+			wantErr: true,
+		},
+		{
+			name:    "Check",
+			args:    []string{"kat", "check", "-policies", "testdata/check/policies", "-f", "testdata/check/manifests"},
+			golden:  "testdata/check.golden",
+			wantErr: true,
+		},
+		{
+			name:   "CheckClean",
+			args:   []string{"kat", "check", "-policies", "testdata/check/policies", "-f", "testdata/check/manifests/clean.yaml"},
+			golden: "testdata/check_clean.golden",
+		},
+		{
+			name:   "OnlyMutating",
+			args:   []string{"kat", "-only-mutating", "test-policies-pass"},
+			golden: "testdata/only_mutating.golden",
+		},
+		{
+			name:   "OnlyValidating",
+			args:   []string{"kat", "-only-validating", "test-policies-pass"},
+			golden: "testdata/only_validating.golden",
+		},
+		{
+			name:    "JSONSummary",
+			args:    []string{"kat", "-json", "-summary", "-no-timestamps", "test-policies-fail/block-pod-exec"},
+			golden:  "testdata/json_summary.golden",
+			wantErr: true,
+		},
+		{
+			name:   "SeparateTestsDir",
+			args:   []string{"kat", "-v", "-policies-dir", "testdata/split/policies", "-tests-dir", "testdata/split/tests"},
+			golden: "testdata/separate_tests_dir.golden",
+		},
+		{
+			name:    "CompareQuantities",
+			args:    []string{"kat", "-compare-quantities", "testdata/quantities"},
+			golden:  "testdata/compare_quantities.golden",
+			wantErr: true,
+		},
+		{
+			name:    "CheckReinvocation",
+			args:    []string{"kat", "-check-reinvocation", "testdata/reinvocation"},
+			golden:  "testdata/check_reinvocation.golden",
+			wantErr: true,
+		},
+		{
+			name:    "ComparePolicies",
+			args:    []string{"kat", "-v", "-compare", "testdata/compare/old-replica-limit.yaml", "testdata/compare/replicas"},
+			golden:  "testdata/compare.golden",
+			wantErr: true,
+		},
+		{
+			name:   "CheckDeterminism",
+			args:   []string{"kat", "-check-determinism", "test-policies-pass"},
+			golden: "testdata/all_policies.golden",
+		},
+		{
+			name: "ExtraVars",
+			args: []string{
+				"kat", "-v", "-extra-var", "cluster=testdata/extra-vars/cluster.yaml",
+				"-extra-var", "tenant=object.metadata.namespace.split('-')[0]", "testdata/extra-vars/suite",
+			},
+			golden: "testdata/extra_vars.golden",
+		},
+		{
+			name:   "SuitePerPolicy",
+			args:   []string{"kat", "-v", "-no-timestamps", "-suite-per-policy", "test-policies-pass/chained"},
+			golden: "testdata/suite_per_policy.golden",
+		},
+		{
+			name:   "SuitePerPolicyRun",
+			args:   []string{"kat", "-v", "-no-timestamps", "-suite-per-policy", "-run", "add-team-label/require-team-label/missing", "test-policies-pass/chained"},
+			golden: "testdata/suite_per_policy_run.golden",
+		},
+		{
+			name:   "SuitePerPolicyRunSuite",
+			args:   []string{"kat", "-v", "-no-timestamps", "-suite-per-policy", "-run", "add-team-label/require-team-label$", "test-policies-pass/chained"},
+			golden: "testdata/suite_per_policy_run_suite.golden",
+		},
 		{
 			name:   "JSONOutput",
 			args:   []string{"kat", "-json", "test-policies-pass/mutating"},
@@ -81,6 +357,587 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestRun_ExitCodes(t *testing.T) {
+	t.Parallel()
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "UnknownFlag", args: []string{"kat", "-no-such-flag"}, want: exitSetupFailed},
+		{name: "InvalidBenchtime", args: []string{"kat", "-benchtime", "fast", "test-policies-pass"}, want: exitSetupFailed},
+		{name: "ConflictingKindFilters", args: []string{"kat", "-only-mutating", "-only-validating", "test-policies-pass"}, want: exitSetupFailed},
+		{name: "RecordWithoutKubeconfig", args: []string{"kat", "-record", "-kubeconfig", "does-not-exist", "test-policies-pass/validating/replica-limit"}, want: exitSetupFailed},
+		{name: "InvalidKubeVersion", args: []string{"kat", "-kube-version", "newest", "test-policies-pass"}, want: exitSetupFailed},
+		{name: "NegativeNestingLimit", args: []string{"kat", "-max-comprehension-nesting", "-1", "test-policies-pass"}, want: exitSetupFailed},
+		{name: "CostLimitExceeded", args: []string{"kat", "-cost-limit", "1", "test-policies-pass/validating/block-privileged-containers"}, want: exitTestsFailed},
+		{name: "InvalidExtraVar", args: []string{"kat", "-extra-var", "tenant", "test-policies-pass"}, want: exitSetupFailed},
+		{name: "ReservedExtraVar", args: []string{"kat", "-extra-var", "object=1", "test-policies-pass"}, want: exitSetupFailed},
+		{name: "TestsDirWithoutPoliciesDir", args: []string{"kat", "-tests-dir", "testdata/split/tests", "testdata/split/policies"}, want: exitSetupFailed},
+		{name: "OrphanTestsDirStrict", args: []string{"kat", "-strict", "-policies-dir", "testdata/split/policies", "-tests-dir", "testdata/split/tests"}, want: exitSetupFailed},
+		{name: "PoliciesDirWithPaths", args: []string{"kat", "-policies-dir", "testdata/split/policies", "test-policies-pass"}, want: exitSetupFailed},
+		{name: "LintWithoutValidateOnly", args: []string{"kat", "-lint", "testdata/lint"}, want: exitSetupFailed},
+		{name: "StrictInvalidPolicy", args: []string{"kat", "-strict", "testdata/invalid-policy"}, want: exitSetupFailed},
+		{name: "SuiteMaxTestDurationExceeded", args: []string{"kat", "testdata/max-test-duration"}, want: exitTestsFailed},
+		{name: "NegativeMaxFailures", args: []string{"kat", "-max-failures", "-1", "testdata/lint"}, want: exitSetupFailed},
+		{name: "FailfastWithMaxFailures", args: []string{"kat", "-failfast", "-max-failures", "2", "testdata/lint"}, want: exitSetupFailed},
+		{name: "MaxFailuresReached", args: []string{"kat", "-max-failures", "1", "testdata/group-failures"}, want: exitTestsFailed},
+		{name: "NegativeMaxTestDuration", args: []string{"kat", "-max-test-duration", "-1s", "testdata/lint"}, want: exitSetupFailed},
+		{name: "ServerFieldsNotStripped", args: []string{"kat", "testdata/server-fields"}, want: exitTestsFailed},
+		{name: "InvalidStripField", args: []string{"kat", "-strip-field", "metadata..uid", "testdata/server-fields"}, want: exitSetupFailed},
+		{name: "DirOutsideRoot", args: []string{"kat", "-dir", "../policies", "test-policies-pass"}, want: exitSetupFailed},
+		{name: "FailOnEmptySuites", args: []string{"kat", "-fail-on-empty-suites", "testdata/empty-suites"}, want: exitTestsFailed},
+		{name: "RequireTests", args: []string{"kat", "-require-tests", "testdata/unmatched-policy"}, want: exitSetupFailed},
+		{name: "StrictParamsUsage", args: []string{"kat", "-strict", "testdata/params-usage"}, want: exitSetupFailed},
+		{name: "StrictLoadWarnings", args: []string{"kat", "-strict", "testdata/unmatched-policy"}, want: exitSetupFailed},
+		{name: "StrictDanglingBinding", args: []string{"kat", "-strict", "testdata/dangling-binding"}, want: exitSetupFailed},
+		{name: "StrictTypeChecking", args: []string{"kat", "-strict", "-warn-deprecations", "testdata/type-checking"}, want: exitSetupFailed},
+		{name: "CheckWithoutManifests", args: []string{"kat", "check", "-policies", "testdata/check/policies"}, want: exitSetupFailed},
+		{name: "CheckDenied", args: []string{"kat", "check", "-policies", "testdata/check/policies", "-f", "testdata/check/manifests"}, want: exitTestsFailed},
+		{name: "MissingPath", args: []string{"kat", "does-not-exist"}, want: exitSetupFailed},
+		{name: "TestFailures", args: []string{"kat", "test-policies-fail"}, want: exitTestsFailed},
+	}
+
+	mockGetenv := func(_ string) string { return "" }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := run(t.Context(), tt.args, mockGetenv, os.Stdin, devNull, devNull)
+			if err == nil {
+				t.Fatal("run() error = nil, want error")
+			}
+
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	t.Parallel()
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	if err := run(t.Context(), []string{"kat", "-h"}, func(string) string { return "" }, os.Stdin, devNull, devNull); err != nil {
+		t.Errorf("run(-h) error = %v, want nil", err)
+	}
+}
+
+func TestRun_BadFlag(t *testing.T) {
+	t.Parallel()
+
+	stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+
+	err = run(t.Context(), []string{"kat", "-no-such-flag"}, func(string) string { return "" }, os.Stdin, stdout, stdout)
+	if !errors.Is(err, errUsage) {
+		t.Fatalf("run() error = %v, want usage error", err)
+	}
+
+	// main prints the error to stderr; the flag package must not print it too.
+	got, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 0 {
+		t.Errorf("run() printed %q, want nothing", got)
+	}
+}
+
+func TestRecordResponse_NoRequest(t *testing.T) {
+	t.Parallel()
+
+	// A test of only params, e.g. x.allow.params.yaml, has no request; it must
+	// fail before the cluster is contacted.
+	test := &loader.TestCase{Name: "x.allow"}
+
+	if err := recordResponse(t.Context(), nil, test); !errors.Is(err, errNoRequestToRecord) {
+		t.Errorf("recordResponse() error = %v, want %v", err, errNoRequestToRecord)
+	}
+}
+
+//nolint:paralleltest // CPU profiling is process-global, see pprof.StartCPUProfile.
+func TestRun_Profiles(t *testing.T) {
+	if err := pprof.StartCPUProfile(io.Discard); err != nil {
+		t.Skip("CPU profiling already enabled, e.g. by go test -cpuprofile")
+	}
+
+	pprof.StopCPUProfile()
+
+	dir := t.TempDir()
+	cpuProfile := filepath.Join(dir, "cpu.out")
+	memProfile := filepath.Join(dir, "mem.out")
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+	args := []string{"kat", "-cpuprofile", cpuProfile, "-memprofile", memProfile, "test-policies-fail"}
+
+	// Profiles must be written even when tests fail.
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull); err == nil {
+		t.Fatal("run() error = nil, want test failures")
+	}
+
+	for _, path := range []string{cpuProfile, memProfile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("profile %s not written: %v", path, err)
+		}
+
+		if info.Size() == 0 {
+			t.Errorf("profile %s is empty", path)
+		}
+	}
+}
+
+func TestRun_OutputDir(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "reports")
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+	args := []string{"kat", "-json", "-no-timestamps", "-output-dir", dir, "test-policies-pass/validating"}
+
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "replica-limit.json"))
+	if err != nil {
+		t.Fatalf("suite report not written: %v", err)
+	}
+
+	for line := range strings.Lines(string(data)) {
+		var event struct {
+			Package string `json:"package"`
+		}
+
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid JSON event %q: %v", line, err)
+		}
+
+		if event.Package != "replica-limit" {
+			t.Errorf("suite report holds event of package %q, want only replica-limit", event.Package)
+		}
+	}
+
+	if !strings.Contains(string(data), `"action":"summary"`) {
+		t.Errorf("suite report has no summary event:\n%s", data)
+	}
+}
+
+func TestRun_EventLog(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.json")
+
+	stdout, err := os.Create(filepath.Join(dir, "stdout.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+	args := []string{"kat", "-v", "-no-timestamps", "-event-log", path, "test-policies-pass/mutating/add-default-labels"}
+
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, stdout, stdout); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	verbose, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(verbose), "=== RUN") {
+		t.Errorf("stdout is not verbose:\n%s", verbose)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("event log not written: %v", err)
+	}
+
+	// The event log holds exactly what -json prints.
+	want, err := os.ReadFile("testdata/json_no_timestamps.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(string(want), sanitizeOutput(string(got))); diff != "" {
+		t.Errorf("event log mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRun_CSV(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "results.csv")
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+	args := []string{"kat", "-no-timestamps", "-csv", path, "test-policies-pass/validating/replica-limit", "testdata/group-failures"}
+
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull); err == nil {
+		t.Fatal("run() error = nil, want the failures of testdata/group-failures")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("csv file not written: %v", err)
+	}
+
+	golden := "testdata/csv.golden"
+	if *update {
+		if err := os.WriteFile(golden, got, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("csv mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRun_HTML(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "report.html")
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+	args := []string{"kat", "-no-timestamps", "-html", path, "test-policies-pass/validating/replica-limit", "testdata/group-failures"}
+
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull); err == nil {
+		t.Fatal("run() error = nil, want the failures of testdata/group-failures")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("html file not written: %v", err)
+	}
+
+	golden := "testdata/html.golden"
+	if *update {
+		if err := os.WriteFile(golden, got, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("html mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseFlags_Output(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    outputs
+		wantErr error
+	}{
+		{name: "Default", args: []string{"kat"}},
+		{name: "JSONStdout", args: []string{"kat", "-output", "json"}, want: outputs{json: true}},
+		{
+			name: "TextAndFiles",
+			args: []string{"kat", "-output", "text", "-output", "junit=report.xml", "-output", "json=events.ndjson"},
+			want: outputs{junitFile: "report.xml", eventLog: "events.ndjson"},
+		},
+		{
+			name: "OlderFlags",
+			args: []string{"kat", "-json", "-csv", "results.csv", "-output", "html=report.html"},
+			want: outputs{json: true, csvFile: "results.csv", htmlFile: "report.html"},
+		},
+		{name: "SameFileTwice", args: []string{"kat", "-csv", "a.csv", "-output", "csv=a.csv"}, want: outputs{csvFile: "a.csv"}},
+		{name: "TwoStdoutFormats", args: []string{"kat", "-json", "-output", "text"}, wantErr: errOutputStdout},
+		{name: "ConflictingFiles", args: []string{"kat", "-event-log", "a.json", "-output", "json=b.json"}, wantErr: errOutputConflict},
+		{name: "JUnitWithoutFile", args: []string{"kat", "-output", "junit"}, wantErr: errOutputFile},
+		{name: "TextToFile", args: []string{"kat", "-output", "text=out.txt"}, wantErr: errOutputTextFile},
+		{name: "UnknownFormat", args: []string{"kat", "-output", "tap=out.tap"}, wantErr: errOutputFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := parseFlags(tt.args, os.Stdout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseFlags() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			got := outputs{json: cfg.jsonOutput, eventLog: cfg.eventLog, csvFile: cfg.csvFile, htmlFile: cfg.htmlFile, junitFile: cfg.junitFile}
+			if got != tt.want {
+				t.Errorf("outputs = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Outputs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	junitPath := filepath.Join(dir, "report.xml")
+	eventsPath := filepath.Join(dir, "events.ndjson")
+
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+	args := []string{
+		"kat", "-no-timestamps", "-output", "text", "-output", "junit=" + junitPath, "-output", "json=" + eventsPath,
+		"test-policies-pass/validating/replica-limit", "testdata/group-failures",
+	}
+
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, stdout, stdout); err == nil {
+		t.Fatal("run() error = nil, want the failures of testdata/group-failures")
+	}
+
+	text, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(text), "FAIL\tadd-team-label") {
+		t.Errorf("stdout is not the text output:\n%s", text)
+	}
+
+	events, err := os.ReadFile(eventsPath)
+	if err != nil {
+		t.Fatalf("event log not written: %v", err)
+	}
+
+	if !strings.Contains(string(events), `"action":"fail","counts"`) {
+		t.Errorf("event log has no final event:\n%s", events)
+	}
+
+	got, err := os.ReadFile(junitPath)
+	if err != nil {
+		t.Fatalf("junit file not written: %v", err)
+	}
+
+	golden := "testdata/junit.golden"
+	if *update {
+		if err := os.WriteFile(golden, got, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("junit mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseFlags_MaxFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "Default", args: []string{"kat"}, want: 0},
+		{name: "MaxFailures", args: []string{"kat", "-max-failures", "20"}, want: 20},
+		{name: "Failfast", args: []string{"kat", "-failfast"}, want: 1},
+		{name: "FailfastWithMaxFailuresOne", args: []string{"kat", "-failfast", "-max-failures", "1"}, want: 1},
+		{name: "FailfastWithUnlimited", args: []string{"kat", "-failfast", "-max-failures", "0"}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := parseFlags(tt.args, os.Stdout)
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+
+			if cfg.maxFailures != tt.want {
+				t.Errorf("maxFailures = %d, want %d", cfg.maxFailures, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Config(t *testing.T) {
+	t.Parallel()
+
+	configFile := "strict: true\ndefault-namespace: team-a\ncost-limit: 1000000\nstrip-field:\n- status\n- metadata.uid\n"
+
+	tests := []struct {
+		name    string
+		config  string
+		args    []string
+		check   func(t *testing.T, cfg *config)
+		wantErr error
+	}{
+		{
+			name:   "Defaults",
+			config: configFile,
+			check: func(t *testing.T, cfg *config) {
+				t.Helper()
+
+				if !cfg.strict || cfg.defaultNamespace != "team-a" || cfg.costLimit != 1000000 {
+					t.Errorf("strict, defaultNamespace, costLimit = %v, %q, %d, want true, team-a, 1000000",
+						cfg.strict, cfg.defaultNamespace, cfg.costLimit)
+				}
+
+				if diff := cmp.Diff([][]string{{"status"}, {"metadata", "uid"}}, cfg.stripFields); diff != "" {
+					t.Errorf("stripFields mismatch (-want +got):\n%s", diff)
+				}
+			},
+		},
+		{
+			name:   "FlagsOverride",
+			config: configFile,
+			args:   []string{"-strict=false", "-default-namespace", "team-b", "-strip-field", "spec"},
+			check: func(t *testing.T, cfg *config) {
+				t.Helper()
+
+				if cfg.strict || cfg.defaultNamespace != "team-b" || cfg.costLimit != 1000000 {
+					t.Errorf("strict, defaultNamespace, costLimit = %v, %q, %d, want false, team-b, 1000000",
+						cfg.strict, cfg.defaultNamespace, cfg.costLimit)
+				}
+
+				if diff := cmp.Diff([][]string{{"spec"}}, cfg.stripFields); diff != "" {
+					t.Errorf("stripFields mismatch (-want +got):\n%s", diff)
+				}
+			},
+		},
+		{
+			name:    "UnknownFlag",
+			config:  "stirct: true\n",
+			wantErr: errConfigUnknownFlag,
+		},
+		{
+			name:    "NestedValue",
+			config:  "default-namespace:\n  name: team-a\n",
+			wantErr: errConfigValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "kat.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := parseFlags(append([]string{"kat", "-config", path}, tt.args...), os.Stdout)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("parseFlags() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestParseBenchtime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    benchtime
+		wantErr bool
+	}{
+		{value: "100x", want: benchtime{iterations: 100}},
+		{value: "250ms", want: benchtime{duration: 250 * time.Millisecond}},
+		{value: "0x", wantErr: true},
+		{value: "abcx", wantErr: true},
+		{value: "-1s", wantErr: true},
+		{value: "fast", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseBenchtime(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBenchtime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("parseBenchtime(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
 func runTestCase(t *testing.T, tt runTestSpec) {
 	t.Helper()
 
@@ -91,7 +948,7 @@ func runTestCase(t *testing.T, tt runTestSpec) {
 	// but os.Getenv is fine as long as tests don't depend on env vars unless specified.
 	mockGetenv := func(_ string) string { return "" }
 
-	err := run(t.Context(), tt.args, mockGetenv, os.Stdin, w)
+	err := run(t.Context(), tt.args, mockGetenv, os.Stdin, w, w)
 	w.Close()
 
 	if (err != nil) != tt.wantErr {
@@ -136,6 +993,8 @@ var (
 	suiteDurationRegex = regexp.MustCompile(`\t\d+\.\d+s`)
 	jsonTimeRegex      = regexp.MustCompile(`"time":"[^"]+"`)
 	elapsedRegex       = regexp.MustCompile(`"elapsed":[\d\.]+`)
+	evalElapsedRegex   = regexp.MustCompile(`,"evalElapsed":[\d\.e+-]+`)
+	kubeVersionRegex   = regexp.MustCompile(`("k8s\.io/[a-z]+":"|k8s\.io/[a-z]+: +)v[^"\n]+`)
 )
 
 func sanitizeOutput(output string) string {
@@ -148,6 +1007,10 @@ func sanitizeOutput(output string) string {
 	output = jsonTimeRegex.ReplaceAllString(output, `"time":"2000-01-01T00:00:00Z"`)
 	// Replace JSON elapsed
 	output = elapsedRegex.ReplaceAllString(output, `"elapsed":0`)
+	// Evaluation times vary; drop them
+	output = evalElapsedRegex.ReplaceAllString(output, "")
+	// Kubernetes library versions change with dependency updates
+	output = kubeVersionRegex.ReplaceAllString(output, "${1}v0.0.0")
 
 	// Normalize paths in output if they appear (e.g. windows vs linux)
 	// Kat seems to output suite names which are derived from paths.
@@ -159,3 +1022,104 @@ func sanitizeOutput(output string) string {
 
 	return output
 }
+
+func TestRun_Docs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+	args := []string{"kat", "docs", "-o", dir, "test-policies-pass/mutating"}
+
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull); err != nil {
+		t.Fatalf("run(docs) error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+
+	want := []string{
+		"add-default-labels.md",
+		"mutating-with-binding.md",
+		"namespace-selector-binding-mutating.md",
+		"sidecar-injection.md",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("docs files mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRun_Gen(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.CopyFS(dir, os.DirFS("internal/gen/testdata/suite")); err != nil {
+		t.Fatal(err)
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+	args := []string{"kat", "gen", dir}
+
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull); err != nil {
+		t.Fatalf("run(gen) error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "tests", "pod-standards.0.deny.message.txt")); err != nil {
+		t.Errorf("generated message file: %v", err)
+	}
+
+	// A second run must not overwrite the generated, possibly edited, files.
+	err = run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull)
+	if exitCode(err) != exitSetupFailed {
+		t.Errorf("second run(gen) error = %v, want exit code %d", err, exitSetupFailed)
+	}
+}
+
+func TestRun_CheckPoliciesURL(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/policies.yaml", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/check/policies/policies.yaml")
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	mockGetenv := func(_ string) string { return "" }
+
+	args := []string{"kat", "check", "-policies", server.URL + "/policies.yaml", "-f", "testdata/check/manifests"}
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull); exitCode(err) != exitTestsFailed {
+		t.Errorf("run(check) error = %v, want exit code %d", err, exitTestsFailed)
+	}
+
+	args = []string{"kat", "check", "-policies", server.URL + "/missing.yaml", "-f", "testdata/check/manifests"}
+	if err := run(t.Context(), args, mockGetenv, os.Stdin, devNull, devNull); !errors.Is(err, errFetchPolicies) {
+		t.Errorf("run(check) error = %v, want %v", err, errFetchPolicies)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// suiteOutputs opens the per-suite report files of -output-dir.
+type suiteOutputs struct {
+	dir string
+	ext string
+	// opened records the files written in this run. Suites sharing a name
+	// append to the same file instead of overwriting it.
+	opened map[string]bool
+}
+
+// newSuiteOutputs creates dir for reports named "<suite>.json" with JSON
+// output and "<suite>.txt" otherwise.
+func newSuiteOutputs(dir string, jsonOutput bool) (*suiteOutputs, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create output directory: %w", err)
+	}
+
+	ext := ".txt"
+	if jsonOutput {
+		ext = ".json"
+	}
+
+	return &suiteOutputs{dir: dir, ext: ext, opened: map[string]bool{}}, nil
+}
+
+// open opens the report file of a suite. Names are sanitized like those of
+// -dump-failures.
+func (o *suiteOutputs) open(suiteName string) (io.WriteCloser, error) {
+	path := filepath.Join(o.dir, dumpPath(suiteName)+o.ext)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("create output directory: %w", err)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if o.opened[path] {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	f, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open suite report: %w", err)
+	}
+
+	o.opened[path] = true
+
+	return f, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errOutputFormat   = errors.New("unknown -output format")
+	errOutputFile     = errors.New("-output needs a file")
+	errOutputStdout   = errors.New("only one -output format can go to stdout")
+	errOutputConflict = errors.New("conflicting files for the same output format")
+	errOutputTextFile = errors.New("-output text goes to stdout and takes no file")
+)
+
+// Formats of -output. Only text and json can go to stdout; text goes only
+// there.
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJUnit = "junit"
+	outputCSV   = "csv"
+	outputHTML  = "html"
+)
+
+// outputFlags collects repeated -output flags.
+type outputFlags []string
+
+func (f *outputFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *outputFlags) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+// outputs are the destinations of the results of a run: the format of stdout
+// and the files other renderers write to.
+type outputs struct {
+	json      bool
+	eventLog  string
+	csvFile   string
+	htmlFile  string
+	junitFile string
+}
+
+// parseOutputs merges the -output values, each "<format>" for stdout or
+// "<format>=<file>", with the older flags they generalize: -json is
+// "json", -event-log "json=<file>", -csv "csv=<file>" and -html
+// "html=<file>". All renderers are fed the same results, so the exit code
+// does not depend on them.
+func parseOutputs(values []string, legacy outputs) (outputs, error) {
+	result := legacy
+	stdout := ""
+
+	if legacy.json {
+		stdout = outputJSON
+	}
+
+	for _, value := range values {
+		format, file, hasFile := strings.Cut(value, "=")
+
+		if !hasFile {
+			if format != outputText && format != outputJSON {
+				if isOutputFormat(format) {
+					return outputs{}, fmt.Errorf("%w: %s=<file>", errOutputFile, format)
+				}
+
+				return outputs{}, fmt.Errorf("%w %q", errOutputFormat, format)
+			}
+
+			if stdout != "" && stdout != format {
+				return outputs{}, fmt.Errorf("%w, got %s and %s", errOutputStdout, stdout, format)
+			}
+
+			stdout = format
+			result.json = format == outputJSON
+
+			continue
+		}
+
+		var target *string
+
+		switch format {
+		case outputJSON:
+			target = &result.eventLog
+		case outputJUnit:
+			target = &result.junitFile
+		case outputCSV:
+			target = &result.csvFile
+		case outputHTML:
+			target = &result.htmlFile
+		case outputText:
+			return outputs{}, errOutputTextFile
+		default:
+			return outputs{}, fmt.Errorf("%w %q", errOutputFormat, format)
+		}
+
+		if file == "" {
+			return outputs{}, fmt.Errorf("%w: %s=<file>", errOutputFile, format)
+		}
+
+		if *target != "" && *target != file {
+			return outputs{}, fmt.Errorf("%w %s: %s and %s", errOutputConflict, format, *target, file)
+		}
+
+		*target = file
+	}
+
+	return result, nil
+}
+
+// isOutputFormat reports whether format is a known -output format.
+func isOutputFormat(format string) bool {
+	switch format {
+	case outputText, outputJSON, outputJUnit, outputCSV, outputHTML:
+		return true
+	default:
+		return false
+	}
+}
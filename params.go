@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/zemanlx/kat/internal/cluster"
+	"github.com/zemanlx/kat/internal/loader"
+	"github.com/zemanlx/kat/internal/runner"
+)
+
+var errAmbiguousParams = errors.New("params match the paramRef selector")
+
+// clusterParams resolves binding paramRefs from a live cluster for tests that
+// have no local params. A nil *clusterParams resolves nothing.
+type clusterParams struct {
+	resolver *cluster.ParamResolver
+	stderr   io.Writer
+	// warned holds the bindings whose params failed to resolve, which are
+	// reported once.
+	warned map[string]bool
+}
+
+// newClusterParams returns a clusterParams when -kubeconfig or -context is
+// set. If the cluster cannot be configured, it warns and returns nil so tests
+// run with local params only.
+func newClusterParams(cfg *config, stderr io.Writer) *clusterParams {
+	if cfg.kubeconfig == "" && cfg.kubeContext == "" {
+		return nil
+	}
+
+	client, err := cluster.NewClientFromKubeconfig(cfg.kubeconfig, cfg.kubeContext)
+	if err != nil {
+		fmt.Fprintf(stderr, "warning: cluster params disabled: %v\n", err)
+
+		return nil
+	}
+
+	return &clusterParams{resolver: cluster.NewParamResolver(client), stderr: stderr, warned: make(map[string]bool)}
+}
+
+// resolve sets test.Params from the cluster when the test has no params and
+// the policy binding references them. Failures to fetch params are reported
+// as a warning once per binding and leave the test unchanged. The API server
+// evaluates a policy once per param matching a selector, which a test with a
+// single expected outcome cannot express, so more than one match is an error
+// failing the test.
+func (p *clusterParams) resolve(
+	ctx context.Context,
+	test *loader.TestCase,
+	policies runner.Policies,
+) error {
+	if p == nil || test.Params != nil {
+		return nil
+	}
+
+	ref, binding, ok := paramRefFor(policies)
+	if !ok {
+		return nil
+	}
+
+	if ref.Namespace == "" && test.Request != nil {
+		ref.Namespace = test.Request.Namespace
+	}
+
+	params, err := p.resolver.Resolve(ctx, ref)
+	if err != nil {
+		if !p.warned[binding] {
+			p.warned[binding] = true
+			fmt.Fprintf(p.stderr, "warning: %s: resolve params from cluster: %v\n", binding, err)
+		}
+
+		return nil
+	}
+
+	if len(params) > 1 {
+		names := make([]string, 0, len(params))
+		for _, param := range params {
+			names = append(names, param.GetNamespace()+"/"+param.GetName())
+		}
+
+		return fmt.Errorf("%d %w of %s (%s), and the API server evaluates the policy against each; "+
+			"add a .params.yaml or .matrix.yaml to test them", len(params), errAmbiguousParams, binding, strings.Join(names, ", "))
+	}
+
+	if len(params) == 1 {
+		test.Params = params[0]
+	}
+
+	return nil
+}
+
+// paramRefFor returns the cluster reference of the params used by the
+// evaluated policy, preferring the mutating policy as EvaluateTest does, and
+// names the binding holding it.
+func paramRefFor(policies runner.Policies) (cluster.ParamRef, string, bool) {
+	mutatingPolicy, mutatingBinding := policies.MutatingPolicy, policies.MutatingBinding
+	validatingPolicy, validatingBinding := policies.ValidatingPolicy, policies.ValidatingBinding
+
+	switch {
+	case mutatingPolicy != nil && mutatingPolicy.Spec.ParamKind != nil &&
+		mutatingBinding != nil && mutatingBinding.Spec.ParamRef != nil:
+		paramRef := mutatingBinding.Spec.ParamRef
+
+		return cluster.ParamRef{
+			APIVersion: mutatingPolicy.Spec.ParamKind.APIVersion,
+			Kind:       mutatingPolicy.Spec.ParamKind.Kind,
+			Namespace:  paramRef.Namespace,
+			Name:       paramRef.Name,
+			Selector:   paramRef.Selector,
+		}, "MutatingAdmissionPolicyBinding " + mutatingBinding.Name, true
+	case validatingPolicy != nil && validatingPolicy.Spec.ParamKind != nil &&
+		validatingBinding != nil && validatingBinding.Spec.ParamRef != nil:
+		paramRef := validatingBinding.Spec.ParamRef
+
+		return cluster.ParamRef{
+			APIVersion: validatingPolicy.Spec.ParamKind.APIVersion,
+			Kind:       validatingPolicy.Spec.ParamKind.Kind,
+			Namespace:  paramRef.Namespace,
+			Name:       paramRef.Name,
+			Selector:   paramRef.Selector,
+		}, "ValidatingAdmissionPolicyBinding " + validatingBinding.Name, true
+	default:
+		return cluster.ParamRef{}, "", false
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/zemanlx/kat/internal/cluster"
+	"github.com/zemanlx/kat/internal/loader"
+	"github.com/zemanlx/kat/internal/runner"
+)
+
+// newParamsAPIServer serves the "limits" ConfigMap in namespace "default",
+// two "tier=gold" ConfigMaps in namespace "team", and fails in namespace
+// "broken".
+func newParamsAPIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	writeJSON := func(w http.ResponseWriter, code int, body string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_, _ = io.WriteString(w, body)
+	}
+	configMap := func(namespace, name string) string {
+		return `{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"` + name + `","namespace":"` + namespace + `"},"data":{"maxReplicas":"3"}}`
+	}
+	failure := `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"InternalError","code":500,"message":"boom"}`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"kind":"APIVersions","versions":["v1"]}`)
+	})
+	mux.HandleFunc("GET /apis", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"kind":"APIGroupList","apiVersion":"v1","groups":[]}`)
+	})
+	mux.HandleFunc("GET /api/v1", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"kind":"APIResourceList","groupVersion":"v1","resources":[`+
+			`{"name":"configmaps","singularName":"configmap","namespaced":true,"kind":"ConfigMap","verbs":["get","list"]}]}`)
+	})
+	mux.HandleFunc("GET /api/v1/namespaces/{namespace}/configmaps/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("namespace") == "broken" {
+			writeJSON(w, http.StatusInternalServerError, failure)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, configMap(r.PathValue("namespace"), r.PathValue("name")))
+	})
+	mux.HandleFunc("GET /api/v1/namespaces/{namespace}/configmaps", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("namespace") == "broken" {
+			writeJSON(w, http.StatusInternalServerError, failure)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, `{"kind":"ConfigMapList","apiVersion":"v1","metadata":{},"items":[`+
+			configMap("team", "gold-b")+","+configMap("team", "gold-a")+`]}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClusterParams_Resolve(t *testing.T) {
+	t.Parallel()
+
+	server := newParamsAPIServer(t)
+
+	client, err := cluster.NewClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	policies := func(paramRef *admissionregv1.ParamRef) runner.Policies {
+		return runner.Policies{
+			ValidatingPolicy: &admissionregv1.ValidatingAdmissionPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "replica-limit"},
+				Spec: admissionregv1.ValidatingAdmissionPolicySpec{
+					ParamKind: &admissionregv1.ParamKind{APIVersion: "v1", Kind: "ConfigMap"},
+				},
+			},
+			ValidatingBinding: &admissionregv1.ValidatingAdmissionPolicyBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "replica-limit-binding"},
+				Spec:       admissionregv1.ValidatingAdmissionPolicyBindingSpec{ParamRef: paramRef},
+			},
+		}
+	}
+	newTest := func() *loader.TestCase {
+		return &loader.TestCase{Name: "test", Request: &admissionv1.AdmissionRequest{Namespace: "team"}}
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		t.Parallel()
+
+		params := &clusterParams{resolver: cluster.NewParamResolver(client), stderr: io.Discard, warned: make(map[string]bool)}
+		test := newTest()
+
+		if err := params.resolve(t.Context(), test, policies(&admissionregv1.ParamRef{Name: "limits"})); err != nil {
+			t.Fatalf("resolve() error = %v", err)
+		}
+
+		if test.Params == nil || test.Params.GetName() != "limits" {
+			t.Errorf("Params = %v, want the limits ConfigMap", test.Params)
+		}
+	})
+
+	t.Run("several selector matches", func(t *testing.T) {
+		t.Parallel()
+
+		params := &clusterParams{resolver: cluster.NewParamResolver(client), stderr: io.Discard, warned: make(map[string]bool)}
+		selector := &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}}
+		test := newTest()
+
+		err := params.resolve(t.Context(), test, policies(&admissionregv1.ParamRef{Selector: selector}))
+		if !errors.Is(err, errAmbiguousParams) || !strings.Contains(err.Error(), "team/gold-a, team/gold-b") {
+			t.Errorf("resolve() error = %v, want %v naming both params", err, errAmbiguousParams)
+		}
+
+		if test.Params != nil {
+			t.Errorf("Params = %v, want none", test.Params)
+		}
+	})
+
+	t.Run("errors warned once per binding", func(t *testing.T) {
+		t.Parallel()
+
+		var stderr bytes.Buffer
+
+		params := &clusterParams{resolver: cluster.NewParamResolver(client), stderr: &stderr, warned: make(map[string]bool)}
+		ref := &admissionregv1.ParamRef{Name: "limits", Namespace: "broken"}
+
+		for range 3 {
+			if err := params.resolve(t.Context(), newTest(), policies(ref)); err != nil {
+				t.Fatalf("resolve() error = %v", err)
+			}
+		}
+
+		if got := strings.Count(stderr.String(), "warning: ValidatingAdmissionPolicyBinding replica-limit-binding: resolve params from cluster"); got != 1 {
+			t.Errorf("got %d warnings, want 1:\n%s", got, stderr.String())
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package evaluator_test
+
+import (
+	"context"
+
+	"github.com/zemanlx/kat/pkg/evaluator"
+)
+
+// These assignments pin the signatures of the public API: an incompatible
+// change fails to compile here before it breaks a caller.
+//
+//nolint:gochecknoglobals // Compile-time API checks
+var (
+	_ func(...evaluator.Option) (*evaluator.Evaluator, error)                                          = evaluator.New
+	_ func(*evaluator.Evaluator, context.Context, evaluator.ValidatingInput) (evaluator.Result, error) = (*evaluator.Evaluator).EvaluateValidating
+	_ func(*evaluator.Evaluator, context.Context, evaluator.MutatingInput) (evaluator.Result, error)   = (*evaluator.Evaluator).EvaluateMutating
+
+	_ func(uint, uint) evaluator.Option = evaluator.WithKubeVersion
+	_ func(uint64) evaluator.Option     = evaluator.WithCostLimit
+	_ func(int) evaluator.Option        = evaluator.WithComprehensionNestingLimit
+	_ func() evaluator.Option           = evaluator.WithCreateMissingParents
+	_ func() evaluator.Option           = evaluator.WithPolicyNameAuditAnnotationKeys
+
+	// Keyed literals naming every field keep fields from being removed or renamed.
+	_ = evaluator.ValidatingInput{
+		Policy: nil, Binding: nil, Request: nil, Object: nil, OldObject: nil,
+		Params: nil, Namespace: nil, Authorizer: nil, UserInfo: nil,
+	}
+	_ = evaluator.MutatingInput{
+		Policy: nil, Binding: nil, Request: nil, Object: nil, OldObject: nil,
+		Params: nil, Namespace: nil, Authorizer: nil, UserInfo: nil,
+	}
+	_ = evaluator.Result{
+		Applied: false, SkipReason: "", Allowed: false, Message: "", Warnings: nil,
+		AuditAnnotations: nil, Object: nil, Patch: nil, Notes: nil,
+	}
+	_ = evaluator.PatchOperation{Op: "", Path: "", From: "", Value: nil}
+)
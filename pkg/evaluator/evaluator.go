@@ -0,0 +1,280 @@
+// Package evaluator answers "would this object be admitted by this policy"
+// without a cluster. It evaluates ValidatingAdmissionPolicies and
+// MutatingAdmissionPolicies with the same CEL environment and semantics as kat:
+//
+//	eval, err := evaluator.New()
+//	if err != nil {
+//		return err
+//	}
+//
+//	result, err := eval.EvaluateValidating(ctx, evaluator.ValidatingInput{
+//		Policy: policy,
+//		Object: deployment,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//
+//	if !result.Allowed {
+//		fmt.Println("denied:", result.Message)
+//	}
+//
+// # Stability
+//
+// The exported identifiers of this package follow semantic versioning: they
+// are not removed or changed incompatibly within a major version. Input and
+// [Result] structs may gain fields, so construct them with field names. New
+// [Option] constructors may be added at any time. Messages and notes are meant
+// for humans and may change between releases.
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+var (
+	errNoPolicy = errors.New("policy is required")
+	errNoObject = errors.New("object or request is required")
+)
+
+// Evaluator evaluates admission policies. It is not safe for concurrent use.
+type Evaluator struct {
+	eval *evaluator.Evaluator
+}
+
+// Option configures an [Evaluator].
+type Option func(*options)
+
+type options struct {
+	eval []evaluator.Option
+}
+
+// WithKubeVersion limits CEL to the libraries available in the API server of
+// the given Kubernetes minor version, so expressions using newer functions fail.
+func WithKubeVersion(major, minor uint) Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithKubeVersion(utilversion.MajorMinor(major, minor)))
+	}
+}
+
+// WithCostLimit fails any expression evaluation whose runtime cost exceeds limit
+// instead of the API server's per-call limit of 1000000, the default. 0
+// disables the limit.
+func WithCostLimit(limit uint64) Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithCostLimit(limit))
+	}
+}
+
+// WithComprehensionNestingLimit rejects expressions nesting more than limit
+// comprehensions.
+func WithComprehensionNestingLimit(limit int) Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithComprehensionNestingLimit(limit))
+	}
+}
+
+// WithCreateMissingParents creates missing parent maps for JSON Patch add
+// operations instead of failing. The API server does not do this.
+func WithCreateMissingParents() Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithCreateMissingParents())
+	}
+}
+
+// WithPolicyNameAuditAnnotationKeys reports audit annotation keys as
+// "<policy-name>/<key>", as the API server writes them to the audit log.
+func WithPolicyNameAuditAnnotationKeys() Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithPolicyNameAuditAnnotationKeys())
+	}
+}
+
+// New returns an Evaluator configured by opts.
+func New(opts ...Option) (*Evaluator, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	eval, err := evaluator.New(o.eval...)
+	if err != nil {
+		return nil, fmt.Errorf("create evaluator: %w", err)
+	}
+
+	return &Evaluator{eval: eval}, nil
+}
+
+// ValidatingInput is an admission request to evaluate against a validating
+// policy. Policy is required, and so is Object unless Request is set.
+type ValidatingInput struct {
+	Policy  *admissionregv1.ValidatingAdmissionPolicy
+	Binding *admissionregv1.ValidatingAdmissionPolicyBinding // nil evaluates the policy unbound
+	// Request is the admission request; nil submits Object as a CREATE.
+	Request    *admissionv1.AdmissionRequest
+	Object     *unstructured.Unstructured
+	OldObject  *unstructured.Unstructured
+	Params     *unstructured.Unstructured
+	Namespace  *unstructured.Unstructured // The namespace object, for namespaceSelector and namespaceObject
+	Authorizer authorizer.Authorizer      // Used with UserInfo; nil leaves authorizer unset
+	UserInfo   user.Info
+}
+
+// MutatingInput is an admission request to evaluate against a mutating policy.
+// Policy is required, and so is Object unless Request is set.
+type MutatingInput struct {
+	Policy  *admissionv1beta1.MutatingAdmissionPolicy
+	Binding *admissionv1beta1.MutatingAdmissionPolicyBinding // nil evaluates the policy unbound
+	// Request is the admission request; nil submits Object as a CREATE.
+	Request    *admissionv1.AdmissionRequest
+	Object     *unstructured.Unstructured
+	OldObject  *unstructured.Unstructured
+	Params     *unstructured.Unstructured
+	Namespace  *unstructured.Unstructured // The namespace object, for namespaceSelector and namespaceObject
+	Authorizer authorizer.Authorizer      // Used with UserInfo; nil leaves authorizer unset
+	UserInfo   user.Info
+}
+
+// PatchOperation is an RFC 6902 JSON Patch operation emitted by a mutation.
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Result is the outcome of evaluating a policy.
+type Result struct {
+	// Applied reports whether the policy applied to the request; SkipReason
+	// explains why it did not.
+	Applied    bool
+	SkipReason string
+
+	Allowed          bool
+	Message          string // Denial message
+	Warnings         []string
+	AuditAnnotations map[string]string
+
+	// Object is the mutated object; nil when a mutating policy changed nothing.
+	Object *unstructured.Unstructured
+	// Patch lists the JSON Patch operations applied by a mutating policy.
+	Patch []PatchOperation
+
+	// Notes describe deviations from API server behavior applied during evaluation.
+	Notes []string
+}
+
+// EvaluateValidating evaluates a validating policy against an admission request.
+// A denial is not an error; errors mean the policy could not be evaluated.
+func (e *Evaluator) EvaluateValidating(ctx context.Context, in ValidatingInput) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, fmt.Errorf("evaluate policy: %w", err)
+	}
+
+	if in.Policy == nil {
+		return Result{}, errNoPolicy
+	}
+
+	request, err := requestFor(in.Request, in.Object)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result, err := e.eval.EvaluateValidating(evaluator.ValidatingInput{
+		Policy:     in.Policy,
+		Binding:    in.Binding,
+		Request:    request,
+		Object:     in.Object,
+		OldObject:  in.OldObject,
+		Params:     in.Params,
+		Namespace:  in.Namespace,
+		Authorizer: in.Authorizer,
+		UserInfo:   in.UserInfo,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("evaluate policy %s: %w", in.Policy.Name, err)
+	}
+
+	return newResult(result), nil
+}
+
+// EvaluateMutating evaluates a mutating policy against an admission request.
+func (e *Evaluator) EvaluateMutating(ctx context.Context, in MutatingInput) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, fmt.Errorf("evaluate policy: %w", err)
+	}
+
+	if in.Policy == nil {
+		return Result{}, errNoPolicy
+	}
+
+	request, err := requestFor(in.Request, in.Object)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result, err := e.eval.EvaluateMutating(evaluator.MutatingInput{
+		Policy:     in.Policy,
+		Binding:    in.Binding,
+		Request:    request,
+		Object:     in.Object,
+		OldObject:  in.OldObject,
+		Params:     in.Params,
+		Namespace:  in.Namespace,
+		Authorizer: in.Authorizer,
+		UserInfo:   in.UserInfo,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("evaluate policy %s: %w", in.Policy.Name, err)
+	}
+
+	return newResult(result), nil
+}
+
+func requestFor(request *admissionv1.AdmissionRequest, object *unstructured.Unstructured) (*admissionv1.AdmissionRequest, error) {
+	if request != nil {
+		return request, nil
+	}
+
+	if object == nil {
+		return nil, errNoObject
+	}
+
+	return loader.CreateRequest(object), nil
+}
+
+func newResult(result *evaluator.EvaluationResult) Result {
+	patch := make([]PatchOperation, 0, len(result.Patch))
+	for _, op := range result.Patch {
+		patch = append(patch, PatchOperation(op))
+	}
+
+	if len(patch) == 0 {
+		patch = nil
+	}
+
+	return Result{
+		Applied:          result.SkipReason == "",
+		SkipReason:       result.SkipReason,
+		Allowed:          result.Allowed,
+		Message:          result.Message,
+		Warnings:         result.Warnings,
+		AuditAnnotations: result.AuditAnnotations,
+		Object:           result.PatchedObject,
+		Patch:            patch,
+		Notes:            result.Notes,
+	}
+}
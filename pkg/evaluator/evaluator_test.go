@@ -0,0 +1,234 @@
+package evaluator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zemanlx/kat/pkg/evaluator"
+)
+
+const validatingPolicy = `
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: replica-limit
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups: ["apps"]
+      apiVersions: ["v1"]
+      operations: ["CREATE", "UPDATE"]
+      resources: ["deployments"]
+  validations:
+  - expression: object.spec.replicas <= 5
+    message: too many replicas
+`
+
+const mutatingPolicy = `
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingAdmissionPolicy
+metadata:
+  name: add-team-label
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups: ["apps"]
+      apiVersions: ["v1"]
+      operations: ["CREATE"]
+      resources: ["deployments"]
+  mutations:
+  - patchType: JSONPatch
+    jsonPatch:
+      expression: "[JSONPatch{op: 'add', path: '/metadata/labels', value: {'team': 'platform'}}]"
+`
+
+func decode[T any](t *testing.T, data string) *T {
+	t.Helper()
+
+	var obj T
+	if err := yaml.Unmarshal([]byte(data), &obj); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	return &obj
+}
+
+func deployment(replicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "web", "namespace": "default"},
+		"spec":       map[string]any{"replicas": replicas},
+	}}
+}
+
+func metaGVK(group, version, kind string) metav1.GroupVersionKind {
+	return metav1.GroupVersionKind{Group: group, Version: version, Kind: kind}
+}
+
+func metaGVR(group, version, resource string) metav1.GroupVersionResource {
+	return metav1.GroupVersionResource{Group: group, Version: version, Resource: resource}
+}
+
+func TestEvaluateValidating(t *testing.T) {
+	t.Parallel()
+
+	policy := decode[admissionregv1.ValidatingAdmissionPolicy](t, validatingPolicy)
+
+	tests := []struct {
+		name        string
+		input       evaluator.ValidatingInput
+		wantApplied bool
+		wantAllowed bool
+		wantMessage string
+	}{
+		{
+			name:        "allowed",
+			input:       evaluator.ValidatingInput{Policy: policy, Object: deployment(3)},
+			wantApplied: true,
+			wantAllowed: true,
+		},
+		{
+			name:        "denied",
+			input:       evaluator.ValidatingInput{Policy: policy, Object: deployment(10)},
+			wantApplied: true,
+			wantMessage: "too many replicas",
+		},
+		{
+			name: "operation not matched",
+			input: evaluator.ValidatingInput{
+				Policy: policy,
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Delete,
+					Kind:      metaGVK("apps", "v1", "Deployment"),
+					Resource:  metaGVR("apps", "v1", "deployments"),
+					Name:      "web",
+					Namespace: "default",
+				},
+				OldObject: deployment(10),
+			},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := evaluator.New()
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result, err := eval.EvaluateValidating(t.Context(), tt.input)
+			if err != nil {
+				t.Fatalf("EvaluateValidating() error = %v", err)
+			}
+
+			if result.Applied != tt.wantApplied {
+				t.Errorf("Applied = %v, want %v (skip reason %q)", result.Applied, tt.wantApplied, result.SkipReason)
+			}
+
+			if result.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v", result.Allowed, tt.wantAllowed)
+			}
+
+			if result.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestEvaluateMutating(t *testing.T) {
+	t.Parallel()
+
+	eval, err := evaluator.New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	policy := decode[admissionv1beta1.MutatingAdmissionPolicy](t, mutatingPolicy)
+
+	result, err := eval.EvaluateMutating(t.Context(), evaluator.MutatingInput{Policy: policy, Object: deployment(1)})
+	if err != nil {
+		t.Fatalf("EvaluateMutating() error = %v", err)
+	}
+
+	if !result.Applied || !result.Allowed {
+		t.Fatalf("Applied, Allowed = %v, %v, want true, true", result.Applied, result.Allowed)
+	}
+
+	if diff := cmp.Diff(map[string]string{"team": "platform"}, result.Object.GetLabels()); diff != "" {
+		t.Errorf("mutated labels mismatch (-want +got):\n%s", diff)
+	}
+
+	wantPatch := []evaluator.PatchOperation{
+		{Op: "add", Path: "/metadata/labels", Value: map[string]any{"team": "platform"}},
+	}
+	if diff := cmp.Diff(wantPatch, result.Patch); diff != "" {
+		t.Errorf("Patch mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	t.Parallel()
+
+	eval, err := evaluator.New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	policy := decode[admissionregv1.ValidatingAdmissionPolicy](t, validatingPolicy)
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		name  string
+		ctx   context.Context //nolint:containedctx // Test input
+		input evaluator.ValidatingInput
+		want  error
+	}{
+		{
+			name:  "canceled context",
+			ctx:   canceled,
+			input: evaluator.ValidatingInput{Policy: policy, Object: deployment(1)},
+			want:  context.Canceled,
+		},
+		{
+			name:  "no policy",
+			ctx:   t.Context(),
+			input: evaluator.ValidatingInput{Object: deployment(1)},
+		},
+		{
+			name:  "no object or request",
+			ctx:   t.Context(),
+			input: evaluator.ValidatingInput{Policy: policy},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := eval.EvaluateValidating(tt.ctx, tt.input)
+			if err == nil {
+				t.Fatal("EvaluateValidating() error = nil, want error")
+			}
+
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("EvaluateValidating() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package evaluator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+
+	internal "github.com/zemanlx/kat/internal/evaluator"
+	"github.com/zemanlx/kat/internal/loader"
+	"github.com/zemanlx/kat/pkg/evaluator"
+)
+
+// resultFields are the fields of the internal evaluation result: those mapped
+// to Result, and those Result leaves out on purpose.
+//
+//nolint:gochecknoglobals // Static lookup table
+var resultFields = map[string]bool{
+	"Allowed":              true,
+	"Message":              true,
+	"Warnings":             true,
+	"AuditAnnotations":     true,
+	"PatchedObject":        true,
+	"Patch":                true,
+	"Notes":                true,
+	"SkipReason":           true,
+	"PatchType":            false, // Always JSONPatch for Patch
+	"AppliedConfiguration": false, // Patch holds the operations either way
+	"Matched":              false, // Applied covers it
+	"Mutated":              false, // Object is nil when nothing changed
+	"FailedValidation":     false, // Test expectations of the CLI
+	"Status":               false, // Test expectations of the CLI
+}
+
+// TestResult_Fields fails when the internal evaluation result gains a field
+// that Result neither maps nor deliberately leaves out.
+func TestResult_Fields(t *testing.T) {
+	t.Parallel()
+
+	fields := reflect.TypeFor[internal.EvaluationResult]()
+	for i := range fields.NumField() {
+		if _, ok := resultFields[fields.Field(i).Name]; !ok {
+			t.Errorf("EvaluationResult.%s is neither mapped to Result nor listed as left out", fields.Field(i).Name)
+		}
+	}
+}
+
+// TestResult_MatchesInternal checks that Result reports what the evaluator
+// used by the kat CLI returns for the same input.
+func TestResult_MatchesInternal(t *testing.T) {
+	t.Parallel()
+
+	validating := decode[admissionregv1.ValidatingAdmissionPolicy](t, validatingPolicy)
+	warnBinding := &admissionregv1.ValidatingAdmissionPolicyBinding{
+		Spec: admissionregv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        validating.Name,
+			ValidationActions: []admissionregv1.ValidationAction{admissionregv1.Warn},
+		},
+	}
+	mutating := decode[admissionv1beta1.MutatingAdmissionPolicy](t, mutatingPolicy)
+
+	internalEval, err := internal.New()
+	if err != nil {
+		t.Fatalf("internal New() error = %v", err)
+	}
+
+	eval, err := evaluator.New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		replicas    int64
+		binding     *admissionregv1.ValidatingAdmissionPolicyBinding
+		mutating    bool
+		wantAllowed bool
+	}{
+		{name: "allow", replicas: 3, wantAllowed: true},
+		{name: "deny", replicas: 10},
+		{name: "warn", replicas: 10, binding: warnBinding, wantAllowed: true},
+		{name: "mutate", replicas: 1, mutating: true, wantAllowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			object := deployment(tt.replicas)
+			request := loader.CreateRequest(object)
+
+			var (
+				want *internal.EvaluationResult
+				got  evaluator.Result
+				err  error
+			)
+
+			if tt.mutating {
+				want, err = internalEval.EvaluateMutating(internal.MutatingInput{Policy: mutating, Request: request, Object: object})
+				if err != nil {
+					t.Fatalf("internal EvaluateMutating() error = %v", err)
+				}
+
+				got, err = eval.EvaluateMutating(t.Context(), evaluator.MutatingInput{Policy: mutating, Object: object})
+			} else {
+				want, err = internalEval.EvaluateValidating(internal.ValidatingInput{
+					Policy: validating, Binding: tt.binding, Request: request, Object: object,
+				})
+				if err != nil {
+					t.Fatalf("internal EvaluateValidating() error = %v", err)
+				}
+
+				got, err = eval.EvaluateValidating(t.Context(), evaluator.ValidatingInput{
+					Policy: validating, Binding: tt.binding, Object: object,
+				})
+			}
+
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+
+			if want.Allowed != tt.wantAllowed {
+				t.Fatalf("internal Allowed = %v, want %v", want.Allowed, tt.wantAllowed)
+			}
+
+			var patch []evaluator.PatchOperation
+			for _, op := range want.Patch {
+				patch = append(patch, evaluator.PatchOperation(op))
+			}
+
+			wantResult := evaluator.Result{
+				Applied:          want.SkipReason == "",
+				SkipReason:       want.SkipReason,
+				Allowed:          want.Allowed,
+				Message:          want.Message,
+				Warnings:         want.Warnings,
+				AuditAnnotations: want.AuditAnnotations,
+				Object:           want.PatchedObject,
+				Patch:            patch,
+				Notes:            want.Notes,
+			}
+
+			if diff := cmp.Diff(wantResult, got); diff != "" {
+				t.Errorf("Result mismatch (-internal +got):\n%s", diff)
+			}
+		})
+	}
+}
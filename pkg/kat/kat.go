@@ -0,0 +1,201 @@
+// Package kat runs kat test suites from Go tests, so policy repositories can
+// gate their admission policies with "go test" instead of the kat binary:
+//
+//	func TestPolicies(t *testing.T) {
+//		suites, err := kat.Load("policies")
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//
+//		kat.Run(t, suites)
+//	}
+//
+// Suites are discovered and evaluated exactly as by the kat CLI, and failing
+// tests report the same messages and diffs.
+//
+// # Stability
+//
+// The exported identifiers of this package follow semantic versioning: they
+// are not removed or changed incompatibly within a major version. [Suite] is
+// opaque; its contents may grow. New [Option] constructors may be added at any
+// time. Failure messages are meant for humans and may change between releases;
+// do not parse them. Everything under internal/ carries no guarantees.
+package kat
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/zemanlx/kat/internal/evaluator"
+	"github.com/zemanlx/kat/internal/loader"
+	"github.com/zemanlx/kat/internal/runner"
+)
+
+// Suite is a directory of admission policies and the tests exercising them.
+type Suite struct {
+	suite *loader.TestSuite
+}
+
+// Name returns the suite name, its directory relative to the loaded path.
+func (s *Suite) Name() string {
+	return s.suite.Name
+}
+
+// Load discovers the test suites under each path as the kat CLI does: a path is
+// either a suite directory itself or searched recursively for suites. As with
+// the CLI's -default-namespace default, test objects of namespaced built-in
+// kinds without a namespace, and their requests, are in the "default"
+// namespace.
+func Load(paths ...string) ([]*Suite, error) {
+	var suites []*Suite
+
+	for _, path := range paths {
+		pathSuites, err := loader.Load(path, loader.Options{DefaultNamespace: defaultNamespace})
+		if err != nil {
+			return nil, fmt.Errorf("load test suites from %s: %w", path, err)
+		}
+
+		for _, suite := range pathSuites {
+			suites = append(suites, &Suite{suite: suite})
+		}
+	}
+
+	return suites, nil
+}
+
+// defaultNamespace is the namespace the kat CLI defaults -default-namespace to.
+const defaultNamespace = "default"
+
+// Option configures how [Run] evaluates tests.
+type Option func(*options)
+
+type options struct {
+	eval            []evaluator.Option
+	maxTestDuration time.Duration
+}
+
+// WithCreateMissingParents creates missing parent objects when applying JSON
+// patches, like the -create-parents flag.
+func WithCreateMissingParents() Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithCreateMissingParents())
+	}
+}
+
+// WithNoUnexpectedWarnings fails tests that produce warnings without expecting
+// any, like the -assert-no-unexpected-warnings flag.
+func WithNoUnexpectedWarnings() Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithAssertNoUnexpectedWarnings())
+	}
+}
+
+// WithRequireGold fails mutating tests without a gold file, like the
+// -require-gold flag.
+func WithRequireGold() Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithRequireGold())
+	}
+}
+
+// WithQuantityComparison compares resource quantities of expected objects by
+// value, like the -compare-quantities flag.
+func WithQuantityComparison() Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithQuantityComparison())
+	}
+}
+
+// WithKubeVersion limits CEL to the libraries of the given Kubernetes minor
+// version, like the -kube-version flag.
+func WithKubeVersion(major, minor uint) Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithKubeVersion(utilversion.MajorMinor(major, minor)))
+	}
+}
+
+// WithComprehensionNestingLimit rejects expressions nesting more than limit
+// comprehensions, like the -max-comprehension-nesting flag.
+func WithComprehensionNestingLimit(limit int) Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithComprehensionNestingLimit(limit))
+	}
+}
+
+// WithCostLimit fails expressions whose runtime cost exceeds limit instead of
+// the API server's per-call limit, the default, like the -cost-limit flag. 0
+// disables the limit.
+func WithCostLimit(limit uint64) Option {
+	return func(o *options) {
+		o.eval = append(o.eval, evaluator.WithCostLimit(limit))
+	}
+}
+
+// WithMaxTestDuration fails tests whose policy evaluation takes longer than
+// d, like the -max-test-duration flag. A suite's kat.yaml overrides it.
+func WithMaxTestDuration(d time.Duration) Option {
+	return func(o *options) {
+		o.maxTestDuration = d
+	}
+}
+
+// Run runs every test of the suites as a subtest of t named "<suite>/<test>".
+// A failing test reports the same message as the kat CLI. Load warnings, such
+// as test files matching no policy or policies the API server would reject,
+// are logged.
+func Run(t *testing.T, suites []*Suite, opts ...Option) {
+	t.Helper()
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	eval, err := evaluator.New(o.eval...)
+	if err != nil {
+		t.Fatalf("create evaluator: %v", err)
+	}
+
+	for _, suite := range suites {
+		t.Run(suite.suite.Name, func(t *testing.T) {
+			for _, warning := range suite.suite.Warnings {
+				t.Logf("warning: %s", warning)
+			}
+
+			for _, problem := range suite.suite.PolicyProblems {
+				t.Logf("warning: %v", problem)
+			}
+
+			for _, test := range suite.suite.Tests {
+				t.Run(test.Name, func(t *testing.T) {
+					runTest(t, eval, suite.suite, test, o.maxTestDuration)
+				})
+			}
+		})
+	}
+}
+
+func runTest(
+	t *testing.T,
+	eval *evaluator.Evaluator,
+	suite *loader.TestSuite,
+	test *loader.TestCase,
+	maxTestDuration time.Duration,
+) {
+	t.Helper()
+
+	policies, err := runner.ForTest(suite, test)
+	if err != nil {
+		t.Fatalf("%s: %v", test.FilePath, err)
+	}
+
+	result := policies.Evaluate(eval, test)
+	evaluator.CheckDuration(result, suite.DurationLimit(maxTestDuration))
+
+	if !result.Passed {
+		t.Errorf("%s:\n%s", test.FilePath, result.Message)
+	}
+}
@@ -0,0 +1,48 @@
+package kat_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/zemanlx/kat/pkg/kat"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	suites, err := kat.Load(filepath.Join("..", "..", "test-policies-pass"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(suites) == 0 {
+		t.Fatal("Load() returned no suites")
+	}
+
+	kat.Run(t, suites)
+}
+
+// TestLoad_DefaultNamespace checks that a Pod without a namespace is evaluated
+// in the default namespace, as by the kat CLI.
+func TestLoad_DefaultNamespace(t *testing.T) {
+	t.Parallel()
+
+	suites, err := kat.Load(filepath.Join("testdata", "default-namespace"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(suites) != 1 {
+		t.Fatalf("Load() returned %d suites, want 1", len(suites))
+	}
+
+	kat.Run(t, suites)
+}
+
+func TestLoad_MissingPath(t *testing.T) {
+	t.Parallel()
+
+	if _, err := kat.Load(filepath.Join("testdata", "missing")); err == nil {
+		t.Error("Load() error = nil, want error")
+	}
+}
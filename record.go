@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/zemanlx/kat/internal/cluster"
+	"github.com/zemanlx/kat/internal/loader"
+	"github.com/zemanlx/kat/internal/reporter"
+)
+
+var errNoRequestToRecord = errors.New("test has no request to record")
+
+// recordResponses submits every test to the cluster as a dry-run request and
+// writes the admission response next to the test as <test>.response.yaml.
+// Later runs compare their results against these files.
+func recordResponses(ctx context.Context, suites []*loader.TestSuite, cfg *config, stdout, stderr *os.File) error {
+	client, err := cluster.NewClientFromKubeconfig(cfg.kubeconfig, cfg.kubeContext)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	rep := reporter.New(stdout)
+	configureReporter(rep, cfg, stderr)
+
+	for _, suite := range suites {
+		suiteRep := rep.StartSuite(suite.Name)
+
+		for _, test := range suite.Tests {
+			suiteRep.StartTest(test.Name, test.FilePath)
+
+			if err := recordResponse(ctx, client, test); err != nil {
+				suiteRep.ReportFail(test.Name, err.Error())
+
+				continue
+			}
+
+			suiteRep.ReportPass(test.Name)
+		}
+
+		suiteRep.End()
+	}
+
+	if err := rep.Summary(); err != nil {
+		return fmt.Errorf("record summary: %w", err)
+	}
+
+	return rep.Results().Err()
+}
+
+func recordResponse(ctx context.Context, client *cluster.Client, test *loader.TestCase) error {
+	if test.Error != nil {
+		return fmt.Errorf("test loading error: %w", test.Error)
+	}
+
+	if test.Request == nil {
+		return errNoRequestToRecord
+	}
+
+	response, err := client.Admit(ctx, test.Request.Operation, test.Object, test.OldObject)
+	if err != nil {
+		return fmt.Errorf("record response: %w", err)
+	}
+
+	data, err := yaml.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("marshal response: %w", err)
+	}
+
+	//nolint:gosec // Recorded responses are committed alongside the tests.
+	if err := os.WriteFile(test.ResponseFilePath(), data, 0o644); err != nil {
+		return fmt.Errorf("write response: %w", err)
+	}
+
+	return nil
+}
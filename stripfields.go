@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+// stripFieldFlags collects repeated -strip-field flags.
+type stripFieldFlags []string
+
+func (f *stripFieldFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stripFieldFlags) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+// parseStripFields returns the field paths to strip from test objects: the
+// server-populated fields when server is set, followed by fields.
+func parseStripFields(server bool, fields []string) ([][]string, error) {
+	var paths []string
+	if server {
+		paths = append(paths, loader.ServerFields...)
+	}
+
+	paths = append(paths, fields...)
+
+	parsed := make([][]string, 0, len(paths))
+
+	for _, path := range paths {
+		keys, err := loader.ParseFieldPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("-strip-field: %w", err)
+		}
+
+		parsed = append(parsed, keys)
+	}
+
+	return parsed, nil
+}
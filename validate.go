@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/zemanlx/kat/internal/lint"
+	"github.com/zemanlx/kat/internal/loader"
+)
+
+var errValidationFailed = errors.New("validation failed")
+
+// validateOnly loads every path and reports all problems found in policies,
+// bindings and test files without evaluating any test.
+func validateOnly(cfg *config, stdout, stderr io.Writer) error {
+	var (
+		suites   []*loader.TestSuite
+		problems []error
+	)
+
+	for _, path := range cfg.testPaths {
+		pathSuites, pathProblems := loader.Validate(path, cfg.testsDir)
+		suites = append(suites, pathSuites...)
+		problems = append(problems, pathProblems...)
+	}
+
+	if cfg.lint {
+		problems = append(problems, lintSuites(suites)...)
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintln(stdout, problem)
+	}
+
+	if err := reportLoadWarnings(suites, cfg, stderr); err != nil {
+		return fmt.Errorf("%w: %w", errLoad, err)
+	}
+
+	tests := 0
+	for _, suite := range suites {
+		tests += len(suite.Tests)
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(stdout, "FAIL\t%d problem(s) in %d suite(s), %d test(s)\n", len(problems), len(suites), tests)
+
+		return fmt.Errorf("%w: %w: %d problem(s)", errLoad, errValidationFailed, len(problems))
+	}
+
+	fmt.Fprintf(stdout, "ok\t%d suite(s), %d test(s)\n", len(suites), tests)
+
+	return nil
+}
+
+// lintSuites reports the unguarded field accesses in the expressions of the
+// policies of suites.
+func lintSuites(suites []*loader.TestSuite) []error {
+	var problems []error
+
+	for _, suite := range suites {
+		var findings []lint.Finding
+
+		for _, policy := range suite.ValidatingPolicies {
+			findings = append(findings, lint.ValidatingPolicy(policy)...)
+		}
+
+		for _, policy := range suite.MutatingPolicies {
+			findings = append(findings, lint.MutatingPolicy(policy)...)
+		}
+
+		for _, finding := range findings {
+			problems = append(problems, fmt.Errorf("%s: %s", suite.Name, finding))
+		}
+	}
+
+	return problems
+}
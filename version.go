@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"runtime/debug"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/zemanlx/kat/internal/reporter"
+)
+
+// kubernetesModules are the Kubernetes libraries whose versions decide which
+// admission API fields and CEL libraries kat supports.
+//
+//nolint:gochecknoglobals // Static lookup table
+var kubernetesModules = []string{"k8s.io/api", "k8s.io/apiserver"}
+
+// runVersion implements "kat version": it prints the build information of
+// the kat binary.
+func runVersion(args []string, stdout *os.File) error {
+	fs := flag.NewFlagSet(args[0]+" version", flag.ContinueOnError)
+
+	if err := parseFlagSet(fs, args[2:], stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: parse flags: %w", errUsage, err)
+	}
+
+	return printVersion(stdout, buildInfo())
+}
+
+// buildInfo describes the kat binary from the build information the Go
+// toolchain embeds in it: the VCS revision and commit time of go build in a
+// checkout, and the versions of the Kubernetes libraries.
+func buildInfo() *reporter.BuildInfo {
+	build := &reporter.BuildInfo{Version: getVersion()}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.time":
+			build.Date = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+
+	for _, dep := range info.Deps {
+		if !slices.Contains(kubernetesModules, dep.Path) {
+			continue
+		}
+
+		version := dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Version
+		}
+
+		if build.Kubernetes == nil {
+			build.Kubernetes = make(map[string]string, len(kubernetesModules))
+		}
+
+		build.Kubernetes[dep.Path] = version
+	}
+
+	return build
+}
+
+// printVersion writes build as "kat <version>" followed by the revision,
+// date and Kubernetes library versions known.
+func printVersion(w io.Writer, build *reporter.BuildInfo) error {
+	fmt.Fprintf(w, "kat %s\n", build.Version)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+
+	if build.Revision != "" {
+		revision := build.Revision
+		if build.Modified {
+			revision += " (modified)"
+		}
+
+		fmt.Fprintf(tw, "  revision:\t%s\n", revision)
+	}
+
+	if build.Date != "" {
+		fmt.Fprintf(tw, "  date:\t%s\n", build.Date)
+	}
+
+	for _, module := range slices.Sorted(maps.Keys(build.Kubernetes)) {
+		fmt.Fprintf(tw, "  %s:\t%s\n", module, build.Kubernetes[module])
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("print version: %w", err)
+	}
+
+	return nil
+}